@@ -31,6 +31,9 @@ type APIInfo struct {
 
 	// port
 	Port int64 `json:"port,omitempty"`
+
+	// Risk score of the API, from 0 to 100, computed from its findings, spec drift, exposure and authentication
+	RiskScore int64 `json:"riskScore,omitempty"`
 }
 
 // Validate validates this Api info
@@ -37,6 +37,9 @@ const (
 
 	// APIInventorySortKeyHasProvidedSpec captures enum value "hasProvidedSpec"
 	APIInventorySortKeyHasProvidedSpec APIInventorySortKey = "hasProvidedSpec"
+
+	// APIInventorySortKeyRiskScore captures enum value "riskScore"
+	APIInventorySortKeyRiskScore APIInventorySortKey = "riskScore"
 )
 
 // for schema
@@ -44,7 +47,7 @@ var apiInventorySortKeyEnum []interface{}
 
 func init() {
 	var res []APIInventorySortKey
-	if err := json.Unmarshal([]byte(`["name","port","hasReconstructedSpec","hasProvidedSpec"]`), &res); err != nil {
+	if err := json.Unmarshal([]byte(`["name","port","hasReconstructedSpec","hasProvidedSpec","riskScore"]`), &res); err != nil {
 		panic(err)
 	}
 	for _, v := range res {
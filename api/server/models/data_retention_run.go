@@ -0,0 +1,106 @@
+// Code generated by go-swagger; DO NOT EDIT.
+
+package models
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the swagger generate command
+
+import (
+	"context"
+
+	"github.com/go-openapi/errors"
+	"github.com/go-openapi/strfmt"
+	"github.com/go-openapi/swag"
+	"github.com/go-openapi/validate"
+)
+
+// DataRetentionRun data retention run
+//
+// swagger:model DataRetentionRun
+type DataRetentionRun struct {
+
+	// Number of API annotations deleted since their API no longer exists
+	DeletedAPIAnnotations int64 `json:"deletedApiAnnotations"`
+
+	// Number of API event annotations deleted together with the events
+	DeletedEventAnnotations int64 `json:"deletedEventAnnotations"`
+
+	// Number of API events deleted by the run
+	DeletedEvents int64 `json:"deletedEvents"`
+
+	// end time
+	// Format: date-time
+	EndTime strfmt.DateTime `json:"endTime,omitempty"`
+
+	// Error that stopped the run, if any
+	Error string `json:"error,omitempty"`
+
+	// start time
+	// Format: date-time
+	StartTime strfmt.DateTime `json:"startTime,omitempty"`
+}
+
+// Validate validates this data retention run
+func (m *DataRetentionRun) Validate(formats strfmt.Registry) error {
+	var res []error
+
+	if err := m.validateEndTime(formats); err != nil {
+		res = append(res, err)
+	}
+
+	if err := m.validateStartTime(formats); err != nil {
+		res = append(res, err)
+	}
+
+	if len(res) > 0 {
+		return errors.CompositeValidationError(res...)
+	}
+	return nil
+}
+
+func (m *DataRetentionRun) validateEndTime(formats strfmt.Registry) error {
+	if swag.IsZero(m.EndTime) { // not required
+		return nil
+	}
+
+	if err := validate.FormatOf("endTime", "body", "date-time", m.EndTime.String(), formats); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (m *DataRetentionRun) validateStartTime(formats strfmt.Registry) error {
+	if swag.IsZero(m.StartTime) { // not required
+		return nil
+	}
+
+	if err := validate.FormatOf("startTime", "body", "date-time", m.StartTime.String(), formats); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ContextValidate validates this data retention run based on context it is used
+func (m *DataRetentionRun) ContextValidate(ctx context.Context, formats strfmt.Registry) error {
+	return nil
+}
+
+// MarshalBinary interface implementation
+func (m *DataRetentionRun) MarshalBinary() ([]byte, error) {
+	if m == nil {
+		return nil, nil
+	}
+	return swag.WriteJSON(m)
+}
+
+// UnmarshalBinary interface implementation
+func (m *DataRetentionRun) UnmarshalBinary(b []byte) error {
+	var res DataRetentionRun
+	if err := swag.ReadJSON(b, &res); err != nil {
+		return err
+	}
+	*m = res
+	return nil
+}
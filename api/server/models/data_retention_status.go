@@ -0,0 +1,103 @@
+// Code generated by go-swagger; DO NOT EDIT.
+
+package models
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the swagger generate command
+
+import (
+	"context"
+
+	"github.com/go-openapi/errors"
+	"github.com/go-openapi/strfmt"
+	"github.com/go-openapi/swag"
+)
+
+// DataRetentionStatus data retention status
+//
+// swagger:model DataRetentionStatus
+type DataRetentionStatus struct {
+
+	// Whether a retention policy is configured
+	Enabled bool `json:"enabled"`
+
+	// last run
+	LastRun *DataRetentionRun `json:"lastRun,omitempty"`
+}
+
+// Validate validates this data retention status
+func (m *DataRetentionStatus) Validate(formats strfmt.Registry) error {
+	var res []error
+
+	if err := m.validateLastRun(formats); err != nil {
+		res = append(res, err)
+	}
+
+	if len(res) > 0 {
+		return errors.CompositeValidationError(res...)
+	}
+	return nil
+}
+
+func (m *DataRetentionStatus) validateLastRun(formats strfmt.Registry) error {
+	if swag.IsZero(m.LastRun) { // not required
+		return nil
+	}
+
+	if m.LastRun != nil {
+		if err := m.LastRun.Validate(formats); err != nil {
+			if ve, ok := err.(*errors.Validation); ok {
+				return ve.ValidateName("lastRun")
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ContextValidate validate this data retention status based on the context it is used
+func (m *DataRetentionStatus) ContextValidate(ctx context.Context, formats strfmt.Registry) error {
+	var res []error
+
+	if err := m.contextValidateLastRun(ctx, formats); err != nil {
+		res = append(res, err)
+	}
+
+	if len(res) > 0 {
+		return errors.CompositeValidationError(res...)
+	}
+	return nil
+}
+
+func (m *DataRetentionStatus) contextValidateLastRun(ctx context.Context, formats strfmt.Registry) error {
+
+	if m.LastRun != nil {
+		if err := m.LastRun.ContextValidate(ctx, formats); err != nil {
+			if ve, ok := err.(*errors.Validation); ok {
+				return ve.ValidateName("lastRun")
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
+// MarshalBinary interface implementation
+func (m *DataRetentionStatus) MarshalBinary() ([]byte, error) {
+	if m == nil {
+		return nil, nil
+	}
+	return swag.WriteJSON(m)
+}
+
+// UnmarshalBinary interface implementation
+func (m *DataRetentionStatus) UnmarshalBinary(b []byte) error {
+	var res DataRetentionStatus
+	if err := swag.ReadJSON(b, &res); err != nil {
+		return err
+	}
+	*m = res
+	return nil
+}
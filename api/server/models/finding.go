@@ -0,0 +1,211 @@
+// Code generated by go-swagger; DO NOT EDIT.
+
+package models
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the swagger generate command
+
+import (
+	"context"
+
+	"github.com/go-openapi/errors"
+	"github.com/go-openapi/strfmt"
+	"github.com/go-openapi/swag"
+	"github.com/go-openapi/validate"
+)
+
+// Finding A finding of a module, the repeated findings of the events of an endpoint of an API are grouped
+//
+// swagger:model Finding
+type Finding struct {
+
+	// api info Id
+	APIInfoID uint32 `json:"apiInfoId,omitempty"`
+
+	// api name
+	APIName string `json:"apiName,omitempty"`
+
+	// api port
+	APIPort int64 `json:"apiPort,omitempty"`
+
+	// Number of events with the finding, 1 for the findings of an API
+	Count int64 `json:"count,omitempty"`
+
+	// description
+	Description string `json:"description,omitempty"`
+
+	// first seen
+	// Format: date-time
+	FirstSeen strfmt.DateTime `json:"firstSeen,omitempty"`
+
+	// Name of the annotation of the module
+	Kind string `json:"kind,omitempty"`
+
+	// The last event of the finding, not set for the findings of an API
+	LastEventID uint32 `json:"lastEventId,omitempty"`
+
+	// last seen
+	// Format: date-time
+	LastSeen strfmt.DateTime `json:"lastSeen,omitempty"`
+
+	// Method of the last event of the finding
+	Method HTTPMethod `json:"method,omitempty"`
+
+	// module
+	Module string `json:"module,omitempty"`
+
+	// name
+	Name string `json:"name,omitempty"`
+
+	// namespace
+	Namespace string `json:"namespace,omitempty"`
+
+	// Path of the last event of the finding
+	Path string `json:"path,omitempty"`
+
+	// severity
+	Severity FindingSeverity `json:"severity,omitempty"`
+
+	// Path of the spec operation of the last event of the finding, if known
+	SpecPath string `json:"specPath,omitempty"`
+}
+
+// Validate validates this finding
+func (m *Finding) Validate(formats strfmt.Registry) error {
+	var res []error
+
+	if err := m.validateFirstSeen(formats); err != nil {
+		res = append(res, err)
+	}
+
+	if err := m.validateLastSeen(formats); err != nil {
+		res = append(res, err)
+	}
+
+	if err := m.validateMethod(formats); err != nil {
+		res = append(res, err)
+	}
+
+	if err := m.validateSeverity(formats); err != nil {
+		res = append(res, err)
+	}
+
+	if len(res) > 0 {
+		return errors.CompositeValidationError(res...)
+	}
+	return nil
+}
+
+func (m *Finding) validateFirstSeen(formats strfmt.Registry) error {
+	if swag.IsZero(m.FirstSeen) { // not required
+		return nil
+	}
+
+	if err := validate.FormatOf("firstSeen", "body", "date-time", m.FirstSeen.String(), formats); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (m *Finding) validateLastSeen(formats strfmt.Registry) error {
+	if swag.IsZero(m.LastSeen) { // not required
+		return nil
+	}
+
+	if err := validate.FormatOf("lastSeen", "body", "date-time", m.LastSeen.String(), formats); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (m *Finding) validateMethod(formats strfmt.Registry) error {
+	if swag.IsZero(m.Method) { // not required
+		return nil
+	}
+
+	if err := m.Method.Validate(formats); err != nil {
+		if ve, ok := err.(*errors.Validation); ok {
+			return ve.ValidateName("method")
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (m *Finding) validateSeverity(formats strfmt.Registry) error {
+	if swag.IsZero(m.Severity) { // not required
+		return nil
+	}
+
+	if err := m.Severity.Validate(formats); err != nil {
+		if ve, ok := err.(*errors.Validation); ok {
+			return ve.ValidateName("severity")
+		}
+		return err
+	}
+
+	return nil
+}
+
+// ContextValidate validate this finding based on the context it is used
+func (m *Finding) ContextValidate(ctx context.Context, formats strfmt.Registry) error {
+	var res []error
+
+	if err := m.contextValidateMethod(ctx, formats); err != nil {
+		res = append(res, err)
+	}
+
+	if err := m.contextValidateSeverity(ctx, formats); err != nil {
+		res = append(res, err)
+	}
+
+	if len(res) > 0 {
+		return errors.CompositeValidationError(res...)
+	}
+	return nil
+}
+
+func (m *Finding) contextValidateMethod(ctx context.Context, formats strfmt.Registry) error {
+
+	if err := m.Method.ContextValidate(ctx, formats); err != nil {
+		if ve, ok := err.(*errors.Validation); ok {
+			return ve.ValidateName("method")
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (m *Finding) contextValidateSeverity(ctx context.Context, formats strfmt.Registry) error {
+
+	if err := m.Severity.ContextValidate(ctx, formats); err != nil {
+		if ve, ok := err.(*errors.Validation); ok {
+			return ve.ValidateName("severity")
+		}
+		return err
+	}
+
+	return nil
+}
+
+// MarshalBinary interface implementation
+func (m *Finding) MarshalBinary() ([]byte, error) {
+	if m == nil {
+		return nil, nil
+	}
+	return swag.WriteJSON(m)
+}
+
+// UnmarshalBinary interface implementation
+func (m *Finding) UnmarshalBinary(b []byte) error {
+	var res Finding
+	if err := swag.ReadJSON(b, &res); err != nil {
+		return err
+	}
+	*m = res
+	return nil
+}
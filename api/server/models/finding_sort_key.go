@@ -0,0 +1,80 @@
+// Code generated by go-swagger; DO NOT EDIT.
+
+package models
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the swagger generate command
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/go-openapi/errors"
+	"github.com/go-openapi/strfmt"
+	"github.com/go-openapi/validate"
+)
+
+// FindingSortKey finding sort key
+//
+// swagger:model FindingSortKey
+type FindingSortKey string
+
+func NewFindingSortKey(value FindingSortKey) *FindingSortKey {
+	v := value
+	return &v
+}
+
+const (
+
+	// FindingSortKeyLastSeen captures enum value "lastSeen"
+	FindingSortKeyLastSeen FindingSortKey = "lastSeen"
+
+	// FindingSortKeyFirstSeen captures enum value "firstSeen"
+	FindingSortKeyFirstSeen FindingSortKey = "firstSeen"
+
+	// FindingSortKeyCount captures enum value "count"
+	FindingSortKeyCount FindingSortKey = "count"
+
+	// FindingSortKeySeverity captures enum value "severity"
+	FindingSortKeySeverity FindingSortKey = "severity"
+)
+
+// for schema
+var findingSortKeyEnum []interface{}
+
+func init() {
+	var res []FindingSortKey
+	if err := json.Unmarshal([]byte(`["lastSeen","firstSeen","count","severity"]`), &res); err != nil {
+		panic(err)
+	}
+	for _, v := range res {
+		findingSortKeyEnum = append(findingSortKeyEnum, v)
+	}
+}
+
+func (m FindingSortKey) validateFindingSortKeyEnum(path, location string, value FindingSortKey) error {
+	if err := validate.EnumCase(path, location, value, findingSortKeyEnum, true); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Validate validates this finding sort key
+func (m FindingSortKey) Validate(formats strfmt.Registry) error {
+	var res []error
+
+	// value enum
+	if err := m.validateFindingSortKeyEnum("", "body", m); err != nil {
+		return err
+	}
+
+	if len(res) > 0 {
+		return errors.CompositeValidationError(res...)
+	}
+	return nil
+}
+
+// ContextValidate validates this finding sort key based on context it is used
+func (m FindingSortKey) ContextValidate(ctx context.Context, formats strfmt.Registry) error {
+	return nil
+}
@@ -287,6 +287,12 @@ func init() {
           },
           {
             "$ref": "#/parameters/apiIdFilter"
+          },
+          {
+            "$ref": "#/parameters/riskScoreGteFilter"
+          },
+          {
+            "$ref": "#/parameters/riskScoreLteFilter"
           }
         ],
         "responses": {
@@ -699,6 +705,171 @@ func init() {
           }
         }
       }
+    },
+    "/dataRetention/status": {
+      "get": {
+        "summary": "Get the status of the last data retention run",
+        "responses": {
+          "200": {
+            "description": "Success",
+            "schema": {
+              "$ref": "#/definitions/DataRetentionStatus"
+            }
+          },
+          "default": {
+            "$ref": "#/responses/UnknownError"
+          }
+        }
+      }
+    },
+    "/findings": {
+      "get": {
+        "summary": "Get the findings of all the modules, across all the APIs",
+        "parameters": [
+          {
+            "$ref": "#/parameters/page"
+          },
+          {
+            "$ref": "#/parameters/pageSize"
+          },
+          {
+            "$ref": "#/parameters/findingSortKey"
+          },
+          {
+            "$ref": "#/parameters/sortDir"
+          },
+          {
+            "$ref": "#/parameters/findingsStartTime"
+          },
+          {
+            "$ref": "#/parameters/findingsEndTime"
+          },
+          {
+            "$ref": "#/parameters/moduleIsFilter"
+          },
+          {
+            "$ref": "#/parameters/kindIsFilter"
+          },
+          {
+            "$ref": "#/parameters/severityIsFilter"
+          },
+          {
+            "$ref": "#/parameters/apiInfoIdIsFilter"
+          },
+          {
+            "$ref": "#/parameters/namespaceIsFilter"
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "Success",
+            "schema": {
+              "type": "object",
+              "required": [
+                "total"
+              ],
+              "properties": {
+                "items": {
+                  "description": "List of findings in the given page. List length must be lower or equal to pageSize",
+                  "type": "array",
+                  "items": {
+                    "$ref": "#/definitions/Finding"
+                  }
+                },
+                "total": {
+                  "description": "Total findings count with the given filters",
+                  "type": "integer"
+                }
+              }
+            }
+          },
+          "default": {
+            "$ref": "#/responses/UnknownError"
+          }
+        }
+      }
+    },
+    "/findings/export/csv": {
+      "get": {
+        "produces": [
+          "text/csv"
+        ],
+        "summary": "Export the findings of all the modules as CSV",
+        "parameters": [
+          {
+            "$ref": "#/parameters/findingsStartTime"
+          },
+          {
+            "$ref": "#/parameters/findingsEndTime"
+          },
+          {
+            "$ref": "#/parameters/moduleIsFilter"
+          },
+          {
+            "$ref": "#/parameters/kindIsFilter"
+          },
+          {
+            "$ref": "#/parameters/severityIsFilter"
+          },
+          {
+            "$ref": "#/parameters/apiInfoIdIsFilter"
+          },
+          {
+            "$ref": "#/parameters/namespaceIsFilter"
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "Success",
+            "schema": {
+              "type": "string"
+            }
+          },
+          "default": {
+            "$ref": "#/responses/UnknownError"
+          }
+        }
+      }
+    },
+    "/findings/export/sarif": {
+      "get": {
+        "summary": "Export the findings of all the modules as a SARIF 2.1.0 log",
+        "parameters": [
+          {
+            "$ref": "#/parameters/findingsStartTime"
+          },
+          {
+            "$ref": "#/parameters/findingsEndTime"
+          },
+          {
+            "$ref": "#/parameters/moduleIsFilter"
+          },
+          {
+            "$ref": "#/parameters/kindIsFilter"
+          },
+          {
+            "$ref": "#/parameters/severityIsFilter"
+          },
+          {
+            "$ref": "#/parameters/apiInfoIdIsFilter"
+          },
+          {
+            "$ref": "#/parameters/namespaceIsFilter"
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "Success",
+            "schema": {
+              "description": "SARIF 2.1.0 log of the findings",
+              "type": "object"
+            }
+          },
+          "default": {
+            "$ref": "#/responses/UnknownError"
+          }
+        }
+      }
     }
   },
   "definitions": {
@@ -869,6 +1040,11 @@ func init() {
         },
         "port": {
           "type": "integer"
+        },
+        "riskScore": {
+          "description": "Risk score of the API, from 0 to 100, computed from its findings, spec drift, exposure and authentication",
+          "type": "integer",
+          "format": "int64"
         }
       }
     },
@@ -893,7 +1069,8 @@ func init() {
         "name",
         "port",
         "hasReconstructedSpec",
-        "hasProvidedSpec"
+        "hasProvidedSpec",
+        "riskScore"
       ]
     },
     "ApiResponse": {
@@ -959,6 +1136,54 @@ func init() {
         }
       }
     },
+    "DataRetentionRun": {
+      "type": "object",
+      "properties": {
+        "deletedApiAnnotations": {
+          "description": "Number of API annotations deleted since their API no longer exists",
+          "type": "integer",
+          "format": "int64",
+          "x-omitempty": false
+        },
+        "deletedEventAnnotations": {
+          "description": "Number of API event annotations deleted together with the events",
+          "type": "integer",
+          "format": "int64",
+          "x-omitempty": false
+        },
+        "deletedEvents": {
+          "description": "Number of API events deleted by the run",
+          "type": "integer",
+          "format": "int64",
+          "x-omitempty": false
+        },
+        "endTime": {
+          "type": "string",
+          "format": "date-time"
+        },
+        "error": {
+          "description": "Error that stopped the run, if any",
+          "type": "string"
+        },
+        "startTime": {
+          "type": "string",
+          "format": "date-time"
+        }
+      }
+    },
+    "DataRetentionStatus": {
+      "type": "object",
+      "properties": {
+        "enabled": {
+          "description": "Whether a retention policy is configured",
+          "type": "boolean",
+          "x-omitempty": false
+        },
+        "lastRun": {
+          "$ref": "#/definitions/DataRetentionRun"
+        }
+      }
+    },
     "DiffType": {
       "type": "string",
       "default": "NO_DIFF",
@@ -969,6 +1194,92 @@ func init() {
         "NO_DIFF"
       ]
     },
+    "Finding": {
+      "description": "A finding of a module, the repeated findings of the events of an endpoint of an API are grouped",
+      "type": "object",
+      "properties": {
+        "apiInfoId": {
+          "type": "integer",
+          "format": "uint32"
+        },
+        "apiName": {
+          "type": "string"
+        },
+        "apiPort": {
+          "type": "integer",
+          "format": "int64"
+        },
+        "count": {
+          "description": "Number of events with the finding, 1 for the findings of an API",
+          "type": "integer",
+          "format": "int64"
+        },
+        "description": {
+          "type": "string"
+        },
+        "firstSeen": {
+          "type": "string",
+          "format": "date-time"
+        },
+        "kind": {
+          "description": "Name of the annotation of the module",
+          "type": "string"
+        },
+        "lastEventId": {
+          "description": "The last event of the finding, not set for the findings of an API",
+          "type": "integer",
+          "format": "uint32"
+        },
+        "lastSeen": {
+          "type": "string",
+          "format": "date-time"
+        },
+        "method": {
+          "description": "Method of the last event of the finding",
+          "$ref": "#/definitions/HttpMethod"
+        },
+        "module": {
+          "type": "string"
+        },
+        "name": {
+          "type": "string"
+        },
+        "namespace": {
+          "type": "string"
+        },
+        "path": {
+          "description": "Path of the last event of the finding",
+          "type": "string"
+        },
+        "severity": {
+          "$ref": "#/definitions/FindingSeverity"
+        },
+        "specPath": {
+          "description": "Path of the spec operation of the last event of the finding, if known",
+          "type": "string"
+        }
+      }
+    },
+    "FindingSeverity": {
+      "description": "Severity of a finding",
+      "type": "string",
+      "enum": [
+        "INFO",
+        "LOW",
+        "MEDIUM",
+        "HIGH",
+        "CRITICAL"
+      ]
+    },
+    "FindingSortKey": {
+      "type": "string",
+      "enum": [
+        "lastSeen",
+        "firstSeen",
+        "count",
+        "severity"
+      ]
+    },
     "HitCount": {
       "type": "object",
       "properties": {
@@ -1187,12 +1498,22 @@ func init() {
       "name": "apiId",
       "in": "query"
     },
+    "apiInfoIdIsFilter": {
+      "type": "array",
+      "items": {
+        "type": "integer",
+        "format": "uint32"
+      },
+      "name": "apiInfoId[is]",
+      "in": "query"
+    },
     "apiInventorySortKey": {
       "enum": [
         "name",
         "port",
         "hasReconstructedSpec",
-        "hasProvidedSpec"
+        "hasProvidedSpec",
+        "riskScore"
       ],
       "type": "string",
       "description": "Sort key",
@@ -1285,6 +1606,33 @@ func init() {
       "in": "query",
       "required": true
     },
+    "findingSortKey": {
+      "enum": [
+        "lastSeen",
+        "firstSeen",
+        "count",
+        "severity"
+      ],
+      "type": "string",
+      "default": "lastSeen",
+      "description": "Sort key",
+      "name": "sortKey",
+      "in": "query"
+    },
+    "findingsEndTime": {
+      "type": "string",
+      "format": "date-time",
+      "description": "The findings first seen before this time",
+      "name": "endTime",
+      "in": "query"
+    },
+    "findingsStartTime": {
+      "type": "string",
+      "format": "date-time",
+      "description": "The findings last seen after this time",
+      "name": "startTime",
+      "in": "query"
+    },
     "hasProvidedSpecFilter": {
       "type": "boolean",
       "name": "hasProvidedSpec[is]",
@@ -1300,6 +1648,14 @@ func init() {
       "name": "hasSpecDiff[is]",
       "in": "query"
     },
+    "kindIsFilter": {
+      "type": "array",
+      "items": {
+        "type": "string"
+      },
+      "name": "kind[is]",
+      "in": "query"
+    },
     "methodIsFilter": {
       "type": "array",
       "items": {
@@ -1319,14 +1675,30 @@ func init() {
       "name": "method[is]",
       "in": "query"
     },
-    "page": {
-      "type": "integer",
-      "description": "Page number of the query",
-      "name": "page",
-      "in": "query",
-      "required": true
-    },
-    "pageSize": {
+    "moduleIsFilter": {
+      "type": "array",
+      "items": {
+        "type": "string"
+      },
+      "name": "module[is]",
+      "in": "query"
+    },
+    "namespaceIsFilter": {
+      "type": "array",
+      "items": {
+        "type": "string"
+      },
+      "name": "namespace[is]",
+      "in": "query"
+    },
+    "page": {
+      "type": "integer",
+      "description": "Page number of the query",
+      "name": "page",
+      "in": "query",
+      "required": true
+    },
+    "pageSize": {
       "maximum": 50,
       "minimum": 1,
       "type": "integer",
@@ -1408,6 +1780,33 @@ func init() {
       "in": "path",
       "required": true
     },
+    "riskScoreGteFilter": {
+      "type": "string",
+      "description": "greater than or equal",
+      "name": "riskScore[gte]",
+      "in": "query"
+    },
+    "riskScoreLteFilter": {
+      "type": "string",
+      "description": "less than or equal",
+      "name": "riskScore[lte]",
+      "in": "query"
+    },
+    "severityIsFilter": {
+      "type": "array",
+      "items": {
+        "enum": [
+          "INFO",
+          "LOW",
+          "MEDIUM",
+          "HIGH",
+          "CRITICAL"
+        ],
+        "type": "string"
+      },
+      "name": "severity[is]",
+      "in": "query"
+    },
     "showNonApi": {
       "type": "boolean",
       "name": "showNonApi",
@@ -1982,7 +2381,8 @@ func init() {
               "name",
               "port",
               "hasReconstructedSpec",
-              "hasProvidedSpec"
+              "hasProvidedSpec",
+              "riskScore"
             ],
             "type": "string",
             "description": "Sort key",
@@ -2066,6 +2466,18 @@ func init() {
             "description": "api id to return",
             "name": "apiId",
             "in": "query"
+          },
+          {
+            "type": "string",
+            "description": "greater than or equal",
+            "name": "riskScore[gte]",
+            "in": "query"
+          },
+          {
+            "type": "string",
+            "description": "less than or equal",
+            "name": "riskScore[lte]",
+            "in": "query"
           }
         ],
         "responses": {
@@ -2707,6 +3119,334 @@ func init() {
           }
         }
       }
+    },
+    "/dataRetention/status": {
+      "get": {
+        "summary": "Get the status of the last data retention run",
+        "responses": {
+          "200": {
+            "description": "Success",
+            "schema": {
+              "$ref": "#/definitions/DataRetentionStatus"
+            }
+          },
+          "default": {
+            "description": "unknown error",
+            "schema": {
+              "$ref": "#/definitions/ApiResponse"
+            }
+          }
+        }
+      }
+    },
+    "/findings": {
+      "get": {
+        "summary": "Get the findings of all the modules, across all the APIs",
+        "parameters": [
+          {
+            "type": "integer",
+            "description": "Page number of the query",
+            "name": "page",
+            "in": "query",
+            "required": true
+          },
+          {
+            "maximum": 50,
+            "minimum": 1,
+            "type": "integer",
+            "description": "Maximum items to return",
+            "name": "pageSize",
+            "in": "query",
+            "required": true
+          },
+          {
+            "enum": [
+              "lastSeen",
+              "firstSeen",
+              "count",
+              "severity"
+            ],
+            "type": "string",
+            "default": "lastSeen",
+            "description": "Sort key",
+            "name": "sortKey",
+            "in": "query"
+          },
+          {
+            "enum": [
+              "ASC",
+              "DESC"
+            ],
+            "type": "string",
+            "default": "ASC",
+            "description": "Sorting direction",
+            "name": "sortDir",
+            "in": "query"
+          },
+          {
+            "type": "string",
+            "format": "date-time",
+            "description": "The findings last seen after this time",
+            "name": "startTime",
+            "in": "query"
+          },
+          {
+            "type": "string",
+            "format": "date-time",
+            "description": "The findings first seen before this time",
+            "name": "endTime",
+            "in": "query"
+          },
+          {
+            "type": "array",
+            "items": {
+              "type": "string"
+            },
+            "name": "module[is]",
+            "in": "query"
+          },
+          {
+            "type": "array",
+            "items": {
+              "type": "string"
+            },
+            "name": "kind[is]",
+            "in": "query"
+          },
+          {
+            "type": "array",
+            "items": {
+              "enum": [
+                "INFO",
+                "LOW",
+                "MEDIUM",
+                "HIGH",
+                "CRITICAL"
+              ],
+              "type": "string"
+            },
+            "name": "severity[is]",
+            "in": "query"
+          },
+          {
+            "type": "array",
+            "items": {
+              "type": "integer",
+              "format": "uint32"
+            },
+            "name": "apiInfoId[is]",
+            "in": "query"
+          },
+          {
+            "type": "array",
+            "items": {
+              "type": "string"
+            },
+            "name": "namespace[is]",
+            "in": "query"
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "Success",
+            "schema": {
+              "type": "object",
+              "required": [
+                "total"
+              ],
+              "properties": {
+                "items": {
+                  "description": "List of findings in the given page. List length must be lower or equal to pageSize",
+                  "type": "array",
+                  "items": {
+                    "$ref": "#/definitions/Finding"
+                  }
+                },
+                "total": {
+                  "description": "Total findings count with the given filters",
+                  "type": "integer"
+                }
+              }
+            }
+          },
+          "default": {
+            "description": "unknown error",
+            "schema": {
+              "$ref": "#/definitions/ApiResponse"
+            }
+          }
+        }
+      }
+    },
+    "/findings/export/csv": {
+      "get": {
+        "produces": [
+          "text/csv"
+        ],
+        "summary": "Export the findings of all the modules as CSV",
+        "parameters": [
+          {
+            "type": "string",
+            "format": "date-time",
+            "description": "The findings last seen after this time",
+            "name": "startTime",
+            "in": "query"
+          },
+          {
+            "type": "string",
+            "format": "date-time",
+            "description": "The findings first seen before this time",
+            "name": "endTime",
+            "in": "query"
+          },
+          {
+            "type": "array",
+            "items": {
+              "type": "string"
+            },
+            "name": "module[is]",
+            "in": "query"
+          },
+          {
+            "type": "array",
+            "items": {
+              "type": "string"
+            },
+            "name": "kind[is]",
+            "in": "query"
+          },
+          {
+            "type": "array",
+            "items": {
+              "enum": [
+                "INFO",
+                "LOW",
+                "MEDIUM",
+                "HIGH",
+                "CRITICAL"
+              ],
+              "type": "string"
+            },
+            "name": "severity[is]",
+            "in": "query"
+          },
+          {
+            "type": "array",
+            "items": {
+              "type": "integer",
+              "format": "uint32"
+            },
+            "name": "apiInfoId[is]",
+            "in": "query"
+          },
+          {
+            "type": "array",
+            "items": {
+              "type": "string"
+            },
+            "name": "namespace[is]",
+            "in": "query"
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "Success",
+            "schema": {
+              "type": "string"
+            }
+          },
+          "default": {
+            "description": "unknown error",
+            "schema": {
+              "$ref": "#/definitions/ApiResponse"
+            }
+          }
+        }
+      }
+    },
+    "/findings/export/sarif": {
+      "get": {
+        "summary": "Export the findings of all the modules as a SARIF 2.1.0 log",
+        "parameters": [
+          {
+            "type": "string",
+            "format": "date-time",
+            "description": "The findings last seen after this time",
+            "name": "startTime",
+            "in": "query"
+          },
+          {
+            "type": "string",
+            "format": "date-time",
+            "description": "The findings first seen before this time",
+            "name": "endTime",
+            "in": "query"
+          },
+          {
+            "type": "array",
+            "items": {
+              "type": "string"
+            },
+            "name": "module[is]",
+            "in": "query"
+          },
+          {
+            "type": "array",
+            "items": {
+              "type": "string"
+            },
+            "name": "kind[is]",
+            "in": "query"
+          },
+          {
+            "type": "array",
+            "items": {
+              "enum": [
+                "INFO",
+                "LOW",
+                "MEDIUM",
+                "HIGH",
+                "CRITICAL"
+              ],
+              "type": "string"
+            },
+            "name": "severity[is]",
+            "in": "query"
+          },
+          {
+            "type": "array",
+            "items": {
+              "type": "integer",
+              "format": "uint32"
+            },
+            "name": "apiInfoId[is]",
+            "in": "query"
+          },
+          {
+            "type": "array",
+            "items": {
+              "type": "string"
+            },
+            "name": "namespace[is]",
+            "in": "query"
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "Success",
+            "schema": {
+              "description": "SARIF 2.1.0 log of the findings",
+              "type": "object"
+            }
+          },
+          "default": {
+            "description": "unknown error",
+            "schema": {
+              "$ref": "#/definitions/ApiResponse"
+            }
+          }
+        }
+      }
     }
   },
   "definitions": {
@@ -2877,6 +3617,11 @@ func init() {
         },
         "port": {
           "type": "integer"
+        },
+        "riskScore": {
+          "description": "Risk score of the API, from 0 to 100, computed from its findings, spec drift, exposure and authentication",
+          "type": "integer",
+          "format": "int64"
         }
       }
     },
@@ -2901,7 +3646,8 @@ func init() {
         "name",
         "port",
         "hasReconstructedSpec",
-        "hasProvidedSpec"
+        "hasProvidedSpec",
+        "riskScore"
       ]
     },
     "ApiResponse": {
@@ -2967,6 +3713,54 @@ func init() {
         }
       }
     },
+    "DataRetentionRun": {
+      "type": "object",
+      "properties": {
+        "deletedApiAnnotations": {
+          "description": "Number of API annotations deleted since their API no longer exists",
+          "type": "integer",
+          "format": "int64",
+          "x-omitempty": false
+        },
+        "deletedEventAnnotations": {
+          "description": "Number of API event annotations deleted together with the events",
+          "type": "integer",
+          "format": "int64",
+          "x-omitempty": false
+        },
+        "deletedEvents": {
+          "description": "Number of API events deleted by the run",
+          "type": "integer",
+          "format": "int64",
+          "x-omitempty": false
+        },
+        "endTime": {
+          "type": "string",
+          "format": "date-time"
+        },
+        "error": {
+          "description": "Error that stopped the run, if any",
+          "type": "string"
+        },
+        "startTime": {
+          "type": "string",
+          "format": "date-time"
+        }
+      }
+    },
+    "DataRetentionStatus": {
+      "type": "object",
+      "properties": {
+        "enabled": {
+          "description": "Whether a retention policy is configured",
+          "type": "boolean",
+          "x-omitempty": false
+        },
+        "lastRun": {
+          "$ref": "#/definitions/DataRetentionRun"
+        }
+      }
+    },
     "DiffType": {
       "type": "string",
       "default": "NO_DIFF",
@@ -2977,6 +3771,92 @@ func init() {
         "NO_DIFF"
       ]
     },
+    "Finding": {
+      "description": "A finding of a module, the repeated findings of the events of an endpoint of an API are grouped",
+      "type": "object",
+      "properties": {
+        "apiInfoId": {
+          "type": "integer",
+          "format": "uint32"
+        },
+        "apiName": {
+          "type": "string"
+        },
+        "apiPort": {
+          "type": "integer",
+          "format": "int64"
+        },
+        "count": {
+          "description": "Number of events with the finding, 1 for the findings of an API",
+          "type": "integer",
+          "format": "int64"
+        },
+        "description": {
+          "type": "string"
+        },
+        "firstSeen": {
+          "type": "string",
+          "format": "date-time"
+        },
+        "kind": {
+          "description": "Name of the annotation of the module",
+          "type": "string"
+        },
+        "lastEventId": {
+          "description": "The last event of the finding, not set for the findings of an API",
+          "type": "integer",
+          "format": "uint32"
+        },
+        "lastSeen": {
+          "type": "string",
+          "format": "date-time"
+        },
+        "method": {
+          "description": "Method of the last event of the finding",
+          "$ref": "#/definitions/HttpMethod"
+        },
+        "module": {
+          "type": "string"
+        },
+        "name": {
+          "type": "string"
+        },
+        "namespace": {
+          "type": "string"
+        },
+        "path": {
+          "description": "Path of the last event of the finding",
+          "type": "string"
+        },
+        "severity": {
+          "$ref": "#/definitions/FindingSeverity"
+        },
+        "specPath": {
+          "description": "Path of the spec operation of the last event of the finding, if known",
+          "type": "string"
+        }
+      }
+    },
+    "FindingSeverity": {
+      "description": "Severity of a finding",
+      "type": "string",
+      "enum": [
+        "INFO",
+        "LOW",
+        "MEDIUM",
+        "HIGH",
+        "CRITICAL"
+      ]
+    },
+    "FindingSortKey": {
+      "type": "string",
+      "enum": [
+        "lastSeen",
+        "firstSeen",
+        "count",
+        "severity"
+      ]
+    },
     "HitCount": {
       "type": "object",
       "properties": {
@@ -3195,12 +4075,22 @@ func init() {
       "name": "apiId",
       "in": "query"
     },
+    "apiInfoIdIsFilter": {
+      "type": "array",
+      "items": {
+        "type": "integer",
+        "format": "uint32"
+      },
+      "name": "apiInfoId[is]",
+      "in": "query"
+    },
     "apiInventorySortKey": {
       "enum": [
         "name",
         "port",
         "hasReconstructedSpec",
-        "hasProvidedSpec"
+        "hasProvidedSpec",
+        "riskScore"
       ],
       "type": "string",
       "description": "Sort key",
@@ -3293,6 +4183,33 @@ func init() {
       "in": "query",
       "required": true
     },
+    "findingSortKey": {
+      "enum": [
+        "lastSeen",
+        "firstSeen",
+        "count",
+        "severity"
+      ],
+      "type": "string",
+      "default": "lastSeen",
+      "description": "Sort key",
+      "name": "sortKey",
+      "in": "query"
+    },
+    "findingsEndTime": {
+      "type": "string",
+      "format": "date-time",
+      "description": "The findings first seen before this time",
+      "name": "endTime",
+      "in": "query"
+    },
+    "findingsStartTime": {
+      "type": "string",
+      "format": "date-time",
+      "description": "The findings last seen after this time",
+      "name": "startTime",
+      "in": "query"
+    },
     "hasProvidedSpecFilter": {
       "type": "boolean",
       "name": "hasProvidedSpec[is]",
@@ -3308,6 +4225,14 @@ func init() {
       "name": "hasSpecDiff[is]",
       "in": "query"
     },
+    "kindIsFilter": {
+      "type": "array",
+      "items": {
+        "type": "string"
+      },
+      "name": "kind[is]",
+      "in": "query"
+    },
     "methodIsFilter": {
       "type": "array",
       "items": {
@@ -3327,6 +4252,22 @@ func init() {
       "name": "method[is]",
       "in": "query"
     },
+    "moduleIsFilter": {
+      "type": "array",
+      "items": {
+        "type": "string"
+      },
+      "name": "module[is]",
+      "in": "query"
+    },
+    "namespaceIsFilter": {
+      "type": "array",
+      "items": {
+        "type": "string"
+      },
+      "name": "namespace[is]",
+      "in": "query"
+    },
     "page": {
       "type": "integer",
       "description": "Page number of the query",
@@ -3416,6 +4357,33 @@ func init() {
       "in": "path",
       "required": true
     },
+    "riskScoreGteFilter": {
+      "type": "string",
+      "description": "greater than or equal",
+      "name": "riskScore[gte]",
+      "in": "query"
+    },
+    "riskScoreLteFilter": {
+      "type": "string",
+      "description": "less than or equal",
+      "name": "riskScore[lte]",
+      "in": "query"
+    },
+    "severityIsFilter": {
+      "type": "array",
+      "items": {
+        "enum": [
+          "INFO",
+          "LOW",
+          "MEDIUM",
+          "HIGH",
+          "CRITICAL"
+        ],
+        "type": "string"
+      },
+      "name": "severity[is]",
+      "in": "query"
+    },
     "showNonApi": {
       "type": "boolean",
       "name": "showNonApi",
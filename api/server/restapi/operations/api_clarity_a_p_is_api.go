@@ -7,6 +7,7 @@ package operations
 
 import (
 	"fmt"
+	"io"
 	"net/http"
 	"strings"
 
@@ -40,6 +41,9 @@ func NewAPIClarityAPIsAPI(spec *loads.Document) *APIClarityAPIsAPI {
 
 		JSONConsumer: runtime.JSONConsumer(),
 
+		CsvProducer: runtime.ProducerFunc(func(w io.Writer, data interface{}) error {
+			return errors.NotImplemented("csv producer has not yet been implemented")
+		}),
 		JSONProducer: runtime.JSONProducer(),
 
 		DeleteAPIInventoryAPIIDSpecsProvidedSpecHandler: DeleteAPIInventoryAPIIDSpecsProvidedSpecHandlerFunc(func(params DeleteAPIInventoryAPIIDSpecsProvidedSpecParams) middleware.Responder {
@@ -87,6 +91,18 @@ func NewAPIClarityAPIsAPI(spec *loads.Document) *APIClarityAPIsAPI {
 		GetDashboardAPIUsageMostUsedHandler: GetDashboardAPIUsageMostUsedHandlerFunc(func(params GetDashboardAPIUsageMostUsedParams) middleware.Responder {
 			return middleware.NotImplemented("operation GetDashboardAPIUsageMostUsed has not yet been implemented")
 		}),
+		GetDataRetentionStatusHandler: GetDataRetentionStatusHandlerFunc(func(params GetDataRetentionStatusParams) middleware.Responder {
+			return middleware.NotImplemented("operation GetDataRetentionStatus has not yet been implemented")
+		}),
+		GetFindingsHandler: GetFindingsHandlerFunc(func(params GetFindingsParams) middleware.Responder {
+			return middleware.NotImplemented("operation GetFindings has not yet been implemented")
+		}),
+		GetFindingsExportCsvHandler: GetFindingsExportCsvHandlerFunc(func(params GetFindingsExportCsvParams) middleware.Responder {
+			return middleware.NotImplemented("operation GetFindingsExportCsv has not yet been implemented")
+		}),
+		GetFindingsExportSarifHandler: GetFindingsExportSarifHandlerFunc(func(params GetFindingsExportSarifParams) middleware.Responder {
+			return middleware.NotImplemented("operation GetFindingsExportSarif has not yet been implemented")
+		}),
 		PostAPIInventoryHandler: PostAPIInventoryHandlerFunc(func(params PostAPIInventoryParams) middleware.Responder {
 			return middleware.NotImplemented("operation PostAPIInventory has not yet been implemented")
 		}),
@@ -128,6 +144,9 @@ type APIClarityAPIsAPI struct {
 	//   - application/json
 	JSONConsumer runtime.Consumer
 
+	// CsvProducer registers a producer for the following mime types:
+	//   - text/csv
+	CsvProducer runtime.Producer
 	// JSONProducer registers a producer for the following mime types:
 	//   - application/json
 	JSONProducer runtime.Producer
@@ -162,6 +181,14 @@ type APIClarityAPIsAPI struct {
 	GetDashboardAPIUsageLatestDiffsHandler GetDashboardAPIUsageLatestDiffsHandler
 	// GetDashboardAPIUsageMostUsedHandler sets the operation handler for the get dashboard API usage most used operation
 	GetDashboardAPIUsageMostUsedHandler GetDashboardAPIUsageMostUsedHandler
+	// GetDataRetentionStatusHandler sets the operation handler for the get data retention status operation
+	GetDataRetentionStatusHandler GetDataRetentionStatusHandler
+	// GetFindingsHandler sets the operation handler for the get findings operation
+	GetFindingsHandler GetFindingsHandler
+	// GetFindingsExportCsvHandler sets the operation handler for the get findings export csv operation
+	GetFindingsExportCsvHandler GetFindingsExportCsvHandler
+	// GetFindingsExportSarifHandler sets the operation handler for the get findings export sarif operation
+	GetFindingsExportSarifHandler GetFindingsExportSarifHandler
 	// PostAPIInventoryHandler sets the operation handler for the post API inventory operation
 	PostAPIInventoryHandler PostAPIInventoryHandler
 	// PostAPIInventoryReviewIDApprovedReviewHandler sets the operation handler for the post API inventory review ID approved review operation
@@ -241,6 +268,9 @@ func (o *APIClarityAPIsAPI) Validate() error {
 		unregistered = append(unregistered, "JSONConsumer")
 	}
 
+	if o.CsvProducer == nil {
+		unregistered = append(unregistered, "CsvProducer")
+	}
 	if o.JSONProducer == nil {
 		unregistered = append(unregistered, "JSONProducer")
 	}
@@ -290,6 +320,18 @@ func (o *APIClarityAPIsAPI) Validate() error {
 	if o.GetDashboardAPIUsageMostUsedHandler == nil {
 		unregistered = append(unregistered, "GetDashboardAPIUsageMostUsedHandler")
 	}
+	if o.GetDataRetentionStatusHandler == nil {
+		unregistered = append(unregistered, "GetDataRetentionStatusHandler")
+	}
+	if o.GetFindingsHandler == nil {
+		unregistered = append(unregistered, "GetFindingsHandler")
+	}
+	if o.GetFindingsExportCsvHandler == nil {
+		unregistered = append(unregistered, "GetFindingsExportCsvHandler")
+	}
+	if o.GetFindingsExportSarifHandler == nil {
+		unregistered = append(unregistered, "GetFindingsExportSarifHandler")
+	}
 	if o.PostAPIInventoryHandler == nil {
 		unregistered = append(unregistered, "PostAPIInventoryHandler")
 	}
@@ -345,6 +387,8 @@ func (o *APIClarityAPIsAPI) ProducersFor(mediaTypes []string) map[string]runtime
 	result := make(map[string]runtime.Producer, len(mediaTypes))
 	for _, mt := range mediaTypes {
 		switch mt {
+		case "text/csv":
+			result["text/csv"] = o.CsvProducer
 		case "application/json":
 			result["application/json"] = o.JSONProducer
 		}
@@ -447,6 +491,22 @@ func (o *APIClarityAPIsAPI) initHandlerCache() {
 		o.handlers["GET"] = make(map[string]http.Handler)
 	}
 	o.handlers["GET"]["/dashboard/apiUsage/mostUsed"] = NewGetDashboardAPIUsageMostUsed(o.context, o.GetDashboardAPIUsageMostUsedHandler)
+	if o.handlers["GET"] == nil {
+		o.handlers["GET"] = make(map[string]http.Handler)
+	}
+	o.handlers["GET"]["/dataRetention/status"] = NewGetDataRetentionStatus(o.context, o.GetDataRetentionStatusHandler)
+	if o.handlers["GET"] == nil {
+		o.handlers["GET"] = make(map[string]http.Handler)
+	}
+	o.handlers["GET"]["/findings"] = NewGetFindings(o.context, o.GetFindingsHandler)
+	if o.handlers["GET"] == nil {
+		o.handlers["GET"] = make(map[string]http.Handler)
+	}
+	o.handlers["GET"]["/findings/export/csv"] = NewGetFindingsExportCsv(o.context, o.GetFindingsExportCsvHandler)
+	if o.handlers["GET"] == nil {
+		o.handlers["GET"] = make(map[string]http.Handler)
+	}
+	o.handlers["GET"]["/findings/export/sarif"] = NewGetFindingsExportSarif(o.context, o.GetFindingsExportSarifHandler)
 	if o.handlers["POST"] == nil {
 		o.handlers["POST"] = make(map[string]http.Handler)
 	}
@@ -92,6 +92,14 @@ type GetAPIInventoryParams struct {
 	  In: query
 	*/
 	PortIs []string
+	/*greater than or equal
+	  In: query
+	*/
+	RiskScoreGte *string
+	/*less than or equal
+	  In: query
+	*/
+	RiskScoreLte *string
 	/*Sorting direction
 	  In: query
 	  Default: "ASC"
@@ -180,6 +188,16 @@ func (o *GetAPIInventoryParams) BindRequest(r *http.Request, route *middleware.M
 		res = append(res, err)
 	}
 
+	qRiskScoreGte, qhkRiskScoreGte, _ := qs.GetOK("riskScore[gte]")
+	if err := o.bindRiskScoreGte(qRiskScoreGte, qhkRiskScoreGte, route.Formats); err != nil {
+		res = append(res, err)
+	}
+
+	qRiskScoreLte, qhkRiskScoreLte, _ := qs.GetOK("riskScore[lte]")
+	if err := o.bindRiskScoreLte(qRiskScoreLte, qhkRiskScoreLte, route.Formats); err != nil {
+		res = append(res, err)
+	}
+
 	qSortDir, qhkSortDir, _ := qs.GetOK("sortDir")
 	if err := o.bindSortDir(qSortDir, qhkSortDir, route.Formats); err != nil {
 		res = append(res, err)
@@ -505,6 +523,42 @@ func (o *GetAPIInventoryParams) bindPortIs(rawData []string, hasKey bool, format
 	return nil
 }
 
+// bindRiskScoreGte binds and validates parameter RiskScoreGte from query.
+func (o *GetAPIInventoryParams) bindRiskScoreGte(rawData []string, hasKey bool, formats strfmt.Registry) error {
+	var raw string
+	if len(rawData) > 0 {
+		raw = rawData[len(rawData)-1]
+	}
+
+	// Required: false
+	// AllowEmptyValue: false
+
+	if raw == "" { // empty values pass all other validations
+		return nil
+	}
+	o.RiskScoreGte = &raw
+
+	return nil
+}
+
+// bindRiskScoreLte binds and validates parameter RiskScoreLte from query.
+func (o *GetAPIInventoryParams) bindRiskScoreLte(rawData []string, hasKey bool, formats strfmt.Registry) error {
+	var raw string
+	if len(rawData) > 0 {
+		raw = rawData[len(rawData)-1]
+	}
+
+	// Required: false
+	// AllowEmptyValue: false
+
+	if raw == "" { // empty values pass all other validations
+		return nil
+	}
+	o.RiskScoreLte = &raw
+
+	return nil
+}
+
 // bindSortDir binds and validates parameter SortDir from query.
 func (o *GetAPIInventoryParams) bindSortDir(rawData []string, hasKey bool, formats strfmt.Registry) error {
 	var raw string
@@ -566,7 +620,7 @@ func (o *GetAPIInventoryParams) bindSortKey(rawData []string, hasKey bool, forma
 // validateSortKey carries on validations for parameter SortKey
 func (o *GetAPIInventoryParams) validateSortKey(formats strfmt.Registry) error {
 
-	if err := validate.EnumCase("sortKey", "query", o.SortKey, []interface{}{"name", "port", "hasReconstructedSpec", "hasProvidedSpec"}, true); err != nil {
+	if err := validate.EnumCase("sortKey", "query", o.SortKey, []interface{}{"name", "port", "hasReconstructedSpec", "hasProvidedSpec", "riskScore"}, true); err != nil {
 		return err
 	}
 
@@ -27,6 +27,8 @@ type GetAPIInventoryURL struct {
 	PageSize               int64
 	PortIsNot              []string
 	PortIs                 []string
+	RiskScoreGte           *string
+	RiskScoreLte           *string
 	SortDir                *string
 	SortKey                string
 	Type                   string
@@ -200,6 +202,22 @@ func (o *GetAPIInventoryURL) Build() (*url.URL, error) {
 		}
 	}
 
+	var riskScoreGteQ string
+	if o.RiskScoreGte != nil {
+		riskScoreGteQ = *o.RiskScoreGte
+	}
+	if riskScoreGteQ != "" {
+		qs.Set("riskScore[gte]", riskScoreGteQ)
+	}
+
+	var riskScoreLteQ string
+	if o.RiskScoreLte != nil {
+		riskScoreLteQ = *o.RiskScoreLte
+	}
+	if riskScoreLteQ != "" {
+		qs.Set("riskScore[lte]", riskScoreLteQ)
+	}
+
 	var sortDirQ string
 	if o.SortDir != nil {
 		sortDirQ = *o.SortDir
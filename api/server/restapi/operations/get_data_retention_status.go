@@ -0,0 +1,55 @@
+// Code generated by go-swagger; DO NOT EDIT.
+
+package operations
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the generate command
+
+import (
+	"net/http"
+
+	"github.com/go-openapi/runtime/middleware"
+)
+
+// GetDataRetentionStatusHandlerFunc turns a function with the right signature into a get data retention status handler
+type GetDataRetentionStatusHandlerFunc func(GetDataRetentionStatusParams) middleware.Responder
+
+// Handle executing the request and returning a response
+func (fn GetDataRetentionStatusHandlerFunc) Handle(params GetDataRetentionStatusParams) middleware.Responder {
+	return fn(params)
+}
+
+// GetDataRetentionStatusHandler interface for that can handle valid get data retention status params
+type GetDataRetentionStatusHandler interface {
+	Handle(GetDataRetentionStatusParams) middleware.Responder
+}
+
+// NewGetDataRetentionStatus creates a new http.Handler for the get data retention status operation
+func NewGetDataRetentionStatus(ctx *middleware.Context, handler GetDataRetentionStatusHandler) *GetDataRetentionStatus {
+	return &GetDataRetentionStatus{Context: ctx, Handler: handler}
+}
+
+/* GetDataRetentionStatus swagger:route GET /dataRetention/status getDataRetentionStatus
+
+Get the status of the last data retention run
+*/
+type GetDataRetentionStatus struct {
+	Context *middleware.Context
+	Handler GetDataRetentionStatusHandler
+}
+
+func (o *GetDataRetentionStatus) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	route, rCtx, _ := o.Context.RouteInfo(r)
+	if rCtx != nil {
+		*r = *rCtx
+	}
+	var Params = NewGetDataRetentionStatusParams()
+	if err := o.Context.BindValidRequest(r, route, &Params); err != nil { // bind params
+		o.Context.Respond(rw, r, route.Produces, route, err)
+		return
+	}
+
+	res := o.Handler.Handle(Params) // actually handle the request
+	o.Context.Respond(rw, r, route.Produces, route, res)
+
+}
@@ -0,0 +1,116 @@
+// Code generated by go-swagger; DO NOT EDIT.
+
+package operations
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the swagger generate command
+
+import (
+	"net/http"
+
+	"github.com/go-openapi/runtime"
+
+	"github.com/openclarity/apiclarity/api/server/models"
+)
+
+// GetDataRetentionStatusOKCode is the HTTP code returned for type GetDataRetentionStatusOK
+const GetDataRetentionStatusOKCode int = 200
+
+/*GetDataRetentionStatusOK Success
+
+swagger:response getDataRetentionStatusOK
+*/
+type GetDataRetentionStatusOK struct {
+
+	/*
+	  In: Body
+	*/
+	Payload *models.DataRetentionStatus `json:"body,omitempty"`
+}
+
+// NewGetDataRetentionStatusOK creates GetDataRetentionStatusOK with default headers values
+func NewGetDataRetentionStatusOK() *GetDataRetentionStatusOK {
+
+	return &GetDataRetentionStatusOK{}
+}
+
+// WithPayload adds the payload to the get data retention status o k response
+func (o *GetDataRetentionStatusOK) WithPayload(payload *models.DataRetentionStatus) *GetDataRetentionStatusOK {
+	o.Payload = payload
+	return o
+}
+
+// SetPayload sets the payload to the get data retention status o k response
+func (o *GetDataRetentionStatusOK) SetPayload(payload *models.DataRetentionStatus) {
+	o.Payload = payload
+}
+
+// WriteResponse to the client
+func (o *GetDataRetentionStatusOK) WriteResponse(rw http.ResponseWriter, producer runtime.Producer) {
+
+	rw.WriteHeader(200)
+	if o.Payload != nil {
+		payload := o.Payload
+		if err := producer.Produce(rw, payload); err != nil {
+			panic(err) // let the recovery middleware deal with this
+		}
+	}
+}
+
+/*GetDataRetentionStatusDefault unknown error
+
+swagger:response getDataRetentionStatusDefault
+*/
+type GetDataRetentionStatusDefault struct {
+	_statusCode int
+
+	/*
+	  In: Body
+	*/
+	Payload *models.APIResponse `json:"body,omitempty"`
+}
+
+// NewGetDataRetentionStatusDefault creates GetDataRetentionStatusDefault with default headers values
+func NewGetDataRetentionStatusDefault(code int) *GetDataRetentionStatusDefault {
+	if code <= 0 {
+		code = 500
+	}
+
+	return &GetDataRetentionStatusDefault{
+		_statusCode: code,
+	}
+}
+
+// WithStatusCode adds the status to the get data retention status default response
+func (o *GetDataRetentionStatusDefault) WithStatusCode(code int) *GetDataRetentionStatusDefault {
+	o._statusCode = code
+	return o
+}
+
+// SetStatusCode sets the status to the get data retention status default response
+func (o *GetDataRetentionStatusDefault) SetStatusCode(code int) {
+	o._statusCode = code
+}
+
+// WithPayload adds the payload to the get data retention status default response
+func (o *GetDataRetentionStatusDefault) WithPayload(payload *models.APIResponse) *GetDataRetentionStatusDefault {
+	o.Payload = payload
+	return o
+}
+
+// SetPayload sets the payload to the get data retention status default response
+func (o *GetDataRetentionStatusDefault) SetPayload(payload *models.APIResponse) {
+	o.Payload = payload
+}
+
+// WriteResponse to the client
+func (o *GetDataRetentionStatusDefault) WriteResponse(rw http.ResponseWriter, producer runtime.Producer) {
+
+	rw.WriteHeader(o._statusCode)
+	if o.Payload != nil {
+		payload := o.Payload
+		if err := producer.Produce(rw, payload); err != nil {
+			panic(err) // let the recovery middleware deal with this
+		}
+	}
+}
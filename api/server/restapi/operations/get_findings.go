@@ -0,0 +1,178 @@
+// Code generated by go-swagger; DO NOT EDIT.
+
+package operations
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the generate command
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/go-openapi/errors"
+	"github.com/go-openapi/runtime/middleware"
+	"github.com/go-openapi/strfmt"
+	"github.com/go-openapi/swag"
+	"github.com/go-openapi/validate"
+
+	"github.com/openclarity/apiclarity/api/server/models"
+)
+
+// GetFindingsHandlerFunc turns a function with the right signature into a get findings handler
+type GetFindingsHandlerFunc func(GetFindingsParams) middleware.Responder
+
+// Handle executing the request and returning a response
+func (fn GetFindingsHandlerFunc) Handle(params GetFindingsParams) middleware.Responder {
+	return fn(params)
+}
+
+// GetFindingsHandler interface for that can handle valid get findings params
+type GetFindingsHandler interface {
+	Handle(GetFindingsParams) middleware.Responder
+}
+
+// NewGetFindings creates a new http.Handler for the get findings operation
+func NewGetFindings(ctx *middleware.Context, handler GetFindingsHandler) *GetFindings {
+	return &GetFindings{Context: ctx, Handler: handler}
+}
+
+/*
+	GetFindings swagger:route GET /findings getFindings
+
+Get the findings of all the modules, across all the APIs
+*/
+type GetFindings struct {
+	Context *middleware.Context
+	Handler GetFindingsHandler
+}
+
+func (o *GetFindings) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	route, rCtx, _ := o.Context.RouteInfo(r)
+	if rCtx != nil {
+		*r = *rCtx
+	}
+	var Params = NewGetFindingsParams()
+	if err := o.Context.BindValidRequest(r, route, &Params); err != nil { // bind params
+		o.Context.Respond(rw, r, route.Produces, route, err)
+		return
+	}
+
+	res := o.Handler.Handle(Params) // actually handle the request
+	o.Context.Respond(rw, r, route.Produces, route, res)
+
+}
+
+// GetFindingsOKBody get findings o k body
+//
+// swagger:model GetFindingsOKBody
+type GetFindingsOKBody struct {
+
+	// List of findings in the given page. List length must be lower or equal to pageSize
+	Items []*models.Finding `json:"items"`
+
+	// Total findings count with the given filters
+	// Required: true
+	Total *int64 `json:"total"`
+}
+
+// Validate validates this get findings o k body
+func (o *GetFindingsOKBody) Validate(formats strfmt.Registry) error {
+	var res []error
+
+	if err := o.validateItems(formats); err != nil {
+		res = append(res, err)
+	}
+
+	if err := o.validateTotal(formats); err != nil {
+		res = append(res, err)
+	}
+
+	if len(res) > 0 {
+		return errors.CompositeValidationError(res...)
+	}
+	return nil
+}
+
+func (o *GetFindingsOKBody) validateItems(formats strfmt.Registry) error {
+	if swag.IsZero(o.Items) { // not required
+		return nil
+	}
+
+	for i := 0; i < len(o.Items); i++ {
+		if swag.IsZero(o.Items[i]) { // not required
+			continue
+		}
+
+		if o.Items[i] != nil {
+			if err := o.Items[i].Validate(formats); err != nil {
+				if ve, ok := err.(*errors.Validation); ok {
+					return ve.ValidateName("getFindingsOK" + "." + "items" + "." + strconv.Itoa(i))
+				}
+				return err
+			}
+		}
+
+	}
+
+	return nil
+}
+
+func (o *GetFindingsOKBody) validateTotal(formats strfmt.Registry) error {
+
+	if err := validate.Required("getFindingsOK"+"."+"total", "body", o.Total); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ContextValidate validate this get findings o k body based on the context it is used
+func (o *GetFindingsOKBody) ContextValidate(ctx context.Context, formats strfmt.Registry) error {
+	var res []error
+
+	if err := o.contextValidateItems(ctx, formats); err != nil {
+		res = append(res, err)
+	}
+
+	if len(res) > 0 {
+		return errors.CompositeValidationError(res...)
+	}
+	return nil
+}
+
+func (o *GetFindingsOKBody) contextValidateItems(ctx context.Context, formats strfmt.Registry) error {
+
+	for i := 0; i < len(o.Items); i++ {
+
+		if o.Items[i] != nil {
+			if err := o.Items[i].ContextValidate(ctx, formats); err != nil {
+				if ve, ok := err.(*errors.Validation); ok {
+					return ve.ValidateName("getFindingsOK" + "." + "items" + "." + strconv.Itoa(i))
+				}
+				return err
+			}
+		}
+
+	}
+
+	return nil
+}
+
+// MarshalBinary interface implementation
+func (o *GetFindingsOKBody) MarshalBinary() ([]byte, error) {
+	if o == nil {
+		return nil, nil
+	}
+	return swag.WriteJSON(o)
+}
+
+// UnmarshalBinary interface implementation
+func (o *GetFindingsOKBody) UnmarshalBinary(b []byte) error {
+	var res GetFindingsOKBody
+	if err := swag.ReadJSON(b, &res); err != nil {
+		return err
+	}
+	*o = res
+	return nil
+}
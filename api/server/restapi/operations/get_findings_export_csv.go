@@ -0,0 +1,56 @@
+// Code generated by go-swagger; DO NOT EDIT.
+
+package operations
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the generate command
+
+import (
+	"net/http"
+
+	"github.com/go-openapi/runtime/middleware"
+)
+
+// GetFindingsExportCsvHandlerFunc turns a function with the right signature into a get findings export csv handler
+type GetFindingsExportCsvHandlerFunc func(GetFindingsExportCsvParams) middleware.Responder
+
+// Handle executing the request and returning a response
+func (fn GetFindingsExportCsvHandlerFunc) Handle(params GetFindingsExportCsvParams) middleware.Responder {
+	return fn(params)
+}
+
+// GetFindingsExportCsvHandler interface for that can handle valid get findings export csv params
+type GetFindingsExportCsvHandler interface {
+	Handle(GetFindingsExportCsvParams) middleware.Responder
+}
+
+// NewGetFindingsExportCsv creates a new http.Handler for the get findings export csv operation
+func NewGetFindingsExportCsv(ctx *middleware.Context, handler GetFindingsExportCsvHandler) *GetFindingsExportCsv {
+	return &GetFindingsExportCsv{Context: ctx, Handler: handler}
+}
+
+/*
+	GetFindingsExportCsv swagger:route GET /findings/export/csv getFindingsExportCsv
+
+Export the findings of all the modules as CSV
+*/
+type GetFindingsExportCsv struct {
+	Context *middleware.Context
+	Handler GetFindingsExportCsvHandler
+}
+
+func (o *GetFindingsExportCsv) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	route, rCtx, _ := o.Context.RouteInfo(r)
+	if rCtx != nil {
+		*r = *rCtx
+	}
+	var Params = NewGetFindingsExportCsvParams()
+	if err := o.Context.BindValidRequest(r, route, &Params); err != nil { // bind params
+		o.Context.Respond(rw, r, route.Produces, route, err)
+		return
+	}
+
+	res := o.Handler.Handle(Params) // actually handle the request
+	o.Context.Respond(rw, r, route.Produces, route, res)
+
+}
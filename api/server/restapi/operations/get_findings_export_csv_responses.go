@@ -0,0 +1,116 @@
+// Code generated by go-swagger; DO NOT EDIT.
+
+package operations
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the swagger generate command
+
+import (
+	"net/http"
+
+	"github.com/go-openapi/runtime"
+
+	"github.com/openclarity/apiclarity/api/server/models"
+)
+
+// GetFindingsExportCsvOKCode is the HTTP code returned for type GetFindingsExportCsvOK
+const GetFindingsExportCsvOKCode int = 200
+
+/*
+GetFindingsExportCsvOK Success
+
+swagger:response getFindingsExportCsvOK
+*/
+type GetFindingsExportCsvOK struct {
+
+	/*
+	  In: Body
+	*/
+	Payload string `json:"body,omitempty"`
+}
+
+// NewGetFindingsExportCsvOK creates GetFindingsExportCsvOK with default headers values
+func NewGetFindingsExportCsvOK() *GetFindingsExportCsvOK {
+
+	return &GetFindingsExportCsvOK{}
+}
+
+// WithPayload adds the payload to the get findings export csv o k response
+func (o *GetFindingsExportCsvOK) WithPayload(payload string) *GetFindingsExportCsvOK {
+	o.Payload = payload
+	return o
+}
+
+// SetPayload sets the payload to the get findings export csv o k response
+func (o *GetFindingsExportCsvOK) SetPayload(payload string) {
+	o.Payload = payload
+}
+
+// WriteResponse to the client
+func (o *GetFindingsExportCsvOK) WriteResponse(rw http.ResponseWriter, producer runtime.Producer) {
+
+	rw.WriteHeader(200)
+	payload := o.Payload
+	if err := producer.Produce(rw, payload); err != nil {
+		panic(err) // let the recovery middleware deal with this
+	}
+}
+
+/*
+GetFindingsExportCsvDefault unknown error
+
+swagger:response getFindingsExportCsvDefault
+*/
+type GetFindingsExportCsvDefault struct {
+	_statusCode int
+
+	/*
+	  In: Body
+	*/
+	Payload *models.APIResponse `json:"body,omitempty"`
+}
+
+// NewGetFindingsExportCsvDefault creates GetFindingsExportCsvDefault with default headers values
+func NewGetFindingsExportCsvDefault(code int) *GetFindingsExportCsvDefault {
+	if code <= 0 {
+		code = 500
+	}
+
+	return &GetFindingsExportCsvDefault{
+		_statusCode: code,
+	}
+}
+
+// WithStatusCode adds the status to the get findings export csv default response
+func (o *GetFindingsExportCsvDefault) WithStatusCode(code int) *GetFindingsExportCsvDefault {
+	o._statusCode = code
+	return o
+}
+
+// SetStatusCode sets the status to the get findings export csv default response
+func (o *GetFindingsExportCsvDefault) SetStatusCode(code int) {
+	o._statusCode = code
+}
+
+// WithPayload adds the payload to the get findings export csv default response
+func (o *GetFindingsExportCsvDefault) WithPayload(payload *models.APIResponse) *GetFindingsExportCsvDefault {
+	o.Payload = payload
+	return o
+}
+
+// SetPayload sets the payload to the get findings export csv default response
+func (o *GetFindingsExportCsvDefault) SetPayload(payload *models.APIResponse) {
+	o.Payload = payload
+}
+
+// WriteResponse to the client
+func (o *GetFindingsExportCsvDefault) WriteResponse(rw http.ResponseWriter, producer runtime.Producer) {
+
+	rw.WriteHeader(o._statusCode)
+	if o.Payload != nil {
+		payload := o.Payload
+		if err := producer.Produce(rw, payload); err != nil {
+			panic(err) // let the recovery middleware deal with this
+		}
+	}
+}
@@ -0,0 +1,205 @@
+// Code generated by go-swagger; DO NOT EDIT.
+
+package operations
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the generate command
+
+import (
+	"errors"
+	"net/url"
+	golangswaggerpaths "path"
+
+	"github.com/go-openapi/strfmt"
+	"github.com/go-openapi/swag"
+)
+
+// GetFindingsExportCsvURL generates an URL for the get findings export csv operation
+type GetFindingsExportCsvURL struct {
+	APIInfoIDIs []uint32
+	EndTime     *strfmt.DateTime
+	KindIs      []string
+	ModuleIs    []string
+	NamespaceIs []string
+	SeverityIs  []string
+	StartTime   *strfmt.DateTime
+
+	_basePath string
+	// avoid unkeyed usage
+	_ struct{}
+}
+
+// WithBasePath sets the base path for this url builder, only required when it's different from the
+// base path specified in the swagger spec.
+// When the value of the base path is an empty string
+func (o *GetFindingsExportCsvURL) WithBasePath(bp string) *GetFindingsExportCsvURL {
+	o.SetBasePath(bp)
+	return o
+}
+
+// SetBasePath sets the base path for this url builder, only required when it's different from the
+// base path specified in the swagger spec.
+// When the value of the base path is an empty string
+func (o *GetFindingsExportCsvURL) SetBasePath(bp string) {
+	o._basePath = bp
+}
+
+// Build a url path and query string
+func (o *GetFindingsExportCsvURL) Build() (*url.URL, error) {
+	var _result url.URL
+
+	var _path = "/findings/export/csv"
+
+	_basePath := o._basePath
+	if _basePath == "" {
+		_basePath = "/api"
+	}
+	_result.Path = golangswaggerpaths.Join(_basePath, _path)
+
+	qs := make(url.Values)
+
+	var aPIInfoIDIsIR []string
+	for _, aPIInfoIDIsI := range o.APIInfoIDIs {
+		aPIInfoIDIsIS := swag.FormatUint32(aPIInfoIDIsI)
+		if aPIInfoIDIsIS != "" {
+			aPIInfoIDIsIR = append(aPIInfoIDIsIR, aPIInfoIDIsIS)
+		}
+	}
+
+	aPIInfoIDIs := swag.JoinByFormat(aPIInfoIDIsIR, "")
+
+	if len(aPIInfoIDIs) > 0 {
+		qsv := aPIInfoIDIs[0]
+		if qsv != "" {
+			qs.Set("apiInfoId[is]", qsv)
+		}
+	}
+
+	var endTimeQ string
+	if o.EndTime != nil {
+		endTimeQ = o.EndTime.String()
+	}
+	if endTimeQ != "" {
+		qs.Set("endTime", endTimeQ)
+	}
+
+	var kindIsIR []string
+	for _, kindIsI := range o.KindIs {
+		kindIsIS := kindIsI
+		if kindIsIS != "" {
+			kindIsIR = append(kindIsIR, kindIsIS)
+		}
+	}
+
+	kindIs := swag.JoinByFormat(kindIsIR, "")
+
+	if len(kindIs) > 0 {
+		qsv := kindIs[0]
+		if qsv != "" {
+			qs.Set("kind[is]", qsv)
+		}
+	}
+
+	var moduleIsIR []string
+	for _, moduleIsI := range o.ModuleIs {
+		moduleIsIS := moduleIsI
+		if moduleIsIS != "" {
+			moduleIsIR = append(moduleIsIR, moduleIsIS)
+		}
+	}
+
+	moduleIs := swag.JoinByFormat(moduleIsIR, "")
+
+	if len(moduleIs) > 0 {
+		qsv := moduleIs[0]
+		if qsv != "" {
+			qs.Set("module[is]", qsv)
+		}
+	}
+
+	var namespaceIsIR []string
+	for _, namespaceIsI := range o.NamespaceIs {
+		namespaceIsIS := namespaceIsI
+		if namespaceIsIS != "" {
+			namespaceIsIR = append(namespaceIsIR, namespaceIsIS)
+		}
+	}
+
+	namespaceIs := swag.JoinByFormat(namespaceIsIR, "")
+
+	if len(namespaceIs) > 0 {
+		qsv := namespaceIs[0]
+		if qsv != "" {
+			qs.Set("namespace[is]", qsv)
+		}
+	}
+
+	var severityIsIR []string
+	for _, severityIsI := range o.SeverityIs {
+		severityIsIS := severityIsI
+		if severityIsIS != "" {
+			severityIsIR = append(severityIsIR, severityIsIS)
+		}
+	}
+
+	severityIs := swag.JoinByFormat(severityIsIR, "")
+
+	if len(severityIs) > 0 {
+		qsv := severityIs[0]
+		if qsv != "" {
+			qs.Set("severity[is]", qsv)
+		}
+	}
+
+	var startTimeQ string
+	if o.StartTime != nil {
+		startTimeQ = o.StartTime.String()
+	}
+	if startTimeQ != "" {
+		qs.Set("startTime", startTimeQ)
+	}
+
+	_result.RawQuery = qs.Encode()
+
+	return &_result, nil
+}
+
+// Must is a helper function to panic when the url builder returns an error
+func (o *GetFindingsExportCsvURL) Must(u *url.URL, err error) *url.URL {
+	if err != nil {
+		panic(err)
+	}
+	if u == nil {
+		panic("url can't be nil")
+	}
+	return u
+}
+
+// String returns the string representation of the path with query string
+func (o *GetFindingsExportCsvURL) String() string {
+	return o.Must(o.Build()).String()
+}
+
+// BuildFull builds a full url with scheme, host, path and query string
+func (o *GetFindingsExportCsvURL) BuildFull(scheme, host string) (*url.URL, error) {
+	if scheme == "" {
+		return nil, errors.New("scheme is required for a full url on GetFindingsExportCsvURL")
+	}
+	if host == "" {
+		return nil, errors.New("host is required for a full url on GetFindingsExportCsvURL")
+	}
+
+	base, err := o.Build()
+	if err != nil {
+		return nil, err
+	}
+
+	base.Scheme = scheme
+	base.Host = host
+	return base, nil
+}
+
+// StringFull returns the string representation of a complete url
+func (o *GetFindingsExportCsvURL) StringFull(scheme, host string) string {
+	return o.Must(o.BuildFull(scheme, host)).String()
+}
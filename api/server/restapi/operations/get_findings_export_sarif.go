@@ -0,0 +1,56 @@
+// Code generated by go-swagger; DO NOT EDIT.
+
+package operations
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the generate command
+
+import (
+	"net/http"
+
+	"github.com/go-openapi/runtime/middleware"
+)
+
+// GetFindingsExportSarifHandlerFunc turns a function with the right signature into a get findings export sarif handler
+type GetFindingsExportSarifHandlerFunc func(GetFindingsExportSarifParams) middleware.Responder
+
+// Handle executing the request and returning a response
+func (fn GetFindingsExportSarifHandlerFunc) Handle(params GetFindingsExportSarifParams) middleware.Responder {
+	return fn(params)
+}
+
+// GetFindingsExportSarifHandler interface for that can handle valid get findings export sarif params
+type GetFindingsExportSarifHandler interface {
+	Handle(GetFindingsExportSarifParams) middleware.Responder
+}
+
+// NewGetFindingsExportSarif creates a new http.Handler for the get findings export sarif operation
+func NewGetFindingsExportSarif(ctx *middleware.Context, handler GetFindingsExportSarifHandler) *GetFindingsExportSarif {
+	return &GetFindingsExportSarif{Context: ctx, Handler: handler}
+}
+
+/*
+	GetFindingsExportSarif swagger:route GET /findings/export/sarif getFindingsExportSarif
+
+Export the findings of all the modules as a SARIF 2.1.0 log
+*/
+type GetFindingsExportSarif struct {
+	Context *middleware.Context
+	Handler GetFindingsExportSarifHandler
+}
+
+func (o *GetFindingsExportSarif) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	route, rCtx, _ := o.Context.RouteInfo(r)
+	if rCtx != nil {
+		*r = *rCtx
+	}
+	var Params = NewGetFindingsExportSarifParams()
+	if err := o.Context.BindValidRequest(r, route, &Params); err != nil { // bind params
+		o.Context.Respond(rw, r, route.Produces, route, err)
+		return
+	}
+
+	res := o.Handler.Handle(Params) // actually handle the request
+	o.Context.Respond(rw, r, route.Produces, route, res)
+
+}
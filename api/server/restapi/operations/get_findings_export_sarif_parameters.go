@@ -0,0 +1,333 @@
+// Code generated by go-swagger; DO NOT EDIT.
+
+package operations
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the swagger generate command
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/go-openapi/errors"
+	"github.com/go-openapi/runtime"
+	"github.com/go-openapi/runtime/middleware"
+	"github.com/go-openapi/strfmt"
+	"github.com/go-openapi/swag"
+	"github.com/go-openapi/validate"
+)
+
+// NewGetFindingsExportSarifParams creates a new GetFindingsExportSarifParams object
+//
+// There are no default values defined in the spec.
+func NewGetFindingsExportSarifParams() GetFindingsExportSarifParams {
+
+	return GetFindingsExportSarifParams{}
+}
+
+// GetFindingsExportSarifParams contains all the bound params for the get findings export sarif operation
+// typically these are obtained from a http.Request
+//
+// swagger:parameters GetFindingsExportSarif
+type GetFindingsExportSarifParams struct {
+
+	// HTTP Request Object
+	HTTPRequest *http.Request `json:"-"`
+
+	/*
+	  In: query
+	*/
+	APIInfoIDIs []uint32
+	/*The findings first seen before this time
+	  In: query
+	*/
+	EndTime *strfmt.DateTime
+	/*
+	  In: query
+	*/
+	KindIs []string
+	/*
+	  In: query
+	*/
+	ModuleIs []string
+	/*
+	  In: query
+	*/
+	NamespaceIs []string
+	/*
+	  In: query
+	*/
+	SeverityIs []string
+	/*The findings last seen after this time
+	  In: query
+	*/
+	StartTime *strfmt.DateTime
+}
+
+// BindRequest both binds and validates a request, it assumes that complex things implement a Validatable(strfmt.Registry) error interface
+// for simple values it will use straight method calls.
+//
+// To ensure default values, the struct must have been initialized with NewGetFindingsExportSarifParams() beforehand.
+func (o *GetFindingsExportSarifParams) BindRequest(r *http.Request, route *middleware.MatchedRoute) error {
+	var res []error
+
+	o.HTTPRequest = r
+
+	qs := runtime.Values(r.URL.Query())
+
+	qAPIInfoIDIs, qhkAPIInfoIDIs, _ := qs.GetOK("apiInfoId[is]")
+	if err := o.bindAPIInfoIDIs(qAPIInfoIDIs, qhkAPIInfoIDIs, route.Formats); err != nil {
+		res = append(res, err)
+	}
+
+	qEndTime, qhkEndTime, _ := qs.GetOK("endTime")
+	if err := o.bindEndTime(qEndTime, qhkEndTime, route.Formats); err != nil {
+		res = append(res, err)
+	}
+
+	qKindIs, qhkKindIs, _ := qs.GetOK("kind[is]")
+	if err := o.bindKindIs(qKindIs, qhkKindIs, route.Formats); err != nil {
+		res = append(res, err)
+	}
+
+	qModuleIs, qhkModuleIs, _ := qs.GetOK("module[is]")
+	if err := o.bindModuleIs(qModuleIs, qhkModuleIs, route.Formats); err != nil {
+		res = append(res, err)
+	}
+
+	qNamespaceIs, qhkNamespaceIs, _ := qs.GetOK("namespace[is]")
+	if err := o.bindNamespaceIs(qNamespaceIs, qhkNamespaceIs, route.Formats); err != nil {
+		res = append(res, err)
+	}
+
+	qSeverityIs, qhkSeverityIs, _ := qs.GetOK("severity[is]")
+	if err := o.bindSeverityIs(qSeverityIs, qhkSeverityIs, route.Formats); err != nil {
+		res = append(res, err)
+	}
+
+	qStartTime, qhkStartTime, _ := qs.GetOK("startTime")
+	if err := o.bindStartTime(qStartTime, qhkStartTime, route.Formats); err != nil {
+		res = append(res, err)
+	}
+	if len(res) > 0 {
+		return errors.CompositeValidationError(res...)
+	}
+	return nil
+}
+
+// bindAPIInfoIDIs binds and validates array parameter APIInfoIDIs from query.
+//
+// Arrays are parsed according to CollectionFormat: "" (defaults to "csv" when empty).
+func (o *GetFindingsExportSarifParams) bindAPIInfoIDIs(rawData []string, hasKey bool, formats strfmt.Registry) error {
+	var qvAPIInfoIDIs string
+	if len(rawData) > 0 {
+		qvAPIInfoIDIs = rawData[len(rawData)-1]
+	}
+
+	// CollectionFormat:
+	aPIInfoIDIsIC := swag.SplitByFormat(qvAPIInfoIDIs, "")
+	if len(aPIInfoIDIsIC) == 0 {
+		return nil
+	}
+
+	var aPIInfoIDIsIR []uint32
+	for i, aPIInfoIDIsIV := range aPIInfoIDIsIC {
+		// items.Format: "uint32"
+		aPIInfoIDIsI, err := swag.ConvertUint32(aPIInfoIDIsIV)
+		if err != nil {
+			return errors.InvalidType(fmt.Sprintf("%s.%v", "apiInfoId[is]", i), "query", "uint32", aPIInfoIDIsI)
+		}
+
+		aPIInfoIDIsIR = append(aPIInfoIDIsIR, aPIInfoIDIsI)
+	}
+
+	o.APIInfoIDIs = aPIInfoIDIsIR
+
+	return nil
+}
+
+// bindEndTime binds and validates parameter EndTime from query.
+func (o *GetFindingsExportSarifParams) bindEndTime(rawData []string, hasKey bool, formats strfmt.Registry) error {
+	var raw string
+	if len(rawData) > 0 {
+		raw = rawData[len(rawData)-1]
+	}
+
+	// Required: false
+	// AllowEmptyValue: false
+
+	if raw == "" { // empty values pass all other validations
+		return nil
+	}
+
+	// Format: date-time
+	value, err := formats.Parse("date-time", raw)
+	if err != nil {
+		return errors.InvalidType("endTime", "query", "strfmt.DateTime", raw)
+	}
+	o.EndTime = (value.(*strfmt.DateTime))
+
+	if err := o.validateEndTime(formats); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// validateEndTime carries on validations for parameter EndTime
+func (o *GetFindingsExportSarifParams) validateEndTime(formats strfmt.Registry) error {
+
+	if err := validate.FormatOf("endTime", "query", "date-time", o.EndTime.String(), formats); err != nil {
+		return err
+	}
+	return nil
+}
+
+// bindKindIs binds and validates array parameter KindIs from query.
+//
+// Arrays are parsed according to CollectionFormat: "" (defaults to "csv" when empty).
+func (o *GetFindingsExportSarifParams) bindKindIs(rawData []string, hasKey bool, formats strfmt.Registry) error {
+	var qvKindIs string
+	if len(rawData) > 0 {
+		qvKindIs = rawData[len(rawData)-1]
+	}
+
+	// CollectionFormat:
+	kindIsIC := swag.SplitByFormat(qvKindIs, "")
+	if len(kindIsIC) == 0 {
+		return nil
+	}
+
+	var kindIsIR []string
+	for _, kindIsIV := range kindIsIC {
+		kindIsI := kindIsIV
+
+		kindIsIR = append(kindIsIR, kindIsI)
+	}
+
+	o.KindIs = kindIsIR
+
+	return nil
+}
+
+// bindModuleIs binds and validates array parameter ModuleIs from query.
+//
+// Arrays are parsed according to CollectionFormat: "" (defaults to "csv" when empty).
+func (o *GetFindingsExportSarifParams) bindModuleIs(rawData []string, hasKey bool, formats strfmt.Registry) error {
+	var qvModuleIs string
+	if len(rawData) > 0 {
+		qvModuleIs = rawData[len(rawData)-1]
+	}
+
+	// CollectionFormat:
+	moduleIsIC := swag.SplitByFormat(qvModuleIs, "")
+	if len(moduleIsIC) == 0 {
+		return nil
+	}
+
+	var moduleIsIR []string
+	for _, moduleIsIV := range moduleIsIC {
+		moduleIsI := moduleIsIV
+
+		moduleIsIR = append(moduleIsIR, moduleIsI)
+	}
+
+	o.ModuleIs = moduleIsIR
+
+	return nil
+}
+
+// bindNamespaceIs binds and validates array parameter NamespaceIs from query.
+//
+// Arrays are parsed according to CollectionFormat: "" (defaults to "csv" when empty).
+func (o *GetFindingsExportSarifParams) bindNamespaceIs(rawData []string, hasKey bool, formats strfmt.Registry) error {
+	var qvNamespaceIs string
+	if len(rawData) > 0 {
+		qvNamespaceIs = rawData[len(rawData)-1]
+	}
+
+	// CollectionFormat:
+	namespaceIsIC := swag.SplitByFormat(qvNamespaceIs, "")
+	if len(namespaceIsIC) == 0 {
+		return nil
+	}
+
+	var namespaceIsIR []string
+	for _, namespaceIsIV := range namespaceIsIC {
+		namespaceIsI := namespaceIsIV
+
+		namespaceIsIR = append(namespaceIsIR, namespaceIsI)
+	}
+
+	o.NamespaceIs = namespaceIsIR
+
+	return nil
+}
+
+// bindSeverityIs binds and validates array parameter SeverityIs from query.
+//
+// Arrays are parsed according to CollectionFormat: "" (defaults to "csv" when empty).
+func (o *GetFindingsExportSarifParams) bindSeverityIs(rawData []string, hasKey bool, formats strfmt.Registry) error {
+	var qvSeverityIs string
+	if len(rawData) > 0 {
+		qvSeverityIs = rawData[len(rawData)-1]
+	}
+
+	// CollectionFormat:
+	severityIsIC := swag.SplitByFormat(qvSeverityIs, "")
+	if len(severityIsIC) == 0 {
+		return nil
+	}
+
+	var severityIsIR []string
+	for i, severityIsIV := range severityIsIC {
+		severityIsI := severityIsIV
+
+		if err := validate.EnumCase(fmt.Sprintf("%s.%v", "severity[is]", i), "query", severityIsI, []interface{}{"INFO", "LOW", "MEDIUM", "HIGH", "CRITICAL"}, true); err != nil {
+			return err
+		}
+
+		severityIsIR = append(severityIsIR, severityIsI)
+	}
+
+	o.SeverityIs = severityIsIR
+
+	return nil
+}
+
+// bindStartTime binds and validates parameter StartTime from query.
+func (o *GetFindingsExportSarifParams) bindStartTime(rawData []string, hasKey bool, formats strfmt.Registry) error {
+	var raw string
+	if len(rawData) > 0 {
+		raw = rawData[len(rawData)-1]
+	}
+
+	// Required: false
+	// AllowEmptyValue: false
+
+	if raw == "" { // empty values pass all other validations
+		return nil
+	}
+
+	// Format: date-time
+	value, err := formats.Parse("date-time", raw)
+	if err != nil {
+		return errors.InvalidType("startTime", "query", "strfmt.DateTime", raw)
+	}
+	o.StartTime = (value.(*strfmt.DateTime))
+
+	if err := o.validateStartTime(formats); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// validateStartTime carries on validations for parameter StartTime
+func (o *GetFindingsExportSarifParams) validateStartTime(formats strfmt.Registry) error {
+
+	if err := validate.FormatOf("startTime", "query", "date-time", o.StartTime.String(), formats); err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+// Code generated by go-swagger; DO NOT EDIT.
+
+package operations
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the swagger generate command
+
+import (
+	"net/http"
+
+	"github.com/go-openapi/runtime"
+
+	"github.com/openclarity/apiclarity/api/server/models"
+)
+
+// GetFindingsExportSarifOKCode is the HTTP code returned for type GetFindingsExportSarifOK
+const GetFindingsExportSarifOKCode int = 200
+
+/*
+GetFindingsExportSarifOK Success
+
+swagger:response getFindingsExportSarifOK
+*/
+type GetFindingsExportSarifOK struct {
+
+	/*
+	  In: Body
+	*/
+	Payload interface{} `json:"body,omitempty"`
+}
+
+// NewGetFindingsExportSarifOK creates GetFindingsExportSarifOK with default headers values
+func NewGetFindingsExportSarifOK() *GetFindingsExportSarifOK {
+
+	return &GetFindingsExportSarifOK{}
+}
+
+// WithPayload adds the payload to the get findings export sarif o k response
+func (o *GetFindingsExportSarifOK) WithPayload(payload interface{}) *GetFindingsExportSarifOK {
+	o.Payload = payload
+	return o
+}
+
+// SetPayload sets the payload to the get findings export sarif o k response
+func (o *GetFindingsExportSarifOK) SetPayload(payload interface{}) {
+	o.Payload = payload
+}
+
+// WriteResponse to the client
+func (o *GetFindingsExportSarifOK) WriteResponse(rw http.ResponseWriter, producer runtime.Producer) {
+
+	rw.WriteHeader(200)
+	payload := o.Payload
+	if err := producer.Produce(rw, payload); err != nil {
+		panic(err) // let the recovery middleware deal with this
+	}
+}
+
+/*
+GetFindingsExportSarifDefault unknown error
+
+swagger:response getFindingsExportSarifDefault
+*/
+type GetFindingsExportSarifDefault struct {
+	_statusCode int
+
+	/*
+	  In: Body
+	*/
+	Payload *models.APIResponse `json:"body,omitempty"`
+}
+
+// NewGetFindingsExportSarifDefault creates GetFindingsExportSarifDefault with default headers values
+func NewGetFindingsExportSarifDefault(code int) *GetFindingsExportSarifDefault {
+	if code <= 0 {
+		code = 500
+	}
+
+	return &GetFindingsExportSarifDefault{
+		_statusCode: code,
+	}
+}
+
+// WithStatusCode adds the status to the get findings export sarif default response
+func (o *GetFindingsExportSarifDefault) WithStatusCode(code int) *GetFindingsExportSarifDefault {
+	o._statusCode = code
+	return o
+}
+
+// SetStatusCode sets the status to the get findings export sarif default response
+func (o *GetFindingsExportSarifDefault) SetStatusCode(code int) {
+	o._statusCode = code
+}
+
+// WithPayload adds the payload to the get findings export sarif default response
+func (o *GetFindingsExportSarifDefault) WithPayload(payload *models.APIResponse) *GetFindingsExportSarifDefault {
+	o.Payload = payload
+	return o
+}
+
+// SetPayload sets the payload to the get findings export sarif default response
+func (o *GetFindingsExportSarifDefault) SetPayload(payload *models.APIResponse) {
+	o.Payload = payload
+}
+
+// WriteResponse to the client
+func (o *GetFindingsExportSarifDefault) WriteResponse(rw http.ResponseWriter, producer runtime.Producer) {
+
+	rw.WriteHeader(o._statusCode)
+	if o.Payload != nil {
+		payload := o.Payload
+		if err := producer.Produce(rw, payload); err != nil {
+			panic(err) // let the recovery middleware deal with this
+		}
+	}
+}
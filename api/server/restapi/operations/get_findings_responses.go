@@ -0,0 +1,118 @@
+// Code generated by go-swagger; DO NOT EDIT.
+
+package operations
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the swagger generate command
+
+import (
+	"net/http"
+
+	"github.com/go-openapi/runtime"
+
+	"github.com/openclarity/apiclarity/api/server/models"
+)
+
+// GetFindingsOKCode is the HTTP code returned for type GetFindingsOK
+const GetFindingsOKCode int = 200
+
+/*
+GetFindingsOK Success
+
+swagger:response getFindingsOK
+*/
+type GetFindingsOK struct {
+
+	/*
+	  In: Body
+	*/
+	Payload *GetFindingsOKBody `json:"body,omitempty"`
+}
+
+// NewGetFindingsOK creates GetFindingsOK with default headers values
+func NewGetFindingsOK() *GetFindingsOK {
+
+	return &GetFindingsOK{}
+}
+
+// WithPayload adds the payload to the get findings o k response
+func (o *GetFindingsOK) WithPayload(payload *GetFindingsOKBody) *GetFindingsOK {
+	o.Payload = payload
+	return o
+}
+
+// SetPayload sets the payload to the get findings o k response
+func (o *GetFindingsOK) SetPayload(payload *GetFindingsOKBody) {
+	o.Payload = payload
+}
+
+// WriteResponse to the client
+func (o *GetFindingsOK) WriteResponse(rw http.ResponseWriter, producer runtime.Producer) {
+
+	rw.WriteHeader(200)
+	if o.Payload != nil {
+		payload := o.Payload
+		if err := producer.Produce(rw, payload); err != nil {
+			panic(err) // let the recovery middleware deal with this
+		}
+	}
+}
+
+/*
+GetFindingsDefault unknown error
+
+swagger:response getFindingsDefault
+*/
+type GetFindingsDefault struct {
+	_statusCode int
+
+	/*
+	  In: Body
+	*/
+	Payload *models.APIResponse `json:"body,omitempty"`
+}
+
+// NewGetFindingsDefault creates GetFindingsDefault with default headers values
+func NewGetFindingsDefault(code int) *GetFindingsDefault {
+	if code <= 0 {
+		code = 500
+	}
+
+	return &GetFindingsDefault{
+		_statusCode: code,
+	}
+}
+
+// WithStatusCode adds the status to the get findings default response
+func (o *GetFindingsDefault) WithStatusCode(code int) *GetFindingsDefault {
+	o._statusCode = code
+	return o
+}
+
+// SetStatusCode sets the status to the get findings default response
+func (o *GetFindingsDefault) SetStatusCode(code int) {
+	o._statusCode = code
+}
+
+// WithPayload adds the payload to the get findings default response
+func (o *GetFindingsDefault) WithPayload(payload *models.APIResponse) *GetFindingsDefault {
+	o.Payload = payload
+	return o
+}
+
+// SetPayload sets the payload to the get findings default response
+func (o *GetFindingsDefault) SetPayload(payload *models.APIResponse) {
+	o.Payload = payload
+}
+
+// WriteResponse to the client
+func (o *GetFindingsDefault) WriteResponse(rw http.ResponseWriter, producer runtime.Producer) {
+
+	rw.WriteHeader(o._statusCode)
+	if o.Payload != nil {
+		payload := o.Payload
+		if err := producer.Produce(rw, payload); err != nil {
+			panic(err) // let the recovery middleware deal with this
+		}
+	}
+}
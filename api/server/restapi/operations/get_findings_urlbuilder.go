@@ -0,0 +1,235 @@
+// Code generated by go-swagger; DO NOT EDIT.
+
+package operations
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the generate command
+
+import (
+	"errors"
+	"net/url"
+	golangswaggerpaths "path"
+
+	"github.com/go-openapi/strfmt"
+	"github.com/go-openapi/swag"
+)
+
+// GetFindingsURL generates an URL for the get findings operation
+type GetFindingsURL struct {
+	APIInfoIDIs []uint32
+	EndTime     *strfmt.DateTime
+	KindIs      []string
+	ModuleIs    []string
+	NamespaceIs []string
+	Page        int64
+	PageSize    int64
+	SeverityIs  []string
+	SortDir     *string
+	SortKey     *string
+	StartTime   *strfmt.DateTime
+
+	_basePath string
+	// avoid unkeyed usage
+	_ struct{}
+}
+
+// WithBasePath sets the base path for this url builder, only required when it's different from the
+// base path specified in the swagger spec.
+// When the value of the base path is an empty string
+func (o *GetFindingsURL) WithBasePath(bp string) *GetFindingsURL {
+	o.SetBasePath(bp)
+	return o
+}
+
+// SetBasePath sets the base path for this url builder, only required when it's different from the
+// base path specified in the swagger spec.
+// When the value of the base path is an empty string
+func (o *GetFindingsURL) SetBasePath(bp string) {
+	o._basePath = bp
+}
+
+// Build a url path and query string
+func (o *GetFindingsURL) Build() (*url.URL, error) {
+	var _result url.URL
+
+	var _path = "/findings"
+
+	_basePath := o._basePath
+	if _basePath == "" {
+		_basePath = "/api"
+	}
+	_result.Path = golangswaggerpaths.Join(_basePath, _path)
+
+	qs := make(url.Values)
+
+	var aPIInfoIDIsIR []string
+	for _, aPIInfoIDIsI := range o.APIInfoIDIs {
+		aPIInfoIDIsIS := swag.FormatUint32(aPIInfoIDIsI)
+		if aPIInfoIDIsIS != "" {
+			aPIInfoIDIsIR = append(aPIInfoIDIsIR, aPIInfoIDIsIS)
+		}
+	}
+
+	aPIInfoIDIs := swag.JoinByFormat(aPIInfoIDIsIR, "")
+
+	if len(aPIInfoIDIs) > 0 {
+		qsv := aPIInfoIDIs[0]
+		if qsv != "" {
+			qs.Set("apiInfoId[is]", qsv)
+		}
+	}
+
+	var endTimeQ string
+	if o.EndTime != nil {
+		endTimeQ = o.EndTime.String()
+	}
+	if endTimeQ != "" {
+		qs.Set("endTime", endTimeQ)
+	}
+
+	var kindIsIR []string
+	for _, kindIsI := range o.KindIs {
+		kindIsIS := kindIsI
+		if kindIsIS != "" {
+			kindIsIR = append(kindIsIR, kindIsIS)
+		}
+	}
+
+	kindIs := swag.JoinByFormat(kindIsIR, "")
+
+	if len(kindIs) > 0 {
+		qsv := kindIs[0]
+		if qsv != "" {
+			qs.Set("kind[is]", qsv)
+		}
+	}
+
+	var moduleIsIR []string
+	for _, moduleIsI := range o.ModuleIs {
+		moduleIsIS := moduleIsI
+		if moduleIsIS != "" {
+			moduleIsIR = append(moduleIsIR, moduleIsIS)
+		}
+	}
+
+	moduleIs := swag.JoinByFormat(moduleIsIR, "")
+
+	if len(moduleIs) > 0 {
+		qsv := moduleIs[0]
+		if qsv != "" {
+			qs.Set("module[is]", qsv)
+		}
+	}
+
+	var namespaceIsIR []string
+	for _, namespaceIsI := range o.NamespaceIs {
+		namespaceIsIS := namespaceIsI
+		if namespaceIsIS != "" {
+			namespaceIsIR = append(namespaceIsIR, namespaceIsIS)
+		}
+	}
+
+	namespaceIs := swag.JoinByFormat(namespaceIsIR, "")
+
+	if len(namespaceIs) > 0 {
+		qsv := namespaceIs[0]
+		if qsv != "" {
+			qs.Set("namespace[is]", qsv)
+		}
+	}
+
+	pageQ := swag.FormatInt64(o.Page)
+	if pageQ != "" {
+		qs.Set("page", pageQ)
+	}
+
+	pageSizeQ := swag.FormatInt64(o.PageSize)
+	if pageSizeQ != "" {
+		qs.Set("pageSize", pageSizeQ)
+	}
+
+	var severityIsIR []string
+	for _, severityIsI := range o.SeverityIs {
+		severityIsIS := severityIsI
+		if severityIsIS != "" {
+			severityIsIR = append(severityIsIR, severityIsIS)
+		}
+	}
+
+	severityIs := swag.JoinByFormat(severityIsIR, "")
+
+	if len(severityIs) > 0 {
+		qsv := severityIs[0]
+		if qsv != "" {
+			qs.Set("severity[is]", qsv)
+		}
+	}
+
+	var sortDirQ string
+	if o.SortDir != nil {
+		sortDirQ = *o.SortDir
+	}
+	if sortDirQ != "" {
+		qs.Set("sortDir", sortDirQ)
+	}
+
+	var sortKeyQ string
+	if o.SortKey != nil {
+		sortKeyQ = *o.SortKey
+	}
+	if sortKeyQ != "" {
+		qs.Set("sortKey", sortKeyQ)
+	}
+
+	var startTimeQ string
+	if o.StartTime != nil {
+		startTimeQ = o.StartTime.String()
+	}
+	if startTimeQ != "" {
+		qs.Set("startTime", startTimeQ)
+	}
+
+	_result.RawQuery = qs.Encode()
+
+	return &_result, nil
+}
+
+// Must is a helper function to panic when the url builder returns an error
+func (o *GetFindingsURL) Must(u *url.URL, err error) *url.URL {
+	if err != nil {
+		panic(err)
+	}
+	if u == nil {
+		panic("url can't be nil")
+	}
+	return u
+}
+
+// String returns the string representation of the path with query string
+func (o *GetFindingsURL) String() string {
+	return o.Must(o.Build()).String()
+}
+
+// BuildFull builds a full url with scheme, host, path and query string
+func (o *GetFindingsURL) BuildFull(scheme, host string) (*url.URL, error) {
+	if scheme == "" {
+		return nil, errors.New("scheme is required for a full url on GetFindingsURL")
+	}
+	if host == "" {
+		return nil, errors.New("host is required for a full url on GetFindingsURL")
+	}
+
+	base, err := o.Build()
+	if err != nil {
+		return nil, err
+	}
+
+	base.Scheme = scheme
+	base.Host = host
+	return base, nil
+}
+
+// StringFull returns the string representation of a complete url
+func (o *GetFindingsURL) StringFull(scheme, host string) string {
+	return o.Must(o.BuildFull(scheme, host)).String()
+}
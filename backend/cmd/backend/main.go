@@ -16,6 +16,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"os"
 
@@ -26,6 +27,7 @@ import (
 	"github.com/openclarity/apiclarity/backend/pkg/backend"
 	"github.com/openclarity/apiclarity/backend/pkg/config"
 	"github.com/openclarity/apiclarity/backend/pkg/database"
+	"github.com/openclarity/apiclarity/backend/pkg/replay"
 	"github.com/openclarity/apiclarity/backend/pkg/version"
 	log_utils "github.com/openclarity/speculator/pkg/utils/log"
 )
@@ -35,6 +37,54 @@ func run(c *cli.Context) {
 	backend.Run()
 }
 
+const (
+	harFlag       = "har"
+	upstreamFlag  = "upstream"
+	batchSizeFlag = "batch-size"
+)
+
+func replayCommand(c *cli.Context) {
+	log_utils.InitLogs(c, os.Stdout)
+
+	harFiles := c.StringSlice(harFlag)
+	if len(harFiles) == 0 {
+		log.Fatalf("At least one HAR file is required (--%v)", harFlag)
+	}
+
+	upstream := c.String(upstreamFlag)
+	if upstream == "" {
+		// replay directly into the trace handling of an in-process backend, which exits once it's done. Unless
+		// configured otherwise, the backend uses a local database.
+		viper.SetDefault(config.DatabaseDriver, database.DBDriverTypeLocal)
+		result, err := backend.Replay(harFiles)
+		if err != nil {
+			log.Fatalf("Failed to replay HAR files: %v", err)
+		}
+		log.Infof("HAR files were replayed: %v", result)
+		if result.Failed > 0 {
+			log.Fatalf("Failed to replay %v telemetries", result.Failed)
+		}
+		return
+	}
+
+	failed := 0
+	for _, harFile := range harFiles {
+		telemetries, err := replay.LoadHARFile(harFile)
+		if err != nil {
+			log.Fatalf("Failed to load HAR file: %v", err)
+		}
+		result, err := replay.ReplayToUpstream(context.Background(), upstream, telemetries, c.Int(batchSizeFlag))
+		if err != nil {
+			log.Fatalf("Failed to replay HAR file %v: %v", harFile, err)
+		}
+		log.Infof("HAR file %v was replayed: %v", harFile, result)
+		failed += result.Failed
+	}
+	if failed > 0 {
+		log.Fatalf("Failed to replay %v telemetries", failed)
+	}
+}
+
 func versionCommand(_ *cli.Context) {
 	fmt.Printf("Version: %s \nCommit: %s\nBuild Time: %s",
 		version.Version, version.CommitHash, version.BuildTimestamp)
@@ -43,13 +93,29 @@ func versionCommand(_ *cli.Context) {
 func main() {
 	viper.SetDefault(config.HealthCheckAddress, ":8081")
 	viper.SetDefault(config.HTTPTracesPort, "9000")
+	viper.SetDefault(config.GRPCTracesPort, "9001")
+	viper.SetDefault(config.OTLPHTTPTracesPort, "4318")
+	viper.SetDefault(config.TLSServerCertFilePath, "/etc/certs/server.crt")
+	viper.SetDefault(config.TLSServerKeyFilePath, "/etc/certs/server.key")
 	viper.SetDefault(config.HTTPTraceSamplingManagerPort, "9990")
 	viper.SetDefault(config.GRPCTraceSamplingManagerPort, "9991")
 	viper.SetDefault(config.BackendRestPort, "8080")
 	viper.SetDefault(config.StateBackupIntervalSec, "30")
 	viper.SetDefault(config.DatabaseCleanerIntervalSec, "30")
+	viper.SetDefault(config.EventRollupIntervalSec, "60")
+	viper.SetDefault(config.RiskScoreIntervalSec, "30")
 	viper.SetDefault(config.StateBackupFileName, "state.gob")
 	viper.SetDefault(config.DatabaseDriver, database.DBDriverTypePostgres)
+	viper.SetDefault(config.TracesQueueSize, "1000")
+	viper.SetDefault(config.TracesQueueWorkers, "4")
+	viper.SetDefault(config.TracesQueueFullPolicy, "reject")
+	viper.SetDefault(config.TracesQueueBlockTimeoutMsec, "1000")
+	viper.SetDefault(config.TracesQueueDrainTimeoutSec, "10")
+	viper.SetDefault(config.TracesMetricsLogIntervalSec, "60")
+	viper.SetDefault(config.DataRetentionBatchSize, "1000")
+	viper.SetDefault(config.DataRetentionIntervalSec, "3600")
+	viper.SetDefault(config.TraceArchiveMaxBodySize, "65536")
+	viper.SetDefault(config.TraceArchiveRedactedHeaders, database.DefaultRedactedHeaders)
 	viper.AutomaticEnv()
 	app := cli.NewApp()
 	app.Usage = ""
@@ -70,6 +136,33 @@ func main() {
 	}
 	runCommand.UsageText = runCommand.Name
 
+	replayCommand := cli.Command{
+		Name:   "replay",
+		Usage:  "Replays captured traffic (HAR files) into APIClarity",
+		Action: replayCommand,
+		Flags: []cli.Flag{
+			cli.StringSliceFlag{
+				Name:  harFlag,
+				Usage: "HAR file to replay, can be repeated",
+			},
+			cli.StringFlag{
+				Name:  upstreamFlag,
+				Usage: "Address (host:port) of the HTTP traces server of a running backend. If not set, the traffic is replayed into an in-process backend, which exits once it's done",
+			},
+			cli.IntFlag{
+				Name:  batchSizeFlag,
+				Value: replay.DefaultBatchSize,
+				Usage: "Number of telemetries posted in a single request to the upstream backend",
+			},
+			cli.StringFlag{
+				Name:  log_utils.LogLevelFlag,
+				Value: log_utils.LogLevelDefaultValue,
+				Usage: log_utils.LogLevelFlagUsage,
+			},
+		},
+	}
+	replayCommand.UsageText = replayCommand.Name + " --har <file> [--upstream <host:port>]"
+
 	versionCommand := cli.Command{
 		Name:   "version",
 		Usage:  "APIClarity Version Details",
@@ -79,6 +172,7 @@ func main() {
 
 	app.Commands = []cli.Command{
 		runCommand,
+		replayCommand,
 		versionCommand,
 	}
 
@@ -19,6 +19,7 @@ import (
 	"context"
 	"fmt"
 	"mime"
+	"net/http"
 	"net/url"
 	"os"
 	"os/signal"
@@ -42,6 +43,7 @@ import (
 	"github.com/openclarity/apiclarity/backend/pkg/k8smonitor"
 	"github.com/openclarity/apiclarity/backend/pkg/modules"
 	"github.com/openclarity/apiclarity/backend/pkg/rest"
+	"github.com/openclarity/apiclarity/backend/pkg/risk"
 	"github.com/openclarity/apiclarity/backend/pkg/traces"
 	pluginsmodels "github.com/openclarity/apiclarity/plugins/api/server/models"
 	_spec "github.com/openclarity/speculator/pkg/spec"
@@ -58,9 +60,12 @@ type Backend struct {
 	apiInventoryLock    sync.RWMutex
 	dbHandler           _database.Database
 	modules             modules.Module
+	traceMetrics        *traces.Metrics
+	traceArchive        _database.TraceArchiveConfig
+	riskScorer          *risk.Scorer
 }
 
-func CreateBackend(config *_config.Config, monitor *k8smonitor.Monitor, speculator *_speculator.Speculator, dbHandler *_database.Handler, modules modules.Module) *Backend {
+func CreateBackend(config *_config.Config, monitor *k8smonitor.Monitor, speculator *_speculator.Speculator, dbHandler *_database.Handler, modules modules.Module, traceMetrics *traces.Metrics, riskScorer *risk.Scorer) *Backend {
 	return &Backend{
 		speculator:          speculator,
 		stateBackupInterval: time.Second * time.Duration(config.StateBackupIntervalSec),
@@ -68,6 +73,30 @@ func CreateBackend(config *_config.Config, monitor *k8smonitor.Monitor, speculat
 		monitor:             monitor,
 		dbHandler:           dbHandler,
 		modules:             modules,
+		traceMetrics:        traceMetrics,
+		traceArchive:        createTraceArchiveConfig(config),
+		riskScorer:          riskScorer,
+	}
+}
+
+func createTracesQueueConfig(config *_config.Config) traces.QueueConfig {
+	return traces.QueueConfig{
+		Size:               config.TracesQueueSize,
+		Workers:            config.TracesQueueWorkers,
+		FullPolicy:         traces.QueueFullPolicy(config.TracesQueueFullPolicy),
+		BlockTimeout:       time.Duration(config.TracesQueueBlockTimeoutMsec) * time.Millisecond,
+		MetricsLogInterval: time.Duration(config.TracesMetricsLogIntervalSec) * time.Second,
+		DrainTimeout:       time.Duration(config.TracesQueueDrainTimeoutSec) * time.Second,
+	}
+}
+
+func createTracesTLSOptions(config *_config.Config) *traces.TLSOptions {
+	if !config.EnableTLS {
+		return nil
+	}
+	return &traces.TLSOptions{
+		CertFile: config.TLSServerCertFilePath,
+		KeyFile:  config.TLSServerKeyFilePath,
 	}
 }
 
@@ -83,6 +112,25 @@ func createDatabaseConfig(config *_config.Config) *_database.DBConfig {
 	}
 }
 
+func createRetentionConfig(config *_config.Config) _database.RetentionConfig {
+	return _database.RetentionConfig{
+		MaxAge:          time.Duration(config.DataRetentionMaxAgeHours) * time.Hour,
+		MaxEventsPerAPI: config.DataRetentionMaxEventsPerAPI,
+		KeepOnlyDiffs:   config.DataRetentionKeepOnlyDiffs,
+		BatchSize:       config.DataRetentionBatchSize,
+		TraceMaxAge:     time.Duration(config.DataRetentionTraceMaxAgeHours) * time.Hour,
+		Interval:        time.Duration(config.DataRetentionIntervalSec) * time.Second,
+	}
+}
+
+func createTraceArchiveConfig(config *_config.Config) _database.TraceArchiveConfig {
+	return _database.TraceArchiveConfig{
+		Enabled:         config.TraceArchiveEnabled,
+		MaxBodySize:     config.TraceArchiveMaxBodySize,
+		RedactedHeaders: config.TraceArchiveRedactedHeaders,
+	}
+}
+
 const defaultChanSize = 100
 
 func Run() {
@@ -106,13 +154,16 @@ func Run() {
 	dbConfig := createDatabaseConfig(config)
 	dbHandler := _database.Init(dbConfig)
 	dbHandler.StartReviewTableCleaner(globalCtx, time.Duration(config.DatabaseCleanerIntervalSec)*time.Second)
+	dbHandler.StartEventRollups(globalCtx, time.Duration(config.EventRollupIntervalSec)*time.Second)
+	retention := _database.NewRetention(dbHandler, createRetentionConfig(config))
+	retention.Start(globalCtx)
 	var clientset kubernetes.Interface
 	if config.K8sLocal {
 		clientset, err = k8smonitor.CreateLocalK8sClientset()
 		if err != nil {
 			log.Fatalf("failed to create K8s clientset: %v", err)
 		}
-	} else if !viper.GetBool(_database.FakeTracesEnvVar) && !viper.GetBool(_database.FakeDataEnvVar) {
+	} else if !viper.GetBool(_database.FakeTracesEnvVar) && !viper.GetBool(_database.FakeDataEnvVar) && len(config.ReplayHARFiles) == 0 {
 		clientset, err = k8smonitor.CreateK8sClientset()
 		if err != nil {
 			log.Fatalf("failed to create K8s clientset: %v", err)
@@ -121,7 +172,7 @@ func Run() {
 
 	var monitor *k8smonitor.Monitor
 	var samplingManager *manager.Manager
-	if !viper.GetBool(_config.NoMonitorEnvVar) && !viper.GetBool(_database.FakeTracesEnvVar) && !viper.GetBool(_database.FakeDataEnvVar) {
+	if !viper.GetBool(_config.NoMonitorEnvVar) && !viper.GetBool(_database.FakeTracesEnvVar) && !viper.GetBool(_database.FakeDataEnvVar) && len(config.ReplayHARFiles) == 0 {
 		monitor, err = k8smonitor.CreateMonitor(clientset)
 		if err != nil {
 			log.Errorf("Failed to create a monitor: %v", err)
@@ -157,22 +208,58 @@ func Run() {
 	}
 
 	module := modules.New(globalCtx, dbHandler, clientset)
-	backend := CreateBackend(config, monitor, speculator, dbHandler, module)
-
-	restServer, err := rest.CreateRESTServer(config.BackendRestPort, speculator, dbHandler, module)
+	traceMetrics := traces.NewMetrics()
+	riskScorer := risk.NewScorer(dbHandler, modules.GetFindingsDescribers(module), time.Duration(config.RiskScoreIntervalSec)*time.Second)
+	riskScorer.Start(globalCtx)
+	modules.AddFindingsListener(module, riskScorer.FindingsChanged)
+	retention.AddPruneListener(riskScorer.FindingsChanged)
+	backend := CreateBackend(config, monitor, speculator, dbHandler, module, traceMetrics, riskScorer)
+
+	restServer, err := rest.CreateRESTServer(config.BackendRestPort, speculator, dbHandler, module, retention)
 	if err != nil {
 		log.Fatalf("Failed to create REST server: %v", err)
 	}
 	restServer.Start(errChan)
 	defer restServer.Stop()
 
-	tracesServer, err := traces.CreateHTTPTracesServer(config.HTTPTracesPort, backend.handleHTTPTrace)
+	tracesQueue, err := traces.NewTraceQueue(createTracesQueueConfig(config), backend.handleHTTPTrace, traceMetrics)
+	if err != nil {
+		log.Fatalf("Failed to create traces queue: %v", err)
+	}
+	// the queue gets its own context so that it is drained after the trace servers stopped
+	// accepting traces, and before the global context is canceled
+	queueCtx, queueCancel := context.WithCancel(globalCtx)
+	tracesQueue.Start(queueCtx)
+	defer func() {
+		queueCancel()
+		tracesQueue.Wait()
+	}()
+	// the health server serves the default mux
+	http.Handle("/metrics/traces", traceMetrics)
+
+	tracesTLSOptions := createTracesTLSOptions(config)
+	tracesServer, err := traces.CreateHTTPTracesServer(config.HTTPTracesPort, tracesQueue.Enqueue, tracesTLSOptions)
 	if err != nil {
 		log.Fatalf("Failed to create trace server: %v", err)
 	}
 	tracesServer.Start(errChan)
 	defer tracesServer.Stop()
 
+	grpcTracesServer, err := traces.CreateGRPCTracesServer(config.GRPCTracesPort, tracesQueue.Enqueue, tracesTLSOptions)
+	if err != nil {
+		log.Fatalf("Failed to create GRPC trace server: %v", err)
+	}
+	if err := grpcTracesServer.Start(errChan); err != nil {
+		log.Fatalf("Failed to start GRPC trace server: %v", err)
+	}
+	defer grpcTracesServer.Stop()
+
+	if config.OTLPTracesEnabled {
+		otlpTracesServer := traces.CreateOTLPTracesServer(config.OTLPHTTPTracesPort, tracesQueue.Enqueue)
+		otlpTracesServer.Start(errChan)
+		defer otlpTracesServer.Stop()
+	}
+
 	backend.startStateBackup(globalCtx)
 
 	healthServer.SetIsReady(true)
@@ -182,6 +269,10 @@ func Run() {
 		go backend.startSendingFakeTraces()
 	}
 
+	if len(config.ReplayHARFiles) > 0 {
+		go backend.replayHARFiles(globalCtx, config.ReplayHARFiles)
+	}
+
 	// Wait for deactivation
 	sig := make(chan os.Signal, 1)
 	signal.Notify(sig, syscall.SIGHUP, syscall.SIGINT, syscall.SIGTERM, syscall.SIGQUIT)
@@ -258,8 +349,9 @@ func (b *Backend) handleHTTPTrace(ctx context.Context, trace *pluginsmodels.Tele
 
 	// Initialize API info
 	apiInfo := _database.APIInfo{
-		Name: telemetry.Request.Host,
-		Port: int64(destPort),
+		Name:                 telemetry.Request.Host,
+		Port:                 int64(destPort),
+		DestinationNamespace: telemetry.DestinationNamespace,
 	}
 
 	// Set API Info type
@@ -269,19 +361,25 @@ func (b *Backend) handleHTTPTrace(ctx context.Context, trace *pluginsmodels.Tele
 		apiInfo.Type = models.APITypeEXTERNAL
 	}
 
+	// the time spent in the database, the inventory and the event, is observed once per trace
+	var dbLatency time.Duration
+
 	isNonAPI := isNonAPI(telemetry)
 	// Don't link non APIs to an API in the inventory
 	if !isNonAPI {
 		// lock the API inventory to avoid creating API entries twice on trace handling races
+		dbStart := time.Now()
 		b.apiInventoryLock.Lock()
 		if err := b.dbHandler.APIInventoryTable().FirstOrCreate(&apiInfo); err != nil {
 			b.apiInventoryLock.Unlock()
 			return fmt.Errorf("failed to get or create API info: %v", err)
 		}
 		b.apiInventoryLock.Unlock()
+		dbLatency += time.Since(dbStart)
 		log.Infof("API Info in DB: %+v", apiInfo)
 
 		// Handle trace telemetry by Speculator
+		speculatorStart := time.Now()
 		specKey := _speculator.GetSpecKey(telemetry.Request.Host, destInfo.Port)
 		if b.speculator.HasProvidedSpec(specKey) {
 			providedDiff, err = b.speculator.DiffTelemetry(telemetry, _spec.DiffSourceProvided)
@@ -300,6 +398,7 @@ func (b *Backend) handleHTTPTrace(ctx context.Context, trace *pluginsmodels.Tele
 				return fmt.Errorf("failed to learn telemetry: %v", err)
 			}
 		}
+		b.traceMetrics.ObserveSince(traces.StageSpeculator, speculatorStart)
 	}
 
 	// Update API event in DB
@@ -360,13 +459,38 @@ func (b *Backend) handleHTTPTrace(ctx context.Context, trace *pluginsmodels.Tele
 
 	event.SpecDiffType = getHighestPrioritySpecDiffType(providedDiffType, reconstructedDiffType)
 
+	unauthenticated := !risk.HasCredentials(trace.Request.Common.Headers)
+	event.Unauthenticated = &unauthenticated
+
+	dbStart := time.Now()
 	b.dbHandler.APIEventsTable().CreateAPIEvent(event)
+	if b.traceArchive.Enabled && event.ID != 0 {
+		b.archiveTrace(ctx, event, trace)
+	}
+	b.traceMetrics.ObserveLatency(traces.StageDatabase, dbLatency+time.Since(dbStart))
 
+	modulesStart := time.Now()
 	b.modules.EventNotify(ctx, &modules.Event{APIEvent: event, Telemetry: trace})
+	b.traceMetrics.ObserveSince(traces.StageModules, modulesStart)
+
+	b.riskScorer.EventNotify(event)
 
 	return nil
 }
 
+// archiveTrace stores the redacted raw trace of the event, so that it can be replayed to the modules.
+// Failing to archive a trace doesn't fail the handling of the trace.
+func (b *Backend) archiveTrace(ctx context.Context, event *_database.APIEvent, trace *pluginsmodels.Telemetry) {
+	eventTrace, err := _database.NewAPIEventTrace(event, trace, b.traceArchive)
+	if err != nil {
+		log.Errorf("Failed to create trace of event %v: %v", event.ID, err)
+		return
+	}
+	if err := b.dbHandler.APIEventTracesTable().Create(ctx, eventTrace); err != nil {
+		log.Errorf("Failed to archive trace of event %v: %v", event.ID, err)
+	}
+}
+
 func convertAPIDiffType(diffType _spec.DiffType) models.DiffType {
 	switch diffType {
 	case _spec.DiffTypeNoDiff:
@@ -0,0 +1,87 @@
+// Copyright © 2022 Cisco Systems, Inc. and its affiliates.
+// All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backend
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	_config "github.com/openclarity/apiclarity/backend/pkg/config"
+	_database "github.com/openclarity/apiclarity/backend/pkg/database"
+	"github.com/openclarity/apiclarity/backend/pkg/modules"
+	"github.com/openclarity/apiclarity/backend/pkg/replay"
+	"github.com/openclarity/apiclarity/backend/pkg/risk"
+	"github.com/openclarity/apiclarity/backend/pkg/traces"
+	_speculator "github.com/openclarity/speculator/pkg/speculator"
+)
+
+// replayHARFiles replays the captured traffic directly into the trace handling, bypassing the traces queue
+// so that the order of the HAR entries is kept.
+func (b *Backend) replayHARFiles(ctx context.Context, fileNames []string) *replay.Result {
+	total := &replay.Result{}
+	for _, fileName := range fileNames {
+		telemetries, err := replay.LoadHARFile(fileName)
+		if err != nil {
+			log.Errorf("Failed to load HAR file: %v", err)
+			total.Failed++
+			continue
+		}
+		result := replay.Replay(ctx, telemetries, b.handleHTTPTrace)
+		log.Infof("HAR file %v was replayed: %v", fileName, result)
+		total.Total += result.Total
+		total.Succeeded += result.Succeeded
+		total.Failed += result.Failed
+	}
+	return total
+}
+
+// Replay replays the HAR files into an in-process backend, without serving anything, and returns once they are
+// handled. A file that can't be loaded counts as a failure. The reconstructed specs are saved in the speculator
+// state, and the risk scores are updated.
+func Replay(fileNames []string) (*replay.Result, error) {
+	config, err := _config.LoadConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	dbHandler := _database.Init(createDatabaseConfig(config))
+	speculator, err := _speculator.DecodeState(config.StateBackupFileName, config.SpeculatorConfig)
+	if err != nil {
+		log.Infof("No speculator state to decode, creating new: %v", err)
+		speculator = _speculator.CreateSpeculator(config.SpeculatorConfig)
+	}
+	module := modules.New(ctx, dbHandler, nil)
+	riskScorer := risk.NewScorer(dbHandler, modules.GetFindingsDescribers(module), time.Duration(config.RiskScoreIntervalSec)*time.Second)
+	modules.AddFindingsListener(module, riskScorer.FindingsChanged)
+	backend := CreateBackend(config, nil, speculator, dbHandler, module, traces.NewMetrics(), riskScorer)
+
+	result := backend.replayHARFiles(ctx, fileNames)
+
+	if err := speculator.EncodeState(config.StateBackupFileName); err != nil {
+		log.Errorf("Failed to encode state: %v", err)
+	}
+	if err := riskScorer.Update(ctx); err != nil {
+		log.Errorf("Failed to update the risk scores: %v", err)
+	}
+
+	return result, nil
+}
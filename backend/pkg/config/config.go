@@ -29,14 +29,41 @@ const (
 	BackendRestPort              = "BACKEND_REST_PORT"
 	TraceSamplingEnabled         = "TRACE_SAMPLING_ENABLED"
 	HTTPTracesPort               = "HTTP_TRACES_PORT"
+	GRPCTracesPort               = "GRPC_TRACES_PORT"
+	OTLPTracesEnabled            = "OTLP_TRACES_ENABLED"
+	OTLPHTTPTracesPort           = "OTLP_HTTP_TRACES_PORT"
+	EnableTLS                    = "ENABLE_TLS"
+	TLSServerCertFilePath        = "TLS_SERVER_CERT_FILE_PATH"
+	TLSServerKeyFilePath         = "TLS_SERVER_KEY_FILE_PATH"
 	HTTPTraceSamplingManagerPort = "HTTP_TRACE_SAMPLING_MANAGER_PORT"
 	GRPCTraceSamplingManagerPort = "GRPC_TRACE_SAMPLING_MANAGER_PORT"
 	HealthCheckAddress           = "HEALTH_CHECK_ADDRESS"
 	StateBackupIntervalSec       = "STATE_BACKUP_INTERVAL_SEC"
 	DatabaseCleanerIntervalSec   = "DATABASE_CLEANER_INTERVAL_SEC"
+	EventRollupIntervalSec       = "EVENT_ROLLUP_INTERVAL_SEC"
+	RiskScoreIntervalSec         = "RISK_SCORE_INTERVAL_SEC"
 	StateBackupFileName          = "STATE_BACKUP_FILE_NAME"
 	NoMonitorEnvVar              = "NO_K8S_MONITOR"
 	K8sLocalEnvVar               = "K8S_LOCAL"
+	ReplayHARFiles               = "REPLAY_HAR_FILES"
+
+	TracesQueueSize             = "TRACES_QUEUE_SIZE"
+	TracesQueueWorkers          = "TRACES_QUEUE_WORKERS"
+	TracesQueueFullPolicy       = "TRACES_QUEUE_FULL_POLICY"
+	TracesQueueBlockTimeoutMsec = "TRACES_QUEUE_BLOCK_TIMEOUT_MSEC"
+	TracesQueueDrainTimeoutSec  = "TRACES_QUEUE_DRAIN_TIMEOUT_SEC"
+	TracesMetricsLogIntervalSec = "TRACES_METRICS_LOG_INTERVAL_SEC"
+
+	DataRetentionMaxAgeHours      = "DATA_RETENTION_MAX_AGE_HOURS"
+	DataRetentionMaxEventsPerAPI  = "DATA_RETENTION_MAX_EVENTS_PER_API"
+	DataRetentionKeepOnlyDiffs    = "DATA_RETENTION_KEEP_ONLY_DIFFS"
+	DataRetentionBatchSize        = "DATA_RETENTION_BATCH_SIZE"
+	DataRetentionIntervalSec      = "DATA_RETENTION_INTERVAL_SEC"
+	DataRetentionTraceMaxAgeHours = "DATA_RETENTION_TRACE_MAX_AGE_HOURS"
+
+	TraceArchiveEnabled         = "TRACE_ARCHIVE_ENABLED"
+	TraceArchiveMaxBodySize     = "TRACE_ARCHIVE_MAX_BODY_SIZE"
+	TraceArchiveRedactedHeaders = "TRACE_ARCHIVE_REDACTED_HEADERS"
 
 	DBNameEnvVar     = "DB_NAME"
 	DBUserEnvVar     = "DB_USER"
@@ -55,18 +82,51 @@ const (
 type Config struct {
 	BackendRestPort            int
 	HTTPTracesPort             int
+	GRPCTracesPort             int
+	OTLPTracesEnabled          bool
+	OTLPHTTPTracesPort         int
 	HealthCheckAddress         string
 	StateBackupIntervalSec     int
 	DatabaseCleanerIntervalSec int
+	EventRollupIntervalSec     int
+	RiskScoreIntervalSec       int
 	StateBackupFileName        string
 	SpeculatorConfig           _speculator.Config
 	K8sLocal                   bool
+	// ReplayHARFiles are replayed into the trace handling once the backend is ready.
+	ReplayHARFiles []string
 
 	// trace sampling config
 	HTTPTraceSamplingManagerPort int
 	GRPCTraceSamplingManagerPort int
 	TraceSamplingEnabled         bool
 
+	// traces servers tls config
+	EnableTLS             bool
+	TLSServerCertFilePath string
+	TLSServerKeyFilePath  string
+
+	// traces queue config
+	TracesQueueSize             int
+	TracesQueueWorkers          int
+	TracesQueueFullPolicy       string
+	TracesQueueBlockTimeoutMsec int
+	TracesQueueDrainTimeoutSec  int
+	TracesMetricsLogIntervalSec int
+
+	// data retention config
+	DataRetentionMaxAgeHours      int
+	DataRetentionMaxEventsPerAPI  int
+	DataRetentionKeepOnlyDiffs    bool
+	DataRetentionBatchSize        int
+	DataRetentionIntervalSec      int
+	DataRetentionTraceMaxAgeHours int
+
+	// raw traces archive config
+	TraceArchiveEnabled         bool
+	TraceArchiveMaxBodySize     int
+	TraceArchiveRedactedHeaders []string
+
 	// database config
 	DatabaseDriver   string
 	DBName           string
@@ -82,15 +142,43 @@ func LoadConfig() (*Config, error) {
 
 	config.BackendRestPort = viper.GetInt(BackendRestPort)
 	config.HTTPTracesPort = viper.GetInt(HTTPTracesPort)
+	config.GRPCTracesPort = viper.GetInt(GRPCTracesPort)
+	config.OTLPTracesEnabled = viper.GetBool(OTLPTracesEnabled)
+	config.OTLPHTTPTracesPort = viper.GetInt(OTLPHTTPTracesPort)
 	config.HTTPTraceSamplingManagerPort = viper.GetInt(HTTPTraceSamplingManagerPort)
 	config.GRPCTraceSamplingManagerPort = viper.GetInt(GRPCTraceSamplingManagerPort)
 	config.TraceSamplingEnabled = viper.GetBool(TraceSamplingEnabled)
 	config.HealthCheckAddress = viper.GetString(HealthCheckAddress)
 	config.StateBackupIntervalSec = viper.GetInt(StateBackupIntervalSec)
 	config.DatabaseCleanerIntervalSec = viper.GetInt(DatabaseCleanerIntervalSec)
+	config.EventRollupIntervalSec = viper.GetInt(EventRollupIntervalSec)
+	config.RiskScoreIntervalSec = viper.GetInt(RiskScoreIntervalSec)
 	config.StateBackupFileName = viper.GetString(StateBackupFileName)
 
+	config.EnableTLS = viper.GetBool(EnableTLS)
+	config.TLSServerCertFilePath = viper.GetString(TLSServerCertFilePath)
+	config.TLSServerKeyFilePath = viper.GetString(TLSServerKeyFilePath)
+
+	config.TracesQueueSize = viper.GetInt(TracesQueueSize)
+	config.TracesQueueWorkers = viper.GetInt(TracesQueueWorkers)
+	config.TracesQueueFullPolicy = viper.GetString(TracesQueueFullPolicy)
+	config.TracesQueueBlockTimeoutMsec = viper.GetInt(TracesQueueBlockTimeoutMsec)
+	config.TracesQueueDrainTimeoutSec = viper.GetInt(TracesQueueDrainTimeoutSec)
+	config.TracesMetricsLogIntervalSec = viper.GetInt(TracesMetricsLogIntervalSec)
+
+	config.DataRetentionMaxAgeHours = viper.GetInt(DataRetentionMaxAgeHours)
+	config.DataRetentionMaxEventsPerAPI = viper.GetInt(DataRetentionMaxEventsPerAPI)
+	config.DataRetentionKeepOnlyDiffs = viper.GetBool(DataRetentionKeepOnlyDiffs)
+	config.DataRetentionBatchSize = viper.GetInt(DataRetentionBatchSize)
+	config.DataRetentionIntervalSec = viper.GetInt(DataRetentionIntervalSec)
+	config.DataRetentionTraceMaxAgeHours = viper.GetInt(DataRetentionTraceMaxAgeHours)
+
+	config.TraceArchiveEnabled = viper.GetBool(TraceArchiveEnabled)
+	config.TraceArchiveMaxBodySize = viper.GetInt(TraceArchiveMaxBodySize)
+	config.TraceArchiveRedactedHeaders = viper.GetStringSlice(TraceArchiveRedactedHeaders)
+
 	config.K8sLocal = viper.GetBool(K8sLocalEnvVar)
+	config.ReplayHARFiles = viper.GetStringSlice(ReplayHARFiles)
 	config.DatabaseDriver = viper.GetString(DatabaseDriver)
 	config.DBPassword = viper.GetString(DBPasswordEnvVar)
 	config.DBUser = viper.GetString(DBUserEnvVar)
@@ -18,6 +18,7 @@ package database
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
@@ -28,6 +29,7 @@ const (
 	apiIDColumnName              = "api_id"
 	moduleNameColumnName         = "module_name"
 	annotationColumnName         = "annotation"
+	severityColumnName           = "severity"
 )
 
 type APIInfoAnnotation struct {
@@ -37,6 +39,11 @@ type APIInfoAnnotation struct {
 	Name       string `json:"name,omitempty" gorm:"column:name;uniqueIndex:api_ann_idx_model" faker:"-"`
 
 	Annotation []byte `json:"annotation,omitempty" gorm:"column:annotation" faker:"-"`
+	// Severity is the level of the severity of the annotation if it is a finding, -1 if it is not, nil if unknown.
+	Severity *int `json:"severity,omitempty" gorm:"column:severity" faker:"-"`
+
+	CreatedAt time.Time `json:"created_at,omitempty" gorm:"column:created_at" faker:"-"`
+	UpdatedAt time.Time `json:"updated_at,omitempty" gorm:"column:updated_at" faker:"-"`
 }
 
 type APIAnnotationsTable interface {
@@ -44,6 +51,10 @@ type APIAnnotationsTable interface {
 	Get(ctx context.Context, modName string, apiID uint, name string) (*APIInfoAnnotation, error)
 	List(ctx context.Context, modName string, apiID uint) ([]*APIInfoAnnotation, error)
 	Delete(ctx context.Context, modName string, apiID uint, names ...string) error
+	ListFindings(ctx context.Context, filters FindingsFilters, page FindingsPage) ([]*APIFinding, error)
+	// ListWithoutSeverity returns the annotations whose severity is unknown after the given ID, by ID.
+	ListWithoutSeverity(ctx context.Context, afterID uint, limit int) ([]*APIInfoAnnotation, error)
+	SetSeverity(ctx context.Context, id uint, severity int) error
 }
 
 type APIInfoAnnotationsTableHandler struct {
@@ -98,3 +109,19 @@ func (am *APIInfoAnnotationsTableHandler) Delete(ctx context.Context, modName st
 		Delete(&APIInfoAnnotation{}).
 		Error
 }
+
+func (am *APIInfoAnnotationsTableHandler) ListWithoutSeverity(ctx context.Context, afterID uint, limit int) ([]*APIInfoAnnotation, error) {
+	var annotations []*APIInfoAnnotation
+
+	if err := am.tx.WithContext(ctx).Where(fmt.Sprintf("%s IS NULL AND %s > ?", severityColumnName, idColumnName), afterID).
+		Order(idColumnName).Limit(limit).Find(&annotations).Error; err != nil {
+		return nil, err
+	}
+
+	return annotations, nil
+}
+
+func (am *APIInfoAnnotationsTableHandler) SetSeverity(ctx context.Context, id uint, severity int) error {
+	return am.tx.WithContext(ctx).Model(&APIInfoAnnotation{}).Where(idColumnName+" = ?", id).
+		Update(severityColumnName, severity).Error
+}
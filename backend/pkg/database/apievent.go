@@ -57,6 +57,7 @@ const (
 	apiInfoIDColumnName            = "api_info_id"
 	isNonAPIColumnName             = "is_non_api"
 	eventTypeColumnName            = "event_type"
+	unauthenticatedColumnName      = "unauthenticated"
 )
 
 const alertAnnotation = "ALERT"
@@ -86,6 +87,9 @@ type APIEvent struct {
 	SpecDiffType             models.DiffType   `json:"specDiffType,omitempty" gorm:"column:spec_diff_type" faker:"oneof: ZOMBIE_DIFF, SHADOW_DIFF, GENERAL_DIFF, NO_DIFF"`
 	HostSpecName             string            `json:"hostSpecName,omitempty" gorm:"column:host_spec_name" faker:"oneof: test.com, example.com, kaki.org"`
 	IsNonAPI                 bool              `json:"isNonApi,omitempty" gorm:"column:is_non_api" faker:"-"`
+	// Unauthenticated is true if the request had no credentials. It is nil for the events stored before it was
+	// recorded, which are not counted in the risk factors of their API.
+	Unauthenticated *bool `json:"unauthenticated,omitempty" gorm:"column:unauthenticated" faker:"-"`
 
 	// Spec diff info
 	// New reconstructed spec json string
@@ -108,6 +112,7 @@ type APIEvent struct {
 //go:generate $GOPATH/bin/mockgen -destination=./mock_apievent.go -package=database github.com/openclarity/apiclarity/backend/pkg/database APIEventsTable
 type APIEventsTable interface {
 	GetAPIEventsWithAnnotations(ctx context.Context, filters GetAPIEventsQuery) ([]*APIEvent, error)
+	CountAPIEvents(ctx context.Context, filters *APIEventsFilters) (int64, error)
 	GetAPIEventsAndTotal(params operations.GetAPIEventsParams) ([]APIEvent, int64, error)
 	GetAPIEvent(eventID uint32) (*APIEvent, error)
 	GetAPIEventReconstructedSpecDiff(eventID uint32) (*APIEvent, error)
@@ -126,6 +131,8 @@ type GetAPIEventsQuery struct {
 	Offset int
 	Limit  int
 	Order  string
+	// Ascending orders the events from the oldest, instead of the newest.
+	Ascending bool
 
 	APIEventsFilters          *APIEventsFilters
 	APIEventAnnotationFilters *APIEventAnnotationFilters
@@ -156,6 +163,7 @@ type HostGroup struct {
 }
 
 type APIEventsFilters struct {
+	APIInfoIDIs           *uint
 	DestinationIPIsNot    []string
 	DestinationIPIs       []string
 	DestinationPortIsNot  []string
@@ -250,10 +258,14 @@ func (a *APIEventsTableHandler) GetAPIEventsWithAnnotations(ctx context.Context,
 		}
 	}
 
+	direction := "DESC"
+	if query.Ascending {
+		direction = "ASC"
+	}
 	tx = tx.Preload("Annotations")
 	if err := tx.Offset(query.Offset).
 		Limit(query.Limit).
-		Order(fmt.Sprintf("%s DESC", query.Order)).
+		Order(fmt.Sprintf("%s %s", query.Order, direction)).
 		WithContext(ctx).
 		Find(&events).Error; err != nil {
 		return nil, err
@@ -261,6 +273,20 @@ func (a *APIEventsTableHandler) GetAPIEventsWithAnnotations(ctx context.Context,
 	return events, nil
 }
 
+func (a *APIEventsTableHandler) CountAPIEvents(ctx context.Context, filters *APIEventsFilters) (int64, error) {
+	var count int64
+
+	tx := a.tx
+	if filters != nil {
+		tx = a.setAPIEventsFilters(filters)
+	}
+	if err := tx.WithContext(ctx).Count(&count).Error; err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
 func (a *APIEventsTableHandler) GroupByAPIInfo() ([]HostGroup, error) {
 	var results []HostGroup
 
@@ -431,6 +457,9 @@ func (a *APIEventsTableHandler) setAPIEventsFilters(filters *APIEventsFilters) *
 	if filters.RequestStartTime != nil && filters.RequestEndTime != nil {
 		tx = tx.Where(CreateTimeFilter(requestTimeColumnName, *filters.RequestStartTime, *filters.RequestEndTime))
 	}
+	if filters.APIInfoIDIs != nil {
+		tx = tx.Where(fmt.Sprintf("%s.%s = ?", apiEventTableName, apiInfoIDColumnName), *filters.APIInfoIDIs)
+	}
 
 	// methods filter
 	tx = FilterIs(tx, methodColumnName, filters.MethodIs)
@@ -16,6 +16,7 @@
 package database
 
 import (
+	"context"
 	"fmt"
 
 	log "github.com/sirupsen/logrus"
@@ -39,6 +40,8 @@ const (
 	reconstructedSpecInfoColumnName = "reconstructed_spec_info"
 	providedSpecColumnName          = "provided_spec"
 	providedSpecInfoColumnName      = "provided_spec_info"
+	destinationNamespaceColumnName  = "destination_namespace"
+	riskScoreColumnName             = "risk_score"
 )
 
 type APIInfo struct {
@@ -54,6 +57,8 @@ type APIInfo struct {
 	ReconstructedSpecInfo string         `json:"reconstructedSpecInfo,omitempty" gorm:"column:reconstructed_spec_info" faker:"-"`
 	ProvidedSpec          string         `json:"providedSpec,omitempty" gorm:"column:provided_spec" faker:"-"`
 	ProvidedSpecInfo      string         `json:"providedSpecInfo,omitempty" gorm:"column:provided_spec_info" faker:"-"`
+	DestinationNamespace  string         `json:"destinationNamespace,omitempty" gorm:"column:destination_namespace" faker:"oneof: default, prod"`
+	RiskScore             int64          `json:"riskScore,omitempty" gorm:"column:risk_score" faker:"-"`
 
 	Annotations []*APIInfoAnnotation `gorm:"foreignKey:APIID;references:ID"`
 }
@@ -70,6 +75,8 @@ type APIInventoryTable interface {
 	First(dest *APIInfo, conds ...interface{}) error
 	FirstOrCreate(apiInfo *APIInfo) error
 	CreateAPIInfo(event *APIInfo)
+	GetAPIIDs(ctx context.Context) ([]uint, error)
+	SetRiskScore(ctx context.Context, apiID uint, score int64) error
 }
 
 type APIInventoryTableHandler struct {
@@ -87,6 +94,7 @@ func APIInfoFromDB(event *APIInfo) *models.APIInfo {
 		ID:                   uint32(event.ID),
 		Name:                 event.Name,
 		Port:                 event.Port,
+		RiskScore:            event.RiskScore,
 	}
 }
 
@@ -150,6 +158,10 @@ func (a *APIInventoryTableHandler) setAPIInventoryFilters(params operations.GetA
 	// has reconstructed spec diff filter
 	table = FilterIsBool(table, hasReconstructedSpecColumnName, params.HasReconstructedSpecIs)
 
+	// risk score filters
+	table = FilterGte(table, riskScoreColumnName, params.RiskScoreGte)
+	table = FilterLte(table, riskScoreColumnName, params.RiskScoreLte)
+
 	return table
 }
 
@@ -166,6 +178,37 @@ func (a *APIInventoryTableHandler) First(dest *APIInfo, conds ...interface{}) er
 	return a.tx.First(dest, conds).Error
 }
 
+// FirstOrCreate gets the API, or creates it. The namespace is not part of the identity of the API, it is set
+// when the API is created, or when it was not known yet.
 func (a *APIInventoryTableHandler) FirstOrCreate(apiInfo *APIInfo) error {
-	return a.tx.Where(*apiInfo).FirstOrCreate(apiInfo).Error
+	namespace := apiInfo.DestinationNamespace
+	query := *apiInfo
+	query.DestinationNamespace = ""
+	if err := a.tx.Where(query).Attrs(APIInfo{DestinationNamespace: namespace}).FirstOrCreate(apiInfo).Error; err != nil {
+		return err
+	}
+	if apiInfo.DestinationNamespace == "" && namespace != "" {
+		if err := a.tx.Where(idColumnName+" = ?", apiInfo.ID).Update(destinationNamespaceColumnName, namespace).Error; err != nil {
+			return err
+		}
+		apiInfo.DestinationNamespace = namespace
+	}
+	return nil
+}
+
+func (a *APIInventoryTableHandler) GetAPIIDs(ctx context.Context) ([]uint, error) {
+	var ids []uint
+	if err := a.tx.WithContext(ctx).Pluck(idColumnName, &ids).Error; err != nil {
+		return nil, fmt.Errorf("failed to get the API IDs: %v", err)
+	}
+
+	return ids, nil
+}
+
+func (a *APIInventoryTableHandler) SetRiskScore(ctx context.Context, apiID uint, score int64) error {
+	if err := a.tx.WithContext(ctx).Where(idColumnName+" = ?", apiID).Update(riskScoreColumnName, score).Error; err != nil {
+		return fmt.Errorf("failed to set the risk score of API %d: %v", apiID, err)
+	}
+
+	return nil
 }
@@ -0,0 +1,165 @@
+// Copyright © 2022 Cisco Systems, Inc. and its affiliates.
+// All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/openclarity/apiclarity/api/server/models"
+)
+
+const (
+	apiRiskFactorsTableName = "api_risk_factors"
+
+	successfulEventsColumnName      = "successful_events"
+	unauthenticatedEventsColumnName = "unauthenticated_events"
+	shadowDiffEventsColumnName      = "shadow_diff_events"
+	zombieDiffEventsColumnName      = "zombie_diff_events"
+)
+
+// APIRiskFactors are the counters of the events of an API that are used to compute its risk score. They are
+// accumulated as the events arrive, and decreased when the data retention deletes the events.
+type APIRiskFactors struct {
+	APIID uint `json:"apiId" gorm:"column:api_id;primaryKey;autoIncrement:false"`
+	// the events with a 2xx response
+	SuccessfulEvents int64 `json:"successfulEvents" gorm:"column:successful_events"`
+	// the events with a 2xx response to a request without credentials
+	UnauthenticatedEvents int64 `json:"unauthenticatedEvents" gorm:"column:unauthenticated_events"`
+	ShadowDiffEvents      int64 `json:"shadowDiffEvents" gorm:"column:shadow_diff_events"`
+	ZombieDiffEvents      int64 `json:"zombieDiffEvents" gorm:"column:zombie_diff_events"`
+
+	UpdatedAt time.Time `json:"updatedAt" gorm:"column:updated_at"`
+}
+
+func (APIRiskFactors) TableName() string {
+	return apiRiskFactorsTableName
+}
+
+// NewAPIRiskFactors returns the counters of the event. The events without a known authentication are not counted.
+func NewAPIRiskFactors(event *APIEvent) APIRiskFactors {
+	factors := APIRiskFactors{APIID: event.APIInfoID}
+	if event.Unauthenticated == nil {
+		return factors
+	}
+	if event.StatusCode >= 200 && event.StatusCode < 300 {
+		factors.SuccessfulEvents = 1
+		if *event.Unauthenticated {
+			factors.UnauthenticatedEvents = 1
+		}
+	}
+	switch event.SpecDiffType {
+	case models.DiffTypeSHADOWDIFF:
+		factors.ShadowDiffEvents = 1
+	case models.DiffTypeZOMBIEDIFF:
+		factors.ZombieDiffEvents = 1
+	}
+	return factors
+}
+
+type APIRiskFactorsTable interface {
+	// Add adds the counters to the risk factors of the APIs.
+	Add(ctx context.Context, factors ...APIRiskFactors) error
+	// Get returns the risk factors of the API, zero if none were added yet.
+	Get(ctx context.Context, apiID uint) (*APIRiskFactors, error)
+}
+
+type APIRiskFactorsTableHandler struct {
+	tx *gorm.DB
+}
+
+func (r *APIRiskFactorsTableHandler) Add(ctx context.Context, factors ...APIRiskFactors) error {
+	if len(factors) == 0 {
+		return nil
+	}
+
+	increment := func(column string) clause.Expr {
+		return gorm.Expr(fmt.Sprintf("%s + excluded.%s", FieldInTable(apiRiskFactorsTableName, column), column))
+	}
+	if err := r.tx.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: apiIDColumnName}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			successfulEventsColumnName:      increment(successfulEventsColumnName),
+			unauthenticatedEventsColumnName: increment(unauthenticatedEventsColumnName),
+			shadowDiffEventsColumnName:      increment(shadowDiffEventsColumnName),
+			zombieDiffEventsColumnName:      increment(zombieDiffEventsColumnName),
+			updatedAtColumnName:             gorm.Expr("excluded." + updatedAtColumnName),
+		}),
+	}).Create(&factors).Error; err != nil {
+		return fmt.Errorf("failed to add the risk factors: %v", err)
+	}
+
+	return nil
+}
+
+func (r *APIRiskFactorsTableHandler) Get(ctx context.Context, apiID uint) (*APIRiskFactors, error) {
+	factors := &APIRiskFactors{}
+	if err := r.tx.WithContext(ctx).Where(apiIDColumnName+" = ?", apiID).First(factors).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return &APIRiskFactors{APIID: apiID}, nil
+		}
+		return nil, fmt.Errorf("failed to get the risk factors of API %d: %v", apiID, err)
+	}
+
+	return factors, nil
+}
+
+// removeEventsFromRiskFactors decrements the risk factors of the APIs of the given events, before they are deleted,
+// and returns the APIs of the events.
+func removeEventsFromRiskFactors(tx *gorm.DB, ids []uint) ([]uint, error) {
+	var events []*APIEvent
+	if err := tx.Table(apiEventTableName).
+		Select(apiInfoIDColumnName, statusCodeColumnName, specDiffTypeColumnName, unauthenticatedColumnName).
+		Where(fmt.Sprintf("%s IN ?", idColumnName), ids).
+		Find(&events).Error; err != nil {
+		return nil, fmt.Errorf("failed to get API events: %v", err)
+	}
+
+	var apiIDs []uint
+	removed := map[uint]*APIRiskFactors{}
+	for _, event := range events {
+		if event.APIInfoID == 0 {
+			continue
+		}
+		factors, ok := removed[event.APIInfoID]
+		if !ok {
+			factors = &APIRiskFactors{APIID: event.APIInfoID}
+			removed[event.APIInfoID] = factors
+			apiIDs = append(apiIDs, event.APIInfoID)
+		}
+		eventFactors := NewAPIRiskFactors(event)
+		factors.SuccessfulEvents -= eventFactors.SuccessfulEvents
+		factors.UnauthenticatedEvents -= eventFactors.UnauthenticatedEvents
+		factors.ShadowDiffEvents -= eventFactors.ShadowDiffEvents
+		factors.ZombieDiffEvents -= eventFactors.ZombieDiffEvents
+	}
+
+	// the factors of events not added yet by the risk scorer are added to negative counters, which they offset
+	factors := make([]APIRiskFactors, 0, len(removed))
+	for _, apiID := range apiIDs {
+		factors = append(factors, *removed[apiID])
+	}
+	if err := (&APIRiskFactorsTableHandler{tx: tx}).Add(tx.Statement.Context, factors...); err != nil {
+		return nil, err
+	}
+
+	return apiIDs, nil
+}
@@ -0,0 +1,89 @@
+// Copyright © 2022 Cisco Systems, Inc. and its affiliates.
+// All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/openclarity/apiclarity/api/server/models"
+	"github.com/openclarity/apiclarity/api/server/restapi/operations"
+)
+
+func TestAPIRiskFactors(t *testing.T) {
+	handler := createTestHandler(t)
+	ctx := context.Background()
+
+	if factors, err := handler.APIRiskFactorsTable().Get(ctx, 1); err != nil || factors.APIID != 1 || factors.SuccessfulEvents != 0 {
+		t.Fatalf("Get() = %+v, %v", factors, err)
+	}
+
+	if err := handler.APIRiskFactorsTable().Add(ctx,
+		APIRiskFactors{APIID: 1, SuccessfulEvents: 2, UnauthenticatedEvents: 1},
+		APIRiskFactors{APIID: 2, ShadowDiffEvents: 1}); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	if err := handler.APIRiskFactorsTable().Add(ctx, APIRiskFactors{APIID: 1, SuccessfulEvents: 3, ZombieDiffEvents: 1}); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+
+	factors, err := handler.APIRiskFactorsTable().Get(ctx, 1)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if factors.SuccessfulEvents != 5 || factors.UnauthenticatedEvents != 1 || factors.ShadowDiffEvents != 0 || factors.ZombieDiffEvents != 1 {
+		t.Errorf("Get() = %+v", factors)
+	}
+}
+
+func TestAPIInventoryRiskScore(t *testing.T) {
+	handler := createTestHandler(t)
+	ctx := context.Background()
+
+	var apis []*APIInfo
+	for i, score := range []int64{40, 10, 70} {
+		api := &APIInfo{Type: models.APITypeINTERNAL, Name: "api", Port: int64(80 + i)}
+		if err := handler.APIInventoryTable().FirstOrCreate(api); err != nil {
+			t.Fatalf("failed to create API: %v", err)
+		}
+		if err := handler.APIInventoryTable().SetRiskScore(ctx, api.ID, score); err != nil {
+			t.Fatalf("SetRiskScore() error = %v", err)
+		}
+		apis = append(apis, api)
+	}
+
+	ids, err := handler.APIInventoryTable().GetAPIIDs(ctx)
+	if err != nil || len(ids) != len(apis) {
+		t.Fatalf("GetAPIIDs() = %v, %v", ids, err)
+	}
+
+	sortDir := "DESC"
+	gte := "20"
+	inventory, total, err := handler.APIInventoryTable().GetAPIInventoryAndTotal(operations.GetAPIInventoryParams{
+		Type:         string(models.APITypeINTERNAL),
+		Page:         1,
+		PageSize:     10,
+		SortKey:      string(models.APIInventorySortKeyRiskScore),
+		SortDir:      &sortDir,
+		RiskScoreGte: &gte,
+	})
+	if err != nil {
+		t.Fatalf("GetAPIInventoryAndTotal() error = %v", err)
+	}
+	if total != 2 || len(inventory) != 2 || inventory[0].ID != apis[2].ID || inventory[1].ID != apis[0].ID || inventory[0].RiskScore != 70 {
+		t.Errorf("GetAPIInventoryAndTotal() = %+v, %v", inventory, total)
+	}
+}
@@ -35,32 +35,41 @@ const (
 )
 
 func (a *APIEventsTableHandler) getAPIUsageDBSession(apiType APIUsageType) (db *gorm.DB, err error) {
+	return a.getAPIUsageTableSession(a.tx, apiEventTableName, apiType)
+}
+
+// getAPIUsageRollupsDBSession returns the equivalent of getAPIUsageDBSession on the events rollups.
+func (a *APIEventsTableHandler) getAPIUsageRollupsDBSession(apiType APIUsageType) (db *gorm.DB, err error) {
+	return a.getAPIUsageTableSession(a.tx.Session(&gorm.Session{NewDB: true}).Table(apiEventRollupsTableName), apiEventRollupsTableName, apiType)
+}
+
+func (a *APIEventsTableHandler) getAPIUsageTableSession(tx *gorm.DB, tableName string, apiType APIUsageType) (db *gorm.DB, err error) {
 	switch apiType {
 	case APIWithDiffs:
-		db = a.tx.Where(hasSpecDiffColumnName+" = ?", true).Session(&gorm.Session{})
+		db = tx.Where(hasSpecDiffColumnName+" = ?", true).Session(&gorm.Session{})
 	case ExistingAPI:
 		// REST api (not a non-api)
 		// no spec diff
 		// have reconstructed OR provided spec
-		db = a.tx.
-			Where(FieldInTable(apiEventTableName, isNonAPIColumnName)+" = ?", false).
-			Where(FieldInTable(apiEventTableName, hasSpecDiffColumnName)+" = ?", false).
+		db = tx.
+			Where(FieldInTable(tableName, isNonAPIColumnName)+" = ?", false).
+			Where(FieldInTable(tableName, hasSpecDiffColumnName)+" = ?", false).
 			Where(FieldInTable(apiInventoryTableName, hasReconstructedSpecColumnName)+" = ? OR "+
 				FieldInTable(apiInventoryTableName, hasProvidedSpecColumnName)+" = ?", true, true).
 			Joins("left join " + apiInventoryTableName + " on " + FieldInTable(apiInventoryTableName, idColumnName) +
-				" = " + FieldInTable(apiEventTableName, apiInfoIDColumnName)).
+				" = " + FieldInTable(tableName, apiInfoIDColumnName)).
 			Session(&gorm.Session{})
 	case NewAPI:
 		// REST api (not a non-api)
 		// no spec diff
 		// no reconstructed AND no provided spec
-		db = a.tx.
-			Where(FieldInTable(apiEventTableName, isNonAPIColumnName)+" = ?", false).
-			Where(FieldInTable(apiEventTableName, hasSpecDiffColumnName)+" = ?", false).
+		db = tx.
+			Where(FieldInTable(tableName, isNonAPIColumnName)+" = ?", false).
+			Where(FieldInTable(tableName, hasSpecDiffColumnName)+" = ?", false).
 			Where(FieldInTable(apiInventoryTableName, hasReconstructedSpecColumnName)+" = ? AND "+
 				FieldInTable(apiInventoryTableName, hasProvidedSpecColumnName)+" = ?", false, false).
 			Joins("left join " + apiInventoryTableName + " on " + FieldInTable(apiInventoryTableName, idColumnName) +
-				" = " + FieldInTable(apiEventTableName, apiInfoIDColumnName)).
+				" = " + FieldInTable(tableName, apiInfoIDColumnName)).
 			Session(&gorm.Session{})
 	default:
 		return nil, fmt.Errorf("unknown API type: %v", apiType)
@@ -71,7 +80,6 @@ func (a *APIEventsTableHandler) getAPIUsageDBSession(apiType APIUsageType) (db *
 
 func (a *APIEventsTableHandler) GetDashboardAPIUsages(startTime, endTime time.Time, apiType APIUsageType) ([]*models.APIUsage, error) {
 	var apiUsages []*models.APIUsage
-	var count int64
 
 	diff := endTime.Sub(startTime)
 
@@ -81,18 +89,25 @@ func (a *APIEventsTableHandler) GetDashboardAPIUsages(startTime, endTime time.Ti
 	if err != nil {
 		return nil, fmt.Errorf("failed to get DB session: %v", err)
 	}
+	rollupsDB, err := a.getAPIUsageRollupsDBSession(apiType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rollups DB session: %v", err)
+	}
+	counter, err := a.newEventsCounter(db, rollupsDB)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create events counter: %v", err)
+	}
 
 	for i := 0; i < hitCountGranularity; i++ {
 		endTime := startTime.Add(timeInterval)
-		st := strfmt.DateTime(startTime)
-		et := strfmt.DateTime(endTime)
 
-		if err := db.Where(CreateTimeFilter(timeColumnName, st, et)).Count(&count).Error; err != nil {
+		count, err := counter.count(startTime, endTime)
+		if err != nil {
 			return nil, fmt.Errorf("failed to query DB: %v", err)
 		}
 
 		apiUsages = append(apiUsages, &models.APIUsage{
-			Time:       st,
+			Time:       strfmt.DateTime(startTime),
 			NumOfCalls: count,
 		})
 
@@ -115,19 +130,24 @@ func (a *APIEventsTableHandler) GetAPIUsages(params operations.GetAPIUsageHitCou
 	filters.EndTime = nil
 	db := a.setAPIEventsFilters(filters).
 		Session(&gorm.Session{})
+	rollupsDB := setRollupsFilters(a.tx.Session(&gorm.Session{NewDB: true}).Table(apiEventRollupsTableName), filters)
+	if rollupsDB != nil {
+		rollupsDB = rollupsDB.Session(&gorm.Session{})
+	}
+	counter, err := a.newEventsCounter(db, rollupsDB)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create events counter: %v", err)
+	}
 
 	for i := 0; i < hitCountGranularity; i++ {
-		var count int64
-		st := strfmt.DateTime(startTime)
-		et := strfmt.DateTime(startTime.Add(timeInterval))
-
-		if err := db.Where(CreateTimeFilter(timeColumnName, st, et)).Count(&count).Error; err != nil {
+		count, err := counter.count(startTime, startTime.Add(timeInterval))
+		if err != nil {
 			return nil, err
 		}
 
 		apiUsages = append(apiUsages, &models.HitCount{
 			Count: count,
-			Time:  st,
+			Time:  strfmt.DateTime(startTime),
 		})
 
 		startTime = startTime.Add(timeInterval)
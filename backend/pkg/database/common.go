@@ -82,6 +82,8 @@ func getSortKeyColumnName(key string) (string, error) {
 		return hasReconstructedSpecColumnName, nil
 	case models.APIInventorySortKeyHasProvidedSpec:
 		return hasProvidedSpecColumnName, nil
+	case models.APIInventorySortKeyRiskScore:
+		return riskScoreColumnName, nil
 	}
 
 	return "", fmt.Errorf("unknown sort key (%v)", key)
@@ -18,6 +18,7 @@ package database
 import (
 	"fmt"
 	"os"
+	"sync"
 
 	log "github.com/sirupsen/logrus"
 	"gorm.io/driver/postgres"
@@ -44,10 +45,16 @@ type Database interface {
 	ReviewTable() ReviewTable
 	APIEventsAnnotationsTable() APIEventAnnotationTable
 	APIInfoAnnotationsTable() APIAnnotationsTable
+	APIEventTracesTable() APIEventTracesTable
+	ModuleAnnotationsTable() ModuleAnnotationsTable
+	APIRiskFactorsTable() APIRiskFactorsTable
 }
 
 type Handler struct {
 	DB *gorm.DB
+
+	// rollupLock serializes the updates of the events rollups, by the rollups and by the deletion of the events.
+	rollupLock sync.Mutex
 }
 
 type DBConfig struct {
@@ -98,6 +105,24 @@ func (db *Handler) APIInfoAnnotationsTable() APIAnnotationsTable {
 	}
 }
 
+func (db *Handler) APIEventTracesTable() APIEventTracesTable {
+	return &APIEventTracesTableHandler{
+		tx: db.DB.Table(apiEventTracesTableName),
+	}
+}
+
+func (db *Handler) ModuleAnnotationsTable() ModuleAnnotationsTable {
+	return &ModuleAnnotationsTableHandler{
+		tx: db.DB.Table(moduleAnnotationsTableName),
+	}
+}
+
+func (db *Handler) APIRiskFactorsTable() APIRiskFactorsTable {
+	return &APIRiskFactorsTableHandler{
+		tx: db.DB.Table(apiRiskFactorsTableName),
+	}
+}
+
 func cleanLocalDataBase(databasePath string) {
 	if _, err := os.Stat(databasePath); !os.IsNotExist(err) {
 		log.Debug("deleting db...")
@@ -129,7 +154,12 @@ func initDataBase(config *DBConfig) *gorm.DB {
 		&APIInfo{},
 		&Review{},
 		&APIEventAnnotation{},
-		&APIInfoAnnotation{}); err != nil {
+		&APIInfoAnnotation{},
+		&APIEventRollup{},
+		&APIEventRollupState{},
+		&APIEventTrace{},
+		&ModuleAnnotation{},
+		&APIRiskFactors{}); err != nil {
 		log.Fatalf("Failed to run auto migration: %v", err)
 	}
 
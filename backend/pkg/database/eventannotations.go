@@ -17,6 +17,7 @@ package database
 
 import (
 	"context"
+	"fmt"
 
 	"gorm.io/gorm"
 )
@@ -35,12 +36,21 @@ type APIEventAnnotation struct {
 	EventID    uint   `json:"event_id,omitempty" gorm:"column:event_id;uniqueIndex:api_event_ann_idx_model" faker:"-"`
 	Name       string `json:"name,omitempty" gorm:"column:name;uniqueIndex:api_event_ann_idx_model" faker:"-"`
 	Annotation []byte `json:"annotation,omitempty" gorm:"column:annotation" faker:"-"`
+	// Severity is the level of the severity of the annotation if it is a finding, -1 if it is not, nil if unknown.
+	// It is stored to sort the findings in the database.
+	Severity *int `json:"severity,omitempty" gorm:"column:severity" faker:"-"`
 }
 
 type APIEventAnnotationTable interface {
 	Create(ctx context.Context, eas ...APIEventAnnotation) error
 	Get(ctx context.Context, modName string, eventID uint, name string) (*APIEventAnnotation, error)
 	List(ctx context.Context, modName string, eventID uint) ([]*APIEventAnnotation, error)
+	// Delete deletes the annotations with the given names, or all the annotations of the module if no name is given.
+	Delete(ctx context.Context, modName string, eventID uint, names ...string) error
+	ListFindingsGroups(ctx context.Context, filters FindingsFilters, page FindingsPage) ([]*EventFindingsGroup, error)
+	// ListWithoutSeverity returns the annotations whose severity is unknown after the given ID, by ID.
+	ListWithoutSeverity(ctx context.Context, afterID uint, limit int) ([]*APIEventAnnotation, error)
+	SetSeverity(ctx context.Context, id uint, severity int) error
 }
 
 type APIEventAnnotationTableHandler struct {
@@ -78,3 +88,28 @@ func (ea *APIEventAnnotationTableHandler) Get(ctx context.Context, modName strin
 
 	return annotation, nil
 }
+
+func (ea *APIEventAnnotationTableHandler) Delete(ctx context.Context, modName string, eventID uint, names ...string) error {
+	t := ea.tx.Where("module_name = ? AND event_id = ?", modName, eventID)
+	if len(names) > 0 {
+		t = t.Where("name IN ?", names)
+	}
+
+	return t.WithContext(ctx).Delete(&APIEventAnnotation{}).Error
+}
+
+func (ea *APIEventAnnotationTableHandler) ListWithoutSeverity(ctx context.Context, afterID uint, limit int) ([]*APIEventAnnotation, error) {
+	var annotations []*APIEventAnnotation
+
+	if err := ea.tx.WithContext(ctx).Where(fmt.Sprintf("%s IS NULL AND %s > ?", severityColumnName, idColumnName), afterID).
+		Order(idColumnName).Limit(limit).Find(&annotations).Error; err != nil {
+		return nil, err
+	}
+
+	return annotations, nil
+}
+
+func (ea *APIEventAnnotationTableHandler) SetSeverity(ctx context.Context, id uint, severity int) error {
+	return ea.tx.WithContext(ctx).Model(&APIEventAnnotation{}).Where(idColumnName+" = ?", id).
+		Update(severityColumnName, severity).Error
+}
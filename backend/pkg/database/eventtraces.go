@@ -0,0 +1,226 @@
+// Copyright © 2022 Cisco Systems, Inc. and its affiliates.
+// All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package database
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/go-openapi/strfmt"
+	"gorm.io/gorm"
+
+	"github.com/openclarity/apiclarity/backend/pkg/utils"
+	pluginsmodels "github.com/openclarity/apiclarity/plugins/api/server/models"
+)
+
+const (
+	apiEventTracesTableName = "api_event_traces"
+
+	// RedactedValue replaces the values of the redacted headers in the archived traces.
+	RedactedValue = "[REDACTED]"
+)
+
+// DefaultRedactedHeaders are the headers that carry credentials or sessions.
+var DefaultRedactedHeaders = append(append([]string{}, utils.CredentialsHeaders...), "set-cookie", "x-csrf-token")
+
+// TraceArchiveConfig is the configuration of the raw traces archive.
+type TraceArchiveConfig struct {
+	Enabled bool
+	// MaxBodySize is the maximum size of the archived request and response bodies, longer bodies are truncated.
+	MaxBodySize int
+	// RedactedHeaders are the headers whose values are not archived (case insensitive).
+	RedactedHeaders []string
+}
+
+// APIEventTrace is the redacted raw trace of an API event, so that the full telemetry can be replayed to the modules.
+type APIEventTrace struct {
+	ID        uint            `gorm:"primarykey" faker:"-"`
+	EventID   uint            `json:"event_id,omitempty" gorm:"column:event_id;uniqueIndex" faker:"-"`
+	Time      strfmt.DateTime `json:"time" gorm:"column:time;index" faker:"-"`
+	Size      int             `json:"size,omitempty" gorm:"column:size" faker:"-"`
+	Truncated bool            `json:"truncated,omitempty" gorm:"column:truncated" faker:"-"`
+	// Trace is the gzip compressed JSON of the telemetry.
+	Trace []byte `json:"trace,omitempty" gorm:"column:trace" faker:"-"`
+}
+
+type APIEventTracesTable interface {
+	Create(ctx context.Context, trace *APIEventTrace) error
+	Get(ctx context.Context, eventID uint) (*APIEventTrace, error)
+}
+
+type APIEventTracesTableHandler struct {
+	tx *gorm.DB
+}
+
+func (APIEventTrace) TableName() string {
+	return apiEventTracesTableName
+}
+
+func (t *APIEventTracesTableHandler) Create(ctx context.Context, trace *APIEventTrace) error {
+	return t.tx.WithContext(ctx).Create(trace).Error
+}
+
+func (t *APIEventTracesTableHandler) Get(ctx context.Context, eventID uint) (*APIEventTrace, error) {
+	trace := &APIEventTrace{}
+
+	if err := t.tx.WithContext(ctx).Where(fmt.Sprintf("%s = ?", eventIDColumnName), eventID).First(trace).Error; err != nil {
+		return nil, err
+	}
+
+	return trace, nil
+}
+
+// NewAPIEventTrace redacts, truncates and compresses the telemetry of the event according to the config.
+// The given telemetry is not modified.
+func NewAPIEventTrace(event *APIEvent, telemetry *pluginsmodels.Telemetry, config TraceArchiveConfig) (*APIEventTrace, error) {
+	// deep copy the telemetry, it is still used by the modules
+	raw, err := json.Marshal(telemetry)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal telemetry: %v", err)
+	}
+	redacted := &pluginsmodels.Telemetry{}
+	if err := json.Unmarshal(raw, redacted); err != nil {
+		return nil, fmt.Errorf("failed to copy telemetry: %v", err)
+	}
+
+	redactedHeaders := map[string]bool{}
+	for _, header := range config.RedactedHeaders {
+		redactedHeaders[strings.ToLower(header)] = true
+	}
+
+	truncated := false
+	if redacted.Request != nil && redacted.Request.Common != nil {
+		truncated = redactCommon(redacted.Request.Common, redactedHeaders, config.MaxBodySize) || truncated
+	}
+	if redacted.Response != nil && redacted.Response.Common != nil {
+		truncated = redactCommon(redacted.Response.Common, redactedHeaders, config.MaxBodySize) || truncated
+	}
+
+	raw, err = json.Marshal(redacted)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal redacted telemetry: %v", err)
+	}
+
+	compressed := &bytes.Buffer{}
+	gw := gzip.NewWriter(compressed)
+	if _, err := gw.Write(raw); err != nil {
+		return nil, fmt.Errorf("failed to compress telemetry: %v", err)
+	}
+	if err := gw.Close(); err != nil {
+		return nil, fmt.Errorf("failed to compress telemetry: %v", err)
+	}
+
+	return &APIEventTrace{
+		EventID:   event.ID,
+		Time:      event.Time,
+		Size:      len(raw),
+		Truncated: truncated,
+		Trace:     compressed.Bytes(),
+	}, nil
+}
+
+// Telemetry decompresses the archived telemetry.
+func (t *APIEventTrace) Telemetry() (*pluginsmodels.Telemetry, error) {
+	gr, err := gzip.NewReader(bytes.NewReader(t.Trace))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress trace: %v", err)
+	}
+	defer gr.Close()
+
+	raw, err := io.ReadAll(gr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress trace: %v", err)
+	}
+
+	telemetry := &pluginsmodels.Telemetry{}
+	if err := json.Unmarshal(raw, telemetry); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal trace: %v", err)
+	}
+
+	return telemetry, nil
+}
+
+// IsRedactedHeaderValue returns true if the value of the header was redacted by the archive, so that the credentials
+// it held are lost.
+func IsRedactedHeaderValue(key, value string) bool {
+	if value == RedactedValue {
+		return true
+	}
+	if !strings.EqualFold(key, "authorization") {
+		return false
+	}
+
+	switch {
+	case strings.HasPrefix(value, "Basic "):
+		decoded, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(value, "Basic "))
+		return err == nil && strings.HasSuffix(string(decoded), ":"+RedactedValue)
+	case strings.HasPrefix(value, "Bearer "):
+		parts := strings.Split(strings.TrimPrefix(value, "Bearer "), ".")
+		return len(parts) == 3 && parts[2] == "" // nolint:gomnd
+	default:
+		return false
+	}
+}
+
+// redactCommon redacts the headers and truncates the body, it returns true if the body was truncated.
+func redactCommon(common *pluginsmodels.Common, redactedHeaders map[string]bool, maxBodySize int) bool {
+	for _, header := range common.Headers {
+		if header != nil && redactedHeaders[strings.ToLower(header.Key)] {
+			header.Value = redactHeaderValue(header.Key, header.Value)
+		}
+	}
+
+	if maxBodySize > 0 && len(common.Body) > maxBodySize {
+		common.Body = common.Body[:maxBodySize]
+		common.TruncatedBody = true
+		return true
+	}
+
+	return false
+}
+
+// redactHeaderValue masks the credentials of the header, while keeping the identity of the authorization header
+// user (the basic auth user name and the JWT claims) so that the user based analysis can be replayed.
+func redactHeaderValue(key, value string) string {
+	if !strings.EqualFold(key, "authorization") {
+		return RedactedValue
+	}
+
+	switch {
+	case strings.HasPrefix(value, "Basic "):
+		decoded, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(value, "Basic "))
+		if err != nil {
+			return RedactedValue
+		}
+		user := strings.SplitN(string(decoded), ":", 2)[0] // nolint:gomnd
+		return "Basic " + base64.StdEncoding.EncodeToString([]byte(user+":"+RedactedValue))
+	case strings.HasPrefix(value, "Bearer "):
+		// a JWT without its signature can't be used as a credential
+		parts := strings.Split(strings.TrimPrefix(value, "Bearer "), ".")
+		if len(parts) != 3 { // nolint:gomnd
+			return RedactedValue
+		}
+		return "Bearer " + parts[0] + "." + parts[1] + "."
+	default:
+		return RedactedValue
+	}
+}
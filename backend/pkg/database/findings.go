@@ -0,0 +1,328 @@
+// Copyright © 2022 Cisco Systems, Inc. and its affiliates.
+// All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/go-openapi/strfmt"
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+
+	"github.com/openclarity/apiclarity/api/server/models"
+)
+
+const (
+	createdAtColumnName = "created_at"
+	updatedAtColumnName = "updated_at"
+)
+
+// FindingsFilters selects the annotations of the findings feed. The modules describe the annotations, the severity
+// is filtered by the caller.
+type FindingsFilters struct {
+	ModuleNameIs []string
+	NameIs       []string
+	APIInfoIDIs  []uint32
+	NamespaceIs  []string
+	// the findings last seen after the start time and first seen before the end time
+	StartTime *time.Time
+	EndTime   *time.Time
+}
+
+// EventFindingsGroup are the annotations with the same name of the events of an endpoint of an API.
+type EventFindingsGroup struct {
+	ModuleName  string
+	Name        string
+	APIInfoID   uint
+	Count       int64
+	FirstSeen   strfmt.DateTime
+	LastSeen    strfmt.DateTime
+	LastEventID uint
+	// the highest level of the severities of the annotations, -1 if unknown
+	Severity int
+
+	// the annotation, the method and the path of the last event, and the path of its spec operation if known
+	Annotation []byte            `gorm:"-"`
+	Method     models.HTTPMethod `gorm:"-"`
+	Path       string            `gorm:"-"`
+	SpecPath   string            `gorm:"-"`
+
+	APIName   string `gorm:"-"`
+	APIPort   int64  `gorm:"-"`
+	Namespace string `gorm:"-"`
+}
+
+// APIFinding is an annotation of an API.
+type APIFinding struct {
+	ModuleName string
+	Name       string
+	Annotation []byte
+	APIID      uint
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+	// the level of the severity of the annotation, -1 if unknown
+	Severity int
+
+	APIName   string `gorm:"-"`
+	APIPort   int64  `gorm:"-"`
+	Namespace string `gorm:"-"`
+}
+
+// findingsAPI is the API of the findings, with the paths of its specs by path ID.
+type findingsAPI struct {
+	ID                    uint
+	Name                  string
+	Port                  int64
+	DestinationNamespace  string
+	ProvidedSpecInfo      string
+	ReconstructedSpecInfo string
+
+	specPaths map[string]string `gorm:"-"`
+}
+
+// FindingsPage orders and pages the findings. The findings are ordered by the time they were last seen, or else
+// by the sort key and then by the time they were last seen. A zero limit returns all the findings.
+type FindingsPage struct {
+	SortKey models.FindingSortKey
+	Desc    bool
+	Offset  int
+	Limit   int
+}
+
+func (p FindingsPage) order(severityColumn, countColumn, firstSeenColumn, lastSeenColumn string) string {
+	dir := "ASC"
+	if p.Desc {
+		dir = "DESC"
+	}
+	lastSeen := lastSeenColumn + " DESC"
+	switch p.SortKey {
+	case models.FindingSortKeySeverity:
+		return fmt.Sprintf("%s %s, %s", severityColumn, dir, lastSeen)
+	case models.FindingSortKeyCount:
+		return fmt.Sprintf("%s %s, %s", countColumn, dir, lastSeen)
+	case models.FindingSortKeyFirstSeen:
+		return fmt.Sprintf("%s %s, %s", firstSeenColumn, dir, lastSeen)
+	case models.FindingSortKeyLastSeen:
+		return fmt.Sprintf("%s %s", lastSeenColumn, dir)
+	}
+	return lastSeen
+}
+
+func (p FindingsPage) apply(tx *gorm.DB) *gorm.DB {
+	if p.Limit > 0 {
+		tx = tx.Limit(p.Limit)
+	}
+	if p.Offset > 0 {
+		tx = tx.Offset(p.Offset)
+	}
+	return tx
+}
+
+// ListFindingsGroups groups the annotations with the same name of the events of an endpoint, the operation of
+// the spec or else the path, with the time of the first and the last events, and the annotation of the last event.
+func (ea *APIEventAnnotationTableHandler) ListFindingsGroups(ctx context.Context, filters FindingsFilters, page FindingsPage) ([]*EventFindingsGroup, error) {
+	var groups []*EventFindingsGroup
+
+	eventTime := FieldInTable(apiEventTableName, timeColumnName)
+	eventID := FieldInTable(apiEventTableName, idColumnName)
+	apiID := FieldInTable(apiEventTableName, apiInfoIDColumnName)
+	endpoint := fmt.Sprintf("CASE WHEN %[1]s <> '' THEN %[1]s WHEN %[2]s <> '' THEN %[2]s ELSE %[3]s END",
+		FieldInTable(apiEventTableName, providedPathIDColumnName), FieldInTable(apiEventTableName, reconstructedPathIDColumnName),
+		FieldInTable(apiEventTableName, pathColumnName))
+	tx := ea.tx.WithContext(ctx).
+		Select(fmt.Sprintf("%[1]s.%[2]s, %[1]s.%[3]s, %[4]s AS api_info_id, COUNT(*) AS count, "+
+			"MIN(%[5]s) AS first_seen, MAX(%[5]s) AS last_seen, MAX(%[6]s) AS last_event_id, MAX(COALESCE(%[1]s.%[7]s, -1)) AS severity",
+			eventAnnotationsTableName, moduleNameColumnName, nameColumnName, apiID, eventTime, eventID, severityColumnName)).
+		Joins(fmt.Sprintf("JOIN %s ON %s = %s", apiEventTableName, eventID, FieldInTable(eventAnnotationsTableName, eventIDColumnName))).
+		Group(fmt.Sprintf("%[1]s.%[2]s, %[1]s.%[3]s, %[4]s, %[5]s, %[6]s",
+			eventAnnotationsTableName, moduleNameColumnName, nameColumnName, apiID, FieldInTable(apiEventTableName, methodColumnName), endpoint)).
+		Order(page.order("severity", "count", "first_seen", "last_seen") + ", last_event_id DESC")
+
+	tx = setFindingsFilters(tx, eventAnnotationsTableName, apiID, filters)
+	// the time of the events is compared as stored, a strfmt.DateTime
+	if filters.StartTime != nil {
+		tx = tx.Where(eventTime+" >= ?", strfmt.DateTime(*filters.StartTime))
+	}
+	if filters.EndTime != nil {
+		tx = tx.Where(eventTime+" <= ?", strfmt.DateTime(*filters.EndTime))
+	}
+
+	if err := page.apply(tx).Scan(&groups).Error; err != nil {
+		return nil, fmt.Errorf("failed to list the findings of the events: %w", err)
+	}
+	if len(groups) == 0 {
+		return groups, nil
+	}
+
+	eventIDs := make([]uint, 0, len(groups))
+	apiIDs := make([]uint, 0, len(groups))
+	for _, g := range groups {
+		eventIDs = append(eventIDs, g.LastEventID)
+		apiIDs = append(apiIDs, g.APIInfoID)
+	}
+	var events []APIEvent
+	if err := ea.tx.Session(&gorm.Session{NewDB: true}).WithContext(ctx).Table(apiEventTableName).
+		Select(fmt.Sprintf("%s, %s, %s, %s, %s", idColumnName, methodColumnName, pathColumnName,
+			providedPathIDColumnName, reconstructedPathIDColumnName)).
+		Where(idColumnName+" IN ?", eventIDs).Find(&events).Error; err != nil {
+		return nil, fmt.Errorf("failed to get the last events of the findings: %w", err)
+	}
+	eventsByID := make(map[uint]APIEvent, len(events))
+	for _, e := range events {
+		eventsByID[e.ID] = e
+	}
+	var anns []APIEventAnnotation
+	if err := ea.tx.Session(&gorm.Session{NewDB: true}).WithContext(ctx).Table(eventAnnotationsTableName).
+		Where(eventIDColumnName+" IN ?", eventIDs).Find(&anns).Error; err != nil {
+		return nil, fmt.Errorf("failed to get the last annotations of the findings: %w", err)
+	}
+	type annKey struct {
+		eventID          uint
+		moduleName, name string
+	}
+	annsByKey := make(map[annKey][]byte, len(anns))
+	for _, ann := range anns {
+		annsByKey[annKey{eventID: ann.EventID, moduleName: ann.ModuleName, name: ann.Name}] = ann.Annotation
+	}
+	apis, err := getFindingsAPIs(ctx, ea.tx, apiIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, g := range groups {
+		e := eventsByID[g.LastEventID]
+		api := apis[g.APIInfoID]
+		g.Annotation = annsByKey[annKey{eventID: g.LastEventID, moduleName: g.ModuleName, name: g.Name}]
+		g.Method, g.Path, g.SpecPath = e.Method, e.Path, api.specPath(e)
+		g.APIName, g.APIPort, g.Namespace = api.Name, api.Port, api.DestinationNamespace
+	}
+
+	return groups, nil
+}
+
+// ListFindings returns the annotations of the APIs, their count is 1.
+func (am *APIInfoAnnotationsTableHandler) ListFindings(ctx context.Context, filters FindingsFilters, page FindingsPage) ([]*APIFinding, error) {
+	var findings []*APIFinding
+
+	apiID := FieldInTable(apiEventAnnotationsTableName, apiIDColumnName)
+	createdAt := FieldInTable(apiEventAnnotationsTableName, createdAtColumnName)
+	updatedAt := FieldInTable(apiEventAnnotationsTableName, updatedAtColumnName)
+	severity := fmt.Sprintf("COALESCE(%s, -1)", FieldInTable(apiEventAnnotationsTableName, severityColumnName))
+	tx := am.tx.WithContext(ctx).
+		Select(fmt.Sprintf("%[1]s.%[2]s, %[1]s.%[3]s, %[1]s.%[4]s, %[1]s.%[5]s, %[1]s.%[6]s, %[1]s.%[7]s, %[8]s AS severity",
+			apiEventAnnotationsTableName, moduleNameColumnName, nameColumnName, annotationColumnName, apiIDColumnName,
+			createdAtColumnName, updatedAtColumnName, severity)).
+		Order(fmt.Sprintf("%s, %s, %s, %s", page.order(severity, updatedAt, createdAt, updatedAt), apiID,
+			FieldInTable(apiEventAnnotationsTableName, moduleNameColumnName), FieldInTable(apiEventAnnotationsTableName, nameColumnName)))
+
+	tx = setFindingsFilters(tx, apiEventAnnotationsTableName, apiID, filters)
+	if filters.StartTime != nil {
+		tx = tx.Where(updatedAt+" >= ?", *filters.StartTime)
+	}
+	if filters.EndTime != nil {
+		tx = tx.Where(createdAt+" <= ?", *filters.EndTime)
+	}
+
+	if err := page.apply(tx).Scan(&findings).Error; err != nil {
+		return nil, fmt.Errorf("failed to list the findings of the APIs: %w", err)
+	}
+	if len(findings) == 0 {
+		return findings, nil
+	}
+
+	apiIDs := make([]uint, 0, len(findings))
+	for _, f := range findings {
+		apiIDs = append(apiIDs, f.APIID)
+	}
+	apis, err := getFindingsAPIs(ctx, am.tx, apiIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range findings {
+		api := apis[f.APIID]
+		f.APIName, f.APIPort, f.Namespace = api.Name, api.Port, api.DestinationNamespace
+	}
+
+	return findings, nil
+}
+
+func getFindingsAPIs(ctx context.Context, tx *gorm.DB, apiIDs []uint) (map[uint]*findingsAPI, error) {
+	var apis []*findingsAPI
+	if err := tx.Session(&gorm.Session{NewDB: true}).WithContext(ctx).Table(apiInventoryTableName).
+		Select(fmt.Sprintf("%s, %s, %s, %s, %s, %s", idColumnName, nameColumnName, portColumnName, destinationNamespaceColumnName,
+			providedSpecInfoColumnName, reconstructedSpecInfoColumnName)).
+		Where(idColumnName+" IN ?", apiIDs).Scan(&apis).Error; err != nil {
+		return nil, fmt.Errorf("failed to get the APIs of the findings: %w", err)
+	}
+	apisByID := make(map[uint]*findingsAPI, len(apis))
+	for _, api := range apis {
+		apisByID[api.ID] = api
+	}
+	return apisByID, nil
+}
+
+// specPath returns the path of the spec operation of the event, the provided spec is preferred.
+func (api *findingsAPI) specPath(event APIEvent) string {
+	if api == nil {
+		return ""
+	}
+	if api.specPaths == nil {
+		api.specPaths = map[string]string{}
+		// the provided paths are added last to override the reconstructed ones
+		for _, specInfo := range []string{api.ReconstructedSpecInfo, api.ProvidedSpecInfo} {
+			if specInfo == "" {
+				continue
+			}
+			info := &models.SpecInfo{}
+			if err := json.Unmarshal([]byte(specInfo), info); err != nil {
+				log.Errorf("Failed to unmarshal the spec info of API %d: %v", api.ID, err)
+				continue
+			}
+			for _, tag := range info.Tags {
+				for _, methodAndPath := range tag.MethodAndPathList {
+					api.specPaths[string(methodAndPath.PathID)] = methodAndPath.Path
+				}
+			}
+		}
+	}
+	if path, ok := api.specPaths[event.ProvidedPathID]; ok && event.ProvidedPathID != "" {
+		return path
+	}
+	if event.ReconstructedPathID != "" {
+		return api.specPaths[event.ReconstructedPathID]
+	}
+	return ""
+}
+
+func setFindingsFilters(tx *gorm.DB, annotationsTable string, apiID string, filters FindingsFilters) *gorm.DB {
+	if len(filters.ModuleNameIs) > 0 {
+		tx = tx.Where(FieldInTable(annotationsTable, moduleNameColumnName)+" IN ?", filters.ModuleNameIs)
+	}
+	if len(filters.NameIs) > 0 {
+		tx = tx.Where(FieldInTable(annotationsTable, nameColumnName)+" IN ?", filters.NameIs)
+	}
+	if len(filters.APIInfoIDIs) > 0 {
+		tx = tx.Where(apiID+" IN ?", filters.APIInfoIDIs)
+	}
+	if len(filters.NamespaceIs) > 0 {
+		tx = tx.Where(fmt.Sprintf("%s IN (SELECT %s FROM %s WHERE %s IN ?)", apiID, idColumnName, apiInventoryTableName,
+			destinationNamespaceColumnName), filters.NamespaceIs)
+	}
+
+	return tx
+}
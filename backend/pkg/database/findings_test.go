@@ -0,0 +1,149 @@
+// Copyright © 2022 Cisco Systems, Inc. and its affiliates.
+// All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-openapi/strfmt"
+
+	"github.com/openclarity/apiclarity/api/server/models"
+)
+
+func TestFindings(t *testing.T) {
+	handler := createTestHandler(t)
+	ctx := context.Background()
+
+	prod := &APIInfo{Name: "users", Port: 80, DestinationNamespace: "prod"}
+	if err := handler.APIInventoryTable().FirstOrCreate(prod); err != nil {
+		t.Fatalf("failed to create API: %v", err)
+	}
+	dev := &APIInfo{Name: "users", Port: 8080}
+	if err := handler.APIInventoryTable().FirstOrCreate(dev); err != nil {
+		t.Fatalf("failed to create API: %v", err)
+	}
+	// the namespace of an API created before it was known is set
+	devAgain := &APIInfo{Name: "users", Port: 8080, DestinationNamespace: "dev"}
+	if err := handler.APIInventoryTable().FirstOrCreate(devAgain); err != nil || devAgain.ID != dev.ID || devAgain.DestinationNamespace != "dev" {
+		t.Fatalf("FirstOrCreate() = %+v, %v", devAgain, err)
+	}
+
+	specInfo := `{"tags":[{"name":"users","methodAndPathList":[{"method":"GET","path":"/users/{id}","pathId":"9e8d3c3c-4b4a-4f21-9f1b-0d1e2c3b4a5f"}]}]}`
+	if err := handler.DB.Model(prod).Update(providedSpecInfoColumnName, specInfo).Error; err != nil {
+		t.Fatalf("failed to set the spec info: %v", err)
+	}
+
+	now := time.Now().UTC().Truncate(time.Second)
+	createEvent := func(api *APIInfo, age time.Duration, path string, anns ...string) *APIEvent {
+		event := &APIEvent{APIInfoID: api.ID, Time: strfmt.DateTime(now.Add(-age)), Method: "GET", Path: path}
+		if path != "/health" {
+			event.ProvidedPathID = "9e8d3c3c-4b4a-4f21-9f1b-0d1e2c3b4a5f"
+		}
+		if err := handler.DB.Create(event).Error; err != nil {
+			t.Fatalf("failed to create event: %v", err)
+		}
+		for _, name := range anns {
+			// the annotations differ from an event to another
+			ann := APIEventAnnotation{ModuleName: "mod", EventID: event.ID, Name: name, Annotation: []byte(path)}
+			if err := handler.APIEventsAnnotationsTable().Create(ctx, ann); err != nil {
+				t.Fatalf("failed to create event annotation: %v", err)
+			}
+		}
+		return event
+	}
+	createEvent(prod, 3*time.Hour, "/users/1", "WEAK_JWT")
+	createEvent(prod, 2*time.Hour, "/users/2", "WEAK_JWT", "NLID")
+	last := createEvent(prod, time.Hour, "/users/3", "WEAK_JWT")
+	createEvent(dev, time.Hour, "/users/4", "WEAK_JWT")
+	health := createEvent(prod, 30*time.Minute, "/health", "WEAK_JWT")
+
+	groups, err := handler.APIEventsAnnotationsTable().ListFindingsGroups(ctx, FindingsFilters{NameIs: []string{"WEAK_JWT"}, NamespaceIs: []string{"prod"}},
+		FindingsPage{SortKey: models.FindingSortKeyCount, Desc: true})
+	if err != nil {
+		t.Fatalf("ListFindingsGroups() error = %v", err)
+	}
+	// grouped by endpoint, the spec operation or else the path
+	if len(groups) != 2 || groups[1].LastEventID != health.ID || groups[1].Count != 1 || string(groups[1].Annotation) != "/health" {
+		t.Fatalf("ListFindingsGroups() = %+v", groups)
+	}
+	g := groups[0]
+	if g.Count != 3 || g.LastEventID != last.ID || string(g.Annotation) != "/users/3" || g.Path != "/users/3" || g.SpecPath != "/users/{id}" ||
+		g.APIName != "users" || g.APIPort != 80 || g.Namespace != "prod" ||
+		!time.Time(g.FirstSeen).Equal(now.Add(-3*time.Hour)) || !time.Time(g.LastSeen).Equal(now.Add(-time.Hour)) {
+		t.Errorf("ListFindingsGroups() = %+v", g)
+	}
+
+	// paged, by last seen time
+	groups, err = handler.APIEventsAnnotationsTable().ListFindingsGroups(ctx, FindingsFilters{NameIs: []string{"WEAK_JWT"}},
+		FindingsPage{SortKey: models.FindingSortKeyLastSeen, Desc: true, Offset: 1, Limit: 2})
+	if err != nil || len(groups) != 2 || groups[0].LastSeen != groups[1].LastSeen || groups[0].LastEventID < groups[1].LastEventID {
+		t.Fatalf("ListFindingsGroups(page) = %+v, %v", groups, err)
+	}
+
+	// by severity, the highest of the group, the unknown severities are the lowest
+	anns, err := handler.APIEventsAnnotationsTable().ListWithoutSeverity(ctx, 0, 100)
+	if err != nil || len(anns) != 6 {
+		t.Fatalf("ListWithoutSeverity() = %+v, %v", anns, err)
+	}
+	for _, ann := range anns {
+		if ann.EventID == health.ID {
+			if err := handler.APIEventsAnnotationsTable().SetSeverity(ctx, ann.ID, 3); err != nil {
+				t.Fatalf("SetSeverity() error = %v", err)
+			}
+		}
+	}
+	if anns, err := handler.APIEventsAnnotationsTable().ListWithoutSeverity(ctx, 0, 100); err != nil || len(anns) != 5 {
+		t.Fatalf("ListWithoutSeverity() = %+v, %v", anns, err)
+	}
+	groups, err = handler.APIEventsAnnotationsTable().ListFindingsGroups(ctx, FindingsFilters{NameIs: []string{"WEAK_JWT"}},
+		FindingsPage{SortKey: models.FindingSortKeySeverity, Desc: true})
+	if err != nil || len(groups) != 3 || groups[0].LastEventID != health.ID || groups[0].Severity != 3 || groups[1].Severity != -1 {
+		t.Fatalf("ListFindingsGroups(severity) = %+v, %v", groups, err)
+	}
+
+	start := now.Add(-90 * time.Minute)
+	end := now.Add(-45 * time.Minute)
+	groups, err = handler.APIEventsAnnotationsTable().ListFindingsGroups(ctx, FindingsFilters{StartTime: &start, EndTime: &end}, FindingsPage{})
+	if err != nil || len(groups) != 2 {
+		t.Fatalf("ListFindingsGroups(startTime) = %+v, %v", groups, err)
+	}
+	for _, g := range groups {
+		if g.Count != 1 || (g.APIInfoID == dev.ID && g.SpecPath != "") {
+			t.Errorf("ListFindingsGroups(startTime) = %+v", g)
+		}
+	}
+
+	if err := handler.APIInfoAnnotationsTable().UpdateOrCreate(ctx, APIInfoAnnotation{ModuleName: "mod", APIID: dev.ID, Name: "GUESSABLE_ID"}); err != nil {
+		t.Fatalf("failed to create API annotation: %v", err)
+	}
+	findings, err := handler.APIInfoAnnotationsTable().ListFindings(ctx, FindingsFilters{APIInfoIDIs: []uint32{uint32(dev.ID)}}, FindingsPage{})
+	if err != nil || len(findings) != 1 || findings[0].Namespace != "dev" || findings[0].CreatedAt.IsZero() || findings[0].Severity != -1 {
+		t.Fatalf("ListFindings() = %+v, %v", findings, err)
+	}
+	if findings, err := handler.APIInfoAnnotationsTable().ListFindings(ctx, FindingsFilters{ModuleNameIs: []string{"other"}}, FindingsPage{}); err != nil || len(findings) != 0 {
+		t.Errorf("ListFindings(other module) = %+v, %v", findings, err)
+	}
+	severity := 1
+	if err := handler.APIInfoAnnotationsTable().UpdateOrCreate(ctx, APIInfoAnnotation{ModuleName: "mod", APIID: prod.ID, Name: "GUESSABLE_ID", Severity: &severity}); err != nil {
+		t.Fatalf("failed to create API annotation: %v", err)
+	}
+	findings, err = handler.APIInfoAnnotationsTable().ListFindings(ctx, FindingsFilters{}, FindingsPage{SortKey: models.FindingSortKeySeverity, Desc: true})
+	if err != nil || len(findings) != 2 || findings[0].APIID != prod.ID || findings[0].Severity != 1 {
+		t.Errorf("ListFindings(severity) = %+v, %v", findings, err)
+	}
+}
@@ -38,6 +38,21 @@ func (m *MockAPIEventsTable) EXPECT() *MockAPIEventsTableMockRecorder {
 	return m.recorder
 }
 
+// CountAPIEvents mocks base method.
+func (m *MockAPIEventsTable) CountAPIEvents(arg0 context.Context, arg1 *APIEventsFilters) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountAPIEvents", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountAPIEvents indicates an expected call of CountAPIEvents.
+func (mr *MockAPIEventsTableMockRecorder) CountAPIEvents(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountAPIEvents", reflect.TypeOf((*MockAPIEventsTable)(nil).CountAPIEvents), arg0, arg1)
+}
+
 // CreateAPIEvent mocks base method.
 func (m *MockAPIEventsTable) CreateAPIEvent(arg0 *APIEvent) {
 	m.ctrl.T.Helper()
@@ -5,6 +5,7 @@
 package database
 
 import (
+	context "context"
 	reflect "reflect"
 
 	gomock "github.com/golang/mock/gomock"
@@ -123,6 +124,21 @@ func (mr *MockAPIInventoryTableMockRecorder) GetAPIID(arg0, arg1 interface{}) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAPIID", reflect.TypeOf((*MockAPIInventoryTable)(nil).GetAPIID), arg0, arg1)
 }
 
+// GetAPIIDs mocks base method.
+func (m *MockAPIInventoryTable) GetAPIIDs(arg0 context.Context) ([]uint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAPIIDs", arg0)
+	ret0, _ := ret[0].([]uint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAPIIDs indicates an expected call of GetAPIIDs.
+func (mr *MockAPIInventoryTableMockRecorder) GetAPIIDs(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAPIIDs", reflect.TypeOf((*MockAPIInventoryTable)(nil).GetAPIIDs), arg0)
+}
+
 // GetAPIInventoryAndTotal mocks base method.
 func (m *MockAPIInventoryTable) GetAPIInventoryAndTotal(arg0 operations.GetAPIInventoryParams) ([]APIInfo, int64, error) {
 	m.ctrl.T.Helper()
@@ -182,3 +198,17 @@ func (mr *MockAPIInventoryTableMockRecorder) PutAPISpec(arg0, arg1, arg2, arg3 i
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutAPISpec", reflect.TypeOf((*MockAPIInventoryTable)(nil).PutAPISpec), arg0, arg1, arg2, arg3)
 }
+
+// SetRiskScore mocks base method.
+func (m *MockAPIInventoryTable) SetRiskScore(arg0 context.Context, arg1 uint, arg2 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetRiskScore", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetRiskScore indicates an expected call of SetRiskScore.
+func (mr *MockAPIInventoryTableMockRecorder) SetRiskScore(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetRiskScore", reflect.TypeOf((*MockAPIInventoryTable)(nil).SetRiskScore), arg0, arg1, arg2)
+}
@@ -33,6 +33,20 @@ func (m *MockDatabase) EXPECT() *MockDatabaseMockRecorder {
 	return m.recorder
 }
 
+// APIEventTracesTable mocks base method.
+func (m *MockDatabase) APIEventTracesTable() APIEventTracesTable {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "APIEventTracesTable")
+	ret0, _ := ret[0].(APIEventTracesTable)
+	return ret0
+}
+
+// APIEventTracesTable indicates an expected call of APIEventTracesTable.
+func (mr *MockDatabaseMockRecorder) APIEventTracesTable() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "APIEventTracesTable", reflect.TypeOf((*MockDatabase)(nil).APIEventTracesTable))
+}
+
 // APIEventsAnnotationsTable mocks base method.
 func (m *MockDatabase) APIEventsAnnotationsTable() APIEventAnnotationTable {
 	m.ctrl.T.Helper()
@@ -89,6 +103,34 @@ func (mr *MockDatabaseMockRecorder) APIInventoryTable() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "APIInventoryTable", reflect.TypeOf((*MockDatabase)(nil).APIInventoryTable))
 }
 
+// APIRiskFactorsTable mocks base method.
+func (m *MockDatabase) APIRiskFactorsTable() APIRiskFactorsTable {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "APIRiskFactorsTable")
+	ret0, _ := ret[0].(APIRiskFactorsTable)
+	return ret0
+}
+
+// APIRiskFactorsTable indicates an expected call of APIRiskFactorsTable.
+func (mr *MockDatabaseMockRecorder) APIRiskFactorsTable() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "APIRiskFactorsTable", reflect.TypeOf((*MockDatabase)(nil).APIRiskFactorsTable))
+}
+
+// ModuleAnnotationsTable mocks base method.
+func (m *MockDatabase) ModuleAnnotationsTable() ModuleAnnotationsTable {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ModuleAnnotationsTable")
+	ret0, _ := ret[0].(ModuleAnnotationsTable)
+	return ret0
+}
+
+// ModuleAnnotationsTable indicates an expected call of ModuleAnnotationsTable.
+func (mr *MockDatabaseMockRecorder) ModuleAnnotationsTable() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ModuleAnnotationsTable", reflect.TypeOf((*MockDatabase)(nil).ModuleAnnotationsTable))
+}
+
 // ReviewTable mocks base method.
 func (m *MockDatabase) ReviewTable() ReviewTable {
 	m.ctrl.T.Helper()
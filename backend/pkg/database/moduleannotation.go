@@ -0,0 +1,92 @@
+// Copyright © 2022 Cisco Systems, Inc. and its affiliates.
+// All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	moduleAnnotationsTableName = "module_annotations"
+)
+
+// ModuleAnnotation is a module level annotation, not related to an API or an event (e.g. the module settings).
+type ModuleAnnotation struct {
+	ID         uint   `gorm:"primarykey" faker:"-"`
+	ModuleName string `json:"module_name,omitempty" gorm:"column:module_name;uniqueIndex:module_ann_idx_model" faker:"-"`
+	Name       string `json:"name,omitempty" gorm:"column:name;uniqueIndex:module_ann_idx_model" faker:"-"`
+
+	Annotation []byte `json:"annotation,omitempty" gorm:"column:annotation" faker:"-"`
+}
+
+type ModuleAnnotationsTable interface {
+	UpdateOrCreate(ctx context.Context, am ...ModuleAnnotation) error
+	Get(ctx context.Context, modName string, name string) (*ModuleAnnotation, error)
+	List(ctx context.Context, modName string) ([]*ModuleAnnotation, error)
+	Delete(ctx context.Context, modName string, names ...string) error
+}
+
+type ModuleAnnotationsTableHandler struct {
+	tx *gorm.DB
+}
+
+func (ModuleAnnotation) TableName() string {
+	return moduleAnnotationsTableName
+}
+
+func (am *ModuleAnnotationsTableHandler) UpdateOrCreate(ctx context.Context, annotations ...ModuleAnnotation) error {
+	return am.tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: moduleNameColumnName}, {Name: nameColumnName}},
+		UpdateAll: true,
+	}).WithContext(ctx).Create(&annotations).Error
+}
+
+func (am *ModuleAnnotationsTableHandler) Get(ctx context.Context, modName string, name string) (*ModuleAnnotation, error) {
+	var model ModuleAnnotation
+
+	if err := am.tx.Where(fmt.Sprintf("%s = ? AND %s = ?", moduleNameColumnName, nameColumnName), modName, name).
+		WithContext(ctx).
+		First(&model).
+		Error; err != nil {
+		return nil, err
+	}
+
+	return &model, nil
+}
+
+func (am *ModuleAnnotationsTableHandler) List(ctx context.Context, modName string) ([]*ModuleAnnotation, error) {
+	var annotations []*ModuleAnnotation
+
+	if err := am.tx.Where(fmt.Sprintf("%s = ?", moduleNameColumnName), modName).
+		WithContext(ctx).
+		Find(&annotations).
+		Error; err != nil {
+		return nil, err
+	}
+
+	return annotations, nil
+}
+
+func (am *ModuleAnnotationsTableHandler) Delete(ctx context.Context, modName string, names ...string) error {
+	return am.tx.Where(fmt.Sprintf("%s = ? AND %s IN ?", moduleNameColumnName, nameColumnName), modName, names).
+		WithContext(ctx).
+		Delete(&ModuleAnnotation{}).
+		Error
+}
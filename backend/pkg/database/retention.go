@@ -0,0 +1,341 @@
+// Copyright © 2022 Cisco Systems, Inc. and its affiliates.
+// All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package database
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/go-openapi/strfmt"
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+const defaultRetentionBatchSize = 1000
+
+type RetentionConfig struct {
+	// MaxAge is the age after which API events are deleted. Zero disables age based pruning.
+	MaxAge time.Duration
+	// MaxEventsPerAPI is the number of most recent API events kept per API. Zero disables count based pruning.
+	MaxEventsPerAPI int
+	// KeepOnlyDiffs limits the pruning to events without a spec diff, so that the diff history is kept.
+	KeepOnlyDiffs bool
+	// TraceMaxAge is the age after which the archived raw traces are deleted, while their events are kept.
+	// Zero keeps the traces as long as their events.
+	TraceMaxAge time.Duration
+	// BatchSize is the maximum number of events deleted in a single transaction.
+	BatchSize int
+	// Interval is the time between two retention runs.
+	Interval time.Duration
+}
+
+func (c RetentionConfig) Enabled() bool {
+	return c.MaxAge > 0 || c.MaxEventsPerAPI > 0 || c.TraceMaxAge > 0
+}
+
+type RetentionRunStatus struct {
+	StartTime               time.Time
+	EndTime                 time.Time
+	DeletedEvents           int64
+	DeletedEventAnnotations int64
+	DeletedEventTraces      int64
+	DeletedAPIAnnotations   int64
+	Err                     error
+
+	prunedAPIs map[uint]bool
+}
+
+// PruneListener is called with the API whose events were pruned.
+type PruneListener func(apiID uint)
+
+// Retention periodically prunes the API events, together with their annotations, traces, rollups and risk factors,
+// according to the retention config.
+type Retention struct {
+	db         *gorm.DB
+	rollupLock *sync.Mutex
+	config     RetentionConfig
+
+	lock           sync.RWMutex
+	lastRun        *RetentionRunStatus
+	pruneListeners []PruneListener
+}
+
+func NewRetention(dbHandler *Handler, config RetentionConfig) *Retention {
+	if config.BatchSize <= 0 {
+		config.BatchSize = defaultRetentionBatchSize
+	}
+
+	return &Retention{
+		db:         dbHandler.DB,
+		rollupLock: &dbHandler.rollupLock,
+		config:     config,
+	}
+}
+
+func (r *Retention) Enabled() bool {
+	return r.config.Enabled()
+}
+
+// AddPruneListener adds a listener of the APIs whose events are pruned, it is called after each run.
+func (r *Retention) AddPruneListener(l PruneListener) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.pruneListeners = append(r.pruneListeners, l)
+}
+
+// LastRun returns the status of the last completed run, or nil if no run was completed yet.
+func (r *Retention) LastRun() *RetentionRunStatus {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	if r.lastRun == nil {
+		return nil
+	}
+	lastRun := *r.lastRun
+	return &lastRun
+}
+
+func (r *Retention) Start(ctx context.Context) {
+	if !r.Enabled() {
+		log.Infof("Data retention is disabled")
+		return
+	}
+
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				log.Debugf("Stopping data retention")
+				return
+			case <-time.After(r.config.Interval):
+				status := r.Run(ctx)
+				if status.Err != nil {
+					log.Errorf("Data retention run failed: %v", status.Err)
+				} else {
+					log.Infof("Data retention run completed: deleted events=%v, event annotations=%v, event traces=%v, api annotations=%v",
+						status.DeletedEvents, status.DeletedEventAnnotations, status.DeletedEventTraces, status.DeletedAPIAnnotations)
+				}
+			}
+		}
+	}()
+}
+
+// Run prunes the API events once and records the run status.
+func (r *Retention) Run(ctx context.Context) *RetentionRunStatus {
+	status := &RetentionRunStatus{
+		StartTime:  time.Now().UTC(),
+		prunedAPIs: map[uint]bool{},
+	}
+
+	status.Err = r.run(ctx, status)
+	status.EndTime = time.Now().UTC()
+
+	r.lock.Lock()
+	r.lastRun = status
+	listeners := r.pruneListeners
+	r.lock.Unlock()
+
+	// the APIs are told even if the run failed, their events pruned before the failure are deleted
+	for apiID := range status.prunedAPIs {
+		for _, l := range listeners {
+			l(apiID)
+		}
+	}
+
+	return status
+}
+
+func (r *Retention) run(ctx context.Context, status *RetentionRunStatus) error {
+	db := r.db.WithContext(ctx)
+
+	if r.config.MaxAge > 0 {
+		cutoff := strfmt.DateTime(time.Now().UTC().Add(-r.config.MaxAge))
+		if err := r.pruneEvents(db, status, func(tx *gorm.DB) *gorm.DB {
+			return tx.Where(fmt.Sprintf("%s < ?", timeColumnName), cutoff)
+		}); err != nil {
+			return fmt.Errorf("failed to prune events by age: %v", err)
+		}
+	}
+
+	if r.config.MaxEventsPerAPI > 0 {
+		if err := r.pruneEventsPerAPI(db, status); err != nil {
+			return fmt.Errorf("failed to prune events per API: %v", err)
+		}
+	}
+
+	if r.config.TraceMaxAge > 0 {
+		if err := r.pruneTraces(db, status); err != nil {
+			return fmt.Errorf("failed to prune event traces by age: %v", err)
+		}
+	}
+
+	deleted, err := deleteOrphanAPIAnnotations(db)
+	if err != nil {
+		return fmt.Errorf("failed to delete orphan API annotations: %v", err)
+	}
+	status.DeletedAPIAnnotations += deleted
+
+	return nil
+}
+
+func (r *Retention) pruneEventsPerAPI(db *gorm.DB, status *RetentionRunStatus) error {
+	var apiInfoIDs []uint
+	if err := db.Table(apiEventTableName).
+		Group(apiInfoIDColumnName).
+		Having("COUNT(*) > ?", r.config.MaxEventsPerAPI).
+		Pluck(apiInfoIDColumnName, &apiInfoIDs).Error; err != nil {
+		return fmt.Errorf("failed to get APIs exceeding the max events: %v", err)
+	}
+
+	for _, apiInfoID := range apiInfoIDs {
+		// the newest event that is out of the allowed window, it and all older events are pruned
+		var lastIDs []uint
+		if err := db.Table(apiEventTableName).
+			Where(fmt.Sprintf("%s = ?", apiInfoIDColumnName), apiInfoID).
+			Order(fmt.Sprintf("%s DESC", idColumnName)).
+			Offset(r.config.MaxEventsPerAPI).
+			Limit(1).
+			Pluck(idColumnName, &lastIDs).Error; err != nil {
+			return fmt.Errorf("failed to get oldest kept event of API %v: %v", apiInfoID, err)
+		}
+		if len(lastIDs) == 0 {
+			continue
+		}
+
+		if err := r.pruneEvents(db, status, func(tx *gorm.DB) *gorm.DB {
+			return tx.Where(fmt.Sprintf("%s = ? AND %s <= ?", apiInfoIDColumnName, idColumnName), apiInfoID, lastIDs[0])
+		}); err != nil {
+			return fmt.Errorf("failed to prune events of API %v: %v", apiInfoID, err)
+		}
+	}
+
+	return nil
+}
+
+// pruneEvents deletes in batches the events matching the scope, together with their annotations and traces.
+func (r *Retention) pruneEvents(db *gorm.DB, status *RetentionRunStatus, scope func(tx *gorm.DB) *gorm.DB) error {
+	for {
+		tx := db.Table(apiEventTableName).Scopes(scope)
+		if r.config.KeepOnlyDiffs {
+			tx = tx.Where(fmt.Sprintf("%s = ?", hasSpecDiffColumnName), false)
+		}
+
+		var ids []uint
+		if err := tx.Order(idColumnName).Limit(r.config.BatchSize).Pluck(idColumnName, &ids).Error; err != nil {
+			return fmt.Errorf("failed to get events to prune: %v", err)
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+
+		if err := r.deleteEvents(db, ids, status); err != nil {
+			return err
+		}
+
+		if len(ids) < r.config.BatchSize {
+			return nil
+		}
+	}
+}
+
+// pruneTraces deletes in batches the traces older than the trace max age.
+func (r *Retention) pruneTraces(db *gorm.DB, status *RetentionRunStatus) error {
+	cutoff := strfmt.DateTime(time.Now().UTC().Add(-r.config.TraceMaxAge))
+	for {
+		var ids []uint
+		if err := db.Table(apiEventTracesTableName).
+			Where(fmt.Sprintf("%s < ?", timeColumnName), cutoff).
+			Order(idColumnName).
+			Limit(r.config.BatchSize).
+			Pluck(idColumnName, &ids).Error; err != nil {
+			return fmt.Errorf("failed to get event traces to prune: %v", err)
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+
+		result := db.Where(fmt.Sprintf("%s IN ?", idColumnName), ids).Delete(&APIEventTrace{})
+		if result.Error != nil {
+			return fmt.Errorf("failed to delete event traces: %v", result.Error)
+		}
+		status.DeletedEventTraces += result.RowsAffected
+
+		if len(ids) < r.config.BatchSize {
+			return nil
+		}
+	}
+}
+
+func (r *Retention) deleteEvents(db *gorm.DB, ids []uint, status *RetentionRunStatus) error {
+	r.rollupLock.Lock()
+	defer r.rollupLock.Unlock()
+
+	var deletedEvents, deletedAnnotations, deletedTraces int64
+	var apiIDs []uint
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := removeEventsFromRollups(tx, ids); err != nil {
+			return err
+		}
+
+		var err error
+		if apiIDs, err = removeEventsFromRiskFactors(tx, ids); err != nil {
+			return err
+		}
+
+		result := tx.Where(fmt.Sprintf("%s IN ?", eventIDColumnName), ids).Delete(&APIEventAnnotation{})
+		if result.Error != nil {
+			return fmt.Errorf("failed to delete event annotations: %v", result.Error)
+		}
+		deletedAnnotations = result.RowsAffected
+
+		result = tx.Where(fmt.Sprintf("%s IN ?", eventIDColumnName), ids).Delete(&APIEventTrace{})
+		if result.Error != nil {
+			return fmt.Errorf("failed to delete event traces: %v", result.Error)
+		}
+		deletedTraces = result.RowsAffected
+
+		result = tx.Where(fmt.Sprintf("%s IN ?", idColumnName), ids).Delete(&APIEvent{})
+		if result.Error != nil {
+			return fmt.Errorf("failed to delete events: %v", result.Error)
+		}
+		deletedEvents = result.RowsAffected
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	status.DeletedEvents += deletedEvents
+	status.DeletedEventAnnotations += deletedAnnotations
+	status.DeletedEventTraces += deletedTraces
+	for _, apiID := range apiIDs {
+		status.prunedAPIs[apiID] = true
+	}
+	return nil
+}
+
+func deleteOrphanAPIAnnotations(db *gorm.DB) (int64, error) {
+	result := db.Where(fmt.Sprintf("%s NOT IN (?)", apiIDColumnName), db.Table(apiInventoryTableName).Select(idColumnName)).
+		Delete(&APIInfoAnnotation{})
+	if result.Error != nil {
+		return 0, result.Error
+	}
+
+	return result.RowsAffected, nil
+}
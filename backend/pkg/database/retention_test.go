@@ -0,0 +1,213 @@
+// Copyright © 2022 Cisco Systems, Inc. and its affiliates.
+// All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package database
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-openapi/strfmt"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"github.com/openclarity/apiclarity/api/server/models"
+)
+
+func createTestHandler(t *testing.T) *Handler {
+	t.Helper()
+
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "db.db")), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+	if err := db.AutoMigrate(&APIEvent{}, &APIInfo{}, &APIEventAnnotation{}, &APIInfoAnnotation{}, &APIEventRollup{}, &APIEventRollupState{}, &APIEventTrace{}, &ModuleAnnotation{}, &APIRiskFactors{}); err != nil {
+		t.Fatalf("failed to migrate db: %v", err)
+	}
+
+	return &Handler{DB: db}
+}
+
+func createTestEvent(t *testing.T, handler *Handler, apiInfoID uint, age time.Duration, hasSpecDiff bool) *APIEvent {
+	t.Helper()
+
+	event := &APIEvent{
+		APIInfoID:   apiInfoID,
+		Time:        strfmt.DateTime(time.Now().UTC().Add(-age)),
+		HasSpecDiff: hasSpecDiff,
+	}
+	if err := handler.DB.Create(event).Error; err != nil {
+		t.Fatalf("failed to create event: %v", err)
+	}
+	annotation := &APIEventAnnotation{ModuleName: "test", EventID: event.ID, Name: "ann"}
+	if err := handler.DB.Create(annotation).Error; err != nil {
+		t.Fatalf("failed to create event annotation: %v", err)
+	}
+	trace := &APIEventTrace{EventID: event.ID, Time: event.Time}
+	if err := handler.DB.Create(trace).Error; err != nil {
+		t.Fatalf("failed to create event trace: %v", err)
+	}
+
+	return event
+}
+
+func countRows(t *testing.T, handler *Handler, model interface{}) int64 {
+	t.Helper()
+
+	var count int64
+	if err := handler.DB.Model(model).Count(&count).Error; err != nil {
+		t.Fatalf("failed to count rows: %v", err)
+	}
+	return count
+}
+
+func TestRetention_Run(t *testing.T) {
+	tests := []struct {
+		name                      string
+		config                    RetentionConfig
+		wantDeletedEvents         int64
+		wantDeletedTraces         int64
+		wantDeletedAPIAnnotations int64
+	}{
+		{
+			name:                      "max age",
+			config:                    RetentionConfig{MaxAge: time.Hour, BatchSize: 2},
+			wantDeletedEvents:         4,
+			wantDeletedTraces:         4,
+			wantDeletedAPIAnnotations: 1,
+		},
+		{
+			name:                      "max age keep only diffs",
+			config:                    RetentionConfig{MaxAge: time.Hour, KeepOnlyDiffs: true, BatchSize: 2},
+			wantDeletedEvents:         3,
+			wantDeletedTraces:         3,
+			wantDeletedAPIAnnotations: 1,
+		},
+		{
+			name:                      "max events per API",
+			config:                    RetentionConfig{MaxEventsPerAPI: 2, BatchSize: 2},
+			wantDeletedEvents:         3,
+			wantDeletedTraces:         3,
+			wantDeletedAPIAnnotations: 1,
+		},
+		{
+			name:                      "trace max age",
+			config:                    RetentionConfig{TraceMaxAge: time.Hour, BatchSize: 2},
+			wantDeletedEvents:         0,
+			wantDeletedTraces:         4,
+			wantDeletedAPIAnnotations: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := createTestHandler(t)
+
+			api := &APIInfo{Name: "test", Port: 80}
+			if err := handler.DB.Create(api).Error; err != nil {
+				t.Fatalf("failed to create api: %v", err)
+			}
+			// api 1: 4 old events (one of them with a diff) and 1 new event
+			createTestEvent(t, handler, api.ID, 3*time.Hour, false)
+			createTestEvent(t, handler, api.ID, 3*time.Hour, true)
+			createTestEvent(t, handler, api.ID, 2*time.Hour, false)
+			createTestEvent(t, handler, api.ID, 2*time.Hour, false)
+			createTestEvent(t, handler, api.ID, 0, false)
+			// api 2: 1 new event
+			createTestEvent(t, handler, api.ID+1, 0, false)
+
+			for _, apiID := range []uint{api.ID, 1000} {
+				if err := handler.DB.Create(&APIInfoAnnotation{ModuleName: "test", APIID: apiID, Name: "ann"}).Error; err != nil {
+					t.Fatalf("failed to create api annotation: %v", err)
+				}
+			}
+
+			retention := NewRetention(handler, tt.config)
+			if retention.LastRun() != nil {
+				t.Fatalf("LastRun() expected nil before the first run")
+			}
+			status := retention.Run(context.Background())
+			if status.Err != nil {
+				t.Fatalf("Run() error = %v", status.Err)
+			}
+			if status.DeletedEvents != tt.wantDeletedEvents || status.DeletedEventAnnotations != tt.wantDeletedEvents {
+				t.Errorf("Run() deleted events = %v, event annotations = %v, want %v",
+					status.DeletedEvents, status.DeletedEventAnnotations, tt.wantDeletedEvents)
+			}
+			if status.DeletedEventTraces != tt.wantDeletedTraces {
+				t.Errorf("Run() deleted event traces = %v, want %v", status.DeletedEventTraces, tt.wantDeletedTraces)
+			}
+			if status.DeletedAPIAnnotations != tt.wantDeletedAPIAnnotations {
+				t.Errorf("Run() deleted api annotations = %v, want %v", status.DeletedAPIAnnotations, tt.wantDeletedAPIAnnotations)
+			}
+			if got := countRows(t, handler, &APIEvent{}); got != 6-tt.wantDeletedEvents {
+				t.Errorf("events left = %v, want %v", got, 6-tt.wantDeletedEvents)
+			}
+			if got := countRows(t, handler, &APIEventAnnotation{}); got != 6-tt.wantDeletedEvents {
+				t.Errorf("event annotations left = %v, want %v", got, 6-tt.wantDeletedEvents)
+			}
+			if got := countRows(t, handler, &APIEventTrace{}); got != 6-tt.wantDeletedTraces {
+				t.Errorf("event traces left = %v, want %v", got, 6-tt.wantDeletedTraces)
+			}
+			if lastRun := retention.LastRun(); lastRun == nil || lastRun.DeletedEvents != status.DeletedEvents {
+				t.Errorf("LastRun() = %+v, want %+v", lastRun, status)
+			}
+		})
+	}
+}
+
+func TestRetention_RiskFactors(t *testing.T) {
+	handler := createTestHandler(t)
+	ctx := context.Background()
+
+	unauthenticated := true
+	var factors []APIRiskFactors
+	for _, event := range []*APIEvent{
+		{APIInfoID: 1, Time: strfmt.DateTime(time.Now().UTC().Add(-2 * time.Hour)), StatusCode: 200, Unauthenticated: &unauthenticated},
+		{APIInfoID: 1, Time: strfmt.DateTime(time.Now().UTC().Add(-2 * time.Hour)), StatusCode: 200, SpecDiffType: models.DiffTypeSHADOWDIFF, Unauthenticated: &unauthenticated},
+		// stored before the authentication was recorded, it was not counted
+		{APIInfoID: 1, Time: strfmt.DateTime(time.Now().UTC().Add(-2 * time.Hour)), StatusCode: 200},
+		{APIInfoID: 1, Time: strfmt.DateTime(time.Now().UTC()), StatusCode: 200, Unauthenticated: &unauthenticated},
+	} {
+		if err := handler.DB.Create(event).Error; err != nil {
+			t.Fatalf("failed to create event: %v", err)
+		}
+		factors = append(factors, NewAPIRiskFactors(event))
+	}
+	if err := handler.APIRiskFactorsTable().Add(ctx, factors...); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+
+	retention := NewRetention(handler, RetentionConfig{MaxAge: time.Hour})
+	var pruned []uint
+	retention.AddPruneListener(func(apiID uint) {
+		pruned = append(pruned, apiID)
+	})
+	if status := retention.Run(ctx); status.Err != nil || status.DeletedEvents != 3 {
+		t.Fatalf("Run() = %+v", status)
+	}
+
+	got, err := handler.APIRiskFactorsTable().Get(ctx, 1)
+	if err != nil || got.SuccessfulEvents != 1 || got.UnauthenticatedEvents != 1 || got.ShadowDiffEvents != 0 {
+		t.Errorf("risk factors = %+v, %v", got, err)
+	}
+	if len(pruned) != 1 || pruned[0] != 1 {
+		t.Errorf("pruned APIs = %v", pruned)
+	}
+}
@@ -0,0 +1,402 @@
+// Copyright © 2022 Cisco Systems, Inc. and its affiliates.
+// All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-openapi/strfmt"
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/openclarity/apiclarity/api/server/models"
+)
+
+const (
+	apiEventRollupsTableName     = "api_event_rollups"
+	apiEventRollupStateTableName = "api_event_rollup_state"
+
+	// NOTE: when changing one of the column names change also the gorm label in APIEventRollup.
+	granularityColumnName = "granularity"
+	bucketTimeColumnName  = "bucket_time"
+	statusClassColumnName = "status_class"
+	hitCountColumnName    = "hit_count"
+
+	rollupBatchSize = 5000
+	rollupStateID   = 1
+)
+
+type RollupGranularity string
+
+const (
+	RollupGranularityMinute RollupGranularity = "MINUTE"
+	RollupGranularityHour   RollupGranularity = "HOUR"
+	RollupGranularityDay    RollupGranularity = "DAY"
+)
+
+// rollupGranularities are ordered from the coarsest to the finest.
+var rollupGranularities = []RollupGranularity{RollupGranularityDay, RollupGranularityHour, RollupGranularityMinute}
+
+func (g RollupGranularity) Duration() time.Duration {
+	switch g {
+	case RollupGranularityMinute:
+		return time.Minute
+	case RollupGranularityHour:
+		return time.Hour
+	case RollupGranularityDay:
+		return 24 * time.Hour // nolint:gomnd
+	}
+	return 0
+}
+
+// APIEventRollup is the number of API events of a time bucket with the same API, path, method and status class.
+type APIEventRollup struct {
+	ID uint `gorm:"primarykey" faker:"-"`
+
+	Granularity     RollupGranularity `json:"granularity" gorm:"column:granularity;uniqueIndex:api_event_rollup_idx"`
+	BucketTime      strfmt.DateTime   `json:"bucketTime" gorm:"column:bucket_time;uniqueIndex:api_event_rollup_idx"`
+	APIInfoID       uint              `json:"apiInfoId" gorm:"column:api_info_id;uniqueIndex:api_event_rollup_idx"`
+	HostSpecName    string            `json:"hostSpecName" gorm:"column:host_spec_name;uniqueIndex:api_event_rollup_idx"`
+	DestinationPort int64             `json:"destinationPort" gorm:"column:destination_port;uniqueIndex:api_event_rollup_idx"`
+	Method          models.HTTPMethod `json:"method" gorm:"column:method;uniqueIndex:api_event_rollup_idx"`
+	Path            string            `json:"path" gorm:"column:path;uniqueIndex:api_event_rollup_idx"`
+	// StatusClass is the first digit of the status code (e.g. 2 for 2xx).
+	StatusClass int64 `json:"statusClass" gorm:"column:status_class;uniqueIndex:api_event_rollup_idx"`
+	HasSpecDiff bool  `json:"hasSpecDiff" gorm:"column:has_spec_diff;uniqueIndex:api_event_rollup_idx"`
+	IsNonAPI    bool  `json:"isNonApi" gorm:"column:is_non_api;uniqueIndex:api_event_rollup_idx"`
+
+	HitCount int64 `json:"hitCount" gorm:"column:hit_count"`
+}
+
+func (APIEventRollup) TableName() string {
+	return apiEventRollupsTableName
+}
+
+// APIEventRollupState keeps track of the API events that were already rolled up. The events are rolled up in the
+// order of their IDs, whatever their time, so an API event is in the rollups if and only if its ID is not greater
+// than LastEventID.
+type APIEventRollupState struct {
+	ID          uint `gorm:"primarykey"`
+	LastEventID uint `gorm:"column:last_event_id"`
+}
+
+func (APIEventRollupState) TableName() string {
+	return apiEventRollupStateTableName
+}
+
+var rollupKeyColumns = []clause.Column{
+	{Name: granularityColumnName},
+	{Name: bucketTimeColumnName},
+	{Name: apiInfoIDColumnName},
+	{Name: hostSpecNameColumnName},
+	{Name: destinationPortColumnName},
+	{Name: methodColumnName},
+	{Name: pathColumnName},
+	{Name: statusClassColumnName},
+	{Name: hasSpecDiffColumnName},
+	{Name: isNonAPIColumnName},
+}
+
+// StartEventRollups periodically rolls up the new API events into the time buckets.
+func (db *Handler) StartEventRollups(ctx context.Context, interval time.Duration) {
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				log.Debugf("Stopping events rollup")
+				return
+			case <-time.After(interval):
+				if err := db.RollupEvents(ctx); err != nil {
+					log.Errorf("Failed to rollup API events: %v", err)
+				}
+			}
+		}
+	}()
+}
+
+// RollupEvents adds all the API events that were not rolled up yet to the rollups.
+func (db *Handler) RollupEvents(ctx context.Context) error {
+	db.rollupLock.Lock()
+	defer db.rollupLock.Unlock()
+
+	tx := db.DB.WithContext(ctx)
+
+	state := &APIEventRollupState{ID: rollupStateID}
+	if err := tx.FirstOrCreate(state).Error; err != nil {
+		return fmt.Errorf("failed to get rollup state: %v", err)
+	}
+
+	for {
+		var events []APIEvent
+		if err := tx.Table(apiEventTableName).
+			Select(idColumnName, timeColumnName, apiInfoIDColumnName, hostSpecNameColumnName, destinationPortColumnName,
+				methodColumnName, pathColumnName, statusCodeColumnName, hasSpecDiffColumnName, isNonAPIColumnName).
+			Where(fmt.Sprintf("%s > ?", idColumnName), state.LastEventID).
+			Order(idColumnName).
+			Limit(rollupBatchSize).
+			Find(&events).Error; err != nil {
+			return fmt.Errorf("failed to get API events: %v", err)
+		}
+		if len(events) == 0 {
+			return nil
+		}
+
+		state.LastEventID = events[len(events)-1].ID
+
+		if err := tx.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Clauses(clause.OnConflict{
+				Columns: rollupKeyColumns,
+				DoUpdates: clause.Assignments(map[string]interface{}{
+					hitCountColumnName: gorm.Expr(fmt.Sprintf("%s + excluded.%s", FieldInTable(apiEventRollupsTableName, hitCountColumnName), hitCountColumnName)),
+				}),
+			}).CreateInBatches(createEventRollups(events), rollupBatchSize).Error; err != nil {
+				return fmt.Errorf("failed to update rollups: %v", err)
+			}
+			if err := tx.Save(state).Error; err != nil {
+				return fmt.Errorf("failed to save rollup state: %v", err)
+			}
+			return nil
+		}); err != nil {
+			return err
+		}
+
+		if len(events) < rollupBatchSize {
+			return nil
+		}
+	}
+}
+
+// removeEventsFromRollups decrements the rollups of the rolled up events among the given ones, before they are deleted.
+// The rollups that are left without events are deleted. The rollup lock must be held.
+func removeEventsFromRollups(tx *gorm.DB, ids []uint) error {
+	var state APIEventRollupState
+	if err := tx.Table(apiEventRollupStateTableName).First(&state, rollupStateID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return fmt.Errorf("failed to get rollup state: %v", err)
+	}
+
+	var events []APIEvent
+	if err := tx.Table(apiEventTableName).
+		Select(idColumnName, timeColumnName, apiInfoIDColumnName, hostSpecNameColumnName, destinationPortColumnName,
+			methodColumnName, pathColumnName, statusCodeColumnName, hasSpecDiffColumnName, isNonAPIColumnName).
+		Where(fmt.Sprintf("%s IN ? AND %s <= ?", idColumnName, idColumnName), ids, state.LastEventID).
+		Find(&events).Error; err != nil {
+		return fmt.Errorf("failed to get API events: %v", err)
+	}
+	if len(events) == 0 {
+		return nil
+	}
+
+	for _, rollup := range createEventRollups(events) {
+		if err := tx.Model(&APIEventRollup{}).
+			Where(map[string]interface{}{
+				granularityColumnName:     rollup.Granularity,
+				bucketTimeColumnName:      rollup.BucketTime,
+				apiInfoIDColumnName:       rollup.APIInfoID,
+				hostSpecNameColumnName:    rollup.HostSpecName,
+				destinationPortColumnName: rollup.DestinationPort,
+				methodColumnName:          rollup.Method,
+				pathColumnName:            rollup.Path,
+				statusClassColumnName:     rollup.StatusClass,
+				hasSpecDiffColumnName:     rollup.HasSpecDiff,
+				isNonAPIColumnName:        rollup.IsNonAPI,
+			}).
+			Update(hitCountColumnName, gorm.Expr(fmt.Sprintf("%s - ?", hitCountColumnName), rollup.HitCount)).Error; err != nil {
+			return fmt.Errorf("failed to update rollups: %v", err)
+		}
+	}
+	if err := tx.Where(fmt.Sprintf("%s <= ?", hitCountColumnName), 0).Delete(&APIEventRollup{}).Error; err != nil {
+		return fmt.Errorf("failed to delete empty rollups: %v", err)
+	}
+
+	return nil
+}
+
+func createEventRollups(events []APIEvent) []*APIEventRollup {
+	rollups := make(map[APIEventRollup]*APIEventRollup)
+
+	for _, event := range events {
+		for _, granularity := range rollupGranularities {
+			key := APIEventRollup{
+				Granularity:     granularity,
+				BucketTime:      strfmt.DateTime(time.Time(event.Time).UTC().Truncate(granularity.Duration())),
+				APIInfoID:       event.APIInfoID,
+				HostSpecName:    event.HostSpecName,
+				DestinationPort: event.DestinationPort,
+				Method:          event.Method,
+				Path:            event.Path,
+				StatusClass:     event.StatusCode / 100, // nolint:gomnd
+				HasSpecDiff:     event.HasSpecDiff,
+				IsNonAPI:        event.IsNonAPI,
+			}
+			rollup, ok := rollups[key]
+			if !ok {
+				rollup = &key
+				rollups[key] = rollup
+			}
+			rollup.HitCount++
+		}
+	}
+
+	ret := make([]*APIEventRollup, 0, len(rollups))
+	for _, rollup := range rollups {
+		ret = append(ret, rollup)
+	}
+	return ret
+}
+
+// eventsCounter counts the API events in a time range, using the rollups for the whole buckets of the range, and the
+// events that are not rolled up yet.
+type eventsCounter struct {
+	// events is the filtered API events session.
+	events *gorm.DB
+	// rollups is the filtered rollups session, nil when the filters can't be applied on the rollups.
+	rollups *gorm.DB
+	// lastEventID is the last rolled up API event, zero when there are no rollups.
+	lastEventID uint
+}
+
+func (a *APIEventsTableHandler) newEventsCounter(events, rollups *gorm.DB) (*eventsCounter, error) {
+	counter := &eventsCounter{
+		events:  events,
+		rollups: rollups,
+	}
+	if rollups == nil {
+		return counter, nil
+	}
+
+	var state APIEventRollupState
+	err := a.tx.Session(&gorm.Session{NewDB: true}).Table(apiEventRollupStateTableName).First(&state, rollupStateID).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return counter, nil
+		}
+		return nil, fmt.Errorf("failed to get rollup state: %v", err)
+	}
+	counter.lastEventID = state.LastEventID
+
+	return counter, nil
+}
+
+func (c *eventsCounter) count(startTime, endTime time.Time) (int64, error) {
+	var count int64
+
+	granularity, rollupStart, rollupEnd, ok := c.getRollupRange(startTime, endTime)
+	if !ok {
+		err := c.events.Where(CreateTimeFilter(timeColumnName, strfmt.DateTime(startTime), strfmt.DateTime(endTime))).
+			Count(&count).Error
+		return count, err
+	}
+
+	// raw events before and after the rolled up buckets, and in the buckets but not rolled up yet
+	events := c.events.
+		Select("COUNT(*)").
+		Where(fmt.Sprintf("((%[1]s >= ? AND %[1]s < ?) OR (%[1]s >= ? AND %[1]s <= ?) OR (%[1]s >= ? AND %[1]s < ? AND %[2]s > ?))",
+			timeColumnName, FieldInTable(apiEventTableName, idColumnName)),
+			strfmt.DateTime(startTime), strfmt.DateTime(rollupStart),
+			strfmt.DateTime(rollupEnd), strfmt.DateTime(endTime),
+			strfmt.DateTime(rollupStart), strfmt.DateTime(rollupEnd), c.lastEventID)
+	rollups := c.rollups.
+		Select(fmt.Sprintf("COALESCE(SUM(%s), 0)", FieldInTable(apiEventRollupsTableName, hitCountColumnName))).
+		Where(fmt.Sprintf("%s = ? AND %s >= ? AND %s < ?",
+			FieldInTable(apiEventRollupsTableName, granularityColumnName),
+			FieldInTable(apiEventRollupsTableName, bucketTimeColumnName),
+			FieldInTable(apiEventRollupsTableName, bucketTimeColumnName)),
+			granularity, strfmt.DateTime(rollupStart), strfmt.DateTime(rollupEnd))
+
+	if err := c.events.Session(&gorm.Session{NewDB: true}).Raw("SELECT (?) + (?)", events, rollups).Scan(&count).Error; err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// getRollupRange returns the coarsest granularity that fits in the time range,
+// and the part of the range that is covered by whole rollup buckets.
+func (c *eventsCounter) getRollupRange(startTime, endTime time.Time) (RollupGranularity, time.Time, time.Time, bool) {
+	if c.rollups == nil || c.lastEventID == 0 {
+		return "", time.Time{}, time.Time{}, false
+	}
+
+	for _, granularity := range rollupGranularities {
+		duration := granularity.Duration()
+		if duration > endTime.Sub(startTime) {
+			continue
+		}
+
+		rollupStart := startTime.UTC().Truncate(duration)
+		if rollupStart.Before(startTime) {
+			rollupStart = rollupStart.Add(duration)
+		}
+		rollupEnd := endTime.UTC().Truncate(duration)
+		if !rollupStart.Before(rollupEnd) {
+			continue
+		}
+
+		return granularity, rollupStart, rollupEnd, true
+	}
+
+	return "", time.Time{}, time.Time{}, false
+}
+
+// setRollupsFilters applies the API events filters on the rollups,
+// it returns nil if some of the filters are not kept by the rollups.
+func setRollupsFilters(tx *gorm.DB, filters *APIEventsFilters) *gorm.DB {
+	// the rollups are bucketed by the event time, not by the request time
+	if filters.RequestStartTime != nil || filters.RequestEndTime != nil ||
+		len(filters.ProvidedPathIDIs) > 0 || len(filters.ReconstructedPathIDIs) > 0 ||
+		len(filters.StatusCodeIs) > 0 || len(filters.StatusCodeIsNot) > 0 || filters.StatusCodeGte != nil || filters.StatusCodeLte != nil ||
+		len(filters.SourceIPIs) > 0 || len(filters.SourceIPIsNot) > 0 ||
+		len(filters.DestinationIPIs) > 0 || len(filters.DestinationIPIsNot) > 0 ||
+		len(filters.SpecDiffTypeIs) > 0 {
+		return nil
+	}
+
+	if filters.APIInfoIDIs != nil {
+		tx = tx.Where(fmt.Sprintf("%s = ?", FieldInTable(apiEventRollupsTableName, apiInfoIDColumnName)), *filters.APIInfoIDIs)
+	}
+
+	tx = FilterIs(tx, methodColumnName, filters.MethodIs)
+
+	tx = FilterIs(tx, pathColumnName, filters.PathIs)
+	tx = FilterIsNot(tx, pathColumnName, filters.PathIsNot)
+	tx = FilterContains(tx, pathColumnName, filters.PathContains)
+	tx = FilterStartsWith(tx, pathColumnName, filters.PathStart)
+	tx = FilterEndsWith(tx, pathColumnName, filters.PathEnd)
+
+	tx = FilterIs(tx, destinationPortColumnName, filters.DestinationPortIs)
+	tx = FilterIsNot(tx, destinationPortColumnName, filters.DestinationPortIsNot)
+
+	tx = FilterIsBool(tx, hasSpecDiffColumnName, filters.HasSpecDiffIs)
+
+	tx = FilterIs(tx, hostSpecNameColumnName, filters.SpecIs)
+	tx = FilterIsNot(tx, hostSpecNameColumnName, filters.SpecIsNot)
+	tx = FilterContains(tx, hostSpecNameColumnName, filters.SpecContains)
+	tx = FilterStartsWith(tx, hostSpecNameColumnName, filters.SpecStart)
+	tx = FilterEndsWith(tx, hostSpecNameColumnName, filters.SpecEnd)
+
+	if !filters.ShowNonAPI {
+		tx = tx.Where(fmt.Sprintf("%s = ?", isNonAPIColumnName), false)
+	}
+
+	return tx
+}
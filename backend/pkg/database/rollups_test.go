@@ -0,0 +1,203 @@
+// Copyright © 2022 Cisco Systems, Inc. and its affiliates.
+// All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-openapi/strfmt"
+	"gorm.io/gorm"
+	"gotest.tools/assert"
+
+	"github.com/openclarity/apiclarity/api/server/models"
+	"github.com/openclarity/apiclarity/api/server/restapi/operations"
+)
+
+func TestHandler_RollupEvents(t *testing.T) {
+	handler := createTestHandler(t)
+	api := &APIInfo{Name: "test", Port: 80, HasProvidedSpec: true}
+	if err := handler.DB.Create(api).Error; err != nil {
+		t.Fatalf("failed to create api: %v", err)
+	}
+
+	now := time.Now().UTC()
+	methods := []models.HTTPMethod{models.HTTPMethodGET, models.HTTPMethodPOST}
+	// an event every 7 minutes in the last 2 days
+	for i := 0; i < 2*24*60/7; i++ {
+		event := &APIEvent{
+			APIInfoID:    api.ID,
+			Time:         strfmt.DateTime(now.Add(-time.Duration(i*7)*time.Minute - 13*time.Second)),
+			Method:       methods[i%len(methods)],
+			Path:         "/users",
+			StatusCode:   200,
+			HostSpecName: "test",
+			HasSpecDiff:  i%5 == 0,
+		}
+		if err := handler.DB.Create(event).Error; err != nil {
+			t.Fatalf("failed to create event: %v", err)
+		}
+	}
+
+	hitCountParams := operations.GetAPIUsageHitCountParams{
+		StartTime: strfmt.DateTime(now.Add(-36 * time.Hour)),
+		EndTime:   strfmt.DateTime(now),
+		MethodIs:  []string{string(models.HTTPMethodGET)},
+	}
+	dashboardStart, dashboardEnd := now.Add(-6*time.Hour), now
+
+	// before the rollup, the counts are computed from the raw events
+	wantHitCounts, err := handler.APIEventsTable().GetAPIUsages(hitCountParams)
+	assert.NilError(t, err)
+	var total int64
+	for _, hitCount := range wantHitCounts {
+		total += hitCount.Count
+	}
+	assert.Assert(t, total > 0)
+	wantDiffsUsages, err := handler.APIEventsTable().GetDashboardAPIUsages(dashboardStart, dashboardEnd, APIWithDiffs)
+	assert.NilError(t, err)
+	wantExistingUsages, err := handler.APIEventsTable().GetDashboardAPIUsages(dashboardStart, dashboardEnd, ExistingAPI)
+	assert.NilError(t, err)
+
+	assert.NilError(t, handler.RollupEvents(context.Background()))
+
+	var rollupsCount int64
+	assert.NilError(t, handler.DB.Model(&APIEventRollup{}).Count(&rollupsCount).Error)
+	assert.Assert(t, rollupsCount > 0)
+	// a second run has nothing to add
+	assert.NilError(t, handler.RollupEvents(context.Background()))
+	var rollupsCountAfter int64
+	assert.NilError(t, handler.DB.Model(&APIEventRollup{}).Count(&rollupsCountAfter).Error)
+	assert.Equal(t, rollupsCount, rollupsCountAfter)
+
+	hitCounts, err := handler.APIEventsTable().GetAPIUsages(hitCountParams)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, hitCounts, wantHitCounts)
+
+	diffsUsages, err := handler.APIEventsTable().GetDashboardAPIUsages(dashboardStart, dashboardEnd, APIWithDiffs)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, diffsUsages, wantDiffsUsages)
+
+	existingUsages, err := handler.APIEventsTable().GetDashboardAPIUsages(dashboardStart, dashboardEnd, ExistingAPI)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, existingUsages, wantExistingUsages)
+
+	// the api filter is applied on the rollups, the request time filters fall back to the raw events
+	otherAPIID := api.ID + 1
+	requestTime := strfmt.DateTime(now)
+	assert.Assert(t, setRollupsFilters(handler.DB, &APIEventsFilters{RequestStartTime: &requestTime, RequestEndTime: &requestTime}) == nil)
+	for apiID, want := range map[uint]int64{api.ID: countRows(t, handler, &APIEvent{}), otherAPIID: 0} {
+		apiID := apiID
+		filters := &APIEventsFilters{APIInfoIDIs: &apiID, ShowNonAPI: true}
+		table := handler.APIEventsTable().(*APIEventsTableHandler)
+		counter, err := table.newEventsCounter(table.setAPIEventsFilters(filters).Session(&gorm.Session{}),
+			setRollupsFilters(handler.DB.Table(apiEventRollupsTableName), filters).Session(&gorm.Session{}))
+		assert.NilError(t, err)
+		count, err := counter.count(now.Add(-72*time.Hour), now)
+		assert.NilError(t, err)
+		assert.Equal(t, count, want)
+	}
+
+	// a late event in the rolled up buckets is counted before and after it's rolled up
+	late := &APIEvent{APIInfoID: api.ID, Time: strfmt.DateTime(now.Add(-30 * time.Hour)), Method: models.HTTPMethodGET, Path: "/users", StatusCode: 200}
+	assert.NilError(t, handler.DB.Create(late).Error)
+	for i := 0; i < 2; i++ {
+		hitCounts, err = handler.APIEventsTable().GetAPIUsages(hitCountParams)
+		assert.NilError(t, err)
+		var lateTotal int64
+		for _, hitCount := range hitCounts {
+			lateTotal += hitCount.Count
+		}
+		assert.Equal(t, lateTotal, total+1)
+		assert.NilError(t, handler.RollupEvents(context.Background()))
+	}
+
+	// the pruned events are removed from the rollups
+	status := NewRetention(handler, RetentionConfig{MaxAge: 24 * time.Hour}).Run(context.Background())
+	assert.NilError(t, status.Err)
+	assert.Assert(t, status.DeletedEvents > 0)
+	for _, granularity := range rollupGranularities {
+		var rolledUp int64
+		assert.NilError(t, handler.DB.Model(&APIEventRollup{}).Where("granularity = ?", granularity).
+			Select("COALESCE(SUM(hit_count), 0)").Scan(&rolledUp).Error)
+		assert.Equal(t, rolledUp, countRows(t, handler, &APIEvent{}))
+	}
+}
+
+func TestEventsCounter_getRollupRange(t *testing.T) {
+	base := time.Date(2022, 5, 1, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name            string
+		lastEventID     uint
+		startTime       time.Time
+		endTime         time.Time
+		wantOk          bool
+		wantGranularity RollupGranularity
+		wantStart       time.Time
+		wantEnd         time.Time
+	}{
+		{
+			name:      "no rollups",
+			startTime: base,
+			endTime:   base.Add(time.Hour),
+			wantOk:    false,
+		},
+		{
+			name:        "range shorter than a minute",
+			lastEventID: 10,
+			startTime:   base,
+			endTime:     base.Add(30 * time.Second),
+			wantOk:      false,
+		},
+		{
+			name:            "hour buckets inside the range",
+			lastEventID:     10,
+			startTime:       base.Add(-10 * time.Minute),
+			endTime:         base.Add(2*time.Hour + 10*time.Minute),
+			wantOk:          true,
+			wantGranularity: RollupGranularityHour,
+			wantStart:       base,
+			wantEnd:         base.Add(2 * time.Hour),
+		},
+		{
+			name:            "minute buckets inside the range",
+			lastEventID:     10,
+			startTime:       base.Add(-10 * time.Second),
+			endTime:         base.Add(50*time.Minute + 30*time.Second),
+			wantOk:          true,
+			wantGranularity: RollupGranularityMinute,
+			wantStart:       base,
+			wantEnd:         base.Add(50 * time.Minute),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &eventsCounter{
+				rollups:     &gorm.DB{},
+				lastEventID: tt.lastEventID,
+			}
+			granularity, start, end, ok := c.getRollupRange(tt.startTime, tt.endTime)
+			assert.Equal(t, ok, tt.wantOk)
+			if !ok {
+				return
+			}
+			assert.Equal(t, granularity, tt.wantGranularity)
+			assert.Equal(t, start, tt.wantStart)
+			assert.Equal(t, end, tt.wantEnd)
+		})
+	}
+}
@@ -33,6 +33,40 @@ type SourceObject struct {
 	External   bool                            `json:"external"`
 	EndUsers   EndUsers                        `json:"end_users,omitempty"`
 	Authorized bool                            `json:"authorized"`
+	// Roles are the permissions of the roles, groups and scopes of the end users calling the operation from this source.
+	Roles RolePermissions `json:"roles,omitempty"`
+}
+
+type RolePermission struct {
+	Role       string `json:"role"`
+	Authorized bool   `json:"authorized"`
+}
+
+type RolePermissions []*RolePermission
+
+func (perms RolePermissions) Find(fn func(perm *RolePermission) bool) (int, *RolePermission) {
+	for i, perm := range perms {
+		if fn(perm) {
+			return i, perm
+		}
+	}
+	return 0, nil
+}
+
+// Authorizes returns false when roles were learned for the source but none of the user roles is authorized.
+// Users without roles, and sources without learned roles, are not restricted by role.
+func (perms RolePermissions) Authorizes(user *DetectedUser) bool {
+	if user == nil || len(user.Roles) == 0 || len(perms) == 0 {
+		return true
+	}
+	for _, role := range user.Roles {
+		if _, perm := perms.Find(func(perm *RolePermission) bool {
+			return perm.Role == role
+		}); perm != nil && perm.Authorized {
+			return true
+		}
+	}
+	return false
 }
 
 type DetectedUserSource int32
@@ -41,6 +75,9 @@ const (
 	DetectedUserSourceJWT = iota
 	DetectedUserSourceBasic
 	DetectedUserSourceXConsumerIDHeader
+	DetectedUserSourceHeader
+	DetectedUserSourceCookie
+	DetectedUserSourceMTLS
 )
 
 func (d *DetectedUserSource) UnmarshalJSON(b []byte) error {
@@ -54,6 +91,12 @@ func (d *DetectedUserSource) UnmarshalJSON(b []byte) error {
 		*d = DetectedUserSourceBasic
 	case "KONG_X_CONSUMER_ID":
 		*d = DetectedUserSourceXConsumerIDHeader
+	case "HEADER":
+		*d = DetectedUserSourceHeader
+	case "COOKIE":
+		*d = DetectedUserSourceCookie
+	case "MTLS":
+		*d = DetectedUserSourceMTLS
 	}
 	return nil
 }
@@ -72,6 +115,12 @@ func (d DetectedUserSource) String() string {
 		return "BASIC"
 	case DetectedUserSourceXConsumerIDHeader:
 		return "KONG_X_CONSUMER_ID"
+	case DetectedUserSourceHeader:
+		return "HEADER"
+	case DetectedUserSourceCookie:
+		return "COOKIE"
+	case DetectedUserSourceMTLS:
+		return "MTLS"
 	}
 	return ""
 }
@@ -91,6 +140,8 @@ type DetectedUser struct {
 	Source    DetectedUserSource `json:"source"`
 	ID        string             `json:"id"`
 	IPAddress string             `json:"ip_address"`
+	// Roles are the roles, groups and scopes of the user, in the "<claim>:<value>" format (e.g. "groups:admins").
+	Roles []string `json:"roles,omitempty"`
 }
 
 type AuthorizationModel struct {
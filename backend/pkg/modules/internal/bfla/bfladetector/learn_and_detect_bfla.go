@@ -347,6 +347,7 @@ func (l *learnAndDetectBFLA) updateAuthorizationModel(path, method string, clien
 		}
 		if user != nil {
 			authzModel.Operations[0].Audience[0].EndUsers = append(authzModel.Operations[0].Audience[0].EndUsers, user)
+			authzModel.Operations[0].Audience[0].updateRoles(user, authorize, updateAuthorized)
 		}
 		authzModelEntry.Set(authzModel)
 		return nil
@@ -363,6 +364,7 @@ func (l *learnAndDetectBFLA) updateAuthorizationModel(path, method string, clien
 		}
 		if user != nil {
 			op.Audience[0].EndUsers = append(op.Audience[0].EndUsers, user)
+			op.Audience[0].updateRoles(user, authorize, updateAuthorized)
 		}
 		authzModel.Operations = append(authzModel.Operations, op)
 		authzModelEntry.Set(authzModel)
@@ -382,6 +384,7 @@ func (l *learnAndDetectBFLA) updateAuthorizationModel(path, method string, clien
 		sa := &SourceObject{External: external, K8sObject: clientRef, Authorized: authorize}
 		if user != nil {
 			sa.EndUsers = append(sa.EndUsers, user)
+			sa.updateRoles(user, authorize, updateAuthorized)
 		}
 		op.Audience = append(op.Audience, sa)
 		authzModelEntry.Set(authzModel)
@@ -395,10 +398,16 @@ func (l *learnAndDetectBFLA) updateAuthorizationModel(path, method string, clien
 			audience.EndUsers = append(audience.EndUsers, user)
 			authzModelEntry.Set(authzModel)
 		}
+		audience.updateRoles(user, authorize, updateAuthorized)
 	}
 
 	// TODO think of a prettier way to be able to update only on certain cases
 	if updateAuthorized {
+		if !authorize && user != nil && len(user.Roles) > 0 {
+			// denying a user with roles denies only its roles, the source keeps its authorization
+			authzModelEntry.Set(authzModel)
+			return nil
+		}
 		oldAuthorized := audience.Authorized
 		authzModel.Operations[opIndex].Audience[audienceIndex].Authorized = authorize
 		if oldAuthorized != authorize {
@@ -411,6 +420,28 @@ func (l *learnAndDetectBFLA) updateAuthorizationModel(path, method string, clien
 	return nil
 }
 
+// updateRoles adds the user roles to the audience, the new roles get the authorize permission.
+// When updateAuthorized is set, the permission of the already known roles is updated as well.
+// Roles are not added to sources that learned no roles, so that they don't become role restricted after the learning.
+func (sa *SourceObject) updateRoles(user *DetectedUser, authorize, updateAuthorized bool) {
+	if !authorize && !updateAuthorized && len(sa.Roles) == 0 {
+		return
+	}
+	for _, role := range user.Roles {
+		role := role
+		_, perm := sa.Roles.Find(func(perm *RolePermission) bool {
+			return perm.Role == role
+		})
+		if perm == nil {
+			sa.Roles = append(sa.Roles, &RolePermission{Role: role, Authorized: authorize})
+			continue
+		}
+		if updateAuthorized {
+			perm.Authorized = authorize
+		}
+	}
+}
+
 func (l *learnAndDetectBFLA) IsLearning(apiID uint) bool {
 	tracesProcessed, err := l.tracesCounterMap.Get(apiID)
 	if err != nil {
@@ -429,7 +460,7 @@ func (l *learnAndDetectBFLA) IsUnauthorized(path, method string, clientRef *k8st
 	return l.isUnauthorized(path, method, clientRef, apiID, user)
 }
 
-func (l *learnAndDetectBFLA) isUnauthorized(path, method string, clientRef *k8straceannotator.K8sObjectRef, apiID uint, _ *DetectedUser) bool {
+func (l *learnAndDetectBFLA) isUnauthorized(path, method string, clientRef *k8straceannotator.K8sObjectRef, apiID uint, user *DetectedUser) bool {
 	var err error
 	external := clientRef == nil
 	authzModelEntry, err := l.authzModelsMap.Get(apiID)
@@ -460,7 +491,7 @@ func (l *learnAndDetectBFLA) isUnauthorized(path, method string, clientRef *k8st
 		return true
 	}
 
-	return !aud.Authorized
+	return !aud.Authorized || !aud.Roles.Authorizes(user)
 }
 
 func (l *learnAndDetectBFLA) SendTrace(trace *CompositeTrace) {
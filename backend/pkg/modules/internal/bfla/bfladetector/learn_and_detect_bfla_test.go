@@ -374,6 +374,63 @@ func Test_learnAndDetectBFLA_ApproveTrace(t *testing.T) {
 	}
 }
 
+func Test_learnAndDetectBFLA_DetectRoles(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	backendAccessor := core.NewMockBackendAccessor(ctrl)
+
+	apiID := mapID2name["user"]
+	storedAuthModels := map[uint]bfladetector.AuthorizationModel{}
+	storedTracesProcessed := map[uint]int{}
+	storedTracesToLearn := map[uint]int{apiID: 2}
+	detector := initBFLADetector(ctrl, backendAccessor, storedAuthModels, storedTracesProcessed, storedTracesToLearn)
+	backendAccessor.EXPECT().GetAPIInfo(context.Background(), gomock.Any()).Return(getAPIInfoWithTags("/users/{id}"), nil).AnyTimes()
+
+	sendTrace := func(method, user string, roles ...string) {
+		trace := buildTrace(method, "/users/1", "frontend", "user", user)
+		trace.DetectedUser.Roles = roles
+		detector.SendTrace(trace)
+		time.Sleep(100 * time.Millisecond)
+	}
+	// learning: only admins delete users, both admins and regular users get them
+	sendTrace("DELETE", "admin1", "roles:admin")
+	sendTrace("GET", "user1", "roles:user")
+	// detection: a regular user deletes a user from the same frontend
+	sendTrace("DELETE", "user1", "roles:user")
+
+	frontend := newClientRef("frontend")
+	for _, tt := range []struct {
+		method string
+		user   *bfladetector.DetectedUser
+		want   bool
+	}{
+		{method: "DELETE", user: &bfladetector.DetectedUser{ID: "user1", Roles: []string{"roles:user"}}, want: true},
+		{method: "DELETE", user: &bfladetector.DetectedUser{ID: "user2", Roles: []string{"roles:user", "roles:admin"}}, want: false},
+		{method: "DELETE", user: &bfladetector.DetectedUser{ID: "user3"}, want: false},
+		{method: "GET", user: &bfladetector.DetectedUser{ID: "user1", Roles: []string{"roles:user"}}, want: false},
+	} {
+		if got := detector.IsUnauthorized("/users/{id}", tt.method, frontend, apiID, tt.user); got != tt.want {
+			t.Errorf("IsUnauthorized(%s, %+v) = %v, want %v", tt.method, tt.user, got, tt.want)
+		}
+	}
+
+	// approving the regular user authorizes its role
+	detector.ApproveTrace("/users/{id}", "DELETE", frontend, apiID, &bfladetector.DetectedUser{ID: "user1", Roles: []string{"roles:user"}})
+	time.Sleep(100 * time.Millisecond)
+	if detector.IsUnauthorized("/users/{id}", "DELETE", frontend, apiID, &bfladetector.DetectedUser{ID: "user4", Roles: []string{"roles:user"}}) {
+		t.Errorf("IsUnauthorized() = true after approving the role")
+	}
+	// denying it only denies the role
+	detector.DenyTrace("/users/{id}", "DELETE", frontend, apiID, &bfladetector.DetectedUser{ID: "user1", Roles: []string{"roles:user"}})
+	time.Sleep(100 * time.Millisecond)
+	if !detector.IsUnauthorized("/users/{id}", "DELETE", frontend, apiID, &bfladetector.DetectedUser{ID: "user4", Roles: []string{"roles:user"}}) {
+		t.Errorf("IsUnauthorized() = false after denying the role")
+	}
+	if detector.IsUnauthorized("/users/{id}", "DELETE", frontend, apiID, &bfladetector.DetectedUser{ID: "admin1", Roles: []string{"roles:admin"}}) {
+		t.Errorf("IsUnauthorized() = true for the admin role after denying the user role")
+	}
+}
+
 func toJSON(v interface{}) []byte {
 	bb, _ := json.Marshal(v)
 	return bb
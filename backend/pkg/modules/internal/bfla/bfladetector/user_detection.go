@@ -25,35 +25,368 @@ import (
 	"github.com/golang-jwt/jwt/v4"
 )
 
-func GetUserID(headers http.Header) (*DetectedUser, error) {
-	if xcustomerID := headers.Get("x-customer-id"); xcustomerID != "" {
-		return &DetectedUser{Source: DetectedUserSourceXConsumerIDHeader, ID: xcustomerID}, nil
+const (
+	authorizationHeader = "authorization"
+	xCustomerIDHeader   = "x-customer-id"
+	defaultJWTClaimPath = "sub"
+
+	// DefaultRoleClaims are the JWT claims holding the user roles, groups and scopes by default.
+	DefaultRoleClaims = "roles,groups,scope,scp,realm_access.roles"
+
+	// Extractor types of the user identity extractors configuration.
+	ExtractorTypeKong   = "kong"
+	ExtractorTypeBasic  = "basic"
+	ExtractorTypeJWT    = "jwt"
+	ExtractorTypeHeader = "header"
+	ExtractorTypeCookie = "cookie"
+	ExtractorTypeMTLS   = "mtls"
+)
+
+// UserIdentityExtractor detects the user that made a request from the request headers.
+// It returns nil when the identity source it handles is not present in the request.
+type UserIdentityExtractor interface {
+	ExtractUser(headers http.Header) (*DetectedUser, error)
+}
+
+// HeaderExtractor uses the value of a header as the user ID.
+type HeaderExtractor struct {
+	Header string
+	Source DetectedUserSource
+}
+
+func (e *HeaderExtractor) ExtractUser(headers http.Header) (*DetectedUser, error) {
+	if value := headers.Get(e.Header); value != "" {
+		return &DetectedUser{Source: e.Source, ID: value}, nil
 	}
-	authz := headers.Get("authorization")
-	if authz == "" {
+	return nil, nil
+}
+
+// BasicAuthExtractor uses the username of the basic authorization header as the user ID.
+type BasicAuthExtractor struct{}
+
+func (e *BasicAuthExtractor) ExtractUser(headers http.Header) (*DetectedUser, error) {
+	authz := headers.Get(authorizationHeader)
+	if !strings.HasPrefix(authz, "Basic ") {
 		return nil, nil
 	}
-	if strings.HasPrefix(authz, "Basic ") {
-		basic := strings.TrimPrefix(authz, "Basic ")
-		usernameAndPassword, err := base64.StdEncoding.DecodeString(basic)
-		if err != nil {
-			return nil, fmt.Errorf("cannot decode basic authz header: %w", err)
+	basic := strings.TrimPrefix(authz, "Basic ")
+	usernameAndPassword, err := base64.StdEncoding.DecodeString(basic)
+	if err != nil {
+		return nil, fmt.Errorf("cannot decode basic authz header: %w", err)
+	}
+	usernameAndPasswordParts := strings.Split(string(usernameAndPassword), ":")
+
+	// nolint:gomnd
+	if len(usernameAndPasswordParts) < 2 {
+		return nil, errors.New("broken basic auth header")
+	}
+	return &DetectedUser{Source: DetectedUserSourceBasic, ID: usernameAndPasswordParts[0]}, nil
+}
+
+// JWTClaimExtractor uses a claim of the bearer JWT as the user ID.
+// ClaimPath is the list of keys leading to the claim, e.g. ["realm_access", "user_id"].
+type JWTClaimExtractor struct {
+	ClaimPath []string
+}
+
+func (e *JWTClaimExtractor) ExtractUser(headers http.Header) (*DetectedUser, error) {
+	claims, err := getBearerClaims(headers)
+	if err != nil || claims == nil {
+		return nil, err
+	}
+
+	switch claim := getClaim(claims, e.ClaimPath).(type) {
+	case string:
+		if claim == "" {
+			return nil, nil
+		}
+		return &DetectedUser{Source: DetectedUserSourceJWT, ID: claim}, nil
+	case float64, bool:
+		return &DetectedUser{Source: DetectedUserSourceJWT, ID: fmt.Sprint(claim)}, nil
+	}
+	return nil, nil
+}
+
+// getBearerClaims returns the claims of the bearer JWT, or nil if the request has no bearer token.
+func getBearerClaims(headers http.Header) (jwt.MapClaims, error) {
+	authz := headers.Get(authorizationHeader)
+	if !strings.HasPrefix(authz, "Bearer ") {
+		return nil, nil
+	}
+	bearer := strings.TrimPrefix(authz, "Bearer ")
+	claims := jwt.MapClaims{}
+	if _, _, err := jwt.NewParser(jwt.WithoutClaimsValidation()).ParseUnverified(bearer, claims); err != nil {
+		return nil, fmt.Errorf("unsuported bearer token: %w", err)
+	}
+	return claims, nil
+}
+
+// getClaim returns the claim at the path of keys, or nil if it doesn't exist.
+func getClaim(claims jwt.MapClaims, path []string) interface{} {
+	var claim interface{} = map[string]interface{}(claims)
+	for _, key := range path {
+		obj, ok := claim.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		if claim, ok = obj[key]; !ok {
+			return nil
+		}
+	}
+	return claim
+}
+
+// JWTRolesExtractor extracts the roles, groups and scopes of the user from claims of the bearer JWT.
+// A claim can be either a list of strings (e.g. "groups") or a space separated string (e.g. "scope").
+type JWTRolesExtractor struct {
+	// ClaimPaths are the "." separated paths of the claims, e.g. "realm_access.roles".
+	ClaimPaths []string
+}
+
+// ParseJWTRolesExtractor parses a comma separated list of claim paths.
+func ParseJWTRolesExtractor(config string) *JWTRolesExtractor {
+	if strings.TrimSpace(config) == "" {
+		config = DefaultRoleClaims
+	}
+	e := &JWTRolesExtractor{}
+	for _, claimPath := range strings.Split(config, ",") {
+		if claimPath = strings.TrimSpace(claimPath); claimPath != "" {
+			e.ClaimPaths = append(e.ClaimPaths, claimPath)
+		}
+	}
+	return e
+}
+
+// ExtractRoles returns the user roles in the "<claim path>:<value>" format.
+func (e *JWTRolesExtractor) ExtractRoles(headers http.Header) []string {
+	claims, err := getBearerClaims(headers)
+	if err != nil || claims == nil {
+		return nil
+	}
+
+	var roles []string
+	for _, claimPath := range e.ClaimPaths {
+		var values []string
+		switch claim := getClaim(claims, strings.Split(claimPath, ".")).(type) {
+		case string:
+			values = strings.Fields(claim)
+		case []interface{}:
+			for _, value := range claim {
+				if value, ok := value.(string); ok && value != "" {
+					values = append(values, value)
+				}
+			}
+		}
+		for _, value := range values {
+			roles = append(roles, claimPath+":"+value)
+		}
+	}
+	return roles
+}
+
+// CookieExtractor uses the value of a cookie, typically a session ID, as the user ID.
+type CookieExtractor struct {
+	Name string
+}
+
+func (e *CookieExtractor) ExtractUser(headers http.Header) (*DetectedUser, error) {
+	cookie, err := (&http.Request{Header: headers}).Cookie(e.Name)
+	if err != nil || cookie.Value == "" {
+		return nil, nil
+	}
+	return &DetectedUser{Source: DetectedUserSourceCookie, ID: cookie.Value}, nil
+}
+
+// MTLSSubjectExtractor uses the subject of the client certificate, forwarded by the TLS terminating proxy in a header,
+// as the user ID. The header value can be either the subject DN (e.g. "CN=client,O=org") or an Envoy
+// x-forwarded-client-cert header. The common name is used when present, otherwise the whole subject.
+type MTLSSubjectExtractor struct {
+	Header string
+}
+
+func (e *MTLSSubjectExtractor) ExtractUser(headers http.Header) (*DetectedUser, error) {
+	value := headers.Get(e.Header)
+	if value == "" {
+		return nil, nil
+	}
+	subject := value
+	if strings.Contains(value, "Subject=") || strings.Contains(value, "By=") || strings.Contains(value, "Hash=") {
+		subject = getXFCCSubject(value)
+	}
+	if subject == "" {
+		return nil, nil
+	}
+	if cn := getCommonName(subject); cn != "" {
+		return &DetectedUser{Source: DetectedUserSourceMTLS, ID: cn}, nil
+	}
+	return &DetectedUser{Source: DetectedUserSourceMTLS, ID: subject}, nil
+}
+
+// getXFCCSubject returns the subject of the first element of an x-forwarded-client-cert header,
+// falling back to its URI (e.g. a SPIFFE ID) when the subject is not forwarded.
+func getXFCCSubject(xfcc string) string {
+	var subject, uri string
+	var field strings.Builder
+	quoted := false
+	handleField := func() {
+		keyValue := strings.SplitN(field.String(), "=", 2) // nolint:gomnd
+		field.Reset()
+		if len(keyValue) != 2 { // nolint:gomnd
+			return
 		}
-		usernameAndPasswordParts := strings.Split(string(usernameAndPassword), ":")
+		switch strings.ToLower(strings.TrimSpace(keyValue[0])) {
+		case "subject":
+			subject = strings.Trim(keyValue[1], `"`)
+		case "uri":
+			uri = keyValue[1]
+		}
+	}
+	for _, c := range xfcc {
+		switch {
+		case c == '"':
+			quoted = !quoted
+			field.WriteRune(c)
+		case c == ';' && !quoted:
+			handleField()
+		case c == ',' && !quoted:
+			// the next elements are the certificates of the proxies
+			handleField()
+			if subject != "" {
+				return subject
+			}
+			return uri
+		default:
+			field.WriteRune(c)
+		}
+	}
+	handleField()
+	if subject != "" {
+		return subject
+	}
+	return uri
+}
 
-		// nolint:gomnd
-		if len(usernameAndPasswordParts) < 2 {
-			return nil, errors.New("broken basic auth header")
+// getCommonName returns the CN attribute of a DN, in either the RFC 4514 ("CN=a,O=b") or the "/O=b/CN=a" format.
+func getCommonName(dn string) string {
+	sep := ","
+	if strings.HasPrefix(dn, "/") {
+		sep = "/"
+	}
+	for _, attr := range strings.Split(dn, sep) {
+		keyValue := strings.SplitN(strings.TrimSpace(attr), "=", 2)     // nolint:gomnd
+		if len(keyValue) == 2 && strings.EqualFold(keyValue[0], "CN") { // nolint:gomnd
+			return keyValue[1]
 		}
-		return &DetectedUser{Source: DetectedUserSourceBasic, ID: usernameAndPasswordParts[0]}, nil
 	}
-	if strings.HasPrefix(authz, "Bearer ") {
-		bearer := strings.TrimPrefix(authz, "Bearer ")
-		claims := &jwt.RegisteredClaims{}
-		if _, _, err := jwt.NewParser(jwt.WithoutClaimsValidation()).ParseUnverified(bearer, claims); err != nil {
-			return nil, fmt.Errorf("unsuported bearer token: %w", err)
+	return ""
+}
+
+// UserIdentityExtractors is an ordered chain of extractors, the first one that detects a user wins.
+type UserIdentityExtractors []UserIdentityExtractor
+
+// DefaultUserIdentityExtractors returns the x-customer-id header, basic auth and JWT subject extractors.
+func DefaultUserIdentityExtractors() UserIdentityExtractors {
+	return UserIdentityExtractors{
+		&HeaderExtractor{Header: xCustomerIDHeader, Source: DetectedUserSourceXConsumerIDHeader},
+		&BasicAuthExtractor{},
+		&JWTClaimExtractor{ClaimPath: []string{defaultJWTClaimPath}},
+	}
+}
+
+// ParseUserIdentityExtractors parses a comma separated list of extractors, each one in the "<type>[:<arg>]" format:
+//
+//	kong                  the x-customer-id header set by the Kong gateway
+//	basic                 the basic auth username
+//	jwt[:<claim path>]    a claim of the bearer JWT, with "." separated path (default "sub")
+//	header:<name>         the value of a header, e.g. an API key
+//	cookie:<name>         the value of a cookie, e.g. a session ID
+//	mtls:<header>         the client certificate subject forwarded by the proxy in a header
+func ParseUserIdentityExtractors(config string) (UserIdentityExtractors, error) {
+	if strings.TrimSpace(config) == "" {
+		return DefaultUserIdentityExtractors(), nil
+	}
+
+	var extractors UserIdentityExtractors
+	for _, entry := range strings.Split(config, ",") {
+		typeAndArg := strings.SplitN(strings.TrimSpace(entry), ":", 2) // nolint:gomnd
+		extractorType := strings.ToLower(typeAndArg[0])
+		arg := ""
+		if len(typeAndArg) == 2 { // nolint:gomnd
+			arg = strings.TrimSpace(typeAndArg[1])
+		}
+
+		switch extractorType {
+		case ExtractorTypeKong:
+			extractors = append(extractors, &HeaderExtractor{Header: xCustomerIDHeader, Source: DetectedUserSourceXConsumerIDHeader})
+		case ExtractorTypeBasic:
+			extractors = append(extractors, &BasicAuthExtractor{})
+		case ExtractorTypeJWT:
+			if arg == "" {
+				arg = defaultJWTClaimPath
+			}
+			extractors = append(extractors, &JWTClaimExtractor{ClaimPath: strings.Split(arg, ".")})
+		case ExtractorTypeHeader, ExtractorTypeCookie, ExtractorTypeMTLS:
+			if arg == "" {
+				return nil, fmt.Errorf("missing name for user identity extractor %q", extractorType)
+			}
+			switch extractorType {
+			case ExtractorTypeHeader:
+				extractors = append(extractors, &HeaderExtractor{Header: arg, Source: DetectedUserSourceHeader})
+			case ExtractorTypeCookie:
+				extractors = append(extractors, &CookieExtractor{Name: arg})
+			case ExtractorTypeMTLS:
+				extractors = append(extractors, &MTLSSubjectExtractor{Header: arg})
+			}
+		default:
+			return nil, fmt.Errorf("unknown user identity extractor %q", extractorType)
 		}
-		return &DetectedUser{Source: DetectedUserSourceJWT, ID: claims.Subject}, nil
 	}
-	return nil, ErrUnsupportedAuthScheme
+
+	return extractors, nil
+}
+
+// GetUserID returns the user detected by the first extractor of the chain that finds one.
+// If no user is detected, the first extraction error is returned.
+func (extractors UserIdentityExtractors) GetUserID(headers http.Header) (*DetectedUser, error) {
+	var firstErr error
+	usesAuthorization := false
+	for _, extractor := range extractors {
+		switch extractor.(type) {
+		case *BasicAuthExtractor, *JWTClaimExtractor:
+			usesAuthorization = true
+		}
+
+		user, err := extractor.ExtractUser(headers)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		if user != nil {
+			return user, nil
+		}
+	}
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	if usesAuthorization && headers.Get(authorizationHeader) != "" && !hasAuthScheme(headers, "Basic ", "Bearer ") {
+		return nil, ErrUnsupportedAuthScheme
+	}
+	return nil, nil
+}
+
+func hasAuthScheme(headers http.Header, schemes ...string) bool {
+	authz := headers.Get(authorizationHeader)
+	for _, scheme := range schemes {
+		if strings.HasPrefix(authz, scheme) {
+			return true
+		}
+	}
+	return false
+}
+
+// GetUserID detects the user with the default extractors.
+func GetUserID(headers http.Header) (*DetectedUser, error) {
+	return DefaultUserIdentityExtractors().GetUserID(headers)
 }
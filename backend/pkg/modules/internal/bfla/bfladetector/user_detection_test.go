@@ -19,6 +19,8 @@ import (
 	"net/http"
 	"reflect"
 	"testing"
+
+	"github.com/golang-jwt/jwt/v4"
 )
 
 func TestGetUserID(t *testing.T) {
@@ -100,3 +102,193 @@ func TestGetUserID(t *testing.T) {
 		})
 	}
 }
+
+func newTestJWT(t *testing.T, claims jwt.MapClaims) string {
+	t.Helper()
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte("secret"))
+	if err != nil {
+		t.Fatalf("failed to sign token: %v", err)
+	}
+	return token
+}
+
+func TestUserIdentityExtractors_GetUserID(t *testing.T) {
+	token := newTestJWT(t, jwt.MapClaims{
+		"sub":       "subject",
+		"email":     "user@example.com",
+		"client_id": 1234,
+		"realm":     map[string]interface{}{"user": "nested"},
+	})
+	tests := []struct {
+		name    string
+		config  string
+		headers map[string]string
+		want    *DetectedUser
+		wantErr bool
+	}{{
+		name:    "jwt claim",
+		config:  "jwt:email",
+		headers: map[string]string{"authorization": "Bearer " + token},
+		want:    &DetectedUser{Source: DetectedUserSourceJWT, ID: "user@example.com"},
+	}, {
+		name:    "jwt nested claim",
+		config:  "jwt:realm.user",
+		headers: map[string]string{"authorization": "Bearer " + token},
+		want:    &DetectedUser{Source: DetectedUserSourceJWT, ID: "nested"},
+	}, {
+		name:    "jwt missing claim falls back to the next extractor",
+		config:  "jwt:username,jwt:client_id",
+		headers: map[string]string{"authorization": "Bearer " + token},
+		want:    &DetectedUser{Source: DetectedUserSourceJWT, ID: "1234"},
+	}, {
+		name:    "custom header",
+		config:  "header:x-api-key,jwt",
+		headers: map[string]string{"x-api-key": "key1", "authorization": "Bearer " + token},
+		want:    &DetectedUser{Source: DetectedUserSourceHeader, ID: "key1"},
+	}, {
+		name:    "cookie",
+		config:  "cookie:session_id",
+		headers: map[string]string{"cookie": "theme=dark; session_id=abc123"},
+		want:    &DetectedUser{Source: DetectedUserSourceCookie, ID: "abc123"},
+	}, {
+		name:    "mtls subject dn",
+		config:  "mtls:x-ssl-client-subject",
+		headers: map[string]string{"x-ssl-client-subject": "CN=orders,O=acme"},
+		want:    &DetectedUser{Source: DetectedUserSourceMTLS, ID: "orders"},
+	}, {
+		name:   "mtls envoy xfcc",
+		config: "mtls:x-forwarded-client-cert",
+		headers: map[string]string{
+			"x-forwarded-client-cert": `By=spiffe://cluster.local/ns/default/sa/payment;Hash=abcd;Subject="CN=orders,O=acme";URI=spiffe://cluster.local/ns/default/sa/orders`,
+		},
+		want: &DetectedUser{Source: DetectedUserSourceMTLS, ID: "orders"},
+	}, {
+		name:   "mtls envoy xfcc without subject",
+		config: "mtls:x-forwarded-client-cert",
+		headers: map[string]string{
+			"x-forwarded-client-cert": `By=spiffe://cluster.local/ns/default/sa/payment;Hash=abcd;Subject="";URI=spiffe://cluster.local/ns/default/sa/orders`,
+		},
+		want: &DetectedUser{Source: DetectedUserSourceMTLS, ID: "spiffe://cluster.local/ns/default/sa/orders"},
+	}, {
+		name:    "no user detected",
+		config:  "header:x-api-key,cookie:session_id",
+		headers: map[string]string{"authorization": "Digest 123"},
+		want:    nil,
+	}, {
+		name:    "unsupported auth scheme",
+		config:  "basic,jwt",
+		headers: map[string]string{"authorization": "Digest 123"},
+		wantErr: true,
+	}, {
+		name:    "broken jwt",
+		config:  "jwt:email",
+		headers: map[string]string{"authorization": "Bearer 123123123"},
+		wantErr: true,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			extractors, err := ParseUserIdentityExtractors(tt.config)
+			if err != nil {
+				t.Fatalf("ParseUserIdentityExtractors() error = %v", err)
+			}
+			httpHeaders := http.Header{}
+			for k, v := range tt.headers {
+				httpHeaders.Add(k, v)
+			}
+			got, err := extractors.GetUserID(httpHeaders)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetUserID() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetUserID() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseUserIdentityExtractors(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  string
+		want    UserIdentityExtractors
+		wantErr bool
+	}{{
+		name:   "default",
+		config: "",
+		want:   DefaultUserIdentityExtractors(),
+	}, {
+		name:   "all types",
+		config: "kong, basic, jwt, jwt:a.b, header:x-api-key, cookie:sid, mtls:x-client-dn",
+		want: UserIdentityExtractors{
+			&HeaderExtractor{Header: "x-customer-id", Source: DetectedUserSourceXConsumerIDHeader},
+			&BasicAuthExtractor{},
+			&JWTClaimExtractor{ClaimPath: []string{"sub"}},
+			&JWTClaimExtractor{ClaimPath: []string{"a", "b"}},
+			&HeaderExtractor{Header: "x-api-key", Source: DetectedUserSourceHeader},
+			&CookieExtractor{Name: "sid"},
+			&MTLSSubjectExtractor{Header: "x-client-dn"},
+		},
+	}, {
+		name:    "missing header name",
+		config:  "header",
+		wantErr: true,
+	}, {
+		name:    "unknown type",
+		config:  "oauth",
+		wantErr: true,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseUserIdentityExtractors(tt.config)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseUserIdentityExtractors() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseUserIdentityExtractors() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJWTRolesExtractor_ExtractRoles(t *testing.T) {
+	token := newTestJWT(t, jwt.MapClaims{
+		"sub":          "subject",
+		"groups":       []string{"admins", "devs"},
+		"scope":        "users.read users.write",
+		"realm_access": map[string]interface{}{"roles": []string{"offline_access"}},
+	})
+	tests := []struct {
+		name    string
+		config  string
+		headers map[string]string
+		want    []string
+	}{{
+		name:    "default claims",
+		config:  "",
+		headers: map[string]string{"authorization": "Bearer " + token},
+		want:    []string{"groups:admins", "groups:devs", "scope:users.read", "scope:users.write", "realm_access.roles:offline_access"},
+	}, {
+		name:    "custom claims",
+		config:  "scope",
+		headers: map[string]string{"authorization": "Bearer " + token},
+		want:    []string{"scope:users.read", "scope:users.write"},
+	}, {
+		name:    "no token",
+		config:  "",
+		headers: map[string]string{"authorization": "Basic dGVzdDI6cGFzczEK"},
+		want:    nil,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			httpHeaders := http.Header{}
+			for k, v := range tt.headers {
+				httpHeaders.Add(k, v)
+			}
+			if got := ParseJWTRolesExtractor(tt.config).ExtractRoles(httpHeaders); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ExtractRoles() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -24,10 +24,12 @@ import (
 	"time"
 
 	log "github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
 
 	"github.com/openclarity/apiclarity/backend/pkg/database"
 	"github.com/openclarity/apiclarity/backend/pkg/modules/internal/bfla/bfladetector"
 	"github.com/openclarity/apiclarity/backend/pkg/modules/internal/bfla/k8straceannotator"
+	"github.com/openclarity/apiclarity/backend/pkg/modules/internal/bfla/policy"
 	"github.com/openclarity/apiclarity/backend/pkg/modules/internal/bfla/recovery"
 	"github.com/openclarity/apiclarity/backend/pkg/modules/internal/bfla/restapi"
 	"github.com/openclarity/apiclarity/backend/pkg/modules/internal/core"
@@ -38,6 +40,12 @@ const (
 	nrOfTracesToLearn   = 100
 	moduleVersion       = "0.0.0"
 	persistenceInterval = 5 * time.Second
+
+	// userIdentityExtractorsEnvVar is the comma separated chain of extractors used to detect the user of an API call,
+	// see bfladetector.ParseUserIdentityExtractors for the format.
+	userIdentityExtractorsEnvVar = "BFLA_USER_IDENTITY_EXTRACTORS"
+	// userRoleClaimsEnvVar is the comma separated list of JWT claims holding the user roles, groups and scopes.
+	userRoleClaimsEnvVar = "BFLA_USER_ROLE_CLAIMS"
 )
 
 type bfla struct {
@@ -45,6 +53,9 @@ type bfla struct {
 	bflaDetector bfladetector.BFLADetector
 	k8s          k8straceannotator.K8sClient
 
+	userIdentityExtractors bfladetector.UserIdentityExtractors
+	userRolesExtractor     *bfladetector.JWTRolesExtractor
+
 	accessor core.BackendAccessor
 }
 
@@ -64,6 +75,12 @@ func newModule(ctx context.Context, accessor core.BackendAccessor) (_ core.Modul
 		return nil, fmt.Errorf("failed to init bfla module: %w", err)
 	}
 
+	p.userIdentityExtractors, err = bfladetector.ParseUserIdentityExtractors(viper.GetString(userIdentityExtractorsEnvVar))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse user identity extractors: %w", err)
+	}
+	p.userRolesExtractor = bfladetector.ParseJWTRolesExtractor(viper.GetString(userRoleClaimsEnvVar))
+
 	sp := recovery.NewStatePersister(ctx, accessor, bfladetector.ModuleName, persistenceInterval)
 	p.bflaDetector = bfladetector.NewBFLADetector(ctx, nrOfTracesToLearn, accessor, eventAlerter{accessor}, sp)
 
@@ -71,6 +88,7 @@ func newModule(ctx context.Context, accessor core.BackendAccessor) (_ core.Modul
 		bflaDetector: p.bflaDetector,
 		state:        sp,
 		accessor:     accessor,
+		k8s:          p.k8s,
 	}
 	p.httpHandler = restapi.HandlerWithOptions(handler, restapi.ChiServerOptions{BaseURL: core.BaseHTTPPath + "/" + bfladetector.ModuleName})
 	return p, nil
@@ -132,7 +150,8 @@ func (p *bfla) addDetectedUser(ctx context.Context, cmpTrace *bfladetector.Compo
 		return nil
 	}
 
-	cmpTrace.DetectedUser, err = bfladetector.GetUserID(convertHeadersToMap(trace.Request.Common.Headers))
+	headers := convertHeadersToMap(trace.Request.Common.Headers)
+	cmpTrace.DetectedUser, err = p.userIdentityExtractors.GetUserID(headers)
 	if err != nil {
 		log.Error(err)
 	}
@@ -140,6 +159,7 @@ func (p *bfla) addDetectedUser(ctx context.Context, cmpTrace *bfladetector.Compo
 		return nil
 	}
 	cmpTrace.DetectedUser.IPAddress = trace.SourceAddress
+	cmpTrace.DetectedUser.Roles = p.userRolesExtractor.ExtractRoles(headers)
 	annDest := core.Annotation{Name: bfladetector.DetectedIDAnnotationName}
 	if annDest.Annotation, err = json.Marshal(cmpTrace.DetectedUser); err != nil {
 		return fmt.Errorf("unable to marshal user: %w", err)
@@ -230,6 +250,30 @@ func getBFLAAnnotations(ctx context.Context, accessor core.BackendAccessor, even
 	return
 }
 
+// DescribeEventFinding describes the violations of the authorization model, they are the alerts of the module.
+// The other annotations of the events are what the detector learns from.
+func (p *bfla) DescribeEventFinding(event *database.APIEvent, ann core.Annotation) (core.Finding, bool) {
+	if ann.Name != core.AlertAnnotation {
+		return core.Finding{}, false
+	}
+	f := core.Finding{
+		Name:        "Broken function level authorization",
+		Description: fmt.Sprintf("The client is not authorized to call %s %s by the learnt authorization model", event.Method, event.Path),
+		Severity:    core.FindingSeverityHigh,
+	}
+	if string(ann.Annotation) == core.AlertInfo.String() {
+		// the request was rejected by the API
+		f.Description += ", the request was rejected"
+		f.Severity = core.FindingSeverityLow
+	}
+	return f, true
+}
+
+// DescribeAPIFinding returns false, the annotations of the APIs are the state of the detector.
+func (p *bfla) DescribeAPIFinding(apiID uint, ann core.Annotation) (core.Finding, bool) {
+	return core.Finding{}, false
+}
+
 func (p *bfla) EventAnnotationNotify(modName string, eventID uint, ann core.Annotation) error {
 	log.Debugf("[BFLA] EventAnnotationNotify %s %d %s", modName, eventID, ann.Name)
 	return nil
@@ -244,6 +288,7 @@ type httpHandler struct {
 	state        recovery.StatePersister
 	bflaDetector bfladetector.BFLADetector
 	accessor     core.BackendAccessor
+	k8s          k8straceannotator.K8sClient
 }
 
 func (h httpHandler) GetEvent(w http.ResponseWriter, r *http.Request, eventID int) {
@@ -266,16 +311,12 @@ func (h httpHandler) GetEvent(w http.ResponseWriter, r *http.Request, eventID in
 	}
 	e := restapi.APIEventAnnotations{
 		BflaStatus:           restapi.BFLAStatusLEGITIMATE,
-		DestinationK8sObject: (*restapi.K8sObjectRef)(dest),
-		SourceK8sObject:      (*restapi.K8sObjectRef)(src),
+		DestinationK8sObject: ToRestapiK8sObjectRef(dest),
+		SourceK8sObject:      ToRestapiK8sObjectRef(src),
 		External:             src == nil,
 	}
 	if user != nil {
-		e.DetectedUser = &restapi.DetectedUser{
-			Id:        user.ID,
-			Source:    restapi.DetectedUserSource(user.Source.String()),
-			IpAddress: user.IPAddress,
-		}
+		e.DetectedUser = ToRestapiDetectedUser(user)
 	}
 	apiinfo, err := h.accessor.GetAPIInfo(r.Context(), event.APIInfoID)
 	if err != nil {
@@ -296,7 +337,7 @@ func (h httpHandler) GetEvent(w http.ResponseWriter, r *http.Request, eventID in
 
 	resolvedPath := bfladetector.ResolvePath(apiinfo, event)
 	log.Info("IsUnauthorized:", resolvedPath, string(event.Method), src, event.APIInfoID)
-	if h.bflaDetector.IsUnauthorized(resolvedPath, string(event.Method), src, event.APIInfoID, nil) {
+	if h.bflaDetector.IsUnauthorized(resolvedPath, string(event.Method), src, event.APIInfoID, user) {
 		e.BflaStatus = bfladetector.ResolveBFLAStatusInt(int(event.StatusCode))
 		log.Info("e.BflaStatus", e.BflaStatus)
 	}
@@ -345,6 +386,37 @@ func ToRestapiSpecType(specType bfladetector.SpecType) restapi.SpecType {
 	return restapi.SpecTypeNONE
 }
 
+func ToRestapiK8sObjectRef(ref *k8straceannotator.K8sObjectRef) *restapi.K8sObjectRef {
+	if ref == nil {
+		return nil
+	}
+	res := &restapi.K8sObjectRef{
+		ApiVersion: ref.ApiVersion,
+		Kind:       ref.Kind,
+		Name:       ref.Name,
+		Namespace:  ref.Namespace,
+		Uid:        ref.Uid,
+	}
+	if ref.ServiceAccount != "" {
+		serviceAccount := ref.ServiceAccount
+		res.ServiceAccount = &serviceAccount
+	}
+	return res
+}
+
+func ToRestapiDetectedUser(user *bfladetector.DetectedUser) *restapi.DetectedUser {
+	res := &restapi.DetectedUser{
+		Id:        user.ID,
+		Source:    restapi.DetectedUserSource(user.Source.String()),
+		IpAddress: user.IPAddress,
+	}
+	if len(user.Roles) > 0 {
+		roles := user.Roles
+		res.Roles = &roles
+	}
+	return res
+}
+
 func ToRestapiAuthorizationModel(am *bfladetector.AuthorizationModel) *restapi.AuthorizationModel {
 	res := &restapi.AuthorizationModel{}
 	for _, o := range am.Operations {
@@ -353,14 +425,17 @@ func ToRestapiAuthorizationModel(am *bfladetector.AuthorizationModel) *restapi.A
 			resAud := restapi.AuthorizationModelAudience{
 				Authorized: aud.Authorized,
 				External:   aud.External,
-				K8sObject:  (*restapi.K8sObjectRef)(aud.K8sObject),
+				K8sObject:  ToRestapiK8sObjectRef(aud.K8sObject),
 			}
 			for _, user := range aud.EndUsers {
-				resAud.EndUsers = append(resAud.EndUsers, restapi.DetectedUser{
-					Id:        user.ID,
-					Source:    restapi.DetectedUserSource(user.Source.String()),
-					IpAddress: user.IPAddress,
-				})
+				resAud.EndUsers = append(resAud.EndUsers, *ToRestapiDetectedUser(user))
+			}
+			if len(aud.Roles) > 0 {
+				roles := make([]restapi.RolePermission, 0, len(aud.Roles))
+				for _, role := range aud.Roles {
+					roles = append(roles, restapi.RolePermission{Role: role.Role, Authorized: role.Authorized})
+				}
+				resAud.Roles = &roles
 			}
 			resOp.Audience = append(resOp.Audience, resAud)
 		}
@@ -369,6 +444,85 @@ func ToRestapiAuthorizationModel(am *bfladetector.AuthorizationModel) *restapi.A
 	return res
 }
 
+// nolint:stylecheck,revive
+func (h httpHandler) GetAuthorizationModelApiIDExportIstio(w http.ResponseWriter, r *http.Request, apiID int, params restapi.GetAuthorizationModelApiIDExportIstioParams) {
+	ops, dest, ok := h.getAuthorizedOperations(w, r, uint(apiID), params.TrustDomain)
+	if !ok {
+		return
+	}
+	out, err := policy.IstioAuthorizationPolicy(ops, dest)
+	if err != nil {
+		httpResponse(w, http.StatusInternalServerError, &restapi.ApiResponse{Message: err.Error()})
+		return
+	}
+	w.Header().Set("Content-Type", "application/yaml")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", dest.Name+"-authorization-policy.yaml"))
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(out); err != nil {
+		log.Error(err)
+	}
+}
+
+// nolint:stylecheck,revive
+func (h httpHandler) GetAuthorizationModelApiIDExportOpa(w http.ResponseWriter, r *http.Request, apiID int, params restapi.GetAuthorizationModelApiIDExportOpaParams) {
+	ops, dest, ok := h.getAuthorizedOperations(w, r, uint(apiID), params.TrustDomain)
+	if !ok {
+		return
+	}
+	out, err := policy.OPABundle(ops, dest)
+	if err != nil {
+		httpResponse(w, http.StatusInternalServerError, &restapi.ApiResponse{Message: err.Error()})
+		return
+	}
+	w.Header().Set("Content-Type", "application/gzip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", dest.Name+"-bundle.tar.gz"))
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(out); err != nil {
+		log.Error(err)
+	}
+}
+
+// getAuthorizedOperations returns the authorized operations of the learned authorization model and the destination service.
+// On failure, it writes the error response and returns false.
+func (h httpHandler) getAuthorizedOperations(w http.ResponseWriter, r *http.Request, apiID uint, trustDomain *string) ([]*policy.Operation, policy.Destination, bool) {
+	apiInfo, err := h.accessor.GetAPIInfo(r.Context(), apiID)
+	if err != nil {
+		httpResponse(w, http.StatusNotFound, &restapi.ApiResponse{Message: err.Error()})
+		return nil, policy.Destination{}, false
+	}
+	if h.bflaDetector.IsLearning(apiID) {
+		httpResponse(w, http.StatusConflict, &restapi.ApiResponse{Message: fmt.Sprintf("auth model with id=%d is still learning", apiID)})
+		return nil, policy.Destination{}, false
+	}
+	authModel := &bfladetector.AuthorizationModel{}
+	_, found, err := h.state.UseState(apiID, bfladetector.AuthzModelAnnotationName, authModel)
+	if err != nil {
+		httpResponse(w, http.StatusBadRequest, &restapi.ApiResponse{Message: err.Error()})
+		return nil, policy.Destination{}, false
+	}
+	if !found {
+		httpResponse(w, http.StatusNotFound, &restapi.ApiResponse{Message: fmt.Sprintf("auth model with id=%d not found", apiID)})
+		return nil, policy.Destination{}, false
+	}
+	svc, err := k8straceannotator.LookupServiceByHost(h.k8s, apiInfo.Name)
+	if err != nil {
+		httpResponse(w, http.StatusNotFound, &restapi.ApiResponse{Message: fmt.Sprintf("unable to find the destination service of api=%d: %v", apiID, err)})
+		return nil, policy.Destination{}, false
+	}
+
+	td := ""
+	if trustDomain != nil {
+		td = *trustDomain
+	}
+	dest := policy.Destination{
+		APIID:     apiID,
+		Name:      svc.Name,
+		Namespace: svc.Namespace,
+		Selector:  svc.Spec.Selector,
+	}
+	return policy.AuthorizedOperations(authModel, td), dest, true
+}
+
 // nolint:stylecheck,revive
 func (h httpHandler) PutAuthorizationModelApiIDApprove(w http.ResponseWriter, r *http.Request, apiID int, params restapi.PutAuthorizationModelApiIDApproveParams) {
 	done := make(chan struct{})
@@ -387,6 +541,7 @@ func (h httpHandler) PutAuthorizationModelApiIDApprove(w http.ResponseWriter, r
 		httpResponse(w, http.StatusInternalServerError, &restapi.ApiResponse{Message: err.Error()})
 	case <-done:
 		log.Infof("approve applied successfully on api=%d path=%s method=%s ", apiID, params.Path, params.Method)
+		h.accessor.FindingsChanged(uint(apiID))
 		httpResponse(w, http.StatusOK, &restapi.ApiResponse{Message: "Requested approve operation on api event"})
 	}
 }
@@ -409,6 +564,7 @@ func (h httpHandler) PutAuthorizationModelApiIDDeny(w http.ResponseWriter, r *ht
 		httpResponse(w, http.StatusInternalServerError, &restapi.ApiResponse{Message: err.Error()})
 	case <-done:
 		log.Infof("deny applied successfully on api=%d path=%s method=%s ", apiID, params.Path, params.Method)
+		h.accessor.FindingsChanged(uint(apiID))
 		httpResponse(w, http.StatusOK, &restapi.ApiResponse{Message: "Reqested deny operation on api event"})
 	}
 }
@@ -34,6 +34,8 @@ type K8sObjectRef struct {
 	Namespace  string `json:"namespace,omitempty"`
 	// nolint:revive,stylecheck
 	Uid string `json:"uid,omitempty"`
+	// ServiceAccount is the service account of the pods of the object, it's empty for objects without pods.
+	ServiceAccount string `json:"serviceAccount,omitempty"`
 }
 
 func DetectSourceObject(ctx context.Context, k8s K8sClient, trace *pluginsmodels.Telemetry) (runtime.Object, error) {
@@ -96,6 +98,39 @@ func DetectDestinationObject(ctx context.Context, k8s K8sClient, trace *pluginsm
 	return nil, fmt.Errorf("unexpected host name: %s", trace.Request.Host)
 }
 
+// LookupServiceByHost returns the service of a host name in the "<service>[.<namespace>[.svc.<cluster domain>]]" format.
+// When the namespace is missing, the service name must be unique in the cluster.
+func LookupServiceByHost(k8s K8sClient, host string) (*corev1.Service, error) {
+	svcAndNs := strings.Split(host, ".")
+	// nolint:gomnd
+	if len(svcAndNs) >= 2 {
+		svc, err := k8s.ServicesGet(svcAndNs[1], svcAndNs[0])
+		if err != nil {
+			return nil, fmt.Errorf("unable to get service %s.%s: %w", svcAndNs[0], svcAndNs[1], err)
+		}
+		return svc, nil
+	}
+
+	services, err := k8s.ServicesList("")
+	if err != nil {
+		return nil, fmt.Errorf("unable to lookup k8s services: %w", err)
+	}
+	var found *corev1.Service
+	for _, svc := range services {
+		if svc.Name != host {
+			continue
+		}
+		if found != nil {
+			return nil, fmt.Errorf("found service %s in namespaces %s and %s", host, found.Namespace, svc.Namespace)
+		}
+		found = svc
+	}
+	if found == nil {
+		return nil, fmt.Errorf("unable to find service %s", host)
+	}
+	return found, nil
+}
+
 func lookupServices(_ context.Context, k8s K8sClient, wantIP string) (*corev1.Service, error) {
 	services, err := k8s.ServicesList("")
 	if err != nil {
@@ -22,6 +22,8 @@ import (
 	"time"
 
 	log "github.com/sirupsen/logrus"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -84,9 +86,37 @@ func NewRef(obj runtime.Object) *K8sObjectRef {
 		Namespace:  metaObj.GetNamespace(),
 		Name:       metaObj.GetName(),
 		Uid:        string(metaObj.GetUID()),
+
+		ServiceAccount: getServiceAccount(obj),
 	}
 }
 
+func getServiceAccount(obj runtime.Object) string {
+	var podSpec *corev1.PodSpec
+	switch o := obj.(type) {
+	case *corev1.Pod:
+		podSpec = &o.Spec
+	case *appsv1.Deployment:
+		podSpec = &o.Spec.Template.Spec
+	case *appsv1.StatefulSet:
+		podSpec = &o.Spec.Template.Spec
+	case *appsv1.DaemonSet:
+		podSpec = &o.Spec.Template.Spec
+	case *appsv1.ReplicaSet:
+		podSpec = &o.Spec.Template.Spec
+	case *batchv1.Job:
+		podSpec = &o.Spec.Template.Spec
+	case *batchv1.CronJob:
+		podSpec = &o.Spec.JobTemplate.Spec.Template.Spec
+	default:
+		return ""
+	}
+	if podSpec.ServiceAccountName == "" {
+		return defaultServiceAccount
+	}
+	return podSpec.ServiceAccountName
+}
+
 type K8sClient interface {
 	ServicesGet(namespace, name string) (*corev1.Service, error)
 	ServicesList(namespace string) ([]*corev1.Service, error)
@@ -108,7 +138,11 @@ type client struct {
 	resourcesMu   *sync.RWMutex
 }
 
-const ResyncPeriod = 1 * time.Minute
+const (
+	ResyncPeriod = 1 * time.Minute
+
+	defaultServiceAccount = "default"
+)
 
 func NewK8sClient(clientset kubernetes.Interface) (K8sClient, error) {
 	stopCh := make(chan struct{})
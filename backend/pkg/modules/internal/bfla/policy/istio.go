@@ -0,0 +1,159 @@
+// Copyright © 2022 Cisco Systems, Inc. and its affiliates.
+// All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package policy
+
+import (
+	"fmt"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+)
+
+// The subset of the Istio security/v1beta1 AuthorizationPolicy used by the export.
+type istioAuthorizationPolicy struct {
+	APIVersion string                       `json:"apiVersion"`
+	Kind       string                       `json:"kind"`
+	Metadata   istioMetadata                `json:"metadata"`
+	Spec       istioAuthorizationPolicySpec `json:"spec"`
+}
+
+type istioMetadata struct {
+	Name      string            `json:"name"`
+	Namespace string            `json:"namespace"`
+	Labels    map[string]string `json:"labels,omitempty"`
+}
+
+type istioAuthorizationPolicySpec struct {
+	Selector *istioWorkloadSelector `json:"selector,omitempty"`
+	Action   string                 `json:"action"`
+	Rules    []istioRule            `json:"rules"`
+}
+
+type istioWorkloadSelector struct {
+	MatchLabels map[string]string `json:"matchLabels"`
+}
+
+type istioRule struct {
+	From []istioFrom      `json:"from,omitempty"`
+	To   []istioTo        `json:"to"`
+	When []istioCondition `json:"when,omitempty"`
+}
+
+type istioFrom struct {
+	Source istioSource `json:"source"`
+}
+
+type istioSource struct {
+	Principals []string `json:"principals,omitempty"`
+	Namespaces []string `json:"namespaces,omitempty"`
+}
+
+type istioTo struct {
+	Operation istioOperation `json:"operation"`
+}
+
+type istioOperation struct {
+	Methods []string `json:"methods"`
+	Paths   []string `json:"paths"`
+}
+
+type istioCondition struct {
+	Key    string   `json:"key"`
+	Values []string `json:"values"`
+}
+
+// IstioAuthorizationPolicy renders the operations as an ALLOW Istio AuthorizationPolicy for the destination workload.
+// Istio paths only support prefix and suffix wildcards, so a path template is allowed up to its first parameter,
+// e.g. "/carts/{id}/items" becomes "/carts/*". External sources are allowed from any source.
+// The roles become conditions on the request JWT claims, which requires an Istio RequestAuthentication.
+func IstioAuthorizationPolicy(ops []*Operation, dest Destination) ([]byte, error) {
+	policy := istioAuthorizationPolicy{
+		APIVersion: "security.istio.io/v1beta1",
+		Kind:       "AuthorizationPolicy",
+		Metadata: istioMetadata{
+			Name:      fmt.Sprintf("apiclarity-bfla-%s", dest.Name),
+			Namespace: dest.Namespace,
+			Labels: map[string]string{
+				"app.kubernetes.io/managed-by": "apiclarity",
+			},
+		},
+		Spec: istioAuthorizationPolicySpec{
+			Action: "ALLOW",
+			Rules:  []istioRule{},
+		},
+	}
+	if len(dest.Selector) > 0 {
+		policy.Spec.Selector = &istioWorkloadSelector{MatchLabels: dest.Selector}
+	}
+
+	for _, op := range ops {
+		to := []istioTo{{Operation: istioOperation{Methods: []string{op.Method}, Paths: []string{istioPath(op.Path)}}}}
+		for _, source := range op.Sources {
+			rule := istioRule{To: to}
+			switch {
+			case source.External:
+			case source.Principal != "":
+				rule.From = []istioFrom{{Source: istioSource{Principals: []string{source.Principal}}}}
+			case source.Namespace != "":
+				rule.From = []istioFrom{{Source: istioSource{Namespaces: []string{source.Namespace}}}}
+			default:
+				continue
+			}
+			if len(source.Roles) == 0 {
+				policy.Spec.Rules = append(policy.Spec.Rules, rule)
+				continue
+			}
+			// the conditions of a rule are ANDed, so a rule is created per claim to allow any of the roles
+			for _, condition := range istioRolesConditions(source.Roles) {
+				roleRule := rule
+				roleRule.When = []istioCondition{condition}
+				policy.Spec.Rules = append(policy.Spec.Rules, roleRule)
+			}
+		}
+	}
+
+	out, err := yaml.Marshal(policy)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal authorization policy: %v", err)
+	}
+	return out, nil
+}
+
+func istioPath(path string) string {
+	if i := strings.Index(path, "{"); i >= 0 {
+		return path[:i] + "*"
+	}
+	return path
+}
+
+func istioRolesConditions(roles []Role) []istioCondition {
+	var conditions []istioCondition
+	claimIndex := map[string]int{}
+	for _, role := range roles {
+		key := "request.auth.claims"
+		for _, claim := range role.ClaimPath {
+			key += "[" + claim + "]"
+		}
+		i, ok := claimIndex[key]
+		if !ok {
+			i = len(conditions)
+			claimIndex[key] = i
+			conditions = append(conditions, istioCondition{Key: key})
+		}
+		conditions[i].Values = append(conditions[i].Values, role.Value)
+	}
+	return conditions
+}
@@ -0,0 +1,163 @@
+// Copyright © 2022 Cisco Systems, Inc. and its affiliates.
+// All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package policy
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// regoTemplate is the policy of the bundle, it decides on the input of the OPA Envoy plugin (ext_authz)
+// with the operations of the bundle data.
+var regoTemplate = template.Must(template.New("rego").Parse(`# Generated by APIClarity from the BFLA authorization model of {{ .Name }}.{{ .Namespace }}
+package {{ .Package }}
+
+default allow = false
+
+request_method := input.attributes.request.http.method
+
+request_path := split(input.attributes.request.http.path, "?")[0]
+
+source_principal := trim_prefix(input.attributes.source.principal, "spiffe://")
+
+source_namespace := split(source_principal, "/")[2]
+
+jwt_payload = payload {
+	auth := input.attributes.request.http.headers.authorization
+	startswith(auth, "Bearer ")
+	[_, payload, _] := io.jwt.decode(substring(auth, count("Bearer "), -1))
+}
+
+allow {
+	op := data.{{ .Package }}.operations[_]
+	op.method == request_method
+	regex.match(op.path_regex, request_path)
+	source := op.sources[_]
+	source_allowed(source)
+	roles_allowed(source)
+}
+
+source_allowed(source) {
+	source.external
+}
+
+source_allowed(source) {
+	source.principal == source_principal
+}
+
+source_allowed(source) {
+	source.namespace == source_namespace
+}
+
+roles_allowed(source) {
+	count(source.roles) == 0
+}
+
+roles_allowed(source) {
+	role := source.roles[_]
+	walk(jwt_payload, [role.claim_path, claim])
+	claim_values(claim)[_] == role.value
+}
+
+claim_values(claim) = claim {
+	is_array(claim)
+}
+
+claim_values(claim) = split(claim, " ") {
+	is_string(claim)
+}
+`))
+
+type opaManifest struct {
+	Revision string   `json:"revision"`
+	Roots    []string `json:"roots"`
+}
+
+type opaData struct {
+	Operations []*Operation `json:"operations"`
+}
+
+// OPABundle renders the operations as an Open Policy Agent bundle (a gzipped tarball),
+// with the operations as data and a policy allowing only the authorized operations.
+// The policy is in the "apiclarity.bfla.api_<api id>" package and decides on "allow".
+func OPABundle(ops []*Operation, dest Destination) ([]byte, error) {
+	pkg := fmt.Sprintf("apiclarity.bfla.api_%d", dest.APIID)
+	root := strings.ReplaceAll(pkg, ".", "/")
+
+	if ops == nil {
+		ops = []*Operation{}
+	}
+	data, err := json.MarshalIndent(opaData{Operations: ops}, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal bundle data: %v", err)
+	}
+
+	rego := &bytes.Buffer{}
+	if err := regoTemplate.Execute(rego, struct {
+		Destination
+		Package string
+	}{dest, pkg}); err != nil {
+		return nil, fmt.Errorf("failed to render rego policy: %v", err)
+	}
+
+	manifest, err := json.Marshal(opaManifest{
+		Revision: fmt.Sprintf("%x", sha256.Sum256(append(data, rego.Bytes()...))),
+		Roots:    []string{root},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal bundle manifest: %v", err)
+	}
+
+	out := &bytes.Buffer{}
+	gw := gzip.NewWriter(out)
+	tw := tar.NewWriter(gw)
+	for _, file := range []struct {
+		name    string
+		content []byte
+	}{
+		{name: ".manifest", content: manifest},
+		{name: path.Join(root, "data.json"), content: data},
+		{name: path.Join(root, "policy.rego"), content: rego.Bytes()},
+	} {
+		if err := tw.WriteHeader(&tar.Header{
+			Name:    "/" + file.name,
+			Mode:    0o644, // nolint:gomnd
+			Size:    int64(len(file.content)),
+			ModTime: time.Now(),
+		}); err != nil {
+			return nil, fmt.Errorf("failed to write bundle file header: %v", err)
+		}
+		if _, err := tw.Write(file.content); err != nil {
+			return nil, fmt.Errorf("failed to write bundle file: %v", err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return nil, fmt.Errorf("failed to close bundle tarball: %v", err)
+	}
+	if err := gw.Close(); err != nil {
+		return nil, fmt.Errorf("failed to close bundle gzip: %v", err)
+	}
+
+	return out.Bytes(), nil
+}
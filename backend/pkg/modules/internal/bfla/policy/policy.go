@@ -0,0 +1,138 @@
+// Copyright © 2022 Cisco Systems, Inc. and its affiliates.
+// All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package policy renders the authorized operations of a BFLA authorization model as enforceable policies.
+package policy
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/openclarity/apiclarity/backend/pkg/modules/internal/bfla/bfladetector"
+	"github.com/openclarity/apiclarity/backend/pkg/modules/internal/bfla/k8straceannotator"
+)
+
+const DefaultTrustDomain = "cluster.local"
+
+// Destination is the k8s service of the API the policy applies to.
+type Destination struct {
+	APIID     uint
+	Name      string
+	Namespace string
+	// Selector is the pod selector of the service.
+	Selector map[string]string
+}
+
+// Role is a learned role of a user, split into the JWT claim path and the role value.
+type Role struct {
+	ClaimPath []string `json:"claim_path"`
+	Value     string   `json:"value"`
+}
+
+// Source is an authorized caller of an operation.
+type Source struct {
+	External bool `json:"external"`
+	// Principal is the mesh identity of the source, in the "<trust domain>/ns/<namespace>/sa/<service account>" format.
+	Principal string `json:"principal,omitempty"`
+	// Namespace is set instead of the principal when the service account of the source is unknown.
+	Namespace string `json:"namespace,omitempty"`
+	// Roles are the authorized roles of the source end users, empty when the operation is not restricted by role.
+	Roles []Role `json:"roles"`
+}
+
+// Operation is an operation with its authorized sources.
+type Operation struct {
+	Method string `json:"method"`
+	// Path is the path template, e.g. "/users/{id}".
+	Path      string    `json:"path"`
+	PathRegex string    `json:"path_regex"`
+	Sources   []*Source `json:"sources"`
+}
+
+// AuthorizedOperations returns the operations of the model with the sources that are authorized to call them.
+// Operations without authorized sources are omitted.
+func AuthorizedOperations(model *bfladetector.AuthorizationModel, trustDomain string) []*Operation {
+	if trustDomain == "" {
+		trustDomain = DefaultTrustDomain
+	}
+
+	var ops []*Operation
+	for _, op := range model.Operations {
+		policyOp := &Operation{
+			Method:    strings.ToUpper(op.Method),
+			Path:      op.Path,
+			PathRegex: pathTemplateToRegex(op.Path),
+		}
+		for _, aud := range op.Audience {
+			if !aud.Authorized {
+				continue
+			}
+			source := &Source{External: aud.External, Roles: []Role{}}
+			if !aud.External && aud.K8sObject != nil {
+				source.Principal, source.Namespace = getPrincipal(aud.K8sObject, trustDomain)
+			}
+			for _, role := range aud.Roles {
+				if role.Authorized {
+					source.Roles = append(source.Roles, parseRole(role.Role))
+				}
+			}
+			// without roles the source would not be restricted by role, while none of its learned roles is authorized
+			if len(aud.Roles) > 0 && len(source.Roles) == 0 {
+				continue
+			}
+			policyOp.Sources = append(policyOp.Sources, source)
+		}
+		if len(policyOp.Sources) > 0 {
+			ops = append(ops, policyOp)
+		}
+	}
+
+	sort.SliceStable(ops, func(i, j int) bool {
+		if ops[i].Path != ops[j].Path {
+			return ops[i].Path < ops[j].Path
+		}
+		return ops[i].Method < ops[j].Method
+	})
+	return ops
+}
+
+func getPrincipal(obj *k8straceannotator.K8sObjectRef, trustDomain string) (principal, namespace string) {
+	if obj.ServiceAccount == "" {
+		return "", obj.Namespace
+	}
+	return fmt.Sprintf("%s/ns/%s/sa/%s", trustDomain, obj.Namespace, obj.ServiceAccount), ""
+}
+
+// parseRole splits a role in the "<claim path>:<value>" format of bfladetector.DetectedUser.
+func parseRole(role string) Role {
+	parts := strings.SplitN(role, ":", 2) // nolint:gomnd
+	if len(parts) != 2 {                  // nolint:gomnd
+		return Role{Value: role}
+	}
+	return Role{ClaimPath: strings.Split(parts[0], "."), Value: parts[1]}
+}
+
+var pathParamRegex = regexp.MustCompile(`{[^/}]*}`)
+
+// pathTemplateToRegex converts a path template like "/users/{id}" to a regex matching its paths.
+func pathTemplateToRegex(path string) string {
+	parts := pathParamRegex.Split(path, -1)
+	for i := range parts {
+		parts[i] = regexp.QuoteMeta(parts[i])
+	}
+	return "^" + strings.Join(parts, "[^/]+") + "$"
+}
@@ -0,0 +1,279 @@
+// Copyright © 2022 Cisco Systems, Inc. and its affiliates.
+// All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package policy
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/openclarity/apiclarity/backend/pkg/modules/internal/bfla/bfladetector"
+	"github.com/openclarity/apiclarity/backend/pkg/modules/internal/bfla/k8straceannotator"
+)
+
+func testModel() *bfladetector.AuthorizationModel {
+	frontend := &k8straceannotator.K8sObjectRef{Kind: "Deployment", Name: "frontend", Namespace: "sock-shop", Uid: "1", ServiceAccount: "frontend"}
+	orders := &k8straceannotator.K8sObjectRef{Kind: "Deployment", Name: "orders", Namespace: "sock-shop", Uid: "2"}
+	return &bfladetector.AuthorizationModel{
+		Operations: bfladetector.Operations{{
+			Method: "GET",
+			Path:   "/users/{id}",
+			Audience: bfladetector.Audience{
+				{K8sObject: frontend, Authorized: true},
+				{K8sObject: orders, Authorized: true},
+			},
+		}, {
+			Method: "DELETE",
+			Path:   "/users/{id}",
+			Audience: bfladetector.Audience{{
+				K8sObject:  frontend,
+				Authorized: true,
+				Roles: bfladetector.RolePermissions{
+					{Role: "groups:admins", Authorized: true},
+					{Role: "realm_access.roles:admin", Authorized: true},
+					{Role: "groups:users", Authorized: false},
+				},
+			}},
+		}, {
+			Method:   "POST",
+			Path:     "/login",
+			Audience: bfladetector.Audience{{External: true, Authorized: true}},
+		}, {
+			Method:   "POST",
+			Path:     "/users/{id}/reset",
+			Audience: bfladetector.Audience{{K8sObject: orders, Authorized: false}},
+		}},
+	}
+}
+
+func TestAuthorizedOperations(t *testing.T) {
+	want := []*Operation{{
+		Method:    "POST",
+		Path:      "/login",
+		PathRegex: "^/login$",
+		Sources:   []*Source{{External: true, Roles: []Role{}}},
+	}, {
+		Method:    "DELETE",
+		Path:      "/users/{id}",
+		PathRegex: "^/users/[^/]+$",
+		Sources: []*Source{{
+			Principal: "cluster.local/ns/sock-shop/sa/frontend",
+			Roles: []Role{
+				{ClaimPath: []string{"groups"}, Value: "admins"},
+				{ClaimPath: []string{"realm_access", "roles"}, Value: "admin"},
+			},
+		}},
+	}, {
+		Method:    "GET",
+		Path:      "/users/{id}",
+		PathRegex: "^/users/[^/]+$",
+		Sources: []*Source{
+			{Principal: "cluster.local/ns/sock-shop/sa/frontend", Roles: []Role{}},
+			{Namespace: "sock-shop", Roles: []Role{}},
+		},
+	}}
+
+	got := AuthorizedOperations(testModel(), "")
+	if !reflect.DeepEqual(got, want) {
+		gotJSON, _ := json.Marshal(got)
+		wantJSON, _ := json.Marshal(want)
+		t.Errorf("AuthorizedOperations() got = %s, want %s", gotJSON, wantJSON)
+	}
+	if !regexp.MustCompile(got[1].PathRegex).MatchString("/users/123") || regexp.MustCompile(got[1].PathRegex).MatchString("/users/123/reset") {
+		t.Errorf("unexpected path regex %s", got[1].PathRegex)
+	}
+}
+
+func TestAuthorizedOperations_AllRolesDenied(t *testing.T) {
+	frontend := &k8straceannotator.K8sObjectRef{Kind: "Deployment", Name: "frontend", Namespace: "sock-shop", Uid: "1", ServiceAccount: "frontend"}
+	model := &bfladetector.AuthorizationModel{
+		Operations: bfladetector.Operations{{
+			Method: "DELETE",
+			Path:   "/users/{id}",
+			Audience: bfladetector.Audience{{
+				K8sObject:  frontend,
+				Authorized: true,
+				Roles: bfladetector.RolePermissions{
+					{Role: "groups:users", Authorized: false},
+				},
+			}, {
+				External:   true,
+				Authorized: true,
+			}},
+		}, {
+			Method: "POST",
+			Path:   "/users",
+			Audience: bfladetector.Audience{{
+				K8sObject:  frontend,
+				Authorized: true,
+				Roles: bfladetector.RolePermissions{
+					{Role: "groups:users", Authorized: false},
+					{Role: "groups:guests", Authorized: false},
+				},
+			}},
+		}},
+	}
+	want := []*Operation{{
+		Method:    "DELETE",
+		Path:      "/users/{id}",
+		PathRegex: "^/users/[^/]+$",
+		Sources:   []*Source{{External: true, Roles: []Role{}}},
+	}}
+
+	got := AuthorizedOperations(model, "")
+	if !reflect.DeepEqual(got, want) {
+		gotJSON, _ := json.Marshal(got)
+		wantJSON, _ := json.Marshal(want)
+		t.Errorf("AuthorizedOperations() got = %s, want %s", gotJSON, wantJSON)
+	}
+}
+
+func TestIstioAuthorizationPolicy(t *testing.T) {
+	dest := Destination{APIID: 1, Name: "user", Namespace: "sock-shop", Selector: map[string]string{"name": "user"}}
+	got, err := IstioAuthorizationPolicy(AuthorizedOperations(testModel(), "mesh.local"), dest)
+	if err != nil {
+		t.Fatalf("IstioAuthorizationPolicy() error = %v", err)
+	}
+	want := `apiVersion: security.istio.io/v1beta1
+kind: AuthorizationPolicy
+metadata:
+  labels:
+    app.kubernetes.io/managed-by: apiclarity
+  name: apiclarity-bfla-user
+  namespace: sock-shop
+spec:
+  action: ALLOW
+  rules:
+  - to:
+    - operation:
+        methods:
+        - POST
+        paths:
+        - /login
+  - from:
+    - source:
+        principals:
+        - mesh.local/ns/sock-shop/sa/frontend
+    to:
+    - operation:
+        methods:
+        - DELETE
+        paths:
+        - /users/*
+    when:
+    - key: request.auth.claims[groups]
+      values:
+      - admins
+  - from:
+    - source:
+        principals:
+        - mesh.local/ns/sock-shop/sa/frontend
+    to:
+    - operation:
+        methods:
+        - DELETE
+        paths:
+        - /users/*
+    when:
+    - key: request.auth.claims[realm_access][roles]
+      values:
+      - admin
+  - from:
+    - source:
+        principals:
+        - mesh.local/ns/sock-shop/sa/frontend
+    to:
+    - operation:
+        methods:
+        - GET
+        paths:
+        - /users/*
+  - from:
+    - source:
+        namespaces:
+        - sock-shop
+    to:
+    - operation:
+        methods:
+        - GET
+        paths:
+        - /users/*
+  selector:
+    matchLabels:
+      name: user
+`
+	if string(got) != want {
+		t.Errorf("IstioAuthorizationPolicy() got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestOPABundle(t *testing.T) {
+	ops := AuthorizedOperations(testModel(), "")
+	got, err := OPABundle(ops, Destination{APIID: 7, Name: "user", Namespace: "sock-shop"})
+	if err != nil {
+		t.Fatalf("OPABundle() error = %v", err)
+	}
+
+	gr, err := gzip.NewReader(bytes.NewReader(got))
+	if err != nil {
+		t.Fatalf("failed to read bundle gzip: %v", err)
+	}
+	files := map[string][]byte{}
+	tr := tar.NewReader(gr)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("failed to read bundle tarball: %v", err)
+		}
+		content, err := io.ReadAll(tr)
+		if err != nil {
+			t.Fatalf("failed to read bundle file: %v", err)
+		}
+		files[hdr.Name] = content
+	}
+
+	manifest := opaManifest{}
+	if err := json.Unmarshal(files["/.manifest"], &manifest); err != nil {
+		t.Fatalf("failed to unmarshal manifest: %v", err)
+	}
+	if !reflect.DeepEqual(manifest.Roots, []string{"apiclarity/bfla/api_7"}) || manifest.Revision == "" {
+		t.Errorf("unexpected manifest: %+v", manifest)
+	}
+
+	data := opaData{}
+	if err := json.Unmarshal(files["/apiclarity/bfla/api_7/data.json"], &data); err != nil {
+		t.Fatalf("failed to unmarshal data: %v", err)
+	}
+	if !reflect.DeepEqual(data.Operations, ops) {
+		t.Errorf("unexpected bundle data: %s", files["/apiclarity/bfla/api_7/data.json"])
+	}
+
+	rego := string(files["/apiclarity/bfla/api_7/policy.rego"])
+	for _, want := range []string{"package apiclarity.bfla.api_7\n", "op := data.apiclarity.bfla.api_7.operations[_]"} {
+		if !strings.Contains(rego, want) {
+			t.Errorf("policy.rego does not contain %q:\n%s", want, rego)
+		}
+	}
+}
@@ -23,6 +23,7 @@ import (
 
 	"github.com/openclarity/apiclarity/backend/pkg/database"
 	"github.com/openclarity/apiclarity/backend/pkg/modules/internal/core"
+	pluginsmodels "github.com/openclarity/apiclarity/plugins/api/server/models"
 )
 
 const (
@@ -67,7 +68,7 @@ func (r *recovery) Resync(ctx context.Context, events chan *core.Event) (allEven
 					break loop
 				}
 
-				persistedEvents[event.ID] = &core.Event{APIEvent: event}
+				persistedEvents[event.ID] = &core.Event{APIEvent: event, Telemetry: r.getTelemetry(ctx, event.ID)}
 
 			case <-ctx.Done():
 				log.Error(ctx.Err())
@@ -119,6 +120,16 @@ func (r *recovery) getAPIEvents(ctx context.Context, filter database.GetAPIEvent
 	}
 }
 
+// getTelemetry returns the archived telemetry of the event, or nil if it is not available.
+func (r *recovery) getTelemetry(ctx context.Context, eventID uint) *pluginsmodels.Telemetry {
+	telemetry, err := r.accessor.GetAPIEventTelemetry(ctx, eventID)
+	if err != nil {
+		log.Warnf("failed to get the telemetry of event %d: %s", eventID, err)
+		return nil
+	}
+	return telemetry
+}
+
 func (r *recovery) getUnackedEvents(ctx context.Context) chan *database.APIEvent {
 	unackedEvents := make(chan *database.APIEvent)
 	ack := ack
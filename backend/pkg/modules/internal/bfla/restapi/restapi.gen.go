@@ -35,9 +35,15 @@ const (
 const (
 	DetectedUserSourceBASIC DetectedUserSource = "BASIC"
 
+	DetectedUserSourceCOOKIE DetectedUserSource = "COOKIE"
+
+	DetectedUserSourceHEADER DetectedUserSource = "HEADER"
+
 	DetectedUserSourceJWT DetectedUserSource = "JWT"
 
 	DetectedUserSourceKONGXCONSUMERID DetectedUserSource = "KONG_X_CONSUMER_ID"
+
+	DetectedUserSourceMTLS DetectedUserSource = "MTLS"
 )
 
 // Defines values for OperationEnum.
@@ -83,10 +89,11 @@ type AuthorizationModel struct {
 
 // AuthorizationModelAudience defines model for AuthorizationModelAudience.
 type AuthorizationModelAudience struct {
-	Authorized bool           `json:"authorized"`
-	EndUsers   []DetectedUser `json:"end_users"`
-	External   bool           `json:"external"`
-	K8sObject  *K8sObjectRef  `json:"k8s_object,omitempty"`
+	Authorized bool              `json:"authorized"`
+	EndUsers   []DetectedUser    `json:"end_users"`
+	External   bool              `json:"external"`
+	K8sObject  *K8sObjectRef     `json:"k8s_object,omitempty"`
+	Roles      *[]RolePermission `json:"roles,omitempty"`
 }
 
 // AuthorizationModelOperation defines model for AuthorizationModelOperation.
@@ -103,6 +110,7 @@ type BFLAStatus string
 type DetectedUser struct {
 	Id        string             `json:"id"`
 	IpAddress string             `json:"ip_address"`
+	Roles     *[]string          `json:"roles,omitempty"`
 	Source    DetectedUserSource `json:"source"`
 }
 
@@ -111,16 +119,23 @@ type DetectedUserSource string
 
 // K8sObjectRef defines model for K8sObjectRef.
 type K8sObjectRef struct {
-	ApiVersion string `json:"apiVersion"`
-	Kind       string `json:"kind"`
-	Name       string `json:"name"`
-	Namespace  string `json:"namespace"`
-	Uid        string `json:"uid"`
+	ApiVersion     string  `json:"apiVersion"`
+	Kind           string  `json:"kind"`
+	Name           string  `json:"name"`
+	Namespace      string  `json:"namespace"`
+	ServiceAccount *string `json:"serviceAccount,omitempty"`
+	Uid            string  `json:"uid"`
 }
 
 // OperationEnum defines model for OperationEnum.
 type OperationEnum string
 
+// RolePermission defines model for RolePermission.
+type RolePermission struct {
+	Authorized bool   `json:"authorized"`
+	Role       string `json:"role"`
+}
+
 // SpecType defines model for SpecType.
 type SpecType string
 
@@ -143,6 +158,18 @@ type PutAuthorizationModelApiIDDenyParams struct {
 	K8sClientUid string `json:"k8sClientUid"`
 }
 
+// GetAuthorizationModelApiIDExportIstioParams defines parameters for GetAuthorizationModelApiIDExportIstio.
+type GetAuthorizationModelApiIDExportIstioParams struct {
+	// The trust domain of the mesh, used in the source principals (default cluster.local)
+	TrustDomain *string `json:"trustDomain,omitempty"`
+}
+
+// GetAuthorizationModelApiIDExportOpaParams defines parameters for GetAuthorizationModelApiIDExportOpa.
+type GetAuthorizationModelApiIDExportOpaParams struct {
+	// The trust domain of the mesh, used in the source principals (default cluster.local)
+	TrustDomain *string `json:"trustDomain,omitempty"`
+}
+
 // PutAuthorizationModelApiIDLearningResetParams defines parameters for PutAuthorizationModelApiIDLearningReset.
 type PutAuthorizationModelApiIDLearningResetParams struct {
 	NrTraces int `json:"nr_traces"`
@@ -164,6 +191,12 @@ type ServerInterface interface {
 
 	// (PUT /authorizationModel/{apiID}/deny)
 	PutAuthorizationModelApiIDDeny(w http.ResponseWriter, r *http.Request, apiID int, params PutAuthorizationModelApiIDDenyParams)
+	// Export the authorized operations of the authorization model as an Istio AuthorizationPolicy
+	// (GET /authorizationModel/{apiID}/export/istio)
+	GetAuthorizationModelApiIDExportIstio(w http.ResponseWriter, r *http.Request, apiID int, params GetAuthorizationModelApiIDExportIstioParams)
+	// Export the authorized operations of the authorization model as an Open Policy Agent bundle
+	// (GET /authorizationModel/{apiID}/export/opa)
+	GetAuthorizationModelApiIDExportOpa(w http.ResponseWriter, r *http.Request, apiID int, params GetAuthorizationModelApiIDExportOpaParams)
 
 	// (PUT /authorizationModel/{apiID}/learning/reset)
 	PutAuthorizationModelApiIDLearningReset(w http.ResponseWriter, r *http.Request, apiID int, params PutAuthorizationModelApiIDLearningResetParams)
@@ -361,6 +394,86 @@ func (siw *ServerInterfaceWrapper) PutAuthorizationModelApiIDDeny(w http.Respons
 	handler(w, r.WithContext(ctx))
 }
 
+// GetAuthorizationModelApiIDExportIstio operation middleware
+func (siw *ServerInterfaceWrapper) GetAuthorizationModelApiIDExportIstio(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "apiID" -------------
+	var apiID int
+
+	err = runtime.BindStyledParameter("simple", false, "apiID", chi.URLParam(r, "apiID"), &apiID)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "apiID", Err: err})
+		return
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetAuthorizationModelApiIDExportIstioParams
+
+	// ------------- Optional query parameter "trustDomain" -------------
+	if paramValue := r.URL.Query().Get("trustDomain"); paramValue != "" {
+
+	}
+
+	err = runtime.BindQueryParameter("form", true, false, "trustDomain", r.URL.Query(), &params.TrustDomain)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "trustDomain", Err: err})
+		return
+	}
+
+	var handler = func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetAuthorizationModelApiIDExportIstio(w, r, apiID, params)
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler(w, r.WithContext(ctx))
+}
+
+// GetAuthorizationModelApiIDExportOpa operation middleware
+func (siw *ServerInterfaceWrapper) GetAuthorizationModelApiIDExportOpa(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "apiID" -------------
+	var apiID int
+
+	err = runtime.BindStyledParameter("simple", false, "apiID", chi.URLParam(r, "apiID"), &apiID)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "apiID", Err: err})
+		return
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetAuthorizationModelApiIDExportOpaParams
+
+	// ------------- Optional query parameter "trustDomain" -------------
+	if paramValue := r.URL.Query().Get("trustDomain"); paramValue != "" {
+
+	}
+
+	err = runtime.BindQueryParameter("form", true, false, "trustDomain", r.URL.Query(), &params.TrustDomain)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "trustDomain", Err: err})
+		return
+	}
+
+	var handler = func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetAuthorizationModelApiIDExportOpa(w, r, apiID, params)
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler(w, r.WithContext(ctx))
+}
+
 // PutAuthorizationModelApiIDLearningReset operation middleware
 func (siw *ServerInterfaceWrapper) PutAuthorizationModelApiIDLearningReset(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
@@ -671,6 +784,12 @@ func HandlerWithOptions(si ServerInterface, options ChiServerOptions) http.Handl
 	r.Group(func(r chi.Router) {
 		r.Put(options.BaseURL+"/authorizationModel/{apiID}/deny", wrapper.PutAuthorizationModelApiIDDeny)
 	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/authorizationModel/{apiID}/export/istio", wrapper.GetAuthorizationModelApiIDExportIstio)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/authorizationModel/{apiID}/export/opa", wrapper.GetAuthorizationModelApiIDExportOpa)
+	})
 	r.Group(func(r chi.Router) {
 		r.Put(options.BaseURL+"/authorizationModel/{apiID}/learning/reset", wrapper.PutAuthorizationModelApiIDLearningReset)
 	})
@@ -696,26 +815,30 @@ func HandlerWithOptions(si ServerInterface, options ChiServerOptions) http.Handl
 // Base64 encoded, gzipped, json marshaled Swagger object
 var swaggerSpec = []string{
 
-	"H4sIAAAAAAAC/+xY3W7jNhN9FYLfd0nEafdm4TutrWbVXf/AstMCi8BgpHHMjUxqSSqta/jdC1KSRdm0",
-	"42ySol3kKpY4PDNz5sxQ4QYnYpULDlwr3N1glSxhRe3PYByFD8B1wLnQVDPB7etcihykZmCfbhcZjTXV",
-	"hX36v4QF7uL/dRrQToXY+fDL56Cy3BKcgtKMW9RP79Xo9isk+jGEneEEFiWGhkRDOlMgH9vbd223BMOf",
-	"GiSnmdmn1zngLr4VIgPKzaoShUzgOwPbEizhW8EkpLj7xWXIcXuzJTjI2QRULrgC4yEFlUiWG0pwFwcc",
-	"CYuJ9JJqxBSSoAvJEeOIZhlKqAKFxAItKMsKCeoCk73arEApegdOikpLxu8OQqwNb0htWLo2VASFXgrJ",
-	"/rKlGogUskMRZEAlN8BeMo1lIx+mYfWoVg6djmoQg1j5oFLStS1XDsnUvjsNG9d2+wTsAFrRkiax85gJ",
-	"ipQBT+CQIVrZGnc+joCn80KBPJ+ifUHvc3Ja4Pfv1Vw8X9tN2MRN0dX5WcQ11fUw13D6ndrZlcVD0wr0",
-	"UqSeFiE4p3r5eO9Yqx0OaeL1Ze7MwO4GAy9WBmI4msfjsIcJ/hwGk2E0vLI/r6JpNAimISY4nsXjqBeN",
-	"ZvF8EPaj2aD97mN09dHx16TQ35uQbWqZP2+Wz2maSlDKu1zORjf+X3+bYoI/BHFkcvg0Gl7Nf5/3RsN4",
-	"Nggn86jvCW2PRGaYq4BbAZgx2VLgoT5ydg1SVdo5iPaecX+WnK7g6ILKaeJfLbyk7aVT2Hys68qRC0vc",
-	"mH0q2XVDaAluiKZ5LsWDQUiBry2QfWF7sHpb/vaJIXamZCO9odHXeDK6jvphHxM8CU3lppNZbxr2vTgO",
-	"3e1SPBytwx4/D0eTN5aML4TnPBxHvYxKptdoINIiAxSMI0ywZjqD9rrpMkyacPDlxeXFT9VRxGnOcBe/",
-	"u7i8eIfLJrfBd+jB3OhsaM6i/tYs34GdlLvTIUpxF1+B9kwbs8ciS7oCbWf6lw1mJpBqWJTaw7SybKjR",
-	"sgBSfYM5NDKu4c6M+e2NsS4/GWzUP19emj+J4Bq4Lvshz1hiw+l8VWU1GsCnDc6yHu06xEWSmM60Cwta",
-	"ZPrlAnC+hzyeQymFRNKx2JJTZevU7WKEWnjKNy6OlS/YNdr5VTxRNVJt/FaAXDc7d2fGowJo+siPVEX0",
-	"bJz796qXMeB6xp4W16vq8rQs/kOCtFP76Wrsl8P+TYpvUnwxKdb/2XQkqPJoe6IoP1cAE7v/ddXJ5VxL",
-	"moD6Fx2WP54SlKbyOUqI7f43JfwIShD5s4Qg8pfSwVvldpWDB+C6s2HpyX9I7JXtWeyfd67+Q6Xw3DV7",
-	"iAnGEbJ26A+ml4i2rAlWxWpF5bokAuklIGiMzaOzAVGeottFRpGqLqXbHHc2O2q3p3rBhhOlzQ3Wa1BP",
-	"vCjC8Xkc7BTt7ZuGt25zus25zKharb2rVlhlh8QC6SVT1eWEe5Fcd2Z9b/KKHNcuPFleu3FCHaa/a47k",
-	"ZJj5OwAA//+E60MNMBoAAA==",
+	"H4sIAAAAAAAC/+xZXW/buBL9KwTvfbgXIOLc25fCb6qtdbWNY8Mf3QWKwmCkccxWIlmSytY1/N8XpGTr",
+	"w4ztpMmi281TInE4nDnnDIemNjgWmRQcuNG4u8E6XkFG3b/BOArvgJuAc2GoYYK711IJCcowcE83y5RO",
+	"DTW5e/q3giXu4n91Kqed0mPnzS9XQWm5JTgBbRh3Xt+91qObTxCbUx72hhNYFj4MxAaSuQZ1am6/brsl",
+	"GL4aUJymdp5ZS8BdfCNECpTbUS1yFcMjA9sSrOBLzhQkuPuhjlBt2Y9bggPJJqCl4BrsCgnoWDFpIcFd",
+	"HHAknE9kVtQgppECkyuOGEc0TVFMNWgklmhJWZor0BeYtLjJQGt6C7UUtVGM3x6EuDP8SHaGxdIWiiA3",
+	"K6HYN0fVUCSQHoogBaq4dewF01pW8mEGspNaOVx0tHNiPZZrUKXo2j5rCfHMvTvudrqzawOwd9CIllSJ",
+	"nYdMkCcMeAyHCNHSFhI/RsCTRa5BnQ9RW9BtTI4L/PNrvRCPKjolUjg/yolIYQwqY1p7uWvxUMFA6pDV",
+	"6+YsIiq1eJioOHqkFvc0e2DPwKxE4ik5giU1q9O16Kz2fkgVry/z2p7a3WDgeWZdXI8W03HYwwRfhcHk",
+	"OroeuH8H0SwaBrMQEzydT8dRLxrNp4th2I/mw+a7t9HgbW29KoV+a8dtQsv8eTO5oEmiQGvv8KGgDkwO",
+	"6t1tz/WUf/1thgl+E0wjm/a70fVg8fuiN7qezofhZBH1McFvw6AfTjDBvdHoXWRRGM6upp40W4SwBO9X",
+	"bCRjt/BGdRxqTbL3oHSpw4O0PjPuR4zTDO4d0JLG/lEN6o7FEMSxyLnxmuRejloZ5y5lF10ZS31lUk/L",
+	"J8p98YWOnIokKqUSd9ZDAnyNye6FK/nybfG/T3utreTBW6zV2encnVVj8/GlOK01nKrqrq2oxpPR+6gf",
+	"WsVNQqvA2WTem4V9b041dTSTubtXNq147+4lwloyvhSeo8U46qVUMbNGQ5HkKaBgHGGCDTMpNMftBoNJ",
+	"FQ6+vLi8+F/Z1TmVDHfxq4vLi1e42N9c8B16sGV2NlSyqL+1w7fgtLlvtFGCu3gAxrPR2jnOs6IZGNce",
+	"P2wws4GU+2RRKpiWlhU0RuVAyuNsDUbGDdyCwtvtR2tdnL5c1P+/vLR/YsENFNVDpUxZ7MLpfNIFG5XD",
+	"h/WMgo8mD9M8jkGXx+ElzVPzdAHUjpaelUOlhEKqZrElx2jr7ErXCjX30DfO76Mv2Bf9+SweYY2UE7/k",
+	"oNbVzH27PCmAqo78nsqIvtvP59e6lzLgZs4eFtez6vK4LP5GgnQd5OFq7BeN50WKL1J8MinCVymU6TBt",
+	"mHhEfwvd9MjNfqZWR9ongNkKkFG5NigRGWXc3iOYFaAM9IqgXENirxnsm+Lgi6RiPGaSphr9p+QDxWmu",
+	"DaiLVMQ0/S8mXu25VfpuEfx0UlvTLG0S3vb3IwuKYJ1nGVVrO+jYd1BXZ05UXULsmGnoD2VWPYhqRDly",
+	"ykENcY1FyuI1PlO4QtJHy3Yk6YtozxTt7Tcmm6JaCpVRY3+mME5dAP9cGY8kcFQIFwW3wA26yXmSwkkV",
+	"7+7oOgo0mEecCa5KBxM3/3kPB1wtjKIx6B/ot8pP04j3StCGqu9RwtTNf1HCz6AEIb9LCEI+lQ5emNsz",
+	"B/azYmfDkqP3Qe7j41non/ez5i+iwvPV1ANMMI6Qs0N/MLNCtGFd76wDKNoqVMb2sTYBUZ4g+5UR6fLz",
+	"ahPjzmYP7fZYLbhwoqT6dvIc0BOvF1Fb835nx2BvXjq/VFut2mp3yWWpNWftFFbaFUc1psu7YUxaShmA",
+	"2V1bPyPGuyU8Wb6vxwm7MP1Vc09OFpk/BwBtSvR7+iAAAA==",
 }
 
 // GetSwagger returns the content of the embedded swagger specification file
@@ -0,0 +1,453 @@
+// Copyright © 2022 Cisco Systems, Inc. and its affiliates.
+// All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bola
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+
+	"github.com/openclarity/apiclarity/backend/pkg/database"
+	"github.com/openclarity/apiclarity/backend/pkg/modules/internal/bfla/bfladetector"
+	"github.com/openclarity/apiclarity/backend/pkg/modules/internal/bfla/recovery"
+	"github.com/openclarity/apiclarity/backend/pkg/modules/internal/bola/restapi"
+	"github.com/openclarity/apiclarity/backend/pkg/modules/internal/core"
+	"github.com/openclarity/apiclarity/backend/pkg/modules/internal/traceanalyzer/utils"
+	pluginsmodels "github.com/openclarity/apiclarity/plugins/api/server/models"
+)
+
+const (
+	ModuleName          = "bola"
+	moduleVersion       = "0.0.0"
+	persistenceInterval = 30 * time.Second
+
+	// AccessAnnotationName is the annotation of the events with the objects accessed by the user, it's not a finding.
+	// It's written when the user is the first user of one of the objects or on a violation, the events the approve
+	// and deny operations apply to, not on each access.
+	AccessAnnotationName = "accessed_objects"
+	// ViolationAnnotationName is the annotation of the events accessing objects of other users.
+	ViolationAnnotationName = "BOLA_VIOLATION"
+	// objectsStateAnnotationName is the API annotation of the learnt objects, it's not a finding.
+	objectsStateAnnotationName = "objects"
+
+	// userIdentityExtractorsEnvVar is shared with the bfla module, see bfladetector.ParseUserIdentityExtractors.
+	userIdentityExtractorsEnvVar = "BFLA_USER_IDENTITY_EXTRACTORS"
+)
+
+type bola struct {
+	httpHandler http.Handler
+	accessor    core.BackendAccessor
+	apis        *apisObjects
+
+	userIdentityExtractors bfladetector.UserIdentityExtractors
+}
+
+func (p *bola) Name() string              { return ModuleName }
+func (p *bola) HTTPHandler() http.Handler { return p.httpHandler }
+
+func newModule(ctx context.Context, accessor core.BackendAccessor) (core.Module, error) {
+	extractors, err := bfladetector.ParseUserIdentityExtractors(viper.GetString(userIdentityExtractorsEnvVar))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse user identity extractors: %w", err)
+	}
+
+	sp := recovery.NewStatePersister(ctx, accessor, ModuleName, persistenceInterval)
+	p := &bola{
+		accessor:               accessor,
+		apis:                   newAPIsObjects(sp),
+		userIdentityExtractors: extractors,
+	}
+	handler := &httpHandler{accessor: accessor, apis: p.apis}
+	p.httpHandler = restapi.HandlerWithOptions(handler, restapi.ChiServerOptions{BaseURL: core.BaseHTTPPath + "/" + ModuleName})
+	return p, nil
+}
+
+// apiObjects are the learnt objects of an API.
+type apiObjects struct {
+	objects *APIObjects
+
+	// nil when the objects are not persisted
+	state recovery.PersistedValue
+}
+
+// learnt marks the objects as changed, they are marshaled when the persister checkpoints them.
+func (a *apiObjects) learnt() {
+	if a.state != nil {
+		a.state.Set(a.objects)
+	}
+}
+
+// apisObjects holds the learnt objects of each API. With a state persister, the objects are restored from the
+// API annotations the first time an API is seen, and checkpointed periodically.
+type apisObjects struct {
+	lock   sync.Mutex
+	apis   map[uint]*apiObjects
+	states recovery.PersistedMap
+}
+
+func newAPIsObjects(sp recovery.StatePersister) *apisObjects {
+	o := &apisObjects{
+		apis: map[uint]*apiObjects{},
+	}
+	if sp != nil {
+		o.states = recovery.NewPersistedMap(sp, objectsStateAnnotationName, reflect.TypeOf(&APIObjects{}))
+	}
+	return o
+}
+
+func (o *apisObjects) get(apiID uint) *apiObjects {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+
+	if a, ok := o.apis[apiID]; ok {
+		return a
+	}
+
+	a := &apiObjects{objects: NewAPIObjects()}
+	if o.states != nil {
+		state, err := o.states.Get(apiID)
+		if err != nil {
+			log.Warnf("[BOLA] unable to restore the objects of API %d, they won't be persisted: %v", apiID, err)
+		} else {
+			if restored, ok := state.Get().(*APIObjects); state.Exists() && ok && restored != nil {
+				a.objects = restored
+			}
+			a.state = state
+		}
+	}
+	o.apis[apiID] = a
+
+	return a
+}
+
+func (p *bola) EventNotify(ctx context.Context, event *core.Event) {
+	if err := p.eventNotify(ctx, event); err != nil {
+		log.Errorf("[BOLA] EventNotify: %s", err)
+	}
+}
+
+func (p *bola) eventNotify(ctx context.Context, event *core.Event) error {
+	apiEvent, trace := event.APIEvent, event.Telemetry
+	// only the successful accesses are learnt, the API rejected the others
+	if trace == nil || trace.Request == nil || trace.Request.Common == nil || apiEvent.StatusCode < 200 || apiEvent.StatusCode >= 300 {
+		return nil
+	}
+	user, err := p.userIdentityExtractors.GetUserID(convertHeadersToMap(trace.Request.Common.Headers))
+	if err != nil {
+		log.Debugf("[BOLA] unable to detect the user of event %d: %v", apiEvent.ID, err)
+	}
+	if user == nil {
+		return nil
+	}
+
+	apiInfo, err := p.accessor.GetAPIInfo(ctx, apiEvent.APIInfoID)
+	if err != nil {
+		return fmt.Errorf("unable to get API %d: %w", apiEvent.APIInfoID, err)
+	}
+	refs := GetObjectRefs(bfladetector.ResolvePath(apiInfo, apiEvent), apiEvent.Path, utils.GetRequestParams(trace, nil))
+	if len(refs) == 0 {
+		return nil
+	}
+
+	objects := p.apis.get(apiEvent.APIInfoID)
+	violation, learnt := objects.objects.Access(user.ID, apiEvent.ID, refs)
+	if learnt {
+		objects.learnt()
+	}
+	if violation == nil && !learnt {
+		return nil
+	}
+
+	anns := []core.Annotation{}
+	access := core.Annotation{Name: AccessAnnotationName}
+	if access.Annotation, err = json.Marshal(Access{User: user.ID, Objects: refs}); err != nil {
+		return fmt.Errorf("unable to marshal the accessed objects: %w", err)
+	}
+	anns = append(anns, access)
+	if violation != nil {
+		violationAnns, err := getViolationAnnotations(violation)
+		if err != nil {
+			return err
+		}
+		anns = append(anns, violationAnns...)
+	}
+	if err := p.accessor.CreateAPIEventAnnotations(ctx, ModuleName, apiEvent.ID, anns...); err != nil {
+		return fmt.Errorf("failed to create event annotations: %w", err)
+	}
+	return nil
+}
+
+// getViolationAnnotations returns the violation annotation with its alert, critical if the user was denied
+// access to the objects.
+func getViolationAnnotations(violation *Violation) ([]core.Annotation, error) {
+	ann := core.Annotation{Name: ViolationAnnotationName}
+	var err error
+	if ann.Annotation, err = json.Marshal(violation); err != nil {
+		return nil, fmt.Errorf("unable to marshal the violation: %w", err)
+	}
+	alert := core.AlertWarnAnn
+	if violation.Denied {
+		alert = core.AlertCriticalAnn
+	}
+	return []core.Annotation{ann, alert}, nil
+}
+
+func convertHeadersToMap(headers []*pluginsmodels.Header) http.Header {
+	httpheaders := http.Header{}
+	for _, h := range headers {
+		httpheaders.Add(h.Key, h.Value)
+	}
+	return httpheaders
+}
+
+// getBOLAAnnotations returns the accessed objects and the violation of the event, nil if it has none.
+func getBOLAAnnotations(ctx context.Context, accessor core.BackendAccessor, eventID uint) (access *Access, violation *Violation, err error) {
+	anns, err := accessor.ListAPIEventAnnotations(ctx, ModuleName, eventID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to get annotations for event=%d; %v", eventID, err)
+	}
+	for _, ann := range anns {
+		switch ann.Name {
+		case AccessAnnotationName:
+			access = &Access{}
+			if err := json.Unmarshal(ann.Annotation, access); err != nil {
+				return nil, nil, fmt.Errorf("unable to unmarshal the accessed objects of event=%d; %v", eventID, err)
+			}
+		case ViolationAnnotationName:
+			violation = &Violation{}
+			if err := json.Unmarshal(ann.Annotation, violation); err != nil {
+				return nil, nil, fmt.Errorf("unable to unmarshal the violation of event=%d; %v", eventID, err)
+			}
+		}
+	}
+	return access, violation, nil
+}
+
+// DescribeEventFinding describes the violations, the accessed objects are what the module learns from.
+func (p *bola) DescribeEventFinding(event *database.APIEvent, ann core.Annotation) (core.Finding, bool) {
+	if ann.Name != ViolationAnnotationName {
+		return core.Finding{}, false
+	}
+	violation := &Violation{}
+	if err := json.Unmarshal(ann.Annotation, violation); err != nil {
+		log.Warnf("[BOLA] invalid violation of event %d: %v", event.ID, err)
+		return core.Finding{}, false
+	}
+
+	objects := make([]string, 0, len(violation.Objects))
+	for _, o := range violation.Objects {
+		owners := make([]string, 0, len(o.Owners))
+		for _, owner := range o.Owners {
+			owners = append(owners, fmt.Sprintf("'%s' (event %d)", owner.User, owner.EventID))
+		}
+		objects = append(objects, fmt.Sprintf("%s '%s' of %s accessed by %s", o.Name, o.ID, o.Resource, strings.Join(owners, ", ")))
+	}
+	f := core.Finding{
+		Name: "Broken object level authorization",
+		Description: fmt.Sprintf("The user '%s' called %s %s on objects so far accessed only by other users: %s",
+			violation.User, event.Method, event.Path, strings.Join(objects, "; ")),
+		Severity: core.FindingSeverityHigh,
+	}
+	if violation.Denied {
+		f.Description += ", the user was denied access to them"
+		f.Severity = core.FindingSeverityCritical
+	}
+	return f, true
+}
+
+// DescribeAPIFinding returns false, the annotations of the APIs are the learnt objects.
+func (p *bola) DescribeAPIFinding(apiID uint, ann core.Annotation) (core.Finding, bool) {
+	return core.Finding{}, false
+}
+
+type httpHandler struct {
+	accessor core.BackendAccessor
+	apis     *apisObjects
+}
+
+func (h httpHandler) GetVersion(w http.ResponseWriter, r *http.Request) {
+	httpResponse(w, http.StatusOK, &restapi.Version{Version: moduleVersion})
+}
+
+func (h httpHandler) GetObjects(w http.ResponseWriter, r *http.Request, apiID int) {
+	if _, err := h.accessor.GetAPIInfo(r.Context(), uint(apiID)); err != nil {
+		httpResponse(w, http.StatusNotFound, &restapi.ApiResponse{Message: err.Error()})
+		return
+	}
+
+	objects := h.apis.get(uint(apiID)).objects.Objects()
+	res := restapi.Objects{Total: len(objects), Items: make([]restapi.Object, 0, len(objects))}
+	for _, o := range objects {
+		item := restapi.Object{Key: o.Key, Users: toRestapiObjectUsers(o.Users)}
+		if len(o.Denied) > 0 {
+			denied := o.Denied
+			item.DeniedUsers = &denied
+		}
+		if o.Shared {
+			shared := true
+			item.Shared = &shared
+		}
+		res.Items = append(res.Items, item)
+	}
+	httpResponse(w, http.StatusOK, res)
+}
+
+func (h httpHandler) GetEvent(w http.ResponseWriter, r *http.Request, eventID int) {
+	if _, ok := h.getEvent(w, r, eventID); !ok {
+		return
+	}
+	access, violation, err := getBOLAAnnotations(r.Context(), h.accessor, uint(eventID))
+	if err != nil {
+		httpResponse(w, http.StatusBadRequest, &restapi.ApiResponse{Message: err.Error()})
+		return
+	}
+
+	e := restapi.APIEventObjects{BolaStatus: restapi.BOLAStatusNOOBJECTS, Objects: []restapi.ObjectAccess{}}
+	if access == nil {
+		httpResponse(w, http.StatusOK, e)
+		return
+	}
+	e.BolaStatus = restapi.BOLAStatusLEGITIMATE
+	user := access.User
+	e.User = &user
+
+	owners := map[string][]ObjectUser{}
+	if violation != nil {
+		e.BolaStatus = restapi.BOLAStatusVIOLATION
+		if violation.Denied {
+			e.BolaStatus = restapi.BOLAStatusDENIED
+		}
+		for _, o := range violation.Objects {
+			owners[o.Key] = o.Owners
+		}
+	}
+	for _, ref := range access.Objects {
+		o := restapi.ObjectAccess{Key: ref.Key, Resource: ref.Resource, In: restapi.ObjectAccessIn(ref.In), Name: ref.Name, Id: ref.ID}
+		if objectOwners, ok := owners[ref.Key]; ok {
+			restOwners := toRestapiObjectUsers(objectOwners)
+			o.Owners = &restOwners
+		}
+		e.Objects = append(e.Objects, o)
+	}
+	httpResponse(w, http.StatusOK, e)
+}
+
+// nolint:stylecheck,revive
+func (h httpHandler) PutEventIdOperation(w http.ResponseWriter, r *http.Request, eventID int, operation restapi.OperationEnum) {
+	apiEvent, ok := h.getEvent(w, r, eventID)
+	if !ok {
+		return
+	}
+	ctx := r.Context()
+	access, violation, err := getBOLAAnnotations(ctx, h.accessor, uint(eventID))
+	if err != nil {
+		log.Error(err)
+		httpResponse(w, http.StatusBadRequest, &restapi.ApiResponse{Message: err.Error()})
+		return
+	}
+	if access == nil {
+		httpResponse(w, http.StatusNotFound, &restapi.ApiResponse{Message: fmt.Sprintf("no object accessed by a detected user in event with id: %d", eventID)})
+		return
+	}
+
+	log.Infof("apply %s operation on event=%d", operation, eventID)
+	objects := h.apis.get(apiEvent.APIInfoID)
+	switch operation {
+	case restapi.OperationEnumApprove:
+		objects.objects.Approve(access.User, uint(eventID), access.Objects)
+		// the access is legitimate, the event is no longer a violation
+		err = h.accessor.DeleteAPIEventAnnotations(ctx, ModuleName, uint(eventID), ViolationAnnotationName, core.AlertAnnotation)
+	case restapi.OperationEnumDeny:
+		objects.objects.Deny(access.User, access.Objects)
+		if violation != nil && !violation.Denied {
+			violation.Denied = true
+			err = h.replaceViolation(ctx, uint(eventID), violation)
+		}
+	default:
+		httpResponse(w, http.StatusBadRequest, &restapi.ApiResponse{Message: fmt.Sprintf("unknown operation: %s", operation)})
+		return
+	}
+	objects.learnt()
+	if err != nil {
+		log.Error(err)
+		httpResponse(w, http.StatusInternalServerError, &restapi.ApiResponse{Message: err.Error()})
+		return
+	}
+	h.accessor.FindingsChanged(apiEvent.APIInfoID)
+
+	log.Infof("%s operation applied successfully on event=%d", operation, eventID)
+	httpResponse(w, http.StatusOK, &restapi.ApiResponse{Message: fmt.Sprintf("Requested %s operation on api event", operation)})
+}
+
+// replaceViolation stores the violation of the event again with its alert, the annotations can't be updated.
+func (h httpHandler) replaceViolation(ctx context.Context, eventID uint, violation *Violation) error {
+	anns, err := getViolationAnnotations(violation)
+	if err != nil {
+		return err
+	}
+	if err := h.accessor.DeleteAPIEventAnnotations(ctx, ModuleName, eventID, ViolationAnnotationName, core.AlertAnnotation); err != nil {
+		return fmt.Errorf("failed to delete the violation: %w", err)
+	}
+	if err := h.accessor.CreateAPIEventAnnotations(ctx, ModuleName, eventID, anns...); err != nil {
+		return fmt.Errorf("failed to create the violation: %w", err)
+	}
+	return nil
+}
+
+// getEvent returns the event, on failure it writes the error response and returns false.
+func (h httpHandler) getEvent(w http.ResponseWriter, r *http.Request, eventID int) (*database.APIEvent, bool) {
+	uEventID := uint32(eventID)
+	events, err := h.accessor.GetAPIEvents(r.Context(), database.GetAPIEventsQuery{EventID: &uEventID})
+	if err != nil {
+		httpResponse(w, http.StatusBadRequest, &restapi.ApiResponse{Message: err.Error()})
+		return nil, false
+	}
+	if len(events) == 0 {
+		httpResponse(w, http.StatusNotFound, &restapi.ApiResponse{Message: fmt.Sprintf("not found event with id: %d", eventID)})
+		return nil, false
+	}
+	return events[0], true
+}
+
+func toRestapiObjectUsers(users []ObjectUser) []restapi.ObjectUser {
+	res := make([]restapi.ObjectUser, 0, len(users))
+	for _, u := range users {
+		res = append(res, restapi.ObjectUser{User: u.User, EventID: uint32(u.EventID)})
+	}
+	return res
+}
+
+func httpResponse(w http.ResponseWriter, code int, v interface{}) {
+	w.WriteHeader(code)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Error(err)
+		http.Error(w, err.Error(), code)
+		return
+	}
+}
+
+//nolint:gochecknoinits
+func init() {
+	core.RegisterModule(newModule)
+}
@@ -0,0 +1,137 @@
+// Copyright © 2022 Cisco Systems, Inc. and its affiliates.
+// All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bola
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+
+	"github.com/openclarity/apiclarity/backend/pkg/database"
+	"github.com/openclarity/apiclarity/backend/pkg/modules/internal/bfla/bfladetector"
+	"github.com/openclarity/apiclarity/backend/pkg/modules/internal/bola/restapi"
+	"github.com/openclarity/apiclarity/backend/pkg/modules/internal/core"
+	pluginsmodels "github.com/openclarity/apiclarity/plugins/api/server/models"
+)
+
+func newEvent(eventID uint, user string, statusCode int64) *core.Event {
+	return &core.Event{
+		APIEvent: &database.APIEvent{ID: eventID, APIInfoID: 1, Method: "GET", Path: "/orders", StatusCode: statusCode},
+		Telemetry: &pluginsmodels.Telemetry{
+			Request: &pluginsmodels.Request{
+				Method: "GET",
+				Path:   "/orders?orderId=42",
+				Common: &pluginsmodels.Common{Headers: []*pluginsmodels.Header{{Key: "X-Customer-ID", Value: user}}},
+			},
+		},
+	}
+}
+
+func TestBOLA(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	accessor := core.NewMockBackendAccessor(mockCtrl)
+	ctx := context.Background()
+
+	p := &bola{accessor: accessor, apis: newAPIsObjects(nil), userIdentityExtractors: bfladetector.DefaultUserIdentityExtractors()}
+	h := httpHandler{accessor: accessor, apis: p.apis}
+
+	stored := map[uint][]*core.Annotation{}
+	accessor.EXPECT().GetAPIInfo(gomock.Any(), uint(1)).Return(&database.APIInfo{}, nil).AnyTimes()
+	accessor.EXPECT().CreateAPIEventAnnotations(gomock.Any(), ModuleName, gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ string, eventID uint, anns ...core.Annotation) error {
+			for i := range anns {
+				stored[eventID] = append(stored[eventID], &anns[i])
+			}
+			return nil
+		}).AnyTimes()
+	accessor.EXPECT().ListAPIEventAnnotations(gomock.Any(), ModuleName, gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ string, eventID uint) ([]*core.Annotation, error) {
+			return stored[eventID], nil
+		}).AnyTimes()
+	accessor.EXPECT().DeleteAPIEventAnnotations(gomock.Any(), ModuleName, gomock.Any(), ViolationAnnotationName, core.AlertAnnotation).DoAndReturn(
+		func(_ context.Context, _ string, eventID uint, _ ...string) error {
+			stored[eventID] = stored[eventID][:1]
+			return nil
+		}).AnyTimes()
+	accessor.EXPECT().GetAPIEvents(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, filter database.GetAPIEventsQuery) ([]*database.APIEvent, error) {
+			return []*database.APIEvent{{ID: uint(*filter.EventID), APIInfoID: 1}}, nil
+		}).AnyTimes()
+
+	// the deny and the approve operations change the findings of the API
+	accessor.EXPECT().FindingsChanged(uint(1)).Times(2)
+
+	getStatus := func(eventID int) restapi.APIEventObjects {
+		w := httptest.NewRecorder()
+		h.GetEvent(w, httptest.NewRequest(http.MethodGet, "/", nil), eventID)
+		var e restapi.APIEventObjects
+		if err := json.NewDecoder(w.Body).Decode(&e); err != nil {
+			t.Fatalf("invalid event: %v", err)
+		}
+		return e
+	}
+	applyOperation := func(eventID int, operation restapi.OperationEnum) {
+		w := httptest.NewRecorder()
+		h.PutEventIdOperation(w, httptest.NewRequest(http.MethodPut, "/", nil), eventID, operation)
+		if w.Code != http.StatusOK {
+			t.Fatalf("%s operation failed: %s", operation, w.Body)
+		}
+	}
+
+	p.EventNotify(ctx, newEvent(1, "alice", 200))
+	p.EventNotify(ctx, newEvent(2, "bob", 403))
+	p.EventNotify(ctx, newEvent(3, "bob", 200))
+	if len(stored[1]) != 1 || len(stored[2]) != 0 || len(stored[3]) != 3 || stored[3][2].Name != core.AlertAnnotation {
+		t.Fatalf("unexpected annotations: %+v", stored)
+	}
+	e := getStatus(3)
+	if e.BolaStatus != restapi.BOLAStatusVIOLATION || len(e.Objects) != 1 || e.Objects[0].Owners == nil ||
+		(*e.Objects[0].Owners)[0] != (restapi.ObjectUser{User: "alice", EventID: 1}) {
+		t.Errorf("GetEvent() = %+v for a violation", e)
+	}
+	f, ok := p.DescribeEventFinding(&database.APIEvent{ID: 3, Method: "GET", Path: "/orders"}, *stored[3][1])
+	if !ok || f.Severity != core.FindingSeverityHigh ||
+		f.Description != "The user 'bob' called GET /orders on objects so far accessed only by other users: orderId '42' of /orders accessed by 'alice' (event 1)" {
+		t.Errorf("DescribeEventFinding() = %+v", f)
+	}
+
+	// the violation is confirmed, it's critical
+	applyOperation(3, restapi.OperationEnumDeny)
+	if e := getStatus(3); e.BolaStatus != restapi.BOLAStatusDENIED || string(stored[3][2].Annotation) != core.AlertCritical.String() {
+		t.Errorf("GetEvent() = %+v after deny", e)
+	}
+
+	// the access is legitimate
+	applyOperation(3, restapi.OperationEnumApprove)
+	if e := getStatus(3); e.BolaStatus != restapi.BOLAStatusLEGITIMATE {
+		t.Errorf("GetEvent() = %+v after approve", e)
+	}
+	// the accesses which are not violations and teach nothing are not annotated
+	p.EventNotify(ctx, newEvent(4, "bob", 200))
+	p.EventNotify(ctx, newEvent(6, "alice", 200))
+	if len(stored[4]) != 0 || len(stored[6]) != 0 {
+		t.Errorf("unexpected annotations of the legitimate accesses: %+v", stored)
+	}
+
+	if e := getStatus(5); e.BolaStatus != restapi.BOLAStatusNOOBJECTS {
+		t.Errorf("GetEvent() = %+v without accessed objects", e)
+	}
+}
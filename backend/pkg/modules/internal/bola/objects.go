@@ -0,0 +1,348 @@
+// Copyright © 2022 Cisco Systems, Inc. and its affiliates.
+// All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bola
+
+import (
+	"encoding/json"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/openclarity/apiclarity/backend/pkg/modules/internal/traceanalyzer/utils"
+)
+
+const (
+	// The objects learnt for each API are capped, the objects seen first are kept.
+	MaxObjects = 10000
+	// An object that this many distinct users are allowed to access, its first user and the approved ones, is
+	// shared, e.g. a public resource: only the accesses of the denied users are then violations. The accesses of the
+	// other users are violations until they are approved, they don't make the object shared.
+	SharedObjectUsers = 3
+	// The owners given as evidence of a violation are capped.
+	MaxViolationOwners = 10
+	// Longer values are unlikely IDs.
+	MaxIDLength = 128
+)
+
+// idParamRegexp matches the names of the query and body parameters holding an object ID, e.g. id, orderId,
+// order_id or items[0].productUUID. The path parameters always address an object.
+var idParamRegexp = regexp.MustCompile(`(^|[._\-\]])(?i:id|uuid|guid)$|[a-z0-9](Id|ID|Uuid|UUID|Guid|GUID)$`)
+
+// ObjectRef is an object accessed by a request, identified by a parameter.
+type ObjectRef struct {
+	// Key identifies the object in the API: the path of the request up to the path parameter, or the resource
+	// with the query or body parameter.
+	Key string `json:"key"`
+	// Resource is the path of the operation of the spec, or of the request without a spec.
+	Resource string `json:"resource"`
+	In       string `json:"in"`
+	Name     string `json:"name"`
+	ID       string `json:"id"`
+}
+
+// GetObjectRefs returns the objects accessed by a request, sorted by key. The path parameters are known only
+// with the spec path of the operation.
+func GetObjectRefs(specPath string, path string, params utils.Params) []ObjectRef {
+	refs := []ObjectRef{}
+
+	resource := specPath
+	if specPath == "" {
+		resource = path
+	} else {
+		refs = append(refs, getPathObjectRefs(specPath, path)...)
+	}
+	for _, located := range []utils.LocatedParams{{In: utils.ParamInQuery, Params: params.Query}, {In: utils.ParamInBody, Params: params.Body}} {
+		for name, value := range located.Params {
+			if !idParamRegexp.MatchString(name) || !isIDValue(value) {
+				continue
+			}
+			refs = append(refs, ObjectRef{
+				Key:      resource + " " + located.In + ":" + name + "=" + value,
+				Resource: resource,
+				In:       located.In,
+				Name:     name,
+				ID:       value,
+			})
+		}
+	}
+
+	sort.Slice(refs, func(i, j int) bool { return refs[i].Key < refs[j].Key })
+	return refs
+}
+
+// getPathObjectRefs returns an object per path parameter, e.g. the user and the order of /users/1/orders/2.
+func getPathObjectRefs(specPath string, path string) []ObjectRef {
+	refs := []ObjectRef{}
+
+	specSegs := strings.Split(specPath, "/")
+	pathSegs := strings.Split(path, "/")
+	if len(specSegs) != len(pathSegs) {
+		// the path doesn't match the spec path
+		return refs
+	}
+	for i, seg := range specSegs {
+		if !strings.HasPrefix(seg, "{") || !strings.HasSuffix(seg, "}") || !isIDValue(pathSegs[i]) {
+			continue
+		}
+		refs = append(refs, ObjectRef{
+			Key:      strings.Join(pathSegs[:i+1], "/"),
+			Resource: specPath,
+			In:       utils.ParamInPath,
+			Name:     seg[1 : len(seg)-1],
+			ID:       pathSegs[i],
+		})
+	}
+	return refs
+}
+
+func isIDValue(value string) bool {
+	return value != "" && len(value) <= MaxIDLength
+}
+
+// Object is what was learnt of the accesses to an object.
+type Object struct {
+	// Users are the users allowed to access the object, the first one and the approved ones, with the event that
+	// allowed them.
+	Users map[string]uint `json:"users"`
+	// Denied are the users denied access to the object.
+	Denied map[string]bool `json:"denied,omitempty"`
+	// Shared is true while SharedObjectUsers users are allowed to access the object.
+	Shared bool `json:"shared,omitempty"`
+}
+
+func (obj *Object) updateShared() {
+	obj.Shared = len(obj.Users) >= SharedObjectUsers
+}
+
+// ObjectUser is a user allowed to access an object, EventID is the evidence.
+type ObjectUser struct {
+	User    string `json:"user"`
+	EventID uint   `json:"eventID"`
+}
+
+// ObjectViolation is an object accessed by a user while so far only other users accessed it.
+type ObjectViolation struct {
+	ObjectRef
+	Owners []ObjectUser `json:"owners"`
+}
+
+// Violation is the annotation of an event accessing objects of other users.
+type Violation struct {
+	User    string            `json:"user"`
+	Objects []ObjectViolation `json:"objects"`
+	// Denied is true if the user was denied access to one of the objects.
+	Denied bool `json:"denied"`
+}
+
+// Access is the annotation of an event that accessed objects, it's what the approve and deny operations apply to.
+type Access struct {
+	User    string      `json:"user"`
+	Objects []ObjectRef `json:"objects"`
+}
+
+// APIObjects learns the users allowed to access each object of an API.
+type APIObjects struct {
+	lock    sync.Mutex
+	objects map[string]*Object
+	// capped is true once the new objects are no longer learnt
+	capped bool
+}
+
+func NewAPIObjects() *APIObjects {
+	return &APIObjects{
+		objects: map[string]*Object{},
+	}
+}
+
+func (o *APIObjects) MarshalJSON() ([]byte, error) {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+
+	return json.Marshal(o.objects)
+}
+
+func (o *APIObjects) UnmarshalJSON(data []byte) error {
+	objects := map[string]*Object{}
+	if err := json.Unmarshal(data, &objects); err != nil {
+		return err
+	}
+	for key, obj := range objects {
+		if obj == nil {
+			delete(objects, key)
+			continue
+		}
+		if obj.Users == nil {
+			obj.Users = map[string]uint{}
+		}
+		// the objects were shared by the accesses of any users before
+		obj.updateShared()
+	}
+
+	o.lock.Lock()
+	defer o.lock.Unlock()
+	o.objects = objects
+
+	return nil
+}
+
+// get returns the object of the key, learning it if needed. It returns nil once the objects are capped. The lock
+// must be held.
+func (o *APIObjects) get(key string) *Object {
+	obj, ok := o.objects[key]
+	if !ok {
+		if len(o.objects) >= MaxObjects {
+			if !o.capped {
+				log.Warnf("[BOLA] %d objects of an API were learnt, the accesses to its new objects are not checked", MaxObjects)
+				o.capped = true
+			}
+			return nil
+		}
+		obj = &Object{Users: map[string]uint{}}
+		o.objects[key] = obj
+	}
+	return obj
+}
+
+// Access learns a successful access of the user to the objects. The first user of an object is allowed to access
+// it, an access to an object so far accessed only by other users is a violation, nil if there is none. Once
+// SharedObjectUsers users are allowed to access an object, it's shared and only the denied users are violations.
+// learnt is true if the user is the first user of one of the objects.
+func (o *APIObjects) Access(user string, eventID uint, refs []ObjectRef) (violation *Violation, learnt bool) {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+
+	for _, ref := range refs {
+		obj := o.get(ref.Key)
+		if obj == nil {
+			continue
+		}
+		if _, ok := obj.Users[user]; ok {
+			continue
+		}
+		if !obj.Denied[user] {
+			if len(obj.Users) == 0 {
+				obj.Users[user] = eventID
+				learnt = true
+				continue
+			}
+			if obj.Shared {
+				continue
+			}
+		}
+
+		if violation == nil {
+			violation = &Violation{User: user}
+		}
+		violation.Objects = append(violation.Objects, ObjectViolation{ObjectRef: ref, Owners: obj.owners()})
+		violation.Denied = violation.Denied || obj.Denied[user]
+	}
+
+	return violation, learnt
+}
+
+// owners returns the users allowed to access the object, sorted, the first ones are the evidence of a violation.
+func (obj *Object) owners() []ObjectUser {
+	owners := make([]ObjectUser, 0, len(obj.Users))
+	for user, eventID := range obj.Users {
+		owners = append(owners, ObjectUser{User: user, EventID: eventID})
+	}
+	sort.Slice(owners, func(i, j int) bool { return owners[i].User < owners[j].User })
+	if len(owners) > MaxViolationOwners {
+		owners = owners[:MaxViolationOwners]
+	}
+	return owners
+}
+
+// Approve allows the user to access the objects, the event is the evidence.
+func (o *APIObjects) Approve(user string, eventID uint, refs []ObjectRef) {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+
+	for _, ref := range refs {
+		obj := o.get(ref.Key)
+		if obj == nil {
+			continue
+		}
+		obj.Users[user] = eventID
+		delete(obj.Denied, user)
+		obj.updateShared()
+	}
+}
+
+// Deny denies the user access to the objects, its next accesses are violations.
+func (o *APIObjects) Deny(user string, refs []ObjectRef) {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+
+	for _, ref := range refs {
+		obj := o.get(ref.Key)
+		if obj == nil {
+			continue
+		}
+		delete(obj.Users, user)
+		obj.updateShared()
+		if obj.Denied == nil {
+			obj.Denied = map[string]bool{}
+		}
+		obj.Denied[user] = true
+	}
+}
+
+// IsDenied returns true if the user was denied access to one of the objects.
+func (o *APIObjects) IsDenied(user string, refs []ObjectRef) bool {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+
+	for _, ref := range refs {
+		if obj, ok := o.objects[ref.Key]; ok && obj.Denied[user] {
+			return true
+		}
+	}
+	return false
+}
+
+// ObjectState is a copy of what was learnt of an object.
+type ObjectState struct {
+	Key    string
+	Users  []ObjectUser
+	Denied []string
+	Shared bool
+}
+
+// Objects returns what was learnt of the objects, sorted by key.
+func (o *APIObjects) Objects() []ObjectState {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+
+	states := make([]ObjectState, 0, len(o.objects))
+	for key, obj := range o.objects {
+		state := ObjectState{Key: key, Users: make([]ObjectUser, 0, len(obj.Users)), Denied: make([]string, 0, len(obj.Denied)), Shared: obj.Shared}
+		for user, eventID := range obj.Users {
+			state.Users = append(state.Users, ObjectUser{User: user, EventID: eventID})
+		}
+		sort.Slice(state.Users, func(i, j int) bool { return state.Users[i].User < state.Users[j].User })
+		for user := range obj.Denied {
+			state.Denied = append(state.Denied, user)
+		}
+		sort.Strings(state.Denied)
+		states = append(states, state)
+	}
+	sort.Slice(states, func(i, j int) bool { return states[i].Key < states[j].Key })
+	return states
+}
@@ -0,0 +1,169 @@
+// Copyright © 2022 Cisco Systems, Inc. and its affiliates.
+// All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bola
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/openclarity/apiclarity/backend/pkg/modules/internal/traceanalyzer/utils"
+)
+
+func TestGetObjectRefs(t *testing.T) {
+	params := utils.Params{
+		Query: map[string]string{"orderId": "42", "limit": "10", "paid": "true"},
+		Body:  map[string]string{"items[0].product_id": "p1", "comment": "fast", "valid": "yes", "items[0].id": ""},
+	}
+	got := GetObjectRefs("/users/{userId}/orders", "/users/7/orders", params)
+	wanted := []ObjectRef{
+		{Key: "/users/7", Resource: "/users/{userId}/orders", In: "path", Name: "userId", ID: "7"},
+		{Key: "/users/{userId}/orders body:items[0].product_id=p1", Resource: "/users/{userId}/orders", In: "body", Name: "items[0].product_id", ID: "p1"},
+		{Key: "/users/{userId}/orders query:orderId=42", Resource: "/users/{userId}/orders", In: "query", Name: "orderId", ID: "42"},
+	}
+	if !reflect.DeepEqual(got, wanted) {
+		t.Errorf("GetObjectRefs() = %+v, wanted %+v", got, wanted)
+	}
+
+	// without a spec, the path parameters are unknown
+	got = GetObjectRefs("", "/users/7/orders", utils.Params{Query: map[string]string{"id": "42"}})
+	wanted = []ObjectRef{{Key: "/users/7/orders query:id=42", Resource: "/users/7/orders", In: "query", Name: "id", ID: "42"}}
+	if !reflect.DeepEqual(got, wanted) {
+		t.Errorf("GetObjectRefs() = %+v, wanted %+v", got, wanted)
+	}
+
+	if got := GetObjectRefs("/users/{userId}", "/users/7/orders", utils.Params{}); len(got) != 0 {
+		t.Errorf("GetObjectRefs() = %+v for a path not matching the spec path", got)
+	}
+}
+
+func TestAPIObjects_Access(t *testing.T) {
+	o := NewAPIObjects()
+	order := []ObjectRef{{Key: "/orders/1", Resource: "/orders/{id}", In: "path", Name: "id", ID: "1"}}
+
+	if v, learnt := o.Access("alice", 1, order); v != nil || !learnt {
+		t.Fatalf("Access() = %+v, %v for the first user", v, learnt)
+	}
+	if v, learnt := o.Access("alice", 2, order); v != nil || learnt {
+		t.Fatalf("Access() = %+v, %v for the owner", v, learnt)
+	}
+
+	v, _ := o.Access("bob", 3, order)
+	wanted := &Violation{User: "bob", Objects: []ObjectViolation{{ObjectRef: order[0], Owners: []ObjectUser{{User: "alice", EventID: 1}}}}}
+	if !reflect.DeepEqual(v, wanted) {
+		t.Fatalf("Access() = %+v, wanted %+v", v, wanted)
+	}
+	// until approved, each access is a violation
+	if v, _ := o.Access("bob", 4, order); v == nil {
+		t.Errorf("Access() = nil for an access not approved")
+	}
+
+	o.Approve("bob", 4, order)
+	if v, _ := o.Access("bob", 5, order); v != nil {
+		t.Errorf("Access() = %+v for an approved user", v)
+	}
+
+	o.Deny("alice", order)
+	if v, _ := o.Access("alice", 6, order); v == nil || !v.Denied || !reflect.DeepEqual(v.Objects[0].Owners, []ObjectUser{{User: "bob", EventID: 4}}) {
+		t.Errorf("Access() = %+v for a denied user", v)
+	}
+	if !o.IsDenied("alice", order) || o.IsDenied("bob", order) {
+		t.Errorf("IsDenied() is wrong")
+	}
+}
+
+func TestAPIObjects_Shared(t *testing.T) {
+	o := NewAPIObjects()
+	product := []ObjectRef{{Key: "/products/1", Resource: "/products/{id}", In: "path", Name: "id", ID: "1"}}
+
+	o.Access("alice", 1, product)
+	o.Deny("mallory", product)
+	if v, _ := o.Access("bob", 2, product); v == nil {
+		t.Fatalf("Access() = nil for the second user")
+	}
+	// the accesses of the other users don't make the object shared, each one is a violation
+	if v, _ := o.Access("carol", 3, product); v == nil {
+		t.Errorf("Access() = nil for the third user of an object not shared")
+	}
+	if v, _ := o.Access("dave", 4, product); v == nil {
+		t.Errorf("Access() = nil for the fourth user of an object not shared")
+	}
+
+	// the object is approved for many users, e.g. a public product
+	o.Approve("bob", 2, product)
+	o.Approve("carol", 3, product)
+	if v, _ := o.Access("dave", 5, product); v != nil {
+		t.Errorf("Access() = %+v for a shared object", v)
+	}
+	if v, _ := o.Access("mallory", 6, product); v == nil || !v.Denied {
+		t.Errorf("Access() = %+v for a denied user of a shared object", v)
+	}
+	wanted := []ObjectState{{
+		Key:    "/products/1",
+		Users:  []ObjectUser{{User: "alice", EventID: 1}, {User: "bob", EventID: 2}, {User: "carol", EventID: 3}},
+		Denied: []string{"mallory"},
+		Shared: true,
+	}}
+	if got := o.Objects(); !reflect.DeepEqual(got, wanted) {
+		t.Errorf("Objects() = %+v, wanted %+v", got, wanted)
+	}
+
+	// a denied user no longer shares the object
+	o.Deny("carol", product)
+	if v, _ := o.Access("dave", 7, product); v == nil {
+		t.Errorf("Access() = nil once the object is no longer shared")
+	}
+
+	// the new objects are not learnt once the objects are capped
+	for i := len(o.objects); i < MaxObjects; i++ {
+		o.objects[fmt.Sprintf("/orders/%d", i)] = &Object{Users: map[string]uint{"alice": 1}}
+	}
+	if v, _ := o.Access("alice", 8, []ObjectRef{{Key: "/orders/new"}}); v != nil || !o.capped || len(o.objects) != MaxObjects {
+		t.Errorf("Access() = %+v, the objects are not capped", v)
+	}
+}
+
+func TestAPIObjects_Restore(t *testing.T) {
+	o := NewAPIObjects()
+	order := []ObjectRef{{Key: "/orders/1"}}
+	o.Access("alice", 1, order)
+	o.Deny("bob", order)
+
+	state, err := json.Marshal(o)
+	if err != nil {
+		t.Fatalf("failed to marshal the state: %v", err)
+	}
+	restored := &APIObjects{}
+	if err := json.Unmarshal(state, restored); err != nil {
+		t.Fatalf("failed to unmarshal the state: %v", err)
+	}
+	wanted := []ObjectState{{Key: "/orders/1", Users: []ObjectUser{{User: "alice", EventID: 1}}, Denied: []string{"bob"}}}
+	if got := restored.Objects(); !reflect.DeepEqual(got, wanted) {
+		t.Errorf("restored objects = %+v, wanted %+v", got, wanted)
+	}
+	if v, _ := restored.Access("carol", 2, order); v == nil {
+		t.Errorf("Access() = nil after the objects were restored")
+	}
+
+	// an object shared by the accesses of users not allowed to access it is no longer shared
+	if err := json.Unmarshal([]byte(`{"/orders/1":{"users":{"alice":1},"accessors":{"bob":true,"carol":true},"shared":true}}`), restored); err != nil {
+		t.Fatalf("failed to unmarshal the state: %v", err)
+	}
+	if v, _ := restored.Access("dave", 3, order); v == nil {
+		t.Errorf("Access() = nil for an object shared by violations")
+	}
+}
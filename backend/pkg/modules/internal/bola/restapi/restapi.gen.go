@@ -0,0 +1,466 @@
+// Package restapi provides primitives to interact with the openapi HTTP API.
+//
+// Code generated by github.com/deepmap/oapi-codegen version v1.9.1 DO NOT EDIT.
+package restapi
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/deepmap/oapi-codegen/pkg/runtime"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/go-chi/chi/v5"
+)
+
+// Defines values for BOLAStatus.
+const (
+	BOLAStatusDENIED BOLAStatus = "DENIED"
+
+	BOLAStatusLEGITIMATE BOLAStatus = "LEGITIMATE"
+
+	BOLAStatusNOOBJECTS BOLAStatus = "NO_OBJECTS"
+
+	BOLAStatusVIOLATION BOLAStatus = "VIOLATION"
+)
+
+// Defines values for ObjectAccessIn.
+const (
+	ObjectAccessInBody ObjectAccessIn = "body"
+
+	ObjectAccessInPath ObjectAccessIn = "path"
+
+	ObjectAccessInQuery ObjectAccessIn = "query"
+)
+
+// Defines values for OperationEnum.
+const (
+	OperationEnumApprove OperationEnum = "approve"
+
+	OperationEnumDeny OperationEnum = "deny"
+)
+
+// APIEventObjects defines model for APIEventObjects.
+type APIEventObjects struct {
+	BolaStatus BOLAStatus     `json:"bolaStatus"`
+	Objects    []ObjectAccess `json:"objects"`
+
+	// The detected user of the event
+	User *string `json:"user,omitempty"`
+}
+
+// An object that is return in all cases of failures.
+type ApiResponse struct {
+	Message string `json:"message"`
+}
+
+// BOLAStatus defines model for BOLAStatus.
+type BOLAStatus string
+
+// Object defines model for Object.
+type Object struct {
+	DeniedUsers *[]string `json:"deniedUsers,omitempty"`
+	Key         string    `json:"key"`
+
+	// The object is accessed by many users, only the accesses of the denied users are violations
+	Shared *bool        `json:"shared,omitempty"`
+	Users  []ObjectUser `json:"users"`
+}
+
+// ObjectAccess defines model for ObjectAccess.
+type ObjectAccess struct {
+	Id string         `json:"id"`
+	In ObjectAccessIn `json:"in"`
+
+	// Identifies the object in the API
+	Key string `json:"key"`
+
+	// The parameter holding the object ID
+	Name string `json:"name"`
+
+	// For a violation, the users allowed to access the object with their evidence event
+	Owners *[]ObjectUser `json:"owners,omitempty"`
+
+	// The path of the operation of the spec, or of the request without a spec
+	Resource string `json:"resource"`
+}
+
+// ObjectAccessIn defines model for ObjectAccess.In.
+type ObjectAccessIn string
+
+// ObjectUser defines model for ObjectUser.
+type ObjectUser struct {
+	// The event that allowed the user to access the object
+	EventID uint32 `json:"eventID"`
+	User    string `json:"user"`
+}
+
+// Objects defines model for Objects.
+type Objects struct {
+	Items []Object `json:"items"`
+	Total int      `json:"total"`
+}
+
+// OperationEnum defines model for OperationEnum.
+type OperationEnum string
+
+// Version defines model for Version.
+type Version struct {
+	Version string `json:"version"`
+}
+
+// ServerInterface represents all server handlers.
+type ServerInterface interface {
+	// Get the objects accessed by the event with its bola status
+	// (GET /event/{id})
+	GetEvent(w http.ResponseWriter, r *http.Request, id int)
+
+	// (PUT /event/{id}/{operation})
+	PutEventIdOperation(w http.ResponseWriter, r *http.Request, id int, operation OperationEnum)
+	// Get the objects of the API with the users allowed to access them
+	// (GET /objects/{apiID})
+	GetObjects(w http.ResponseWriter, r *http.Request, apiID int)
+	// Get the version of this Module
+	// (GET /version)
+	GetVersion(w http.ResponseWriter, r *http.Request)
+}
+
+// ServerInterfaceWrapper converts contexts to parameters.
+type ServerInterfaceWrapper struct {
+	Handler            ServerInterface
+	HandlerMiddlewares []MiddlewareFunc
+	ErrorHandlerFunc   func(w http.ResponseWriter, r *http.Request, err error)
+}
+
+type MiddlewareFunc func(http.HandlerFunc) http.HandlerFunc
+
+// GetEvent operation middleware
+func (siw *ServerInterfaceWrapper) GetEvent(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id int
+
+	err = runtime.BindStyledParameter("simple", false, "id", chi.URLParam(r, "id"), &id)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	var handler = func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetEvent(w, r, id)
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler(w, r.WithContext(ctx))
+}
+
+// PutEventIdOperation operation middleware
+func (siw *ServerInterfaceWrapper) PutEventIdOperation(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id int
+
+	err = runtime.BindStyledParameter("simple", false, "id", chi.URLParam(r, "id"), &id)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	// ------------- Path parameter "operation" -------------
+	var operation OperationEnum
+
+	err = runtime.BindStyledParameter("simple", false, "operation", chi.URLParam(r, "operation"), &operation)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "operation", Err: err})
+		return
+	}
+
+	var handler = func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PutEventIdOperation(w, r, id, operation)
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler(w, r.WithContext(ctx))
+}
+
+// GetObjects operation middleware
+func (siw *ServerInterfaceWrapper) GetObjects(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "apiID" -------------
+	var apiID int
+
+	err = runtime.BindStyledParameter("simple", false, "apiID", chi.URLParam(r, "apiID"), &apiID)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "apiID", Err: err})
+		return
+	}
+
+	var handler = func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetObjects(w, r, apiID)
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler(w, r.WithContext(ctx))
+}
+
+// GetVersion operation middleware
+func (siw *ServerInterfaceWrapper) GetVersion(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var handler = func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetVersion(w, r)
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler(w, r.WithContext(ctx))
+}
+
+type UnescapedCookieParamError struct {
+	ParamName string
+	Err       error
+}
+
+func (e *UnescapedCookieParamError) Error() string {
+	return fmt.Sprintf("error unescaping cookie parameter '%s'", e.ParamName)
+}
+
+func (e *UnescapedCookieParamError) Unwrap() error {
+	return e.Err
+}
+
+type UnmarshalingParamError struct {
+	ParamName string
+	Err       error
+}
+
+func (e *UnmarshalingParamError) Error() string {
+	return fmt.Sprintf("Error unmarshaling parameter %s as JSON: %s", e.ParamName, e.Err.Error())
+}
+
+func (e *UnmarshalingParamError) Unwrap() error {
+	return e.Err
+}
+
+type RequiredParamError struct {
+	ParamName string
+}
+
+func (e *RequiredParamError) Error() string {
+	return fmt.Sprintf("Query argument %s is required, but not found", e.ParamName)
+}
+
+type RequiredHeaderError struct {
+	ParamName string
+	Err       error
+}
+
+func (e *RequiredHeaderError) Error() string {
+	return fmt.Sprintf("Header parameter %s is required, but not found", e.ParamName)
+}
+
+func (e *RequiredHeaderError) Unwrap() error {
+	return e.Err
+}
+
+type InvalidParamFormatError struct {
+	ParamName string
+	Err       error
+}
+
+func (e *InvalidParamFormatError) Error() string {
+	return fmt.Sprintf("Invalid format for parameter %s: %s", e.ParamName, e.Err.Error())
+}
+
+func (e *InvalidParamFormatError) Unwrap() error {
+	return e.Err
+}
+
+type TooManyValuesForParamError struct {
+	ParamName string
+	Count     int
+}
+
+func (e *TooManyValuesForParamError) Error() string {
+	return fmt.Sprintf("Expected one value for %s, got %d", e.ParamName, e.Count)
+}
+
+// Handler creates http.Handler with routing matching OpenAPI spec.
+func Handler(si ServerInterface) http.Handler {
+	return HandlerWithOptions(si, ChiServerOptions{})
+}
+
+type ChiServerOptions struct {
+	BaseURL          string
+	BaseRouter       chi.Router
+	Middlewares      []MiddlewareFunc
+	ErrorHandlerFunc func(w http.ResponseWriter, r *http.Request, err error)
+}
+
+// HandlerFromMux creates http.Handler with routing matching OpenAPI spec based on the provided mux.
+func HandlerFromMux(si ServerInterface, r chi.Router) http.Handler {
+	return HandlerWithOptions(si, ChiServerOptions{
+		BaseRouter: r,
+	})
+}
+
+func HandlerFromMuxWithBaseURL(si ServerInterface, r chi.Router, baseURL string) http.Handler {
+	return HandlerWithOptions(si, ChiServerOptions{
+		BaseURL:    baseURL,
+		BaseRouter: r,
+	})
+}
+
+// HandlerWithOptions creates http.Handler with additional options
+func HandlerWithOptions(si ServerInterface, options ChiServerOptions) http.Handler {
+	r := options.BaseRouter
+
+	if r == nil {
+		r = chi.NewRouter()
+	}
+	if options.ErrorHandlerFunc == nil {
+		options.ErrorHandlerFunc = func(w http.ResponseWriter, r *http.Request, err error) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		}
+	}
+	wrapper := ServerInterfaceWrapper{
+		Handler:            si,
+		HandlerMiddlewares: options.Middlewares,
+		ErrorHandlerFunc:   options.ErrorHandlerFunc,
+	}
+
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/event/{id}", wrapper.GetEvent)
+	})
+	r.Group(func(r chi.Router) {
+		r.Put(options.BaseURL+"/event/{id}/{operation}", wrapper.PutEventIdOperation)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/objects/{apiID}", wrapper.GetObjects)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/version", wrapper.GetVersion)
+	})
+
+	return r
+}
+
+// Base64 encoded, gzipped, json marshaled Swagger object
+var swaggerSpec = []string{
+
+	"H4sIAAAAAAAC/8xWT2/buBP9KsT8fkci9jY33dzaW2iRxsHGzaUIFrQ0ttmVSJWkHBiGv/uC/yTZZtQt",
+	"doPtKZE1Gr55781wjlDIupEChdGQHUEXO6yZ+3f2kC/2KMxy/RUL/7ZRskFlOLqntazYo2GmdU//V7iB",
+	"DP436RNOQrbJ++XdLESeKMg+ITdYf/drD2BWFKjd9+bQIGTAlGIH+9xqVDZHibpQvDFcCshgtUNSosHC",
+	"YElsCJEbYnZI0BYFXRptFBdbOJ0oKPzWcoUlZF+GxfWIn08UZg3/HXUjhcbrQ2eC+FhidswQrolC0ypB",
+	"uCCsqkjBNGoLZMN41SrUN0AvWK1Ra7Z1uccRxsDnrhR/tKVkQHh2BBRtbb+4X/6xfP/b4sPqESjcLT7m",
+	"q/zTbLUACk/58m62ypf3QGG+uM8X80HaeD4Fr8S1E0oUHMvPGtW5qlcJLqX7Ew/JOL1jrsqUqIFgrglz",
+	"lsCSrA+kZuLgZNaUSFEdnNQhQEfpPU4fRphCsueyYja37g2xlrJCJqKzftSnloXrUi+0s3XH9M8dscHi",
+	"V/TyMkkSF0NxG2Z2QOFbi8rmXsvykNQwUH7Oa16iMHzDURMzoFi4p9lDDolEgtWYVqhhitVoUJGdrEou",
+	"tsOk+TyVTL6IQPV5ul+lIqyXibpMQb+qki9YEiODzsNTXrjZ2WeuCO55iaLoG/8fi2m11LJVxav1m120",
+	"nNXRQY8/6AYLSmQ3jqwtUHvEsjWEuYjvzifvoA6Hc0PQhFrD9K76HMbjuaccGfk8XYB76UdYx3LgPUk3",
+	"UNhIVTMDGbRcmNt3PX4uDG49i3FQj1fmomgHsC8k1RlRyh/QNKWnkYZVA2gd6AtsPi5ayEGL+i5cH/b9",
+	"yJpGyT0CtdMx3YpPqLQj/bKsff9inKsYeH0HnNyA2MjEHfWQf6iY4uZAPsmyrboG56bC8/f2GgHaw4Hp",
+	"zfTmF9evDQrWcMjg9mZ6cwvUzR8HfuKUmxx5ebKPW3QXRtcHeQkZfESzCN3YDQsN2ZejG2pxlvkJY908",
+	"rNqoFmlYVJKSPdtof0M7QO+mU/unkMLYI7Oj1abihYMz+ao90X3CMQtdLkWO5yt+iYvphlB/UcVFwn20",
+	"YW1l/j1kg70kgWqhlFRE9REUdFvXTB28HINuPr9Yu5XJl8ONJnY3Ijrscyc6VHxy7IR26jdtQv2H1quf",
+	"l133vIkRaDKLHJz5erLROXLW829suHFZH9tuLf6vDeWcEBw0ObKG5/PRARBb6O8o77L9PFNgpPt/IkHG",
+	"OjwsH3ZWdVNqZK2qfZ8P7qWg6jmEeEqI86dwHe4ZoNcmiFfgG4oVj0hQ9jTEiRFmmrlXarK+/2sA11NP",
+	"uUUPAAA=",
+}
+
+// GetSwagger returns the content of the embedded swagger specification file
+// or error if failed to decode
+func decodeSpec() ([]byte, error) {
+	zipped, err := base64.StdEncoding.DecodeString(strings.Join(swaggerSpec, ""))
+	if err != nil {
+		return nil, fmt.Errorf("error base64 decoding spec: %s", err)
+	}
+	zr, err := gzip.NewReader(bytes.NewReader(zipped))
+	if err != nil {
+		return nil, fmt.Errorf("error decompressing spec: %s", err)
+	}
+	var buf bytes.Buffer
+	_, err = buf.ReadFrom(zr)
+	if err != nil {
+		return nil, fmt.Errorf("error decompressing spec: %s", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+var rawSpec = decodeSpecCached()
+
+// a naive cached of a decoded swagger spec
+func decodeSpecCached() func() ([]byte, error) {
+	data, err := decodeSpec()
+	return func() ([]byte, error) {
+		return data, err
+	}
+}
+
+// Constructs a synthetic filesystem for resolving external references when loading openapi specifications.
+func PathToRawSpec(pathToFile string) map[string]func() ([]byte, error) {
+	var res = make(map[string]func() ([]byte, error))
+	if len(pathToFile) > 0 {
+		res[pathToFile] = rawSpec
+	}
+
+	return res
+}
+
+// GetSwagger returns the Swagger specification corresponding to the generated code
+// in this file. The external references of Swagger specification are resolved.
+// The logic of resolving external references is tightly connected to "import-mapping" feature.
+// Externally referenced files must be embedded in the corresponding golang packages.
+// Urls can be supported but this task was out of the scope.
+func GetSwagger() (swagger *openapi3.T, err error) {
+	var resolvePath = PathToRawSpec("")
+
+	loader := openapi3.NewLoader()
+	loader.IsExternalRefsAllowed = true
+	loader.ReadFromURIFunc = func(loader *openapi3.Loader, url *url.URL) ([]byte, error) {
+		var pathToFile = url.String()
+		pathToFile = path.Clean(pathToFile)
+		getSpec, ok := resolvePath[pathToFile]
+		if !ok {
+			err1 := fmt.Errorf("path not found: %s", pathToFile)
+			return nil, err1
+		}
+		return getSpec()
+	}
+	var specData []byte
+	specData, err = rawSpec()
+	if err != nil {
+		return
+	}
+	swagger, err = loader.LoadFromData(specData)
+	if err != nil {
+		return
+	}
+	return
+}
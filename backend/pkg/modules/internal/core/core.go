@@ -49,10 +49,10 @@ func RegisterModule(m ModuleFactory) {
 
 type ModuleFactory func(ctx context.Context, accessor BackendAccessor) (Module, error)
 
-func New(ctx context.Context, accessor BackendAccessor) Module {
-	c := &core{}
+func New(ctx context.Context, backendAccessor BackendAccessor) Module {
+	c := &core{accessor: backendAccessor}
 	for _, moduleFactory := range modules {
-		module, err := moduleFactory(ctx, accessor)
+		module, err := moduleFactory(ctx, backendAccessor)
 		if err != nil {
 			log.Error(err)
 			continue
@@ -60,11 +60,15 @@ func New(ctx context.Context, accessor BackendAccessor) Module {
 		log.Infof("Module %s initialized", module.Name())
 		c.modules = append(c.modules, module)
 	}
+	if a, ok := backendAccessor.(*accessor); ok {
+		a.setFindingsDescribers(ctx, c)
+	}
 	return c
 }
 
 type core struct {
-	modules []Module
+	modules  []Module
+	accessor BackendAccessor
 }
 
 func (c *core) Name() string { return "core" }
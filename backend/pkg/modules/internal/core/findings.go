@@ -0,0 +1,139 @@
+// Copyright © 2022 Cisco Systems, Inc. and its affiliates.
+// All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"github.com/openclarity/apiclarity/backend/pkg/database"
+)
+
+// The severities of the findings, from the lowest.
+const (
+	FindingSeverityInfo     = "INFO"
+	FindingSeverityLow      = "LOW"
+	FindingSeverityMedium   = "MEDIUM"
+	FindingSeverityHigh     = "HIGH"
+	FindingSeverityCritical = "CRITICAL"
+)
+
+var findingSeverityLevels = map[string]int{
+	FindingSeverityInfo:     0,
+	FindingSeverityLow:      1,
+	FindingSeverityMedium:   2,
+	FindingSeverityHigh:     3,
+	FindingSeverityCritical: 4,
+}
+
+// FindingSeverityLevel orders the severities, an unknown severity is the lowest.
+func FindingSeverityLevel(severity string) int {
+	if level, ok := findingSeverityLevels[severity]; ok {
+		return level
+	}
+	return -1
+}
+
+// FindingSeverity returns the severity of the level, empty if the level is unknown.
+func FindingSeverity(level int) string {
+	for severity, l := range findingSeverityLevels {
+		if l == level {
+			return severity
+		}
+	}
+	return ""
+}
+
+// Finding describes an annotation that is a finding of a module.
+type Finding struct {
+	Name        string
+	Description string
+	Severity    string
+}
+
+// FindingsDescriber is implemented by the modules to describe their findings in the findings feed.
+// The annotations of the modules which don't implement it are INFO findings, except the alerts.
+type FindingsDescriber interface {
+	// DescribeEventFinding describes an annotation of the event, false if the annotation is not a finding.
+	DescribeEventFinding(event *database.APIEvent, ann Annotation) (Finding, bool)
+	// DescribeAPIFinding describes an annotation of the API, false if the annotation is not a finding.
+	DescribeAPIFinding(apiID uint, ann Annotation) (Finding, bool)
+}
+
+// FindingsDescribers gives the describer of the findings of each module.
+type FindingsDescribers interface {
+	FindingsDescriber(modName string) FindingsDescriber
+}
+
+func (c *core) FindingsDescriber(modName string) FindingsDescriber {
+	for _, m := range c.modules {
+		if m.Name() != modName {
+			continue
+		}
+		if d, ok := m.(FindingsDescriber); ok {
+			return d
+		}
+		break
+	}
+	return DefaultFindingsDescriber{}
+}
+
+// GetFindingsDescribers returns the describers of the findings of the modules, the default one if the modules
+// don't describe their findings.
+func GetFindingsDescribers(m Module) FindingsDescribers {
+	if d, ok := m.(FindingsDescribers); ok {
+		return d
+	}
+	return defaultFindingsDescribers{}
+}
+
+type defaultFindingsDescribers struct{}
+
+func (defaultFindingsDescribers) FindingsDescriber(string) FindingsDescriber {
+	return DefaultFindingsDescriber{}
+}
+
+// DefaultFindingsDescriber describes the annotations of the modules that don't describe their findings.
+type DefaultFindingsDescriber struct{}
+
+func (DefaultFindingsDescriber) DescribeEventFinding(_ *database.APIEvent, ann Annotation) (Finding, bool) {
+	if ann.Name == AlertAnnotation {
+		return Finding{}, false
+	}
+	return Finding{Name: ann.Name, Description: string(ann.Annotation), Severity: FindingSeverityInfo}, true
+}
+
+func (DefaultFindingsDescriber) DescribeAPIFinding(_ uint, ann Annotation) (Finding, bool) {
+	return Finding{Name: ann.Name, Description: string(ann.Annotation), Severity: FindingSeverityInfo}, true
+}
+
+// FindingsListener is called with the API whose findings changed other than by a new event.
+type FindingsListener func(apiID uint)
+
+// FindingsNotifier notifies the listeners when the findings of an API change other than by a new event.
+type FindingsNotifier interface {
+	AddFindingsListener(l FindingsListener)
+}
+
+func (c *core) AddFindingsListener(l FindingsListener) {
+	if n, ok := c.accessor.(FindingsNotifier); ok {
+		n.AddFindingsListener(l)
+	}
+}
+
+// AddFindingsListener adds the listener of the changes of the findings of the modules, if they notify them.
+func AddFindingsListener(m Module, l FindingsListener) {
+	if n, ok := m.(FindingsNotifier); ok {
+		n.AddFindingsListener(l)
+	}
+}
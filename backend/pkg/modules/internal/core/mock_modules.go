@@ -11,6 +11,7 @@ import (
 
 	gomock "github.com/golang/mock/gomock"
 	database "github.com/openclarity/apiclarity/backend/pkg/database"
+	models "github.com/openclarity/apiclarity/plugins/api/server/models"
 	kubernetes "k8s.io/client-go/kubernetes"
 )
 
@@ -100,6 +101,21 @@ func (m *MockBackendAccessor) EXPECT() *MockBackendAccessorMockRecorder {
 	return m.recorder
 }
 
+// CountAPIEvents mocks base method.
+func (m *MockBackendAccessor) CountAPIEvents(arg0 context.Context, arg1 *database.APIEventsFilters) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountAPIEvents", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountAPIEvents indicates an expected call of CountAPIEvents.
+func (mr *MockBackendAccessorMockRecorder) CountAPIEvents(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountAPIEvents", reflect.TypeOf((*MockBackendAccessor)(nil).CountAPIEvents), arg0, arg1)
+}
+
 // CreateAPIEventAnnotations mocks base method.
 func (m *MockBackendAccessor) CreateAPIEventAnnotations(arg0 context.Context, arg1 string, arg2 uint, arg3 ...Annotation) error {
 	m.ctrl.T.Helper()
@@ -119,6 +135,25 @@ func (mr *MockBackendAccessorMockRecorder) CreateAPIEventAnnotations(arg0, arg1,
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAPIEventAnnotations", reflect.TypeOf((*MockBackendAccessor)(nil).CreateAPIEventAnnotations), varargs...)
 }
 
+// DeleteAPIEventAnnotations mocks base method.
+func (m *MockBackendAccessor) DeleteAPIEventAnnotations(arg0 context.Context, arg1 string, arg2 uint, arg3 ...string) error {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1, arg2}
+	for _, a := range arg3 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DeleteAPIEventAnnotations", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteAPIEventAnnotations indicates an expected call of DeleteAPIEventAnnotations.
+func (mr *MockBackendAccessorMockRecorder) DeleteAPIEventAnnotations(arg0, arg1, arg2 interface{}, arg3 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1, arg2}, arg3...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAPIEventAnnotations", reflect.TypeOf((*MockBackendAccessor)(nil).DeleteAPIEventAnnotations), varargs...)
+}
+
 // DeleteAPIInfoAnnotations mocks base method.
 func (m *MockBackendAccessor) DeleteAPIInfoAnnotations(arg0 context.Context, arg1 string, arg2 uint, arg3 ...string) error {
 	m.ctrl.T.Helper()
@@ -138,6 +173,37 @@ func (mr *MockBackendAccessorMockRecorder) DeleteAPIInfoAnnotations(arg0, arg1,
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAPIInfoAnnotations", reflect.TypeOf((*MockBackendAccessor)(nil).DeleteAPIInfoAnnotations), varargs...)
 }
 
+// DeleteModuleAnnotations mocks base method.
+func (m *MockBackendAccessor) DeleteModuleAnnotations(arg0 context.Context, arg1 string, arg2 ...string) error {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DeleteModuleAnnotations", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteModuleAnnotations indicates an expected call of DeleteModuleAnnotations.
+func (mr *MockBackendAccessorMockRecorder) DeleteModuleAnnotations(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteModuleAnnotations", reflect.TypeOf((*MockBackendAccessor)(nil).DeleteModuleAnnotations), varargs...)
+}
+
+// FindingsChanged mocks base method.
+func (m *MockBackendAccessor) FindingsChanged(arg0 uint) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "FindingsChanged", arg0)
+}
+
+// FindingsChanged indicates an expected call of FindingsChanged.
+func (mr *MockBackendAccessorMockRecorder) FindingsChanged(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindingsChanged", reflect.TypeOf((*MockBackendAccessor)(nil).FindingsChanged), arg0)
+}
+
 // GetAPIEventAnnotation mocks base method.
 func (m *MockBackendAccessor) GetAPIEventAnnotation(arg0 context.Context, arg1 string, arg2 uint, arg3 string) (*Annotation, error) {
 	m.ctrl.T.Helper()
@@ -153,6 +219,21 @@ func (mr *MockBackendAccessorMockRecorder) GetAPIEventAnnotation(arg0, arg1, arg
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAPIEventAnnotation", reflect.TypeOf((*MockBackendAccessor)(nil).GetAPIEventAnnotation), arg0, arg1, arg2, arg3)
 }
 
+// GetAPIEventTelemetry mocks base method.
+func (m *MockBackendAccessor) GetAPIEventTelemetry(arg0 context.Context, arg1 uint) (*models.Telemetry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAPIEventTelemetry", arg0, arg1)
+	ret0, _ := ret[0].(*models.Telemetry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAPIEventTelemetry indicates an expected call of GetAPIEventTelemetry.
+func (mr *MockBackendAccessorMockRecorder) GetAPIEventTelemetry(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAPIEventTelemetry", reflect.TypeOf((*MockBackendAccessor)(nil).GetAPIEventTelemetry), arg0, arg1)
+}
+
 // GetAPIEvents mocks base method.
 func (m *MockBackendAccessor) GetAPIEvents(arg0 context.Context, arg1 database.GetAPIEventsQuery) ([]*database.APIEvent, error) {
 	m.ctrl.T.Helper()
@@ -198,6 +279,21 @@ func (mr *MockBackendAccessorMockRecorder) GetAPIInfoAnnotation(arg0, arg1, arg2
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAPIInfoAnnotation", reflect.TypeOf((*MockBackendAccessor)(nil).GetAPIInfoAnnotation), arg0, arg1, arg2, arg3)
 }
 
+// GetModuleAnnotation mocks base method.
+func (m *MockBackendAccessor) GetModuleAnnotation(arg0 context.Context, arg1, arg2 string) (*Annotation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetModuleAnnotation", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*Annotation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetModuleAnnotation indicates an expected call of GetModuleAnnotation.
+func (mr *MockBackendAccessorMockRecorder) GetModuleAnnotation(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetModuleAnnotation", reflect.TypeOf((*MockBackendAccessor)(nil).GetModuleAnnotation), arg0, arg1, arg2)
+}
+
 // K8SClient mocks base method.
 func (m *MockBackendAccessor) K8SClient() kubernetes.Interface {
 	m.ctrl.T.Helper()
@@ -242,6 +338,21 @@ func (mr *MockBackendAccessorMockRecorder) ListAPIInfoAnnotations(arg0, arg1, ar
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAPIInfoAnnotations", reflect.TypeOf((*MockBackendAccessor)(nil).ListAPIInfoAnnotations), arg0, arg1, arg2)
 }
 
+// ListModuleAnnotations mocks base method.
+func (m *MockBackendAccessor) ListModuleAnnotations(arg0 context.Context, arg1 string) ([]*Annotation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListModuleAnnotations", arg0, arg1)
+	ret0, _ := ret[0].([]*Annotation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListModuleAnnotations indicates an expected call of ListModuleAnnotations.
+func (mr *MockBackendAccessorMockRecorder) ListModuleAnnotations(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListModuleAnnotations", reflect.TypeOf((*MockBackendAccessor)(nil).ListModuleAnnotations), arg0, arg1)
+}
+
 // StoreAPIInfoAnnotations mocks base method.
 func (m *MockBackendAccessor) StoreAPIInfoAnnotations(arg0 context.Context, arg1 string, arg2 uint, arg3 ...Annotation) error {
 	m.ctrl.T.Helper()
@@ -260,3 +371,22 @@ func (mr *MockBackendAccessorMockRecorder) StoreAPIInfoAnnotations(arg0, arg1, a
 	varargs := append([]interface{}{arg0, arg1, arg2}, arg3...)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StoreAPIInfoAnnotations", reflect.TypeOf((*MockBackendAccessor)(nil).StoreAPIInfoAnnotations), varargs...)
 }
+
+// StoreModuleAnnotations mocks base method.
+func (m *MockBackendAccessor) StoreModuleAnnotations(arg0 context.Context, arg1 string, arg2 ...Annotation) error {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "StoreModuleAnnotations", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StoreModuleAnnotations indicates an expected call of StoreModuleAnnotations.
+func (mr *MockBackendAccessorMockRecorder) StoreModuleAnnotations(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StoreModuleAnnotations", reflect.TypeOf((*MockBackendAccessor)(nil).StoreModuleAnnotations), varargs...)
+}
@@ -17,9 +17,13 @@ package core
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
+	"sync"
 
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
 	"k8s.io/client-go/kubernetes"
 
 	"github.com/openclarity/apiclarity/backend/pkg/database"
@@ -54,24 +58,44 @@ type BackendAccessor interface {
 
 	GetAPIInfo(ctx context.Context, apiID uint) (*database.APIInfo, error)
 	GetAPIEvents(ctx context.Context, filter database.GetAPIEventsQuery) ([]*database.APIEvent, error)
+	CountAPIEvents(ctx context.Context, filters *database.APIEventsFilters) (int64, error)
+	// GetAPIEventTelemetry returns the archived (redacted) telemetry of the event, or nil if it was not archived.
+	GetAPIEventTelemetry(ctx context.Context, eventID uint) (*pluginsmodels.Telemetry, error)
 
 	GetAPIEventAnnotation(ctx context.Context, modName string, eventID uint, name string) (*Annotation, error)
 	ListAPIEventAnnotations(ctx context.Context, modName string, eventID uint) ([]*Annotation, error)
 	CreateAPIEventAnnotations(ctx context.Context, modName string, eventID uint, annotations ...Annotation) error
+	// DeleteAPIEventAnnotations deletes the annotations with the given names, or all the module annotations of the event if no name is given.
+	DeleteAPIEventAnnotations(ctx context.Context, modName string, eventID uint, name ...string) error
 
 	GetAPIInfoAnnotation(ctx context.Context, modName string, apiID uint, name string) (*Annotation, error)
 	ListAPIInfoAnnotations(ctx context.Context, modName string, apiID uint) ([]*Annotation, error)
 	StoreAPIInfoAnnotations(ctx context.Context, modName string, apiID uint, annotations ...Annotation) error
 	DeleteAPIInfoAnnotations(ctx context.Context, modName string, apiID uint, name ...string) error
+
+	// Module annotations are not related to an API or an event, e.g. the module settings.
+	GetModuleAnnotation(ctx context.Context, modName string, name string) (*Annotation, error)
+	ListModuleAnnotations(ctx context.Context, modName string) ([]*Annotation, error)
+	StoreModuleAnnotations(ctx context.Context, modName string, annotations ...Annotation) error
+	DeleteModuleAnnotations(ctx context.Context, modName string, name ...string) error
+
+	// FindingsChanged tells that the findings of the API changed other than by a new event, e.g. they were
+	// suppressed, approved or deleted. The annotations deleted with DeleteAPIInfoAnnotations are told already.
+	FindingsChanged(apiID uint)
 }
 
 func NewAccessor(dbHandler *database.Handler, clientset kubernetes.Interface) BackendAccessor {
-	return &accessor{dbHandler, clientset}
+	return &accessor{dbHandler: dbHandler, clientset: clientset}
 }
 
 type accessor struct {
 	dbHandler *database.Handler
 	clientset kubernetes.Interface
+
+	lock              sync.Mutex
+	findingsListeners []FindingsListener
+	// the describers of the severities of the annotations, set once the modules are created
+	findingsDescribers FindingsDescribers
 }
 
 func (b *accessor) K8SClient() kubernetes.Interface {
@@ -94,6 +118,29 @@ func (b *accessor) GetAPIEvents(ctx context.Context, filter database.GetAPIEvent
 	return events, nil
 }
 
+func (b *accessor) CountAPIEvents(ctx context.Context, filters *database.APIEventsFilters) (int64, error) {
+	count, err := b.dbHandler.APIEventsTable().CountAPIEvents(ctx, filters)
+	if err != nil {
+		return 0, fmt.Errorf("unable to count apievents: %w", err)
+	}
+	return count, nil
+}
+
+func (b *accessor) GetAPIEventTelemetry(ctx context.Context, eventID uint) (*pluginsmodels.Telemetry, error) {
+	trace, err := b.dbHandler.APIEventTracesTable().Get(ctx, eventID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("unable to get apievent trace: %w", err)
+	}
+	telemetry, err := trace.Telemetry()
+	if err != nil {
+		return nil, fmt.Errorf("unable to read apievent trace: %w", err)
+	}
+	return telemetry, nil
+}
+
 func (b *accessor) GetAPIEventAnnotation(ctx context.Context, modName string, eventID uint, name string) (*Annotation, error) {
 	ann, err := b.dbHandler.APIEventsAnnotationsTable().Get(ctx, modName, eventID, name)
 	if err != nil {
@@ -123,6 +170,7 @@ func (b *accessor) CreateAPIEventAnnotations(ctx context.Context, modName string
 			ModuleName: modName,
 			Name:       a.Name,
 			Annotation: a.Annotation,
+			Severity:   b.eventFindingSeverity(modName, eventID, a),
 		})
 	}
 
@@ -132,6 +180,13 @@ func (b *accessor) CreateAPIEventAnnotations(ctx context.Context, modName string
 	return nil
 }
 
+func (b *accessor) DeleteAPIEventAnnotations(ctx context.Context, modName string, eventID uint, name ...string) error {
+	if err := b.dbHandler.APIEventsAnnotationsTable().Delete(ctx, modName, eventID, name...); err != nil {
+		return fmt.Errorf("unable to delete the apievent annotations: %w", err)
+	}
+	return nil
+}
+
 func (b *accessor) GetAPIInfoAnnotation(ctx context.Context, modName string, apiID uint, name string) (*Annotation, error) {
 	ann, err := b.dbHandler.APIInfoAnnotationsTable().Get(ctx, modName, apiID, name)
 	if err != nil {
@@ -163,6 +218,7 @@ func (b *accessor) StoreAPIInfoAnnotations(ctx context.Context, modName string,
 			ModuleName: modName,
 			Name:       a.Name,
 			Annotation: a.Annotation,
+			Severity:   b.apiFindingSeverity(modName, a),
 		})
 	}
 
@@ -176,5 +232,156 @@ func (b *accessor) DeleteAPIInfoAnnotations(ctx context.Context, modName string,
 	if err := b.dbHandler.APIInfoAnnotationsTable().Delete(ctx, modName, apiID, name...); err != nil {
 		return fmt.Errorf("unable to delete the apiinfo annotation: %w", err)
 	}
+	b.FindingsChanged(apiID)
 	return nil
 }
+
+func (b *accessor) GetModuleAnnotation(ctx context.Context, modName string, name string) (*Annotation, error) {
+	ann, err := b.dbHandler.ModuleAnnotationsTable().Get(ctx, modName, name)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get module annotation: %w", err)
+	}
+	return &Annotation{Name: ann.Name, Annotation: ann.Annotation}, nil
+}
+
+func (b *accessor) ListModuleAnnotations(ctx context.Context, modName string) (annotations []*Annotation, err error) {
+	anns, err := b.dbHandler.ModuleAnnotationsTable().List(ctx, modName)
+	if err != nil {
+		return nil, fmt.Errorf("unable to list module annotations: %w", err)
+	}
+	for _, ann := range anns {
+		annotations = append(annotations, &Annotation{
+			Name:       ann.Name,
+			Annotation: ann.Annotation,
+		})
+	}
+	return annotations, nil
+}
+
+func (b *accessor) StoreModuleAnnotations(ctx context.Context, modName string, annotations ...Annotation) error {
+	var dbAnns []database.ModuleAnnotation
+
+	for _, a := range annotations {
+		dbAnns = append(dbAnns, database.ModuleAnnotation{
+			ModuleName: modName,
+			Name:       a.Name,
+			Annotation: a.Annotation,
+		})
+	}
+
+	if err := b.dbHandler.ModuleAnnotationsTable().UpdateOrCreate(ctx, dbAnns...); err != nil {
+		return fmt.Errorf("unable to store module annotations: %w", err)
+	}
+	return nil
+}
+
+func (b *accessor) DeleteModuleAnnotations(ctx context.Context, modName string, name ...string) error {
+	if err := b.dbHandler.ModuleAnnotationsTable().Delete(ctx, modName, name...); err != nil {
+		return fmt.Errorf("unable to delete the module annotations: %w", err)
+	}
+	return nil
+}
+
+func (b *accessor) FindingsChanged(apiID uint) {
+	b.lock.Lock()
+	listeners := b.findingsListeners
+	b.lock.Unlock()
+
+	for _, l := range listeners {
+		l(apiID)
+	}
+}
+
+func (b *accessor) AddFindingsListener(l FindingsListener) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.findingsListeners = append(b.findingsListeners, l)
+}
+
+// findingsSeverityBatchSize is the number of annotations whose unknown severity is described at once.
+const findingsSeverityBatchSize = 500
+
+// setFindingsDescribers sets the describers of the severities of the annotations once the modules are created, and
+// describes the severity of the annotations stored before, e.g. by a previous version or while the modules were created.
+func (b *accessor) setFindingsDescribers(ctx context.Context, describers FindingsDescribers) {
+	b.lock.Lock()
+	b.findingsDescribers = describers
+	b.lock.Unlock()
+
+	go func() {
+		if err := b.describeUnknownSeverities(ctx); err != nil {
+			log.Errorf("Failed to describe the severity of the annotations: %v", err)
+		}
+	}()
+}
+
+func (b *accessor) getFindingsDescribers() FindingsDescribers {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return b.findingsDescribers
+}
+
+// eventFindingSeverity describes the severity of the annotation without its event, so that it depends on the
+// annotation only and not on its state, e.g. suppressed. It is nil until the describers are set.
+func (b *accessor) eventFindingSeverity(modName string, eventID uint, ann Annotation) *int {
+	describers := b.getFindingsDescribers()
+	if describers == nil {
+		return nil
+	}
+	f, ok := describers.FindingsDescriber(modName).DescribeEventFinding(&database.APIEvent{ID: eventID}, ann)
+	return findingSeverityLevel(f, ok)
+}
+
+// apiFindingSeverity describes the severity of the annotation without its API, as eventFindingSeverity.
+func (b *accessor) apiFindingSeverity(modName string, ann Annotation) *int {
+	describers := b.getFindingsDescribers()
+	if describers == nil {
+		return nil
+	}
+	f, ok := describers.FindingsDescriber(modName).DescribeAPIFinding(0, ann)
+	return findingSeverityLevel(f, ok)
+}
+
+func findingSeverityLevel(f Finding, isFinding bool) *int {
+	level := -1
+	if isFinding {
+		level = FindingSeverityLevel(f.Severity)
+	}
+	return &level
+}
+
+func (b *accessor) describeUnknownSeverities(ctx context.Context) error {
+	for afterID := uint(0); ; {
+		anns, err := b.dbHandler.APIEventsAnnotationsTable().ListWithoutSeverity(ctx, afterID, findingsSeverityBatchSize)
+		if err != nil {
+			return fmt.Errorf("failed to list the event annotations: %w", err)
+		}
+		for _, ann := range anns {
+			severity := b.eventFindingSeverity(ann.ModuleName, ann.EventID, Annotation{Name: ann.Name, Annotation: ann.Annotation})
+			if err := b.dbHandler.APIEventsAnnotationsTable().SetSeverity(ctx, ann.ID, *severity); err != nil {
+				return fmt.Errorf("failed to set the severity of the event annotation: %w", err)
+			}
+			afterID = ann.ID
+		}
+		if len(anns) < findingsSeverityBatchSize {
+			break
+		}
+	}
+
+	for afterID := uint(0); ; {
+		anns, err := b.dbHandler.APIInfoAnnotationsTable().ListWithoutSeverity(ctx, afterID, findingsSeverityBatchSize)
+		if err != nil {
+			return fmt.Errorf("failed to list the apiinfo annotations: %w", err)
+		}
+		for _, ann := range anns {
+			severity := b.apiFindingSeverity(ann.ModuleName, Annotation{Name: ann.Name, Annotation: ann.Annotation})
+			if err := b.dbHandler.APIInfoAnnotationsTable().SetSeverity(ctx, ann.ID, *severity); err != nil {
+				return fmt.Errorf("failed to set the severity of the apiinfo annotation: %w", err)
+			}
+			afterID = ann.ID
+		}
+		if len(anns) < findingsSeverityBatchSize {
+			return nil
+		}
+	}
+}
@@ -19,8 +19,15 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"sort"
+	"strings"
 
 	"github.com/openclarity/apiclarity/backend/pkg/modules/internal/core"
+	"github.com/openclarity/apiclarity/backend/pkg/modules/internal/traceanalyzer/bruteforce"
+	"github.com/openclarity/apiclarity/backend/pkg/modules/internal/traceanalyzer/enumeration"
+	"github.com/openclarity/apiclarity/backend/pkg/modules/internal/traceanalyzer/securityheaders"
+	"github.com/openclarity/apiclarity/backend/pkg/modules/internal/traceanalyzer/sensitive"
+	"github.com/openclarity/apiclarity/backend/pkg/modules/internal/traceanalyzer/unauthenticated"
 )
 
 const (
@@ -63,34 +70,18 @@ func getEventDescription(a core.Annotation) Finding {
 		}
 		return f
 
-	case "REGEXP_MATCHING_REQUEST_BODY":
-		return Finding{
-			ShortDesc:    "Matching regular expression",
-			DetailedDesc: fmt.Sprintf("This event contains a sensitive information in the request body (%s)", a.Annotation),
-			Severity:     SeverityMedium,
-			Alert:        nil,
-		}
-	case "REGEXP_MATCHING_RESPONSE_BODY":
-		return Finding{
-			ShortDesc:    "Matching regular expression",
-			DetailedDesc: fmt.Sprintf("This event contains a sensitive information in the response body (%s)", a.Annotation),
-			Severity:     SeverityMedium,
-			Alert:        nil,
-		}
-	case "REGEXP_MATCHING_REQUEST_HEADERS":
-		return Finding{
-			ShortDesc:    "Matching regular expression",
-			DetailedDesc: fmt.Sprintf("This event contains a sensitive information in the request headers (%s)", a.Annotation),
-			Severity:     SeverityMedium,
-			Alert:        nil,
-		}
-	case "REGEXP_MATCHING_RESPONSE_HEADERS":
-		return Finding{
-			ShortDesc:    "Matching regular expression",
-			DetailedDesc: fmt.Sprintf("This event contains a sensitive information in the response headers (%s)", a.Annotation),
-			Severity:     SeverityMedium,
-			Alert:        nil,
-		}
+	case sensitive.RexgexpMatchingRequestBody:
+		return getSensitiveFinding(a, "the request body")
+	case sensitive.RexgexpMatchingResponseBody:
+		return getSensitiveFinding(a, "the response body")
+	case sensitive.RegexpMatchingRequestHeaders:
+		return getSensitiveFinding(a, "the request headers")
+	case sensitive.RexgexpMatchingResponseHeaders:
+		return getSensitiveFinding(a, "the response headers")
+	case sensitive.RegexpMatchingRequestQueryParams:
+		return getSensitiveFinding(a, "the request query parameters")
+	case sensitive.RegexpMatchingRequestPathParams:
+		return getSensitiveFinding(a, "the request path parameters")
 
 	case "JWT_WEAK_SYMETRIC_SECRET":
 		return Finding{
@@ -141,6 +132,21 @@ func getEventDescription(a core.Annotation) Finding {
 			Severity:     SeverityMedium,
 			Alert:        nil,
 		}
+	case bruteforce.KindPasswordSpraying:
+		return getBruteForceFinding(a, "Password spraying", "A source tried a few passwords for many users")
+	case bruteforce.KindCredentialStuffing:
+		return getBruteForceFinding(a, "Credential stuffing", "Many users failed to authenticate")
+	case bruteforce.KindTokenGuessing:
+		return getBruteForceFinding(a, "Token guessing", "A source tried many invalid tokens")
+	case bruteforce.KindBruteForce:
+		return getBruteForceFinding(a, "Brute force", "A user failed to authenticate many times")
+	case enumeration.KindSequentialEnumeration:
+		return getEnumerationFinding(a, "Sequential enumeration", "A client walked through consecutive identifiers", SeverityHigh)
+	case enumeration.KindIDEnumeration:
+		return getEnumerationFinding(a, "Identifier enumeration", "A client requested many distinct identifiers", SeverityMedium)
+	case enumeration.KindScraping:
+		return getEnumerationFinding(a, "Scraping", "A client read many resources", SeverityMedium)
+
 	case "NLID":
 		var reason ParameterFinding
 		f := Finding{
@@ -166,6 +172,89 @@ func getEventDescription(a core.Annotation) Finding {
 	}
 }
 
+// getSensitiveFinding describes the match of a sensitive rule. The severity of the finding, and its alert, are the
+// ones of the rule.
+func getSensitiveFinding(a core.Annotation, where string) Finding {
+	var match sensitive.Match
+	if err := json.Unmarshal(a.Annotation, &match); err != nil {
+		// annotations stored before the rules had a severity: "<rule id>:<rule description>"
+		return Finding{
+			ShortDesc:    "Matching regular expression",
+			DetailedDesc: fmt.Sprintf("This event contains a sensitive information in %s (%s)", where, a.Annotation),
+			Severity:     SeverityMedium,
+			Alert:        nil,
+		}
+	}
+
+	if match.Location != "" {
+		where = fmt.Sprintf("%s at '%s'", where, match.Location)
+	}
+	return Finding{
+		ShortDesc:    "Matching regular expression",
+		DetailedDesc: fmt.Sprintf("This event contains a sensitive information in %s (%s:%s)", where, match.RuleID, match.Description),
+		Severity:     match.Severity,
+		Alert:        getSeverityAlert(match.Severity),
+	}
+}
+
+// getBruteForceFinding describes a failed authentication during an attack, with what was seen in the window.
+func getBruteForceFinding(a core.Annotation, shortDesc string, detailedDesc string) Finding {
+	f := Finding{
+		ShortDesc:    shortDesc,
+		DetailedDesc: detailedDesc,
+		Severity:     SeverityHigh,
+		Alert:        getSeverityAlert(SeverityHigh),
+	}
+	var detection bruteforce.Detection
+	if err := json.Unmarshal(a.Annotation, &detection); err == nil {
+		f.DetailedDesc = fmt.Sprintf("%s: %s", detailedDesc, detection)
+	}
+	return f
+}
+
+// getEnumerationFinding describes a request of a client enumerating or scraping the API, with what was seen in the
+// window. The enumeration of guessable identifiers is more severe.
+func getEnumerationFinding(a core.Annotation, shortDesc string, detailedDesc string, severity string) Finding {
+	f := Finding{
+		ShortDesc:    shortDesc,
+		DetailedDesc: detailedDesc,
+		Severity:     severity,
+	}
+	var detection enumeration.Detection
+	if err := json.Unmarshal(a.Annotation, &detection); err == nil {
+		f.DetailedDesc = fmt.Sprintf("%s: %s", detailedDesc, detection)
+		if detection.GuessableID {
+			f.Severity = SeverityHigh
+		}
+	}
+	f.Alert = getSeverityAlert(f.Severity)
+	return f
+}
+
+// getSeverityAlert returns the alert raised by a finding of the severity, if any.
+func getSeverityAlert(severity string) *core.Annotation {
+	switch severity {
+	case SeverityCritical:
+		return &core.AlertCriticalAnn
+	case SeverityHigh:
+		return &core.AlertWarnAnn
+	default:
+		return nil
+	}
+}
+
+// alertLevel orders the alerts, the most severe alert of an event is kept.
+func alertLevel(alert *core.Annotation) int {
+	switch string(alert.Annotation) {
+	case core.AlertCritical.String():
+		return int(core.AlertCritical)
+	case core.AlertWarn.String():
+		return int(core.AlertWarn)
+	default:
+		return int(core.AlertInfo)
+	}
+}
+
 func getAPIDescription(a core.Annotation) Finding {
 	switch a.Name {
 	case "GUESSABLE_ID":
@@ -180,10 +269,48 @@ func getAPIDescription(a core.Annotation) Finding {
 		if err != nil {
 			f.DetailedDesc = "A parameter is guessable"
 		} else {
-			f.DetailedDesc = fmt.Sprintf("Parameter '%s' in '%s %s' seems to be guessable", reason.Name, reason.Method, reason.Location)
+			name := reason.Name
+			if reason.In != "" {
+				name = fmt.Sprintf("%s (%s)", reason.Name, reason.In)
+			}
+			f.DetailedDesc = fmt.Sprintf("Parameter '%s' in '%s %s' seems to be guessable", name, reason.Method, reason.Location)
 		}
 		return f
 
+	case securityheaders.KindHSTSMissing:
+		return getEndpointsFinding(a, "Missing HSTS header", "The responses have no Strict-Transport-Security header", SeverityLow)
+	case securityheaders.KindHSTSWeak:
+		return getEndpointsFinding(a, "Weak HSTS policy", "The Strict-Transport-Security max-age of the responses is shorter than 180 days", SeverityLow)
+	case securityheaders.KindContentTypeOptionsMissing:
+		return getEndpointsFinding(a, "Missing X-Content-Type-Options header", "The responses are not protected against MIME sniffing with 'X-Content-Type-Options: nosniff'", SeverityLow)
+	case securityheaders.KindCacheControlMissing:
+		return getEndpointsFinding(a, "Cacheable authenticated responses", "The responses to authenticated requests can be cached, their Cache-Control header has no 'no-store' directive", SeverityMedium)
+	case securityheaders.KindCORSWildcardWithCredentials:
+		return getEndpointsFinding(a, "Permissive CORS policy", "The responses allow any origin with credentials", SeverityHigh)
+	case securityheaders.KindCORSReflectedOrigin:
+		return getEndpointsFinding(a, "Reflected CORS origin", "The responses allow the origin of the request, whatever it is", SeverityMedium)
+	case securityheaders.KindCookieInsecure:
+		return getEndpointsFinding(a, "Insecure cookies", "The responses set cookies without the Secure, HttpOnly or SameSite attributes", SeverityMedium)
+
+	case unauthenticated.KindUnauthenticatedAccess:
+		return getUnauthenticatedAccessFinding(a)
+
+	case bruteforce.KindPasswordSpraying:
+		return getEndpointsFinding(a, "Password spraying", "A source tried a few passwords for many users", SeverityHigh)
+	case bruteforce.KindCredentialStuffing:
+		return getEndpointsFinding(a, "Credential stuffing", "Many users failed to authenticate", SeverityHigh)
+	case bruteforce.KindTokenGuessing:
+		return getEndpointsFinding(a, "Token guessing", "A source tried many invalid tokens", SeverityHigh)
+	case bruteforce.KindBruteForce:
+		return getEndpointsFinding(a, "Brute force", "A user failed to authenticate many times", SeverityHigh)
+
+	case enumeration.KindSequentialEnumeration:
+		return getEndpointsFinding(a, "Sequential enumeration", "Clients walked through consecutive identifiers", SeverityHigh)
+	case enumeration.KindIDEnumeration:
+		return getEndpointsFinding(a, "Identifier enumeration", "Clients requested many distinct identifiers", SeverityMedium)
+	case enumeration.KindScraping:
+		return getEndpointsFinding(a, "Scraping", "Clients read many resources", SeverityMedium)
+
 	default:
 		return Finding{
 			ShortDesc:    a.Name,
@@ -193,3 +320,59 @@ func getAPIDescription(a core.Annotation) Finding {
 		}
 	}
 }
+
+// getEndpointsFinding describes a finding of the security headers, brute force or enumeration analyzers, with the
+// endpoints where it was seen.
+func getEndpointsFinding(a core.Annotation, shortDesc string, detailedDesc string, severity string) Finding {
+	f := Finding{
+		ShortDesc:    shortDesc,
+		DetailedDesc: detailedDesc,
+		Severity:     severity,
+		Alert:        getSeverityAlert(severity),
+	}
+	var finding securityheaders.Finding
+	if err := json.Unmarshal(a.Annotation, &finding); err != nil || len(finding.Endpoints) == 0 {
+		return f
+	}
+
+	endpoints := make([]string, 0, len(finding.Endpoints))
+	for _, e := range finding.Endpoints {
+		endpoint := fmt.Sprintf("'%s %s'", e.Method, e.Location)
+		if e.Detail != "" {
+			endpoint = fmt.Sprintf("%s (%s)", endpoint, e.Detail)
+		}
+		endpoints = append(endpoints, endpoint)
+	}
+	f.DetailedDesc = fmt.Sprintf("%s: %s", detailedDesc, strings.Join(endpoints, ", "))
+
+	return f
+}
+
+// getUnauthenticatedAccessFinding describes the operations that succeeded without authentication, although they
+// usually require it.
+func getUnauthenticatedAccessFinding(a core.Annotation) Finding {
+	f := Finding{
+		ShortDesc:    "Endpoint accessible without authentication",
+		DetailedDesc: "Requests without credentials succeeded on endpoints that usually require authentication",
+		Severity:     SeverityHigh,
+		Alert:        getSeverityAlert(SeverityHigh),
+	}
+	var finding unauthenticated.Finding
+	if err := json.Unmarshal(a.Annotation, &finding); err != nil || len(finding.Operations) == 0 {
+		return f
+	}
+
+	operations := make([]string, 0, len(finding.Operations))
+	for _, op := range finding.Operations {
+		schemes := make([]string, 0, len(op.Schemes))
+		for scheme := range op.Schemes {
+			schemes = append(schemes, scheme)
+		}
+		sort.Strings(schemes)
+		operations = append(operations, fmt.Sprintf("'%s %s' (usually authenticated with %s, %d authenticated and %d unauthenticated requests)",
+			op.Method, op.Location, strings.Join(schemes, ", "), op.Authenticated, op.Unauthenticated))
+	}
+	f.DetailedDesc = fmt.Sprintf("%s: %s", f.DetailedDesc, strings.Join(operations, ", "))
+
+	return f
+}
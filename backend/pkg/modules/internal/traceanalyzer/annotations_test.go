@@ -0,0 +1,83 @@
+// Copyright © 2022 Cisco Systems, Inc. and its affiliates.
+// All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package traceanalyzer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+
+	"github.com/openclarity/apiclarity/backend/pkg/modules/internal/core"
+	"github.com/openclarity/apiclarity/backend/pkg/modules/internal/traceanalyzer/enumeration"
+	"github.com/openclarity/apiclarity/backend/pkg/modules/internal/traceanalyzer/sensitive"
+)
+
+func TestGetSensitiveFinding(t *testing.T) {
+	legacy := getEventDescription(core.Annotation{Name: sensitive.RexgexpMatchingRequestBody, Annotation: []byte("core-001:password")})
+	if legacy.Severity != SeverityMedium || legacy.Alert != nil {
+		t.Errorf("legacy finding = %+v", legacy)
+	}
+
+	f := getEventDescription(core.Annotation{
+		Name:       sensitive.RexgexpMatchingRequestBody,
+		Annotation: []byte(`{"ruleID":"pan","description":"card number","severity":"CRITICAL","location":"$.payment.pan"}`),
+	})
+	if f.Severity != SeverityCritical || f.Alert != &core.AlertCriticalAnn {
+		t.Errorf("finding = %+v", f)
+	}
+	if want := "This event contains a sensitive information in the request body at '$.payment.pan' (pan:card number)"; f.DetailedDesc != want {
+		t.Errorf("finding description = %s, want %s", f.DetailedDesc, want)
+	}
+}
+
+func TestGetEnumerationFinding(t *testing.T) {
+	f := getEventDescription(core.Annotation{
+		Name:       enumeration.KindIDEnumeration,
+		Annotation: []byte(`{"source":"10.0.0.1","parameter":"id","values":100,"window":"10m0s"}`),
+	})
+	if f.Severity != SeverityMedium || f.Alert != nil ||
+		f.DetailedDesc != "A client requested many distinct identifiers: source 10.0.0.1 requested 100 values of 'id' in 10m0s" {
+		t.Errorf("finding = %+v", f)
+	}
+
+	// the identifiers were found guessable
+	f = getEventDescription(core.Annotation{
+		Name:       enumeration.KindIDEnumeration,
+		Annotation: []byte(`{"source":"10.0.0.1","parameter":"id","values":100,"guessableId":true,"window":"10m0s"}`),
+	})
+	if f.Severity != SeverityHigh || f.Alert != &core.AlertWarnAnn {
+		t.Errorf("finding = %+v for guessable identifiers", f)
+	}
+}
+
+func TestSetAlertSeverity(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	accessor := core.NewMockBackendAccessor(mockCtrl)
+	ta := newTestTraceAnalyzer(t, accessor)
+
+	anns := []core.Annotation{
+		{Name: "JWT_NOT_RECOMMENDED_ALG", Annotation: []byte("HS256")},
+		{Name: sensitive.RexgexpMatchingRequestBody, Annotation: []byte(`{"ruleID":"r","severity":"HIGH"}`)},
+		{Name: sensitive.RexgexpMatchingResponseBody, Annotation: []byte(`{"ruleID":"r","severity":"INFO"}`)},
+	}
+	accessor.EXPECT().CreateAPIEventAnnotations(gomock.Any(), moduleName, uint(1), core.AlertWarnAnn).Return(nil)
+	ta.setAlertSeverity(context.Background(), 1, anns)
+
+	// no alert
+	ta.setAlertSeverity(context.Background(), 2, anns[2:])
+}
@@ -0,0 +1,357 @@
+// Copyright © 2022 Cisco Systems, Inc. and its affiliates.
+// All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bruteforce
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/openclarity/apiclarity/backend/pkg/database"
+	"github.com/openclarity/apiclarity/backend/pkg/modules/internal/core"
+	"github.com/openclarity/apiclarity/backend/pkg/modules/internal/traceanalyzer/utils"
+	_utils "github.com/openclarity/apiclarity/backend/pkg/utils"
+	"github.com/openclarity/apiclarity/plugins/api/server/models"
+)
+
+const (
+	// A source fails to authenticate many users with a few passwords.
+	KindPasswordSpraying = "PASSWORD_SPRAYING"
+	// Many users fail to authenticate from a source, or on an endpoint from several sources.
+	KindCredentialStuffing = "CREDENTIAL_STUFFING"
+	// A source fails to authenticate with many tokens of no known user.
+	KindTokenGuessing = "TOKEN_GUESSING"
+	// A user fails to authenticate many times.
+	KindBruteForce = "BRUTE_FORCE"
+)
+
+const (
+	// The failures kept in a window are capped, the oldest ones are dropped.
+	MaxWindowFailures = 1000
+	// The windows tracked by source, user and endpoint are capped.
+	MaxWindows = 10000
+)
+
+const (
+	authorizationHeader = "authorization"
+	basicScheme         = "basic"
+)
+
+var kinds = map[string]bool{
+	KindPasswordSpraying:   true,
+	KindCredentialStuffing: true,
+	KindTokenGuessing:      true,
+	KindBruteForce:         true,
+}
+
+// IsKind returns true if the annotation is a finding of the analyzer.
+func IsKind(name string) bool {
+	return kinds[name]
+}
+
+// Thresholds are the failures, within the window, from which an attack is detected.
+type Thresholds struct {
+	Window time.Duration
+	// PASSWORD_SPRAYING and CREDENTIAL_STUFFING: the distinct users failing from a source.
+	SourceUsers int
+	// PASSWORD_SPRAYING: at most this number of distinct basic auth passwords for the users of a source.
+	SprayingPasswords int
+	// TOKEN_GUESSING: the distinct credentials without a known user failing from a source.
+	SourceTokens int
+	// BRUTE_FORCE: the failures of a user.
+	UserFailures int
+	// CREDENTIAL_STUFFING: the distinct users failing on an endpoint, from at least two sources.
+	EndpointUsers int
+}
+
+func DefaultThresholds() Thresholds {
+	return Thresholds{
+		Window:            5 * time.Minute,
+		SourceUsers:       10,
+		SprayingPasswords: 2,
+		SourceTokens:      20,
+		UserFailures:      10,
+		EndpointUsers:     20,
+	}
+}
+
+// ParseThresholds overrides the default thresholds with a comma separated list of "<name>=<value>", e.g.
+// "window=10m,userFailures=5". The names are window, sourceUsers, sprayingPasswords, sourceTokens, userFailures
+// and endpointUsers.
+func ParseThresholds(config string) (Thresholds, error) {
+	t := DefaultThresholds()
+	err := utils.ParseThresholds(config, &t.Window, map[string]*int{
+		"sourceUsers":       &t.SourceUsers,
+		"sprayingPasswords": &t.SprayingPasswords,
+		"sourceTokens":      &t.SourceTokens,
+		"userFailures":      &t.UserFailures,
+		"endpointUsers":     &t.EndpointUsers,
+	})
+	return t, err
+}
+
+// Detection is the annotation of an event failing during an attack, with what was seen in the window.
+type Detection struct {
+	Source    string `json:"source,omitempty"`
+	User      string `json:"user,omitempty"`
+	Failures  int    `json:"failures"`
+	Users     int    `json:"users,omitempty"`
+	Passwords int    `json:"passwords,omitempty"`
+	Tokens    int    `json:"tokens,omitempty"`
+	Sources   int    `json:"sources,omitempty"`
+	Window    string `json:"window"`
+}
+
+func (d Detection) String() string {
+	s := plural(d.Failures, "failure")
+	if d.User != "" {
+		s += fmt.Sprintf(" of user '%s'", d.User)
+	}
+	if d.Users > 0 {
+		s += " of " + plural(d.Users, "user")
+	}
+	if d.Passwords > 0 {
+		s += " with " + plural(d.Passwords, "password")
+	}
+	if d.Tokens > 0 {
+		s += " with " + plural(d.Tokens, "token")
+	}
+	if d.Source != "" {
+		s += " from " + d.Source
+	} else if d.Sources > 0 {
+		s += " from " + plural(d.Sources, "source")
+	}
+	return s + " in " + d.Window
+}
+
+func plural(n int, word string) string {
+	if n == 1 {
+		return fmt.Sprintf("%d %s", n, word)
+	}
+	return fmt.Sprintf("%d %ss", n, word)
+}
+
+// failure is a failed authentication, the secret is a fingerprint of the password or the token.
+type failure struct {
+	time     time.Time
+	source   string
+	user     string
+	secret   string
+	password bool
+}
+
+// window holds the failures of the last period, by time.
+type window struct {
+	failures []failure
+}
+
+func (w *window) expire(since time.Time) {
+	i := 0
+	for i < len(w.failures) && w.failures[i].time.Before(since) {
+		i++
+	}
+	w.failures = w.failures[i:]
+}
+
+func (w *window) add(f failure, since time.Time) {
+	w.expire(since)
+	w.failures = append(w.failures, f)
+	if len(w.failures) > MaxWindowFailures {
+		w.failures = w.failures[len(w.failures)-MaxWindowFailures:]
+	}
+}
+
+// count returns the number of distinct non empty values of the failures that match.
+func (w *window) count(value func(f failure) string, match func(f failure) bool) int {
+	values := map[string]bool{}
+	for _, f := range w.failures {
+		if v := value(f); v != "" && match(f) {
+			values[v] = true
+		}
+	}
+	return len(values)
+}
+
+func all(failure) bool { return true }
+
+// windows are the windows by key.
+type windows map[string]*window
+
+// get returns the window of the key, nil for an empty key or once the windows are capped.
+func (ws windows) get(key string, since time.Time) *window {
+	if key == "" {
+		return nil
+	}
+	if w, ok := ws[key]; ok {
+		return w
+	}
+	if len(ws) >= MaxWindows {
+		for k, w := range ws {
+			if w.expire(since); len(w.failures) == 0 {
+				delete(ws, k)
+			}
+		}
+		if len(ws) >= MaxWindows {
+			return nil
+		}
+	}
+	w := &window{}
+	ws[key] = w
+	return w
+}
+
+// BruteForce tracks the failed authentications of an API by source, user and endpoint in sliding windows.
+type BruteForce struct {
+	lock       sync.Mutex
+	thresholds Thresholds
+	sources    windows
+	users      windows
+	endpoints  windows
+}
+
+func NewBruteForce(thresholds Thresholds) *BruteForce {
+	return &BruteForce{
+		thresholds: thresholds,
+		sources:    windows{},
+		users:      windows{},
+		endpoints:  windows{},
+	}
+}
+
+// failureStatusCodes are the response status codes of a rejected authentication. Many APIs answer 403 to invalid
+// credentials as well, so a 403 is counted as a failure.
+var failureStatusCodes = map[string]bool{
+	"401": true,
+	"403": true,
+}
+
+// IsFailure returns true if the API rejected the credentials of the request.
+func IsFailure(trace *models.Telemetry) bool {
+	return trace.Response != nil && failureStatusCodes[trace.Response.StatusCode]
+}
+
+// Analyze tracks a failed authentication of the user, detected by the caller if any, on the endpoint at the time of
+// the event. It returns an event annotation per attack kind detected in the windows of the failure, and the API
+// annotations with the detail of each detection.
+func (b *BruteForce) Analyze(t time.Time, endpoint string, user string, trace *models.Telemetry) (eventAnns []core.Annotation, apiAnns []core.Annotation) {
+	if !IsFailure(trace) || trace.Request == nil || trace.Request.Common == nil {
+		return eventAnns, apiAnns
+	}
+	f := failure{time: t, source: utils.GetSourceAddress(trace), user: user}
+	f.secret, f.password = getSecret(trace.Request.Common.Headers)
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	since := t.Add(-b.thresholds.Window)
+	detect := func(kind string, d Detection) {
+		for _, a := range eventAnns {
+			if a.Name == kind {
+				return
+			}
+		}
+		d.Window = b.thresholds.Window.String()
+		bytes, err := json.Marshal(d)
+		if err != nil {
+			return
+		}
+		eventAnns = append(eventAnns, core.Annotation{Name: kind, Annotation: bytes})
+		apiAnns = append(apiAnns, core.Annotation{Name: kind, Annotation: []byte(d.String())})
+	}
+
+	if w := b.sources.get(f.source, since); w != nil {
+		w.add(f, since)
+		b.detectSource(w, f, detect)
+	}
+	if w := b.users.get(f.user, since); w != nil {
+		w.add(f, since)
+		if len(w.failures) >= b.thresholds.UserFailures {
+			detect(KindBruteForce, Detection{User: f.user, Failures: len(w.failures), Sources: w.count(failureSource, all)})
+		}
+	}
+	if w := b.endpoints.get(endpoint, since); w != nil {
+		w.add(f, since)
+		users, sources := w.count(failureUser, all), w.count(failureSource, all)
+		if users >= b.thresholds.EndpointUsers && sources > 1 {
+			detect(KindCredentialStuffing, Detection{Failures: len(w.failures), Users: users, Sources: sources})
+		}
+	}
+
+	return eventAnns, apiAnns
+}
+
+// detectSource detects the attacks from the source of the failure.
+func (b *BruteForce) detectSource(w *window, f failure, detect func(kind string, d Detection)) {
+	if f.user != "" {
+		if users := w.count(failureUser, all); users >= b.thresholds.SourceUsers {
+			d := Detection{Source: f.source, Failures: len(w.failures), Users: users}
+			// the passwords are known with basic auth, a few of them tried for many users is spraying
+			d.Passwords = w.count(failureSecret, func(f failure) bool { return f.password && f.user != "" })
+			if d.Passwords > 0 && d.Passwords <= b.thresholds.SprayingPasswords {
+				detect(KindPasswordSpraying, d)
+			} else {
+				d.Passwords = 0
+				detect(KindCredentialStuffing, d)
+			}
+		}
+	} else if f.secret != "" {
+		withoutUser := func(f failure) bool { return f.user == "" }
+		if tokens := w.count(failureSecret, withoutUser); tokens >= b.thresholds.SourceTokens {
+			detect(KindTokenGuessing, Detection{Source: f.source, Failures: len(w.failures), Tokens: tokens})
+		}
+	}
+}
+
+func failureSource(f failure) string { return f.source }
+func failureUser(f failure) string   { return f.user }
+func failureSecret(f failure) string { return f.secret }
+
+// getSecret returns a fingerprint of the credentials of the request: the basic auth password, the token of the
+// Authorization header or the API key. The secrets are not kept, nor the ones redacted by the archive, which are
+// all alike.
+func getSecret(headers []*models.Header) (secret string, password bool) {
+	for _, h := range headers {
+		key := strings.ToLower(h.Key)
+		value := strings.TrimSpace(h.Value)
+		switch {
+		case value == "", database.IsRedactedHeaderValue(key, value):
+		case key == authorizationHeader:
+			fields := strings.Fields(value)
+			if len(fields) == 2 && strings.ToLower(fields[0]) == basicScheme { //nolint:gomnd
+				if decoded, err := base64.StdEncoding.DecodeString(fields[1]); err == nil {
+					if i := strings.Index(string(decoded), ":"); i >= 0 {
+						return fingerprint(string(decoded[i+1:])), true
+					}
+				}
+			}
+			return fingerprint(value), false
+		case _utils.IsAPIKeyHeader(key):
+			return fingerprint(value), false
+		}
+	}
+	return "", false
+}
+
+func fingerprint(secret string) string {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(secret))
+	return strconv.FormatUint(h.Sum64(), 16) //nolint:gomnd
+}
@@ -0,0 +1,161 @@
+// Copyright © 2022 Cisco Systems, Inc. and its affiliates.
+// All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bruteforce
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/openclarity/apiclarity/backend/pkg/database"
+	"github.com/openclarity/apiclarity/backend/pkg/modules/internal/core"
+	"github.com/openclarity/apiclarity/plugins/api/server/models"
+)
+
+func newTrace(source string, statusCode string, headers ...*models.Header) *models.Telemetry {
+	return &models.Telemetry{
+		SourceAddress: source + ":51000",
+		Request: &models.Request{
+			Method: "POST",
+			Path:   "/login",
+			Common: &models.Common{Headers: headers},
+		},
+		Response: &models.Response{StatusCode: statusCode, Common: &models.Common{}},
+	}
+}
+
+func basicAuth(user string, password string) *models.Header {
+	return &models.Header{Key: "Authorization", Value: "Basic " + base64.StdEncoding.EncodeToString([]byte(user+":"+password))}
+}
+
+func annsNames(anns []core.Annotation) []string {
+	names := []string{}
+	for _, a := range anns {
+		names = append(names, a.Name)
+	}
+	return names
+}
+
+func TestParseThresholds(t *testing.T) {
+	got, err := ParseThresholds(" window=10m, userFailures=5 ")
+	wanted := DefaultThresholds()
+	wanted.Window, wanted.UserFailures = 10*time.Minute, 5
+	if err != nil || got != wanted {
+		t.Errorf("ParseThresholds() = %+v, %v, wanted %+v", got, err, wanted)
+	}
+	if got, err := ParseThresholds(""); err != nil || got != DefaultThresholds() {
+		t.Errorf("ParseThresholds() = %+v, %v for the defaults", got, err)
+	}
+	for _, config := range []string{"window", "window=-1m", "userFailures=0", "users=10"} {
+		if _, err := ParseThresholds(config); err == nil {
+			t.Errorf("ParseThresholds(%q) succeeded", config)
+		}
+	}
+}
+
+func TestBruteForce_PasswordSpraying(t *testing.T) {
+	b := NewBruteForce(DefaultThresholds())
+	now := time.Now()
+
+	var eventAnns, apiAnns []core.Annotation
+	for i := 0; i < b.thresholds.SourceUsers; i++ {
+		user := fmt.Sprintf("user%d", i)
+		eventAnns, apiAnns = b.Analyze(now, "POST /login", user, newTrace("10.0.0.1", "401", basicAuth(user, "Winter2022")))
+		if i < b.thresholds.SourceUsers-1 && len(eventAnns) != 0 {
+			t.Fatalf("Analyze() = %v before the threshold", annsNames(eventAnns))
+		}
+	}
+	if len(eventAnns) != 1 || eventAnns[0].Name != KindPasswordSpraying ||
+		string(eventAnns[0].Annotation) != `{"source":"10.0.0.1","failures":10,"users":10,"passwords":1,"window":"5m0s"}` ||
+		string(apiAnns[0].Annotation) != "10 failures of 10 users with 1 password from 10.0.0.1 in 5m0s" {
+		t.Errorf("Analyze() = %s", eventAnns)
+	}
+
+	// the users are not sprayed with many passwords
+	for i := 0; i < b.thresholds.SourceUsers; i++ {
+		user := fmt.Sprintf("user%d", i)
+		eventAnns, _ = b.Analyze(now.Add(time.Second), "POST /login", user, newTrace("10.0.0.2", "401", basicAuth(user, user)))
+	}
+	if len(eventAnns) != 1 || eventAnns[0].Name != KindCredentialStuffing {
+		t.Errorf("Analyze() = %v with distinct passwords", annsNames(eventAnns))
+	}
+
+	// the passwords redacted by the archive are unknown, not the same one
+	for i := 0; i < b.thresholds.SourceUsers; i++ {
+		user := fmt.Sprintf("user%d", i)
+		eventAnns, _ = b.Analyze(now.Add(2*time.Second), "POST /login", user, newTrace("10.0.0.3", "401", basicAuth(user, database.RedactedValue)))
+	}
+	if len(eventAnns) != 1 || eventAnns[0].Name != KindCredentialStuffing {
+		t.Errorf("Analyze() = %v with redacted passwords", annsNames(eventAnns))
+	}
+}
+
+func TestBruteForce_Windows(t *testing.T) {
+	thresholds := DefaultThresholds()
+	thresholds.UserFailures, thresholds.EndpointUsers = 3, 4
+	b := NewBruteForce(thresholds)
+	now := time.Now()
+
+	// the failures of the window only
+	b.Analyze(now.Add(-time.Hour), "POST /login", "alice", newTrace("10.0.0.1", "401", basicAuth("alice", "1")))
+	b.Analyze(now.Add(-time.Second), "POST /login", "alice", newTrace("10.0.0.1", "401", basicAuth("alice", "2")))
+	if anns, _ := b.Analyze(now, "POST /login", "alice", newTrace("10.0.0.2", "200", basicAuth("alice", "3"))); len(anns) != 0 {
+		t.Errorf("Analyze() = %v for a successful authentication", annsNames(anns))
+	}
+	if anns, _ := b.Analyze(now, "POST /login", "alice", newTrace("10.0.0.2", "401", basicAuth("alice", "4"))); len(anns) != 0 {
+		t.Errorf("Analyze() = %v with an expired failure", annsNames(anns))
+	}
+	anns, apiAnns := b.Analyze(now, "POST /login", "alice", newTrace("10.0.0.3", "401", basicAuth("alice", "5")))
+	if len(anns) != 1 || anns[0].Name != KindBruteForce || string(apiAnns[0].Annotation) != "3 failures of user 'alice' from 3 sources in 5m0s" {
+		t.Errorf("Analyze() = %v, %s", annsNames(anns), apiAnns)
+	}
+	// the credentials are rejected with a 403
+	anns, apiAnns = b.Analyze(now, "POST /login", "alice", newTrace("10.0.0.3", "403", basicAuth("alice", "6")))
+	if len(anns) != 1 || anns[0].Name != KindBruteForce || string(apiAnns[0].Annotation) != "4 failures of user 'alice' from 3 sources in 5m0s" {
+		t.Errorf("Analyze() = %v, %s for a 403", annsNames(anns), apiAnns)
+	}
+	// a success is not a failure
+	if anns, _ := b.Analyze(now, "POST /login", "alice", newTrace("10.0.0.3", "200", basicAuth("alice", "7"))); len(anns) != 0 {
+		t.Errorf("Analyze() = %v for a success", annsNames(anns))
+	}
+
+	// distributed over the sources
+	b.Analyze(now, "POST /login", "bob", newTrace("10.0.0.4", "401", basicAuth("bob", "1")))
+	b.Analyze(now, "POST /login", "carol", newTrace("10.0.0.5", "401", basicAuth("carol", "1")))
+	anns, _ = b.Analyze(now, "POST /login", "dave", newTrace("10.0.0.6", "401", basicAuth("dave", "1")))
+	if len(anns) != 1 || anns[0].Name != KindCredentialStuffing {
+		t.Errorf("Analyze() = %v for many users failing on an endpoint", annsNames(anns))
+	}
+}
+
+func TestBruteForce_TokenGuessing(t *testing.T) {
+	b := NewBruteForce(DefaultThresholds())
+	now := time.Now()
+
+	var anns []core.Annotation
+	for i := 0; i < b.thresholds.SourceTokens; i++ {
+		trace := newTrace("10.0.0.1", "401", &models.Header{Key: "X-Forwarded-For", Value: "192.168.1.1, 10.0.0.1"},
+			&models.Header{Key: "X-API-Key", Value: fmt.Sprintf("key-%d", i)})
+		anns, _ = b.Analyze(now, "GET /orders", "", trace)
+	}
+	var d Detection
+	if len(anns) != 1 || anns[0].Name != KindTokenGuessing || json.Unmarshal(anns[0].Annotation, &d) != nil ||
+		d.Source != "192.168.1.1" || d.Tokens != b.thresholds.SourceTokens {
+		t.Errorf("Analyze() = %s", anns)
+	}
+}
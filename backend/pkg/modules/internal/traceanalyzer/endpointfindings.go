@@ -0,0 +1,119 @@
+// Copyright © 2022 Cisco Systems, Inc. and its affiliates.
+// All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package traceanalyzer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/openclarity/apiclarity/backend/pkg/modules/internal/core"
+	"github.com/openclarity/apiclarity/backend/pkg/modules/internal/traceanalyzer/bruteforce"
+	"github.com/openclarity/apiclarity/backend/pkg/modules/internal/traceanalyzer/enumeration"
+	"github.com/openclarity/apiclarity/backend/pkg/modules/internal/traceanalyzer/securityheaders"
+)
+
+// isEndpointFindingKind returns true if the annotation lists the endpoints of a kind of finding.
+func isEndpointFindingKind(name string) bool {
+	return securityheaders.IsKind(name) || bruteforce.IsKind(name) || enumeration.IsKind(name)
+}
+
+// endpointFindings aggregates the findings of the security headers, brute force and enumeration analyzers: an API annotation
+// per kind lists the endpoints where the kind was seen, and is stored again only when a new endpoint is found.
+// The stored endpoints are loaded the first time an API is seen.
+type endpointFindings struct {
+	lock     sync.Mutex
+	accessor core.BackendAccessor
+	apis     map[uint]map[string]*securityheaders.Finding
+}
+
+func newEndpointFindings(accessor core.BackendAccessor) *endpointFindings {
+	return &endpointFindings{
+		accessor: accessor,
+		apis:     map[uint]map[string]*securityheaders.Finding{},
+	}
+}
+
+// add adds the endpoint of the findings of a trace, it returns the API annotations of the kinds with a new endpoint.
+func (e *endpointFindings) add(ctx context.Context, apiID uint, method string, location string, anns []core.Annotation) (apiAnns []core.Annotation) {
+	if len(anns) == 0 {
+		return apiAnns
+	}
+
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	findings, err := e.load(ctx, apiID)
+	if err != nil {
+		// the stored endpoints would be overwritten, the findings are added once they are loaded
+		log.Warnf("[TraceAnalyzer] unable to load the endpoint findings of API %d: %v", apiID, err)
+		return apiAnns
+	}
+	for _, a := range anns {
+		f, ok := findings[a.Name]
+		if !ok {
+			f = &securityheaders.Finding{}
+			findings[a.Name] = f
+		}
+		if !f.Add(securityheaders.Endpoint{Method: method, Location: location, Detail: string(a.Annotation)}) {
+			continue
+		}
+		bytes, err := json.Marshal(f)
+		if err == nil {
+			apiAnns = append(apiAnns, core.Annotation{Name: a.Name, Annotation: bytes})
+		}
+	}
+
+	return apiAnns
+}
+
+// load returns the findings of the API, loaded from its annotations if needed. The lock must be held.
+func (e *endpointFindings) load(ctx context.Context, apiID uint) (map[string]*securityheaders.Finding, error) {
+	if findings, ok := e.apis[apiID]; ok {
+		return findings, nil
+	}
+
+	anns, err := e.accessor.ListAPIInfoAnnotations(ctx, moduleName, apiID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the annotations: %w", err)
+	}
+	findings := map[string]*securityheaders.Finding{}
+	for _, a := range anns {
+		if !isEndpointFindingKind(a.Name) {
+			continue
+		}
+		f := &securityheaders.Finding{}
+		if err := json.Unmarshal(a.Annotation, f); err != nil {
+			log.Warnf("[TraceAnalyzer] discarding the invalid %s finding of API %d: %v", a.Name, apiID, err)
+			continue
+		}
+		findings[a.Name] = f
+	}
+	e.apis[apiID] = findings
+
+	return findings, nil
+}
+
+// forget drops the findings of the API, they are loaded again from its annotations, e.g. after they were deleted.
+func (e *endpointFindings) forget(apiID uint) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	delete(e.apis, apiID)
+}
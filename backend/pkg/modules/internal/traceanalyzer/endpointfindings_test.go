@@ -0,0 +1,78 @@
+// Copyright © 2022 Cisco Systems, Inc. and its affiliates.
+// All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package traceanalyzer
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+
+	"github.com/openclarity/apiclarity/backend/pkg/modules/internal/core"
+	"github.com/openclarity/apiclarity/backend/pkg/modules/internal/traceanalyzer/securityheaders"
+)
+
+func TestEndpointFindings_add(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	accessor := core.NewMockBackendAccessor(mockCtrl)
+	ctx := context.Background()
+
+	stored := []*core.Annotation{
+		{Name: securityheaders.KindHSTSMissing, Annotation: []byte(`{"endpoints":[{"method":"GET","location":"/users"}]}`)},
+		{Name: "GUESSABLE_ID", Annotation: []byte(`{"location":"/users/{id}"}`)},
+	}
+	accessor.EXPECT().ListAPIInfoAnnotations(gomock.Any(), moduleName, uint(1)).Return(nil, errors.New("db error"))
+	accessor.EXPECT().ListAPIInfoAnnotations(gomock.Any(), moduleName, uint(1)).Return(stored, nil).Times(2)
+
+	h := newEndpointFindings(accessor)
+	anns := []core.Annotation{{Name: securityheaders.KindHSTSMissing}, {Name: securityheaders.KindHSTSWeak, Annotation: []byte("max-age=0")}}
+
+	// the stored endpoints are not overwritten when they can't be loaded
+	if got := h.add(ctx, 1, "GET", "/orders", anns); len(got) != 0 {
+		t.Errorf("add() = %v without the stored findings", got)
+	}
+
+	got := h.add(ctx, 1, "GET", "/orders", anns)
+	if len(got) != 2 || got[0].Name != securityheaders.KindHSTSMissing ||
+		string(got[0].Annotation) != `{"endpoints":[{"method":"GET","location":"/users"},{"method":"GET","location":"/orders"}]}` ||
+		string(got[1].Annotation) != `{"endpoints":[{"method":"GET","location":"/orders","detail":"max-age=0"}]}` {
+		t.Errorf("add() = %v", got)
+	}
+
+	// the findings of a known endpoint are not stored again
+	if got := h.add(ctx, 1, "GET", "/users", anns[:1]); len(got) != 0 {
+		t.Errorf("add() = %v for a known endpoint", got)
+	}
+
+	h.forget(1)
+	if got := h.add(ctx, 1, "GET", "/orders", anns[:1]); len(got) != 1 || !strings.Contains(string(got[0].Annotation), "/orders") {
+		t.Errorf("add() = %v after the findings were forgotten", got)
+	}
+}
+
+func TestGetSecurityHeadersFinding(t *testing.T) {
+	f := getAPIDescription(core.Annotation{
+		Name:       securityheaders.KindCORSWildcardWithCredentials,
+		Annotation: []byte(`{"endpoints":[{"method":"GET","location":"/users"},{"method":"POST","location":"/users","detail":"origin 'a' allowed"}]}`),
+	})
+	if f.Severity != SeverityHigh || f.Alert == nil ||
+		f.DetailedDesc != "The responses allow any origin with credentials: 'GET /users', 'POST /users' (origin 'a' allowed)" {
+		t.Errorf("getAPIDescription() = %+v", f)
+	}
+}
@@ -0,0 +1,343 @@
+// Copyright © 2022 Cisco Systems, Inc. and its affiliates.
+// All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package enumeration
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/openclarity/apiclarity/backend/pkg/modules/internal/core"
+	"github.com/openclarity/apiclarity/backend/pkg/modules/internal/traceanalyzer/utils"
+	"github.com/openclarity/apiclarity/plugins/api/server/models"
+)
+
+const (
+	// A client requests many consecutive integer values of a path parameter of an operation.
+	KindSequentialEnumeration = "SEQUENTIAL_ENUMERATION"
+	// A client requests many distinct values of a path parameter of an operation.
+	KindIDEnumeration = "ID_ENUMERATION"
+	// A client reads many resources successfully.
+	KindScraping = "SCRAPING"
+)
+
+const (
+	// The values kept in a window are capped, the oldest ones are dropped, the values thresholds can't be above it.
+	MaxWindowValues = 200
+	// The windows tracked by client, operation and parameter are capped, the values of an API take at most
+	// MaxWindows * MaxWindowValues.
+	MaxWindows = 1000
+	// The consecutive values cover at least this ratio of the range between the lowest and the highest one.
+	SequentialDensity = 0.5
+)
+
+var kinds = map[string]bool{
+	KindSequentialEnumeration: true,
+	KindIDEnumeration:         true,
+	KindScraping:              true,
+}
+
+// IsKind returns true if the annotation is a finding of the analyzer.
+func IsKind(name string) bool {
+	return kinds[name]
+}
+
+// Thresholds are the successful requests of a client, within the window, from which a behavior is detected.
+type Thresholds struct {
+	Window time.Duration
+	// ID_ENUMERATION: the distinct values of a path parameter of an operation.
+	Values int
+	// SEQUENTIAL_ENUMERATION: the distinct integer values of a path parameter of an operation, mostly consecutive.
+	SequentialValues int
+	// SCRAPING: the successful reads (GET and HEAD requests) on the API.
+	Reads int
+}
+
+func DefaultThresholds() Thresholds {
+	return Thresholds{
+		Window:           10 * time.Minute,
+		Values:           100,
+		SequentialValues: 20,
+		Reads:            1000,
+	}
+}
+
+// ParseThresholds overrides the default thresholds with a comma separated list of "<name>=<value>", e.g.
+// "window=5m,values=50". The names are window, values, sequentialValues and reads, the values thresholds are at
+// most MaxWindowValues.
+func ParseThresholds(config string) (Thresholds, error) {
+	t := DefaultThresholds()
+	if err := utils.ParseThresholds(config, &t.Window, map[string]*int{
+		"values":           &t.Values,
+		"sequentialValues": &t.SequentialValues,
+		"reads":            &t.Reads,
+	}); err != nil {
+		return t, err
+	}
+	if t.Values > MaxWindowValues || t.SequentialValues > MaxWindowValues {
+		return t, fmt.Errorf("the values thresholds are at most %d", MaxWindowValues)
+	}
+	return t, nil
+}
+
+// Detection is the annotation of an event of a client enumerating or scraping the API, with what was seen in the
+// window. The client is the detected user, or else the source of the request.
+type Detection struct {
+	User      string `json:"user,omitempty"`
+	Source    string `json:"source,omitempty"`
+	Operation string `json:"operation,omitempty"`
+	Parameter string `json:"parameter,omitempty"`
+	Values    int    `json:"values,omitempty"`
+	Range     string `json:"range,omitempty"`
+	Reads     int    `json:"reads,omitempty"`
+	// The guessable ID analyzer found the values of the parameter guessable.
+	GuessableID bool   `json:"guessableId,omitempty"`
+	Window      string `json:"window"`
+}
+
+func (d Detection) String() string {
+	s := "source " + d.Source
+	if d.User != "" {
+		s = fmt.Sprintf("user '%s'", d.User)
+	}
+	if d.Parameter != "" {
+		s += fmt.Sprintf(" requested %d values of '%s'", d.Values, d.Parameter)
+		if d.Range != "" {
+			s += " (" + d.Range + ")"
+		}
+	} else {
+		s += fmt.Sprintf(" read %d resources", d.Reads)
+	}
+	s += " in " + d.Window
+	if d.GuessableID {
+		s += ", the identifiers are guessable"
+	}
+	return s
+}
+
+type value struct {
+	time  time.Time
+	value string
+}
+
+// valuesWindow holds the values of the last period, by time, with the count of each distinct value.
+type valuesWindow struct {
+	values []value
+	counts map[string]int
+}
+
+func (w *valuesWindow) drop(n int) {
+	for _, v := range w.values[:n] {
+		if w.counts[v.value]--; w.counts[v.value] == 0 {
+			delete(w.counts, v.value)
+		}
+	}
+	w.values = w.values[n:]
+}
+
+func (w *valuesWindow) expire(since time.Time) {
+	i := 0
+	for i < len(w.values) && w.values[i].time.Before(since) {
+		i++
+	}
+	w.drop(i)
+}
+
+func (w *valuesWindow) add(v value, since time.Time) {
+	w.expire(since)
+	w.values = append(w.values, v)
+	w.counts[v.value]++
+	if len(w.values) > MaxWindowValues {
+		w.drop(len(w.values) - MaxWindowValues)
+	}
+}
+
+func (w *valuesWindow) empty() bool {
+	return len(w.values) == 0
+}
+
+// sequence returns the number of distinct integer values and their range, if they are mostly consecutive.
+func (w *valuesWindow) sequence() (n int, first int64, last int64, ok bool) {
+	ints := make([]int64, 0, len(w.counts))
+	for v := range w.counts {
+		if i, err := strconv.ParseInt(v, 10, 64); err == nil { //nolint:gomnd
+			ints = append(ints, i)
+		}
+	}
+	if len(ints) == 0 {
+		return 0, 0, 0, false
+	}
+	sort.Slice(ints, func(i, j int) bool { return ints[i] < ints[j] })
+	first, last = ints[0], ints[len(ints)-1]
+	span := float64(last) - float64(first) + 1
+	return len(ints), first, last, float64(len(ints))/span >= SequentialDensity
+}
+
+// readsWindow counts the reads of the last period by second, the counts of a client are bounded by the window.
+type readsWindow struct {
+	seconds []int64
+	counts  []int
+	total   int
+}
+
+func (w *readsWindow) expire(since time.Time) {
+	i := 0
+	for i < len(w.seconds) && w.seconds[i] < since.Unix() {
+		w.total -= w.counts[i]
+		i++
+	}
+	w.seconds, w.counts = w.seconds[i:], w.counts[i:]
+}
+
+func (w *readsWindow) add(t time.Time, since time.Time) {
+	w.expire(since)
+	// an event older than the last one is counted in the last second
+	if n := len(w.seconds); n > 0 && w.seconds[n-1] >= t.Unix() {
+		w.counts[n-1]++
+	} else {
+		w.seconds = append(w.seconds, t.Unix())
+		w.counts = append(w.counts, 1)
+	}
+	w.total++
+}
+
+func (w *readsWindow) empty() bool {
+	return w.total == 0
+}
+
+type window interface {
+	expire(since time.Time)
+	empty() bool
+}
+
+// getWindow returns the window of the key, nil once the windows are capped.
+func getWindow(windows map[string]window, key string, since time.Time, newWindow func() window) window {
+	if w, ok := windows[key]; ok {
+		return w
+	}
+	if len(windows) >= MaxWindows {
+		for k, w := range windows {
+			if w.expire(since); w.empty() {
+				delete(windows, k)
+			}
+		}
+		if len(windows) >= MaxWindows {
+			return nil
+		}
+	}
+	w := newWindow()
+	windows[key] = w
+	return w
+}
+
+// Enumeration tracks the successful requests of the clients of an API in sliding windows: the values of the path
+// parameters of each operation, and the reads.
+type Enumeration struct {
+	lock       sync.Mutex
+	thresholds Thresholds
+	values     map[string]window
+	reads      map[string]window
+}
+
+func NewEnumeration(thresholds Thresholds) *Enumeration {
+	return &Enumeration{
+		thresholds: thresholds,
+		values:     map[string]window{},
+		reads:      map[string]window{},
+	}
+}
+
+// Analyze tracks a successful request of the user, detected by the caller if any, on the operation at the time of
+// the event, with the path parameters of the operation. The guessable ID analyzer tells whether the values of a
+// path parameter are guessable. It returns an event annotation per behavior detected in the windows of the client,
+// and the API annotations with the detail of each detection.
+func (e *Enumeration) Analyze(t time.Time, method string, location string, user string, pathParams map[string]string,
+	trace *models.Telemetry, isGuessable func(name string) bool,
+) (eventAnns []core.Annotation, apiAnns []core.Annotation) {
+	client := Detection{User: user}
+	if user == "" {
+		if client.Source = utils.GetSourceAddress(trace); client.Source == "" {
+			return eventAnns, apiAnns
+		}
+	}
+	clientKey := client.User + "\x00" + client.Source
+
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	since := t.Add(-e.thresholds.Window)
+	detect := func(kind string, d Detection) {
+		for _, a := range eventAnns {
+			if a.Name == kind {
+				return
+			}
+		}
+		d.Window = e.thresholds.Window.String()
+		bytes, err := json.Marshal(d)
+		if err != nil {
+			return
+		}
+		eventAnns = append(eventAnns, core.Annotation{Name: kind, Annotation: bytes})
+		apiAnns = append(apiAnns, core.Annotation{Name: kind, Annotation: []byte(d.String())})
+	}
+
+	// the parameters are analyzed in order, so that the same parameter is reported for the same request
+	names := make([]string, 0, len(pathParams))
+	for name := range pathParams {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	operation := method + " " + location
+	for _, name := range names {
+		key := clientKey + "\x00" + operation + "\x00" + name
+		w, _ := getWindow(e.values, key, since, func() window { return &valuesWindow{counts: map[string]int{}} }).(*valuesWindow)
+		if w == nil {
+			continue
+		}
+		w.add(value{time: t, value: pathParams[name]}, since)
+		if len(w.counts) < e.thresholds.SequentialValues && len(w.counts) < e.thresholds.Values {
+			continue
+		}
+
+		d := client
+		d.Operation, d.Parameter = operation, name
+		if n, first, last, ok := w.sequence(); ok && n >= e.thresholds.SequentialValues {
+			d.Values, d.Range = n, fmt.Sprintf("%d..%d", first, last)
+			d.GuessableID = isGuessable(name)
+			detect(KindSequentialEnumeration, d)
+		} else if len(w.counts) >= e.thresholds.Values {
+			d.Values = len(w.counts)
+			d.GuessableID = isGuessable(name)
+			detect(KindIDEnumeration, d)
+		}
+	}
+
+	if method == "GET" || method == "HEAD" {
+		if w, _ := getWindow(e.reads, clientKey, since, func() window { return &readsWindow{} }).(*readsWindow); w != nil {
+			w.add(t, since)
+			if w.total >= e.thresholds.Reads {
+				d := client
+				d.Reads = w.total
+				detect(KindScraping, d)
+			}
+		}
+	}
+
+	return eventAnns, apiAnns
+}
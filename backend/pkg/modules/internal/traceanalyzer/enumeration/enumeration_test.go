@@ -0,0 +1,140 @@
+// Copyright © 2022 Cisco Systems, Inc. and its affiliates.
+// All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package enumeration
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/openclarity/apiclarity/backend/pkg/modules/internal/core"
+	"github.com/openclarity/apiclarity/plugins/api/server/models"
+)
+
+func newTrace(source string) *models.Telemetry {
+	return &models.Telemetry{
+		SourceAddress: source + ":51000",
+		Request:       &models.Request{Method: "GET", Common: &models.Common{}},
+		Response:      &models.Response{StatusCode: "200", Common: &models.Common{}},
+	}
+}
+
+func notGuessable(string) bool { return false }
+
+func annsNames(anns []core.Annotation) []string {
+	names := []string{}
+	for _, a := range anns {
+		names = append(names, a.Name)
+	}
+	return names
+}
+
+func TestParseThresholds(t *testing.T) {
+	got, err := ParseThresholds("window=5m,values=50")
+	wanted := DefaultThresholds()
+	wanted.Window, wanted.Values = 5*time.Minute, 50
+	if err != nil || got != wanted {
+		t.Errorf("ParseThresholds() = %+v, %v, wanted %+v", got, err, wanted)
+	}
+	for _, config := range []string{"reads=many", "userFailures=5", "values=500"} {
+		if _, err := ParseThresholds(config); err == nil {
+			t.Errorf("ParseThresholds(%q) succeeded", config)
+		}
+	}
+}
+
+func TestEnumeration_Sequential(t *testing.T) {
+	e := NewEnumeration(DefaultThresholds())
+	now := time.Now()
+	guessable := func(name string) bool { return name == "orderId" }
+
+	var eventAnns, apiAnns []core.Annotation
+	for i := 0; i < e.thresholds.SequentialValues; i++ {
+		// every other order
+		params := map[string]string{"orderId": fmt.Sprint(1000 + 2*i)}
+		eventAnns, apiAnns = e.Analyze(now, "GET", "/orders/{orderId}", "alice", params, newTrace("10.0.0.1"), guessable)
+		if i < e.thresholds.SequentialValues-1 && len(eventAnns) != 0 {
+			t.Fatalf("Analyze() = %v before the threshold", annsNames(eventAnns))
+		}
+	}
+	if len(eventAnns) != 1 || eventAnns[0].Name != KindSequentialEnumeration ||
+		string(eventAnns[0].Annotation) != `{"user":"alice","operation":"GET /orders/{orderId}","parameter":"orderId","values":20,"range":"1000..1038","guessableId":true,"window":"10m0s"}` ||
+		string(apiAnns[0].Annotation) != "user 'alice' requested 20 values of 'orderId' (1000..1038) in 10m0s, the identifiers are guessable" {
+		t.Errorf("Analyze() = %s, %s", eventAnns, apiAnns)
+	}
+
+	// another client, or another operation, is tracked apart
+	if anns, _ := e.Analyze(now, "DELETE", "/orders/{orderId}", "alice", map[string]string{"orderId": "1"}, newTrace("10.0.0.1"), guessable); len(anns) != 0 {
+		t.Errorf("Analyze() = %v for another operation", annsNames(anns))
+	}
+	if anns, _ := e.Analyze(now, "POST", "/orders/{orderId}", "", map[string]string{"orderId": "1"}, newTrace("10.0.0.1"), guessable); len(anns) != 0 {
+		t.Errorf("Analyze() = %v for another client", annsNames(anns))
+	}
+}
+
+func TestEnumeration_Values(t *testing.T) {
+	thresholds := DefaultThresholds()
+	thresholds.Values = 30
+	e := NewEnumeration(thresholds)
+	now := time.Now()
+
+	// the values expire with the window
+	for i := 0; i < thresholds.Values-1; i++ {
+		e.Analyze(now.Add(-time.Hour), "POST", "/users/{name}", "", map[string]string{"name": fmt.Sprintf("user%d", i)}, newTrace("10.0.0.1"), notGuessable)
+	}
+	var anns []core.Annotation
+	for i := 0; i < thresholds.Values; i++ {
+		// sparse integers, and names
+		value := fmt.Sprintf("user%d", i)
+		if i%2 == 0 {
+			value = fmt.Sprint(i * 1000)
+		}
+		anns, _ = e.Analyze(now, "POST", "/users/{name}", "", map[string]string{"name": value}, newTrace("10.0.0.1"), notGuessable)
+		if i < thresholds.Values-1 && len(anns) != 0 {
+			t.Fatalf("Analyze() = %v with expired values", annsNames(anns))
+		}
+	}
+	if len(anns) != 1 || anns[0].Name != KindIDEnumeration ||
+		string(anns[0].Annotation) != `{"source":"10.0.0.1","operation":"POST /users/{name}","parameter":"name","values":30,"window":"10m0s"}` {
+		t.Errorf("Analyze() = %s", anns)
+	}
+}
+
+func TestEnumeration_Scraping(t *testing.T) {
+	thresholds := DefaultThresholds()
+	thresholds.Reads = 50
+	e := NewEnumeration(thresholds)
+	now := time.Now()
+
+	var eventAnns, apiAnns []core.Annotation
+	for i := 0; i < thresholds.Reads; i++ {
+		eventAnns, apiAnns = e.Analyze(now.Add(time.Duration(i)*time.Second), "GET", "/catalog", "", nil, newTrace("10.0.0.1"), notGuessable)
+		if i < thresholds.Reads-1 && len(eventAnns) != 0 {
+			t.Fatalf("Analyze() = %v before the threshold", annsNames(eventAnns))
+		}
+	}
+	if len(eventAnns) != 1 || eventAnns[0].Name != KindScraping || string(apiAnns[0].Annotation) != "source 10.0.0.1 read 50 resources in 10m0s" {
+		t.Errorf("Analyze() = %s, %s", eventAnns, apiAnns)
+	}
+
+	// the writes are not reads, and the reads expire
+	if anns, _ := e.Analyze(now.Add(time.Minute), "POST", "/catalog", "", nil, newTrace("10.0.0.1"), notGuessable); len(anns) != 0 {
+		t.Errorf("Analyze() = %v for a write", annsNames(anns))
+	}
+	if anns, _ := e.Analyze(now.Add(time.Hour), "GET", "/catalog", "", nil, newTrace("10.0.0.1"), notGuessable); len(anns) != 0 {
+		t.Errorf("Analyze() = %v once the reads expired", annsNames(anns))
+	}
+}
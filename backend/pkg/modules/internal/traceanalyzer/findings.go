@@ -0,0 +1,58 @@
+// Copyright © 2022 Cisco Systems, Inc. and its affiliates.
+// All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package traceanalyzer
+
+import (
+	"github.com/openclarity/apiclarity/backend/pkg/database"
+	"github.com/openclarity/apiclarity/backend/pkg/modules/internal/core"
+)
+
+// DescribeEventFinding describes the findings of the events for the findings feed. The alerts summarize the
+// findings of the event, and the suppressed findings are hidden, as in the annotations endpoints.
+func (p *traceAnalyzer) DescribeEventFinding(event *database.APIEvent, ann core.Annotation) (core.Finding, bool) {
+	if ann.Name == core.AlertAnnotation {
+		return core.Finding{}, false
+	}
+	if _, suppressed := p.suppressions.Find(newFindingScope(event.APIInfoID, ann, string(event.Method), event.Path)); suppressed {
+		return core.Finding{}, false
+	}
+	f := getEventDescription(ann)
+	return toCoreFinding(f), true
+}
+
+// DescribeAPIFinding describes the findings of the APIs for the findings feed, the learnt histories are not findings.
+func (p *traceAnalyzer) DescribeAPIFinding(apiID uint, ann core.Annotation) (core.Finding, bool) {
+	if isLearningStateAnnotation(ann.Name) {
+		return core.Finding{}, false
+	}
+	if _, suppressed := p.suppressions.Find(newFindingScope(apiID, ann, "")); suppressed {
+		return core.Finding{}, false
+	}
+	f := getAPIDescription(ann)
+	return toCoreFinding(f), true
+}
+
+func toCoreFinding(f Finding) core.Finding {
+	severity := f.Severity
+	if severity == SeverityWarn {
+		severity = core.FindingSeverityLow
+	}
+	return core.Finding{
+		Name:        f.ShortDesc,
+		Description: f.DetailedDesc,
+		Severity:    severity,
+	}
+}
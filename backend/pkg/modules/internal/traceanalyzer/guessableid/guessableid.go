@@ -18,7 +18,11 @@ package guessableid
 import (
 	"bytes"
 	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"sort"
 	"strings"
+	"sync"
 
 	edlib "github.com/hbollon/go-edlib"
 	uuid "github.com/satori/go.uuid"
@@ -54,6 +58,8 @@ type paramHistory struct {
 	enoughData bool
 	i          int
 	typeHint   TypeHint
+	// guessable is the verdict of the last full history
+	guessable bool
 }
 
 func newParamHistory(maxHistory uint) *paramHistory {
@@ -196,10 +202,23 @@ func (p *paramHistory) distance() float32 {
 }
 
 type GuessableAnalyzer struct {
+	lock       sync.Mutex
 	maxHistory uint
 	history    map[paramLocKey]*paramHistory
 }
 
+// guessableState is the JSON form of the analyzer. The learning itself is not persisted: the values of the
+// parameters may be sensitive, only the parameters found guessable are, and their values are learnt again.
+type guessableState struct {
+	MaxHistory uint                  `json:"maxHistory"`
+	Guessable  []guessableParamState `json:"guessable"`
+}
+
+type guessableParamState struct {
+	Operation string `json:"operation,omitempty"`
+	Name      string `json:"name"`
+}
+
 func NewGuessableAnalyzer(maxHistory uint) *GuessableAnalyzer {
 	return &GuessableAnalyzer{
 		maxHistory: maxHistory,
@@ -207,12 +226,69 @@ func NewGuessableAnalyzer(maxHistory uint) *GuessableAnalyzer {
 	}
 }
 
+// MaxHistory returns the number of values kept for each parameter.
+func (g *GuessableAnalyzer) MaxHistory() uint {
+	return g.maxHistory
+}
+
+func (g *GuessableAnalyzer) MarshalJSON() ([]byte, error) {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	state := guessableState{MaxHistory: g.maxHistory, Guessable: []guessableParamState{}}
+	for key, p := range g.history {
+		if p.guessable {
+			state.Guessable = append(state.Guessable, guessableParamState{Operation: key.operation, Name: key.name})
+		}
+	}
+	// stable output, so that an unchanged history is persisted identically
+	sort.Slice(state.Guessable, func(i, j int) bool {
+		if state.Guessable[i].Operation != state.Guessable[j].Operation {
+			return state.Guessable[i].Operation < state.Guessable[j].Operation
+		}
+		return state.Guessable[i].Name < state.Guessable[j].Name
+	})
+
+	return json.Marshal(state)
+}
+
+func (g *GuessableAnalyzer) UnmarshalJSON(data []byte) error {
+	var state guessableState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return err
+	}
+	if state.MaxHistory == 0 {
+		return fmt.Errorf("invalid history size %d", state.MaxHistory)
+	}
+
+	history := make(map[paramLocKey]*paramHistory, len(state.Guessable))
+	for _, param := range state.Guessable {
+		if param.Name == "" {
+			return fmt.Errorf("invalid guessable parameter of operation '%s'", param.Operation)
+		}
+		p := newParamHistory(state.MaxHistory)
+		p.guessable = true
+		history[paramLocKey{operation: param.Operation, name: param.Name}] = p
+	}
+
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	g.maxHistory = state.MaxHistory
+	g.history = history
+
+	return nil
+}
+
 func (g *GuessableAnalyzer) IsGuessableParam(location string, name string, value string) (bool, GuessableReason) {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
 	key := paramLocKey{location, name}
 	timeToCheck := g.learnParam(key, value)
 
-	if timeToCheck && g.history[key].enoughData {
-		if similar, reason := g.history[key].isSimilar(); similar {
+	if p := g.history[key]; timeToCheck && p.enoughData {
+		similar, reason := p.isSimilar()
+		if p.guessable = similar; similar {
 			return true, reason
 		}
 	}
@@ -231,3 +307,12 @@ func (g *GuessableAnalyzer) learnParam(key paramLocKey, value string) bool {
 
 	return p.i == 0
 }
+
+// IsGuessable returns true if the last learnt values of the parameter looked guessable, without learning a new value.
+func (g *GuessableAnalyzer) IsGuessable(location string, name string) bool {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	p := g.history[paramLocKey{location, name}]
+	return p != nil && p.guessable
+}
@@ -16,6 +16,7 @@
 package guessableid
 
 import (
+	"encoding/json"
 	"testing"
 
 	uuid "github.com/satori/go.uuid"
@@ -110,3 +111,55 @@ func TestHistory(t *testing.T) {
 		}
 	}
 }
+
+func TestGuessableAnalyzer_JSON(t *testing.T) {
+	values := []string{"0000001", "0000002", "0000003", "0000004", "0000005", "0000006", "0000007", "0000008", "0000009", "0000010"}
+
+	analyzer := NewGuessableAnalyzer(MaxParamHistory)
+	for _, v := range values[:5] {
+		analyzer.IsGuessableParam("", "id", v)
+	}
+	data, err := json.Marshal(analyzer)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+
+	// the values are not persisted
+	if string(data) != `{"maxHistory":10,"guessable":[]}` {
+		t.Errorf("Marshal() = %s", data)
+	}
+	for _, v := range values[5:] {
+		analyzer.IsGuessableParam("", "id", v)
+	}
+	if data, err = json.Marshal(analyzer); err != nil || string(data) != `{"maxHistory":10,"guessable":[{"name":"id"}]}` {
+		t.Fatalf("Marshal() = %s, %v", data, err)
+	}
+
+	// the restored analyzer knows the guessable parameters, and learns their values again
+	restored := &GuessableAnalyzer{}
+	if err := json.Unmarshal(data, restored); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if restored.MaxHistory() != MaxParamHistory {
+		t.Errorf("MaxHistory() = %d", restored.MaxHistory())
+	}
+	if !restored.IsGuessable("", "id") || restored.IsGuessable("", "name") {
+		t.Errorf("IsGuessable() is wrong")
+	}
+	guessable := false
+	for _, v := range values {
+		guessable, _ = restored.IsGuessableParam("", "id", v)
+	}
+	if !guessable {
+		t.Errorf("the restored analyzer did not learn the values again")
+	}
+
+	// the histories persisted with the values are discarded
+	if err := json.Unmarshal([]byte(`{"maxHistory":10,"params":[{"name":"id","history":["1"],"index":0}]}`), restored); err != nil ||
+		restored.IsGuessable("", "id") {
+		t.Errorf("Unmarshal() = %v, the persisted values were restored", err)
+	}
+	if err := json.Unmarshal([]byte(`{"maxHistory":0}`), &GuessableAnalyzer{}); err == nil {
+		t.Errorf("Unmarshal() expected an error for an invalid history size")
+	}
+}
@@ -0,0 +1,176 @@
+// Copyright © 2022 Cisco Systems, Inc. and its affiliates.
+// All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package traceanalyzer
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/openclarity/apiclarity/backend/pkg/modules/internal/bfla/recovery"
+	"github.com/openclarity/apiclarity/backend/pkg/modules/internal/traceanalyzer/bruteforce"
+	"github.com/openclarity/apiclarity/backend/pkg/modules/internal/traceanalyzer/enumeration"
+	"github.com/openclarity/apiclarity/backend/pkg/modules/internal/traceanalyzer/guessableid"
+	"github.com/openclarity/apiclarity/backend/pkg/modules/internal/traceanalyzer/nlid"
+	"github.com/openclarity/apiclarity/backend/pkg/modules/internal/traceanalyzer/unauthenticated"
+)
+
+const (
+	// The learnt histories are API annotations of the module, named with the prefix, they are not findings.
+	learningStateAnnotationPrefix      = "learning_state:"
+	guessableIDStateAnnotationName     = learningStateAnnotationPrefix + "guessable_id"
+	nlidStateAnnotationName            = learningStateAnnotationPrefix + "nlid"
+	unauthenticatedStateAnnotationName = learningStateAnnotationPrefix + "unauthenticated"
+	learningStatePersistenceInterval   = 30 * time.Second
+)
+
+func isLearningStateAnnotation(name string) bool {
+	return strings.HasPrefix(name, learningStateAnnotationPrefix)
+}
+
+// apiLearners are the analyzers that learn from the traces of an API.
+type apiLearners struct {
+	guessableID     *guessableid.GuessableAnalyzer
+	nlid            *nlid.NLID
+	unauthenticated *unauthenticated.UnauthenticatedAccess
+	// the windows of the failed authentications and of the requests of the clients are short-lived, they are
+	// not persisted
+	bruteForce  *bruteforce.BruteForce
+	enumeration *enumeration.Enumeration
+
+	// nil when the histories are not persisted
+	guessableIDState     recovery.PersistedValue
+	nlidState            recovery.PersistedValue
+	unauthenticatedState recovery.PersistedValue
+}
+
+// learnt marks the histories as changed, they are marshaled when the persister checkpoints them.
+func (a *apiLearners) learnt() {
+	if a.guessableIDState != nil {
+		a.guessableIDState.Set(a.guessableID)
+	}
+	if a.nlidState != nil {
+		a.nlidState.Set(a.nlid)
+	}
+	if a.unauthenticatedState != nil {
+		a.unauthenticatedState.Set(a.unauthenticated)
+	}
+}
+
+// learners holds the learning analyzers of each API. With a state persister, the histories are restored from
+// the API annotations the first time an API is seen, and checkpointed periodically.
+type learners struct {
+	lock sync.Mutex
+	apis map[uint]*apiLearners
+
+	bruteForceThresholds  bruteforce.Thresholds
+	enumerationThresholds enumeration.Thresholds
+
+	guessableIDStates     recovery.PersistedMap
+	nlidStates            recovery.PersistedMap
+	unauthenticatedStates recovery.PersistedMap
+}
+
+func newLearners(sp recovery.StatePersister, bruteForceThresholds bruteforce.Thresholds, enumerationThresholds enumeration.Thresholds) *learners {
+	l := &learners{
+		apis:                  map[uint]*apiLearners{},
+		bruteForceThresholds:  bruteForceThresholds,
+		enumerationThresholds: enumerationThresholds,
+	}
+	if sp != nil {
+		l.guessableIDStates = recovery.NewPersistedMap(sp, guessableIDStateAnnotationName, reflect.TypeOf(&guessableid.GuessableAnalyzer{}))
+		l.nlidStates = recovery.NewPersistedMap(sp, nlidStateAnnotationName, reflect.TypeOf(&nlid.NLID{}))
+		l.unauthenticatedStates = recovery.NewPersistedMap(sp, unauthenticatedStateAnnotationName, reflect.TypeOf(&unauthenticated.UnauthenticatedAccess{}))
+	}
+	return l
+}
+
+// lookup returns the learners of the API, if it was seen, without creating them.
+func (l *learners) lookup(apiID uint) (*apiLearners, bool) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	a, ok := l.apis[apiID]
+	return a, ok
+}
+
+// get returns the learners of the API, created and restored the first time.
+func (l *learners) get(apiID uint) *apiLearners {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	if a, ok := l.apis[apiID]; ok {
+		return a
+	}
+
+	a := &apiLearners{
+		guessableID:     guessableid.NewGuessableAnalyzer(guessableid.MaxParamHistory),
+		nlid:            nlid.NewNLID(nlid.NLIDRingBufferSize),
+		unauthenticated: unauthenticated.NewUnauthenticatedAccess(),
+		bruteForce:      bruteforce.NewBruteForce(l.bruteForceThresholds),
+		enumeration:     enumeration.NewEnumeration(l.enumerationThresholds),
+	}
+	if l.guessableIDStates != nil {
+		a.guessableIDState = restoreLearner(l.guessableIDStates, apiID, func(v interface{}) bool {
+			restored, ok := v.(*guessableid.GuessableAnalyzer)
+			if !ok || restored == nil || restored.MaxHistory() != guessableid.MaxParamHistory {
+				return false
+			}
+			a.guessableID = restored
+			return true
+		})
+	}
+	if l.nlidStates != nil {
+		a.nlidState = restoreLearner(l.nlidStates, apiID, func(v interface{}) bool {
+			restored, ok := v.(*nlid.NLID)
+			if !ok || restored == nil || restored.HistorySize() != nlid.NLIDRingBufferSize {
+				return false
+			}
+			a.nlid = restored
+			return true
+		})
+	}
+	if l.unauthenticatedStates != nil {
+		a.unauthenticatedState = restoreLearner(l.unauthenticatedStates, apiID, func(v interface{}) bool {
+			restored, ok := v.(*unauthenticated.UnauthenticatedAccess)
+			if !ok || restored == nil {
+				return false
+			}
+			a.unauthenticated = restored
+			return true
+		})
+	}
+	l.apis[apiID] = a
+
+	return a
+}
+
+// restoreLearner gives the persisted history of the API, if any, to restore. A history that can't be restored
+// is discarded, the analyzer learns again from scratch.
+func restoreLearner(states recovery.PersistedMap, apiID uint, restore func(v interface{}) bool) recovery.PersistedValue {
+	state, err := states.Get(apiID)
+	if err != nil {
+		log.Warnf("[TraceAnalyzer] unable to restore the learnt history of API %d, it won't be persisted: %v", apiID, err)
+		return nil
+	}
+	if state.Exists() && !restore(state.Get()) {
+		log.Warnf("[TraceAnalyzer] discarding the incompatible learnt history of API %d", apiID)
+	}
+	return state
+}
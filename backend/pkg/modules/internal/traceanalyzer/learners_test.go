@@ -0,0 +1,122 @@
+// Copyright © 2022 Cisco Systems, Inc. and its affiliates.
+// All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package traceanalyzer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"gorm.io/gorm"
+
+	"github.com/openclarity/apiclarity/backend/pkg/database"
+	"github.com/openclarity/apiclarity/backend/pkg/modules/internal/bfla/recovery"
+	"github.com/openclarity/apiclarity/backend/pkg/modules/internal/core"
+	"github.com/openclarity/apiclarity/backend/pkg/modules/internal/traceanalyzer/bruteforce"
+	"github.com/openclarity/apiclarity/backend/pkg/modules/internal/traceanalyzer/enumeration"
+	"github.com/openclarity/apiclarity/backend/pkg/modules/internal/traceanalyzer/guessableid"
+)
+
+func TestLearners_RestoreAndPersist(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	accessor := core.NewMockBackendAccessor(mockCtrl)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	values := []string{"0000001", "0000002", "0000003", "0000004", "0000005", "0000006", "0000007", "0000008", "0000009", "0000010"}
+	previous := guessableid.NewGuessableAnalyzer(guessableid.MaxParamHistory)
+	for _, v := range values {
+		previous.IsGuessableParam("", "id", v)
+	}
+	previousState, err := json.Marshal(previous)
+	if err != nil {
+		t.Fatalf("failed to marshal the guessable ID state: %v", err)
+	}
+
+	accessor.EXPECT().GetAPIInfoAnnotation(gomock.Any(), moduleName, uint(1), guessableIDStateAnnotationName).
+		Return(&core.Annotation{Name: guessableIDStateAnnotationName, Annotation: previousState}, nil)
+	accessor.EXPECT().GetAPIInfoAnnotation(gomock.Any(), moduleName, uint(1), nlidStateAnnotationName).
+		Return(nil, fmt.Errorf("unable to get apiinfo annotation: %w", gorm.ErrRecordNotFound))
+	accessor.EXPECT().GetAPIInfoAnnotation(gomock.Any(), moduleName, uint(1), unauthenticatedStateAnnotationName).
+		Return(nil, fmt.Errorf("unable to get apiinfo annotation: %w", gorm.ErrRecordNotFound))
+
+	sp := recovery.NewStatePersister(ctx, accessor, moduleName, learningStatePersistenceInterval)
+	l := newLearners(sp, bruteforce.DefaultThresholds(), enumeration.DefaultThresholds())
+	apiLearners := l.get(1)
+	if l.get(1) != apiLearners {
+		t.Errorf("get() expected the same learners for the API")
+	}
+
+	if !apiLearners.guessableID.IsGuessable("", "id") {
+		t.Errorf("the restored guessable ID history was not used")
+	}
+
+	var stored []string
+	accessor.EXPECT().StoreAPIInfoAnnotations(gomock.Any(), moduleName, uint(1), gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ string, _ uint, anns ...core.Annotation) error {
+			for _, ann := range anns {
+				if !json.Valid(ann.Annotation) {
+					t.Errorf("invalid state %s: %s", ann.Name, ann.Annotation)
+				}
+				stored = append(stored, ann.Name)
+			}
+			return nil
+		})
+	apiLearners.learnt()
+	if err := sp.Persist(ctx); err != nil {
+		t.Fatalf("Persist() error = %v", err)
+	}
+	sort.Strings(stored)
+	if len(stored) != 3 || stored[0] != guessableIDStateAnnotationName || stored[1] != nlidStateAnnotationName ||
+		stored[2] != unauthenticatedStateAnnotationName {
+		t.Errorf("persisted states = %v", stored)
+	}
+}
+
+func TestHTTPHandler_GetAPIAuthentication(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	accessor := core.NewMockBackendAccessor(mockCtrl)
+	ta := newTestTraceAnalyzer(t, accessor)
+	h := httpHandler{ta: ta}
+
+	// the learners are not created for an unknown API
+	accessor.EXPECT().GetAPIInfo(gomock.Any(), uint(2)).Return(nil, fmt.Errorf("failed to retrieve API info for apiID=2: %w", gorm.ErrRecordNotFound))
+	w := httptest.NewRecorder()
+	h.GetAPIAuthentication(w, httptest.NewRequest(http.MethodGet, "/", nil), 2)
+	if w.Code != http.StatusNotFound {
+		t.Errorf("GetAPIAuthentication() = %d for an unknown API", w.Code)
+	}
+	if _, ok := ta.learners.lookup(2); ok {
+		t.Errorf("GetAPIAuthentication() created the learners of an unknown API")
+	}
+
+	accessor.EXPECT().GetAPIInfo(gomock.Any(), uint(1)).Return(&database.APIInfo{}, nil)
+	w = httptest.NewRecorder()
+	h.GetAPIAuthentication(w, httptest.NewRequest(http.MethodGet, "/", nil), 1)
+	var result OperationsAuthentication
+	if err := json.NewDecoder(w.Body).Decode(&result); w.Code != http.StatusOK || err != nil || result.Total != 0 {
+		t.Errorf("GetAPIAuthentication() = %d, %+v, %v", w.Code, result, err)
+	}
+	// the learners of the known API are created once
+	h.GetAPIAuthentication(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil), 1)
+}
@@ -16,13 +16,14 @@
 package nlid
 
 import (
-	"bytes"
 	"container/ring"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
-	"net/url"
 	"sort"
 	"strings"
+	"sync"
 
 	log "github.com/sirupsen/logrus"
 
@@ -46,11 +47,29 @@ type params = map[string]bool
 
 type Reason map[string]interface{}
 
+// NLID learns the IDs of the responses of each API. The IDs are only compared, so a hash of each is kept rather than
+// the value, which may be sensitive.
 type NLID struct {
+	lock          sync.Mutex
 	historySize   int
 	paramsHistory map[utils.API]*ring.Ring
 }
 
+// nlidState is the JSON form of the analyzer, so that its history can be persisted.
+type nlidState struct {
+	HistorySize int `json:"historySize"`
+	// History is, for each API, the hashes of the IDs learnt from each trace, the oldest trace first.
+	History map[utils.API][][]string `json:"history"`
+	// Hashed is false for the histories persisted with the IDs themselves, they are hashed when restored.
+	Hashed bool `json:"hashed"`
+}
+
+// hashID returns the hash of the ID that is kept in the history.
+func hashID(id string) string {
+	sum := sha256.Sum256([]byte(id))
+	return hex.EncodeToString(sum[:])
+}
+
 func NewNLID(historySize int) *NLID {
 	return &NLID{
 		historySize:   historySize,
@@ -58,7 +77,80 @@ func NewNLID(historySize int) *NLID {
 	}
 }
 
-func (n *NLID) Analyze(pathParams map[string]string, trace *pluginsmodels.Telemetry) ([]core.Annotation, []core.Annotation) {
+// HistorySize returns the number of traces kept for each API.
+func (n *NLID) HistorySize() int {
+	return n.historySize
+}
+
+func (n *NLID) MarshalJSON() ([]byte, error) {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	state := nlidState{HistorySize: n.historySize, History: map[utils.API][][]string{}, Hashed: true}
+	for api, r := range n.paramsHistory {
+		history := [][]string{}
+		// the ring points to the oldest trace, the next one to be replaced
+		r.Do(func(p interface{}) {
+			prevParams, ok := p.(params)
+			if !ok {
+				return
+			}
+			ids := make([]string, 0, len(prevParams))
+			for id := range prevParams {
+				ids = append(ids, id)
+			}
+			sort.Strings(ids)
+			history = append(history, ids)
+		})
+		state.History[api] = history
+	}
+
+	return json.Marshal(state)
+}
+
+func (n *NLID) UnmarshalJSON(data []byte) error {
+	var state nlidState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return err
+	}
+	if state.HistorySize <= 0 {
+		return fmt.Errorf("invalid history size %d", state.HistorySize)
+	}
+
+	paramsHistory := make(map[utils.API]*ring.Ring, len(state.History))
+	for api, history := range state.History {
+		if len(history) > state.HistorySize {
+			history = history[len(history)-state.HistorySize:]
+		}
+		r := ring.New(state.HistorySize)
+		for _, ids := range history {
+			p := make(params, len(ids))
+			for _, id := range ids {
+				if !state.Hashed {
+					id = hashID(id)
+				}
+				p[id] = true
+			}
+			r.Value = p
+			r = r.Next()
+		}
+		paramsHistory[api] = r
+	}
+
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	n.historySize = state.HistorySize
+	n.paramsHistory = paramsHistory
+
+	return nil
+}
+
+// Analyze checks the parameters of the request against the IDs learnt from the previous responses of the API,
+// then learns the IDs of the response.
+func (n *NLID) Analyze(reqParams utils.Params, trace *pluginsmodels.Telemetry) ([]core.Annotation, []core.Annotation) {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
 	eventAnns := []core.Annotation{}
 	apiAnns := []core.Annotation{}
 
@@ -66,7 +158,7 @@ func (n *NLID) Analyze(pathParams map[string]string, trace *pluginsmodels.Teleme
 		return eventAnns, apiAnns
 	}
 
-	eventAnns = append(eventAnns, n.getNLIDS(pathParams, *trace)...)
+	eventAnns = append(eventAnns, n.getNLIDS(reqParams, *trace)...)
 	n.learnIDs(*trace)
 
 	return eventAnns, apiAnns
@@ -74,10 +166,9 @@ func (n *NLID) Analyze(pathParams map[string]string, trace *pluginsmodels.Teleme
 
 // We check if a variable is a NLID if, for a Request:
 // - There is already an history of variables for this API
-// - It's in the Request header AND looks like an ID
-// - XXX: it in the path parameter AND it looks like an ID
+// - It's a path, query, header or body parameter AND it looks like an ID
 
-func (n *NLID) getNLIDS(pathParams map[string]string, trace pluginsmodels.Telemetry) []core.Annotation {
+func (n *NLID) getNLIDS(requestParams utils.Params, trace pluginsmodels.Telemetry) []core.Annotation {
 	eventAnns := []core.Annotation{}
 
 	api := getAPI(trace)
@@ -88,27 +179,15 @@ func (n *NLID) getNLIDS(pathParams map[string]string, trace pluginsmodels.Teleme
 	}
 
 	// Get all parameters of the Request
-	//
-	// Get all parameters
 	reqParams := params{}
-
-	// - Header
-	for _, h := range trace.Request.Common.Headers {
-		if maybeID(h.Key, h.Value) {
-			reqParams[h.Value] = true
-		}
-	}
-
-	// - Path
-	for k, v := range pathParams {
-		if maybeID(k, v) {
-			reqParams[v] = true
+	for _, located := range requestParams.ByLocation() {
+		for k, v := range located.Params {
+			if maybeID(k, v) {
+				reqParams[v] = true
+			}
 		}
 	}
 
-	// - Query Params, XXX Don't do it right now
-	// - Body, XXX Don't do it right now
-
 	r := ph
 	r.Do(func(p interface{}) {
 		if p == nil {
@@ -120,7 +199,7 @@ func (n *NLID) getNLIDS(pathParams map[string]string, trace pluginsmodels.Teleme
 		}
 
 		for param := range reqParams {
-			if prevParams[param] {
+			if prevParams[hashID(param)] {
 				// This parameter was already present, that OK, it's not an NLID
 				// Remove it from the parameters to checks
 				reqParams[param] = false // Mark this parameter as already learnt
@@ -157,26 +236,28 @@ func (n *NLID) learnIDs(trace pluginsmodels.Telemetry) {
 	// - Header
 	for _, h := range trace.Response.Common.Headers {
 		if maybeID(h.Key, h.Value) {
-			params[h.Value] = true
+			params[hashID(h.Value)] = true
 		}
 	}
 
 	// Query Parameters
-	u, err := url.Parse(trace.Request.Path)
-	if err == nil {
-		m, _ := url.ParseQuery(u.RawQuery)
-		for k, v := range m {
-			if maybeID(k, v[0]) {
-				params[v[0]] = true // XXX: only get the first value of each query parameter
-			}
+	for k, v := range utils.GetQueryParams(trace.Request.Path) {
+		if maybeID(k, v) {
+			params[hashID(v)] = true // XXX: only get the first value of each query parameter
 		}
 	}
 	// - Learn Response Body parameters
 	if !trace.Response.Common.TruncatedBody && len(trace.Response.Common.Body) > 0 {
-		if err = getBodyParams(params, trace.Response.Common.Body); nil != err {
+		bodyParams, err := utils.GetBodyParams(trace.Response.Common.Body)
+		if err != nil {
 			// Log the problem, but continue anyway, it's not blocking.
 			log.Debugf("unable to get parameters from body: %v", err)
 		}
+		for k, v := range bodyParams {
+			if maybeID(k, v) {
+				params[hashID(v)] = true
+			}
+		}
 	}
 
 	if _, found := n.paramsHistory[api]; !found {
@@ -190,60 +271,6 @@ func (n *NLID) skipTrace(trace *pluginsmodels.Telemetry) bool {
 	return false
 }
 
-func getBodyParams(params params, body []byte) error {
-	var parsed interface{}
-
-	// We deserialize the JSON object this way because json.Unmarshal doesn't
-	// distinguish between int and floats. Here, thanks to d.UseNumber(), we
-	// can switch on json.Number and then try to cast to Int64.
-	d := json.NewDecoder(bytes.NewReader(body))
-	d.UseNumber()
-	if err := d.Decode(&parsed); err != nil {
-		return fmt.Errorf("unable to decode body: %w", err)
-	}
-
-	getDecodedBodyParams(params, parsed, "")
-
-	return nil
-}
-
-func getDecodedBodyParams(o params, val interface{}, prefix string) {
-	switch val := val.(type) {
-	case bool:
-		// Nothing, it's not interresting
-	case json.Number:
-		// We only add integers to the possible list of identifier.
-		// Floats are unlikely indentifiers.
-		if n, err := val.Int64(); err == nil {
-			s := fmt.Sprintf("%d", n)
-			if maybeID(prefix, s) {
-				(o)[s] = true
-			}
-		}
-	case string:
-		if maybeID(prefix, val) {
-			(o)[val] = true
-		}
-	case []interface{}:
-		for i, v := range val {
-			getDecodedBodyParams(o, v, fmt.Sprintf("%s[%d]", prefix, i))
-		}
-	case map[string]interface{}:
-		keys := make([]string, 0, len(val))
-		for k := range val {
-			keys = append(keys, k)
-		}
-		sort.Strings(keys)
-		for _, k := range keys {
-			getDecodedBodyParams(o, val[k], fmt.Sprintf("%s.%s", prefix, k))
-		}
-	case nil:
-		// nothing
-	default:
-		fmt.Println("unknown type not adding: ", val)
-	}
-}
-
 func maybeID(key string, value string) bool {
 	// Check if the key looks like it's an identifier
 	keyLower := strings.ToLower(key)
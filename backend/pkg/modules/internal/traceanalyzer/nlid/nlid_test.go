@@ -17,9 +17,12 @@ package nlid
 
 import (
 	"bytes"
+	"encoding/json"
+	"fmt"
 	"testing"
 
 	"github.com/openclarity/apiclarity/backend/pkg/modules/internal/core"
+	"github.com/openclarity/apiclarity/backend/pkg/modules/internal/traceanalyzer/utils"
 	pluginmodels "github.com/openclarity/apiclarity/plugins/api/server/models"
 )
 
@@ -49,6 +52,7 @@ type testCase struct {
 	method     string
 	headersReq []*pluginmodels.Header
 	headersRes []*pluginmodels.Header
+	bodyReq    []byte
 	bodyRes    []byte
 	wanted     []core.Annotation
 }
@@ -69,9 +73,12 @@ func checkTC(t *testing.T, testCases []testCase) {
 		trace.Response.Common.Headers = tc.headersRes
 		trace.Request.Path = tc.path
 		trace.Request.Method = tc.method
+		trace.Request.Common.Body = tc.bodyReq
 		trace.Response.Common.Body = tc.bodyRes
 
-		eventAnns, _ := analyzer.Analyze(tc.pathParams, &trace)
+		reqParams := utils.GetRequestParams(&trace, nil)
+		reqParams.Path = tc.pathParams
+		eventAnns, _ := analyzer.Analyze(reqParams, &trace)
 		if !sameObs(eventAnns, tc.wanted) {
 			t.Errorf("Wanted: (%v) got (%v)", tc.wanted, eventAnns)
 		}
@@ -94,17 +101,17 @@ func TestNLIDHeaders(t *testing.T) {
 		{host: "example.com", headersReq: []*pluginmodels.Header{{Key: "id", Value: "12"}, {Key: "test", Value: "36"}}, headersRes: []*pluginmodels.Header{}, wanted: []core.Annotation{}},
 
 		// Let's start to learn something which is not an ID keyword
-		{host: "example.com", headersReq: []*pluginmodels.Header{}, headersRes: []*pluginmodels.Header{{Key: "param1", Value: "XXXXXXXX"}, {Key: "param2", Value: "YYYYYYYY"}}, wanted: []core.Annotation{}},
-		{host: "example.com", headersReq: []*pluginmodels.Header{{Key: "param1", Value: "XXXXXXXX"}, {Key: "param2", Value: "YYYYYYYY"}}, headersRes: []*pluginmodels.Header{}, wanted: []core.Annotation{}},
-		{host: "example.com", headersReq: []*pluginmodels.Header{{Key: "param1", Value: "AAAAAAAA"}, {Key: "param2", Value: "YYYYYYYY"}}, headersRes: []*pluginmodels.Header{}, wanted: []core.Annotation{{Name: "NLID", Annotation: []byte("AAAAAAAA")}}},
-		{host: "example.com", headersReq: []*pluginmodels.Header{{Key: "param2", Value: "XXXXXXXX"}, {Key: "param4", Value: "YYYYYYYY"}}, headersRes: []*pluginmodels.Header{}, wanted: []core.Annotation{}},
-		{host: "example.com", headersReq: []*pluginmodels.Header{{Key: "parama", Value: "11111111"}, {Key: "paramb", Value: "22222222"}}, headersRes: []*pluginmodels.Header{}, wanted: []core.Annotation{{Name: "NLID", Annotation: []byte("11111111")}, {Name: "NLID", Annotation: []byte("22222222")}}},
+		{host: "example.com", headersReq: []*pluginmodels.Header{}, headersRes: []*pluginmodels.Header{{Key: "param1Id", Value: "XXXXXXXX"}, {Key: "param2Id", Value: "YYYYYYYY"}}, wanted: []core.Annotation{}},
+		{host: "example.com", headersReq: []*pluginmodels.Header{{Key: "param1Id", Value: "XXXXXXXX"}, {Key: "param2Id", Value: "YYYYYYYY"}}, headersRes: []*pluginmodels.Header{}, wanted: []core.Annotation{}},
+		{host: "example.com", headersReq: []*pluginmodels.Header{{Key: "param1Id", Value: "AAAAAAAA"}, {Key: "param2Id", Value: "YYYYYYYY"}}, headersRes: []*pluginmodels.Header{}, wanted: []core.Annotation{{Name: "NLID", Annotation: []byte("AAAAAAAA")}}},
+		{host: "example.com", headersReq: []*pluginmodels.Header{{Key: "param2Id", Value: "XXXXXXXX"}, {Key: "param4Id", Value: "YYYYYYYY"}}, headersRes: []*pluginmodels.Header{}, wanted: []core.Annotation{}},
+		{host: "example.com", headersReq: []*pluginmodels.Header{{Key: "paramaId", Value: "11111111"}, {Key: "parambId", Value: "22222222"}}, headersRes: []*pluginmodels.Header{}, wanted: []core.Annotation{{Name: "NLID", Annotation: []byte("11111111")}, {Name: "NLID", Annotation: []byte("22222222")}}},
 
 		{host: "example.com", headersReq: []*pluginmodels.Header{{Key: "id", Value: "ééé aaAAA"}, {Key: "test", Value: "YYYYYYYY"}}, headersRes: []*pluginmodels.Header{}, wanted: []core.Annotation{{Name: "NLID", Annotation: []byte("ééé aaAAA")}}},
 		{host: "example.com", headersReq: []*pluginmodels.Header{{Key: "blabla", Value: "ééé aaAAA"}, {Key: "test", Value: "YYYYYYYY"}}, headersRes: []*pluginmodels.Header{}, wanted: []core.Annotation{}},
 
-		{host: "example.com", headersReq: []*pluginmodels.Header{{Key: "blabla", Value: "b889200b-5f7e-4da7-b582-fd64f9473328"}, {Key: "test", Value: "YYYYYYYY"}}, headersRes: []*pluginmodels.Header{}, wanted: []core.Annotation{{Name: "NLID", Annotation: []byte("b889200b-5f7e-4da7-b582-fd64f9473328")}}},
-		{host: "example.com", headersReq: []*pluginmodels.Header{{Key: "blabla", Value: "user_id_23654"}, {Key: "test", Value: "YYYYYYYY"}}, headersRes: []*pluginmodels.Header{}, wanted: []core.Annotation{{Name: "NLID", Annotation: []byte("user_id_23654")}}},
+		{host: "example.com", headersReq: []*pluginmodels.Header{{Key: "blablaId", Value: "b889200b-5f7e-4da7-b582-fd64f9473328"}, {Key: "test", Value: "YYYYYYYY"}}, headersRes: []*pluginmodels.Header{}, wanted: []core.Annotation{{Name: "NLID", Annotation: []byte("b889200b-5f7e-4da7-b582-fd64f9473328")}}},
+		{host: "example.com", headersReq: []*pluginmodels.Header{{Key: "blablaId", Value: "user_id_23654"}, {Key: "test", Value: "YYYYYYYY"}}, headersRes: []*pluginmodels.Header{}, wanted: []core.Annotation{{Name: "NLID", Annotation: []byte("user_id_23654")}}},
 	}
 
 	checkTC(t, testcases)
@@ -113,10 +120,10 @@ func TestNLIDHeaders(t *testing.T) {
 func TestNLIDQueryParams(t *testing.T) {
 	testCases := []testCase{
 		{host: "example.com", path: "/test", wanted: []core.Annotation{}},
-		{host: "example.com", path: "/test?bla=AAAAAAAAAA", wanted: []core.Annotation{}},
-		{host: "example.com", path: "/test?bla=AAAAAAAAAA", wanted: []core.Annotation{}}, // bla parameter will be learnt
-		{host: "example.com", path: "/test", headersReq: []*pluginmodels.Header{{Key: "test", Value: "AAAAAAAAAA"}}, wanted: []core.Annotation{}},
-		{host: "example.com", path: "/test", headersReq: []*pluginmodels.Header{{Key: "test", Value: "BBBbbbBBBb"}}, wanted: []core.Annotation{{Name: "NLID", Annotation: []byte("BBBbbbBBBb")}}},
+		{host: "example.com", path: "/test?blaId=AAAAAAAAAA", wanted: []core.Annotation{{Name: "NLID", Annotation: []byte("AAAAAAAAAA")}}}, // blaId parameter is checked, then learnt
+		{host: "example.com", path: "/test?blaId=AAAAAAAAAA", wanted: []core.Annotation{}},
+		{host: "example.com", path: "/test", headersReq: []*pluginmodels.Header{{Key: "testId", Value: "AAAAAAAAAA"}}, wanted: []core.Annotation{}},
+		{host: "example.com", path: "/test", headersReq: []*pluginmodels.Header{{Key: "testId", Value: "BBBbbbBBBb"}}, wanted: []core.Annotation{{Name: "NLID", Annotation: []byte("BBBbbbBBBb")}}},
 	}
 
 	checkTC(t, testCases)
@@ -138,24 +145,24 @@ func TestNLIDBody(t *testing.T) {
 		// 123654987
 		// blablabla
 		// 321456987654
-		{host: "example.com", path: "/test1", headersReq: []*pluginmodels.Header{{Key: "param", Value: "testtesttest"}}, wanted: []core.Annotation{}},
-		{host: "example.com", path: "/test1", headersReq: []*pluginmodels.Header{{Key: "param", Value: "123654987"}}, wanted: []core.Annotation{}},
-		{host: "example.com", path: "/test1", headersReq: []*pluginmodels.Header{{Key: "param", Value: "blablabla"}}, wanted: []core.Annotation{}},
-		{host: "example.com", path: "/test1", headersReq: []*pluginmodels.Header{{Key: "param", Value: "321456987654"}}, wanted: []core.Annotation{}},
+		{host: "example.com", path: "/test1", headersReq: []*pluginmodels.Header{{Key: "paramId", Value: "testtesttest"}}, wanted: []core.Annotation{}},
+		{host: "example.com", path: "/test1", headersReq: []*pluginmodels.Header{{Key: "paramId", Value: "123654987"}}, wanted: []core.Annotation{}},
+		{host: "example.com", path: "/test1", headersReq: []*pluginmodels.Header{{Key: "paramId", Value: "blablabla"}}, wanted: []core.Annotation{}},
+		{host: "example.com", path: "/test1", headersReq: []*pluginmodels.Header{{Key: "paramId", Value: "321456987654"}}, wanted: []core.Annotation{}},
 
-		{host: "example.com", path: "/testX", headersReq: []*pluginmodels.Header{{Key: "param", Value: "testtesttest"}}, wanted: []core.Annotation{}},
-		{host: "example.com", path: "/testX", headersReq: []*pluginmodels.Header{{Key: "param", Value: "123654987"}}, wanted: []core.Annotation{}},
-		{host: "example.com", path: "/testX", headersReq: []*pluginmodels.Header{{Key: "param", Value: "blablabla"}}, wanted: []core.Annotation{}},
-		{host: "example.com", path: "/testX", headersReq: []*pluginmodels.Header{{Key: "param", Value: "321456987654"}}, wanted: []core.Annotation{}},
+		{host: "example.com", path: "/testX", headersReq: []*pluginmodels.Header{{Key: "paramId", Value: "testtesttest"}}, wanted: []core.Annotation{}},
+		{host: "example.com", path: "/testX", headersReq: []*pluginmodels.Header{{Key: "paramId", Value: "123654987"}}, wanted: []core.Annotation{}},
+		{host: "example.com", path: "/testX", headersReq: []*pluginmodels.Header{{Key: "paramId", Value: "blablabla"}}, wanted: []core.Annotation{}},
+		{host: "example.com", path: "/testX", headersReq: []*pluginmodels.Header{{Key: "paramId", Value: "321456987654"}}, wanted: []core.Annotation{}},
 
-		{host: "example.com", path: "/testX?newid=blablabla&otherid=testtesttest", headersReq: []*pluginmodels.Header{{Key: "param", Value: "321456987654"}}, wanted: []core.Annotation{}},
+		{host: "example.com", path: "/testX?newid=blablabla&otherid=testtesttest", headersReq: []*pluginmodels.Header{{Key: "paramId", Value: "321456987654"}}, wanted: []core.Annotation{}},
 
-		// We don't check for NLIDs in query parameters (we only learn them)
-		{host: "example.com", path: "/testX?newid=blablabla&otherid=testtesttest&strange=THISISNOTCHECKED", headersReq: []*pluginmodels.Header{{Key: "param", Value: "321456987654"}}, wanted: []core.Annotation{}},
+		// The query parameters are learnt and checked
+		{host: "example.com", path: "/testX?newid=blablabla&otherid=testtesttest&strangeId=THISISCHECKED", headersReq: []*pluginmodels.Header{{Key: "paramId", Value: "321456987654"}}, wanted: []core.Annotation{{Name: "NLID", Annotation: []byte("THISISCHECKED")}}},
 
 		// Now let's check for some NLIDs
-		{host: "example.com", path: "/testX", headersReq: []*pluginmodels.Header{{Key: "param", Value: "1234-NLID-5678"}}, wanted: []core.Annotation{{Name: "NLID", Annotation: []byte("1234-NLID-5678")}}},
-		{host: "example.com", path: "/testX", headersReq: []*pluginmodels.Header{{Key: "param", Value: "1234-NLID-5678"}, {Key: "id", Value: "123654987"}}, wanted: []core.Annotation{{Name: "NLID", Annotation: []byte("1234-NLID-5678")}}},
+		{host: "example.com", path: "/testX", headersReq: []*pluginmodels.Header{{Key: "paramId", Value: "1234-NLID-5678"}}, wanted: []core.Annotation{{Name: "NLID", Annotation: []byte("1234-NLID-5678")}}},
+		{host: "example.com", path: "/testX", headersReq: []*pluginmodels.Header{{Key: "paramId", Value: "1234-NLID-5678"}, {Key: "id", Value: "123654987"}}, wanted: []core.Annotation{{Name: "NLID", Annotation: []byte("1234-NLID-5678")}}},
 
 		// The param is too small, it's probably not an ID, don't check for it
 		{host: "example.com", path: "/testX", headersReq: []*pluginmodels.Header{{Key: "param", Value: "1234"}}, wanted: []core.Annotation{}},
@@ -175,9 +182,57 @@ func TestNLIDBody(t *testing.T) {
 		{host: "example.com", method: "GET", path: "/pet/445", pathParams: map[string]string{"petID": "12345679"}, wanted: []core.Annotation{}},
 		{host: "example.com", method: "GET", path: "/pet/448", pathParams: map[string]string{"petID": "12345670"}, wanted: []core.Annotation{{Name: "NLID", Annotation: []byte("12345670")}}},
 
+		// Check if request body fields are NLIDs
+		{host: "example.com", method: "POST", path: "/order", bodyReq: []byte(`{"order": {"petId": 12345678, "quantity": 1}}`), wanted: []core.Annotation{}},
+		{host: "example.com", method: "POST", path: "/order", bodyReq: []byte(`{"order": {"petId": 87654321, "quantity": 1}}`), wanted: []core.Annotation{{Name: "NLID", Annotation: []byte("87654321")}}},
+
 		{host: "example.com", path: "/test3", bodyRes: []byte(`{"paramA": 123456789, "param": "blablabla"}`), wanted: []core.Annotation{}},
-		{host: "example.com", path: "/test4", headersReq: []*pluginmodels.Header{{Key: "param", Value: "blablabla"}}, wanted: []core.Annotation{}},
+		{host: "example.com", path: "/test4", headersReq: []*pluginmodels.Header{{Key: "paramId", Value: "blablabla"}}, wanted: []core.Annotation{}},
 	}
 
 	checkTC(t, testCases)
 }
+
+func TestNLID_JSON(t *testing.T) {
+	trace := pluginmodels.Telemetry{
+		Request:  &pluginmodels.Request{Host: "example.com", Path: "/test", Common: &pluginmodels.Common{}},
+		Response: &pluginmodels.Response{Common: &pluginmodels.Common{}},
+	}
+
+	analyzer := NewNLID(3)
+	for _, id := range []string{"AAAAAAAAAA", "BBBBBBBBBB", "CCCCCCCCCC", "DDDDDDDDDD"} {
+		trace.Response.Common.Headers = []*pluginmodels.Header{{Key: "id", Value: id}}
+		analyzer.Analyze(utils.GetRequestParams(&trace, nil), &trace)
+	}
+	data, err := json.Marshal(analyzer)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	// the IDs are not persisted, only their hashes
+	if want := fmt.Sprintf(`{"historySize":3,"history":{"example.com":[["%s"],["%s"],["%s"]]},"hashed":true}`,
+		hashID("BBBBBBBBBB"), hashID("CCCCCCCCCC"), hashID("DDDDDDDDDD")); string(data) != want {
+		t.Errorf("Marshal() = %s, want %s", data, want)
+	}
+
+	// the histories persisted with the IDs are hashed when restored
+	legacyData := []byte(`{"historySize":3,"history":{"example.com":[["BBBBBBBBBB"],["CCCCCCCCCC"],["DDDDDDDDDD"]]}}`)
+	for _, data := range [][]byte{data, legacyData} {
+		restored := &NLID{}
+		if err := json.Unmarshal(data, restored); err != nil {
+			t.Fatalf("Unmarshal() error = %v", err)
+		}
+		if restored.HistorySize() != 3 {
+			t.Errorf("HistorySize() = %d", restored.HistorySize())
+		}
+		trace.Response.Common.Headers = nil
+		for id, wanted := range map[string][]core.Annotation{
+			"AAAAAAAAAA": {{Name: "NLID", Annotation: []byte("AAAAAAAAAA")}}, // forgotten
+			"DDDDDDDDDD": {},
+		} {
+			trace.Request.Common.Headers = []*pluginmodels.Header{{Key: "id", Value: id}}
+			if eventAnns := restored.getNLIDS(utils.GetRequestParams(&trace, nil), trace); !sameObs(eventAnns, wanted) {
+				t.Errorf("Wanted: (%v) got (%v)", wanted, eventAnns)
+			}
+		}
+	}
+}
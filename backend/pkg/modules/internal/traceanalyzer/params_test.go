@@ -0,0 +1,251 @@
+// Copyright © 2022 Cisco Systems, Inc. and its affiliates.
+// All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package traceanalyzer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/go-openapi/strfmt"
+	"github.com/golang/mock/gomock"
+
+	"github.com/openclarity/apiclarity/api/server/models"
+	"github.com/openclarity/apiclarity/backend/pkg/database"
+	"github.com/openclarity/apiclarity/backend/pkg/modules/internal/bfla/bfladetector"
+	"github.com/openclarity/apiclarity/backend/pkg/modules/internal/core"
+	"github.com/openclarity/apiclarity/backend/pkg/modules/internal/traceanalyzer/enumeration"
+	"github.com/openclarity/apiclarity/backend/pkg/modules/internal/traceanalyzer/securityheaders"
+	"github.com/openclarity/apiclarity/backend/pkg/modules/internal/traceanalyzer/utils"
+	pluginsmodels "github.com/openclarity/apiclarity/plugins/api/server/models"
+)
+
+func specInfoJSON(t *testing.T, pathID string, path string) string {
+	t.Helper()
+
+	specInfo, err := json.Marshal(models.SpecInfo{Tags: []*models.SpecTag{{
+		Name: "default",
+		MethodAndPathList: []*models.MethodAndPath{
+			{Method: models.HTTPMethodGET, Path: path, PathID: strfmt.UUID(pathID)},
+		},
+	}}})
+	if err != nil {
+		t.Fatalf("failed to marshal spec info: %v", err)
+	}
+	return string(specInfo)
+}
+
+func TestTraceAnalyzer_getParams(t *testing.T) {
+	const (
+		providedPathID      = "9a5b37a7-8d95-4c0a-a5a8-5c1c4d1d2f01"
+		reconstructedPathID = "9a5b37a7-8d95-4c0a-a5a8-5c1c4d1d2f02"
+	)
+	trace := &pluginsmodels.Telemetry{
+		Request: &pluginsmodels.Request{
+			Path:   "/users/42?orderId=7",
+			Common: &pluginsmodels.Common{Body: []byte(`{"petId": 12}`)},
+		},
+	}
+
+	tests := []struct {
+		name         string
+		apiInfo      *database.APIInfo
+		event        *database.APIEvent
+		wantSpecPath string
+	}{
+		{
+			name: "reconstructed spec",
+			apiInfo: &database.APIInfo{
+				ProvidedSpecInfo:      specInfoJSON(t, providedPathID, "/users/{id}"),
+				ReconstructedSpecInfo: specInfoJSON(t, reconstructedPathID, "/users/{userId}"),
+			},
+			event:        &database.APIEvent{Method: models.HTTPMethodGET, Path: "/users/42", ProvidedPathID: providedPathID, ReconstructedPathID: reconstructedPathID},
+			wantSpecPath: "/users/{userId}",
+		},
+		{
+			name: "provided spec only matching",
+			apiInfo: &database.APIInfo{
+				ProvidedSpecInfo:      specInfoJSON(t, providedPathID, "/users/{id}"),
+				ReconstructedSpecInfo: specInfoJSON(t, reconstructedPathID, "/users/{userId}"),
+			},
+			event:        &database.APIEvent{Method: models.HTTPMethodGET, Path: "/users/42", ProvidedPathID: providedPathID},
+			wantSpecPath: "/users/{id}",
+		},
+		{
+			name: "reconstructed spec only",
+			apiInfo: &database.APIInfo{
+				ReconstructedSpecInfo: specInfoJSON(t, reconstructedPathID, "/users/{userId}"),
+			},
+			event:        &database.APIEvent{Method: models.HTTPMethodGET, Path: "/users/42", ReconstructedPathID: reconstructedPathID},
+			wantSpecPath: "/users/{userId}",
+		},
+		{
+			name:    "no spec",
+			apiInfo: &database.APIInfo{},
+			event:   &database.APIEvent{Method: models.HTTPMethodGET, Path: "/users/42"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			accessor := core.NewMockBackendAccessor(mockCtrl)
+			accessor.EXPECT().GetAPIInfo(gomock.Any(), gomock.Any()).Return(tt.apiInfo, nil)
+			ta := newTestTraceAnalyzer(t, accessor)
+
+			specPath, params := ta.getParams(context.Background(), tt.event, trace)
+			if specPath != tt.wantSpecPath {
+				t.Errorf("getParams() specPath = %s, want %s", specPath, tt.wantSpecPath)
+			}
+			if tt.wantSpecPath != "" && len(params.Path) != 1 {
+				t.Errorf("getParams() path params = %v", params.Path)
+			}
+			if params.Query["orderId"] != "7" || params.Body["petId"] != "12" {
+				t.Errorf("getParams() params = %+v", params)
+			}
+		})
+	}
+}
+
+func TestTraceAnalyzer_getParamsDeclared(t *testing.T) {
+	const pathID = "9a5b37a7-8d95-4c0a-a5a8-5c1c4d1d2f01"
+	rawSpec := `swagger: "2.0"
+info:
+  title: users
+  version: "1.0"
+paths:
+  /users/{uid}:
+    parameters:
+      - {name: uid, in: path, required: true, type: string}
+    post:
+      parameters:
+        - {name: account, in: query, type: integer}
+        - {name: sort, in: query, type: string, enum: [asc, desc]}
+        - {name: since, in: query, type: string, format: date-time}
+        - {name: X-Tenant, in: header, type: string}
+        - name: body
+          in: body
+          schema:
+            type: object
+            properties:
+              owner: {type: string}
+              items:
+                type: array
+                items:
+                  type: object
+                  properties:
+                    sku: {type: string}
+                    price: {type: number}
+      responses:
+        "200": {description: ok}
+`
+	trace := &pluginsmodels.Telemetry{
+		Request: &pluginsmodels.Request{
+			Path: "/users/42?account=7&sort=asc&since=2022-01-01T00:00:00Z&orderId=3",
+			Common: &pluginsmodels.Common{
+				Headers: []*pluginsmodels.Header{{Key: "X-Tenant", Value: "acme"}},
+				Body:    []byte(`{"owner": "alice", "items": [{"sku": "A1", "price": 1}], "petId": 12}`),
+			},
+		},
+	}
+	specInfo, err := json.Marshal(models.SpecInfo{Tags: []*models.SpecTag{{
+		Name:              "default",
+		MethodAndPathList: []*models.MethodAndPath{{Method: models.HTTPMethodPOST, Path: "/users/{uid}", PathID: strfmt.UUID(pathID)}},
+	}}})
+	if err != nil {
+		t.Fatalf("failed to marshal spec info: %v", err)
+	}
+
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	accessor := core.NewMockBackendAccessor(mockCtrl)
+	accessor.EXPECT().GetAPIInfo(gomock.Any(), gomock.Any()).Return(&database.APIInfo{ProvidedSpec: rawSpec, ProvidedSpecInfo: string(specInfo)}, nil).Times(2)
+	ta := newTestTraceAnalyzer(t, accessor)
+	event := &database.APIEvent{Method: models.HTTPMethodPOST, Path: "/users/42", ProvidedPathID: pathID}
+
+	// the declared parameters are analyzed whatever their name, the parameters named as identifiers are not declared
+	want := utils.Params{
+		Path:   map[string]string{"uid": "42"},
+		Query:  map[string]string{"account": "7"},
+		Header: map[string]string{"X-Tenant": "acme"},
+		Body:   map[string]string{"owner": "alice", "items[0].sku": "A1"},
+	}
+	for i := 0; i < 2; i++ {
+		specPath, params := ta.getParams(context.Background(), event, trace)
+		if specPath != "/users/{uid}" || !reflect.DeepEqual(params, want) {
+			t.Errorf("getParams() = %s, %+v, want %+v", specPath, params, want)
+		}
+	}
+}
+
+func TestTraceAnalyzer_getDetectedUserID(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	ta := newTestTraceAnalyzer(t, core.NewMockBackendAccessor(mockCtrl))
+
+	extractors, err := bfladetector.ParseUserIdentityExtractors("header:X-User")
+	if err != nil {
+		t.Fatalf("ParseUserIdentityExtractors() error = %v", err)
+	}
+	ta.config.userIdentityExtractors = extractors
+	trace := &pluginsmodels.Telemetry{Request: &pluginsmodels.Request{Common: &pluginsmodels.Common{Headers: []*pluginsmodels.Header{
+		{Key: "X-User", Value: "alice"},
+		{Key: "X-Customer-ID", Value: "bob"},
+	}}}}
+	// the configured extractors detect the user, not the default ones
+	if user := ta.getDetectedUserID(trace); user != "alice" {
+		t.Errorf("getDetectedUserID() = %q", user)
+	}
+}
+
+func TestTraceAnalyzer_analyzeEnumerationWithoutSpec(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	accessor := core.NewMockBackendAccessor(mockCtrl)
+	ta := newTestTraceAnalyzer(t, accessor)
+	state := &analysisState{learners: ta.learners, endpointFindings: ta.endpointFindings, weakBasicAuth: ta.weakBasicAuth}
+
+	accessor.EXPECT().GetAPIInfo(gomock.Any(), uint(1)).Return(&database.APIInfo{}, nil).AnyTimes()
+	accessor.EXPECT().ListAPIInfoAnnotations(gomock.Any(), moduleName, uint(1)).Return(nil, nil)
+
+	var eventAnns, apiAnns []core.Annotation
+	for i := 1; i <= ta.config.enumerationThresholds.SequentialValues; i++ {
+		path := fmt.Sprintf("/orders/%d", i)
+		event := &database.APIEvent{ID: uint(i), APIInfoID: 1, Method: models.HTTPMethodGET, Path: path, Time: strfmt.DateTime(time.Now())}
+		trace := &pluginsmodels.Telemetry{
+			SourceAddress: "10.0.0.1:51000",
+			Request:       &pluginsmodels.Request{Method: "GET", Path: path, Common: &pluginsmodels.Common{}},
+			Response:      &pluginsmodels.Response{StatusCode: "200", Common: &pluginsmodels.Common{}},
+		}
+		eventAnns, apiAnns = ta.analyze(context.Background(), state, event, trace, true)
+	}
+
+	// the identifiers of the path are enumerated, the endpoint is the operation of the path
+	var f securityheaders.Finding
+	for _, ann := range apiAnns {
+		if ann.Name == enumeration.KindSequentialEnumeration {
+			if err := json.Unmarshal(ann.Annotation, &f); err != nil {
+				t.Fatalf("invalid finding: %v", err)
+			}
+		}
+	}
+	if len(f.Endpoints) != 1 || f.Endpoints[0].Location != "/orders/{param1}" {
+		t.Errorf("analyze() = %v, %+v without a spec", eventAnns, f)
+	}
+}
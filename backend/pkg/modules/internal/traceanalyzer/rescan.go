@@ -0,0 +1,360 @@
+// Copyright © 2022 Cisco Systems, Inc. and its affiliates.
+// All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package traceanalyzer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-openapi/strfmt"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/openclarity/apiclarity/backend/pkg/database"
+	"github.com/openclarity/apiclarity/backend/pkg/modules/internal/core"
+	"github.com/openclarity/apiclarity/backend/pkg/modules/internal/traceanalyzer/weakbasicauth"
+	"github.com/openclarity/apiclarity/backend/pkg/modules/internal/traceanalyzer/weakjwt"
+	pluginsmodels "github.com/openclarity/apiclarity/plugins/api/server/models"
+)
+
+const (
+	RescanJobStatusRunning   RescanJobStatus = "RUNNING"
+	RescanJobStatusCompleted RescanJobStatus = "COMPLETED"
+	RescanJobStatusFailed    RescanJobStatus = "FAILED"
+	RescanJobStatusCanceled  RescanJobStatus = "CANCELED"
+)
+
+const (
+	rescanEventsPerQuery = 100
+	// maxRescanJobs is the number of jobs kept to report their status.
+	maxRescanJobs = 20
+)
+
+var (
+	errRescanRunning    = errors.New("a re-scan job is already running")
+	errRescanEmptyScope = errors.New("the re-scan requires an API or a time range")
+	errRescanNoArchive  = errors.New("the re-scan requires the archive of the traces, see TRACE_ARCHIVE_ENABLED")
+)
+
+// credentialsAnnotations are the findings of the credentials analyzers, they are kept by the re-scan of the traces
+// whose credentials were redacted by the archive.
+var credentialsAnnotations = map[string]bool{
+	weakbasicauth.KindShortPassword:      true,
+	weakbasicauth.KindKnownPassword:      true,
+	weakbasicauth.KindSamePassword:       true,
+	weakjwt.JWTNoAlgField:                true,
+	weakjwt.JWTAlgFieldNone:              true,
+	weakjwt.JWTNotRecommendedAlg:         true,
+	weakjwt.JWTNoExpireClaim:             true,
+	weakjwt.JWTExpTooFar:                 true,
+	weakjwt.JWTWeakSymetricSecret:        true,
+	weakjwt.JWTSensitiveContentInHeaders: true,
+	weakjwt.JWTSensitiveContentInClaims:  true,
+}
+
+type rescanJob struct {
+	lock   sync.RWMutex
+	status RescanJob
+	cancel context.CancelFunc
+}
+
+func (j *rescanJob) getStatus() RescanJob {
+	j.lock.RLock()
+	defer j.lock.RUnlock()
+
+	return j.status
+}
+
+func (j *rescanJob) update(f func(status *RescanJob)) {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+
+	f(&j.status)
+}
+
+// rescanner re-runs the analyzers over the stored events, so that a change of the rules, the dictionaries
+// or the ignored findings applies to the past events as well.
+type rescanner struct {
+	ctx context.Context
+	ta  *traceAnalyzer
+
+	lock    sync.RWMutex
+	lastID  int64
+	jobs    []*rescanJob // the most recent first
+	running *rescanJob
+}
+
+func newRescanner(ctx context.Context, ta *traceAnalyzer) *rescanner {
+	return &rescanner{
+		ctx: ctx,
+		ta:  ta,
+	}
+}
+
+// Start starts a re-scan job of the scope in the background.
+func (r *rescanner) Start(scope RescanRequest) (RescanJob, error) {
+	if scope.ApiID == nil && scope.StartTime == nil && scope.EndTime == nil {
+		return RescanJob{}, errRescanEmptyScope
+	}
+	if !r.ta.config.traceArchiveEnabled {
+		return RescanJob{}, errRescanNoArchive
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if r.running != nil {
+		return RescanJob{}, errRescanRunning
+	}
+
+	ctx, cancel := context.WithCancel(r.ctx)
+	r.lastID++
+	job := &rescanJob{
+		status: RescanJob{
+			Id:        r.lastID,
+			ApiID:     scope.ApiID,
+			StartTime: scope.StartTime,
+			EndTime:   scope.EndTime,
+			Status:    RescanJobStatusRunning,
+			CreatedAt: time.Now().UTC(),
+		},
+		cancel: cancel,
+	}
+	r.running = job
+	r.jobs = append([]*rescanJob{job}, r.jobs...)
+	if len(r.jobs) > maxRescanJobs {
+		r.jobs = r.jobs[:maxRescanJobs]
+	}
+
+	go func() {
+		err := r.run(ctx, job)
+		job.update(func(status *RescanJob) {
+			completedAt := time.Now().UTC()
+			status.CompletedAt = &completedAt
+			switch {
+			case err == nil:
+				status.Status = RescanJobStatusCompleted
+			case errors.Is(err, context.Canceled):
+				status.Status = RescanJobStatusCanceled
+			default:
+				errMsg := err.Error()
+				status.Status = RescanJobStatusFailed
+				status.Error = &errMsg
+			}
+		})
+		cancel()
+
+		r.lock.Lock()
+		r.running = nil
+		r.lock.Unlock()
+
+		status := job.getStatus()
+		log.Infof("[TraceAnalyzer] re-scan job %d %s: processed events=%d, skipped events=%d",
+			status.Id, status.Status, status.ProcessedEvents, status.SkippedEvents)
+	}()
+
+	return job.getStatus(), nil
+}
+
+// Get returns the status of the job, or false if the job is not known.
+func (r *rescanner) Get(jobID int64) (RescanJob, bool) {
+	job := r.getJob(jobID)
+	if job == nil {
+		return RescanJob{}, false
+	}
+	return job.getStatus(), true
+}
+
+// List returns the status of the recent jobs, the most recent first.
+func (r *rescanner) List() []RescanJob {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	jobs := []RescanJob{}
+	for _, job := range r.jobs {
+		jobs = append(jobs, job.getStatus())
+	}
+	return jobs
+}
+
+// Cancel cancels the job if it is running, it returns false if the job is not known.
+func (r *rescanner) Cancel(jobID int64) bool {
+	job := r.getJob(jobID)
+	if job == nil {
+		return false
+	}
+	job.cancel()
+	return true
+}
+
+func (r *rescanner) getJob(jobID int64) *rescanJob {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	for _, job := range r.jobs {
+		if job.status.Id == jobID {
+			return job
+		}
+	}
+	return nil
+}
+
+func (r *rescanner) run(ctx context.Context, job *rescanJob) error {
+	status := job.getStatus()
+	filters := &database.APIEventsFilters{ShowNonAPI: true}
+	if status.ApiID != nil {
+		apiID := uint(*status.ApiID)
+		filters.APIInfoIDIs = &apiID
+	}
+	// by default the range ends at the job creation, the newer events are analyzed live
+	startTime, endTime := strfmt.DateTime(time.Time{}), strfmt.DateTime(status.CreatedAt)
+	if status.StartTime != nil {
+		startTime = strfmt.DateTime(*status.StartTime)
+	}
+	if status.EndTime != nil {
+		endTime = strfmt.DateTime(*status.EndTime)
+	}
+	filters.StartTime, filters.EndTime = &startTime, &endTime
+	// the API annotations are replaced only when all the events of the API are re-scanned
+	replaceAPIAnns := status.StartTime == nil && status.EndTime == nil
+
+	total, err := r.ta.accessor.CountAPIEvents(ctx, filters)
+	if err != nil {
+		return fmt.Errorf("failed to count the events to re-scan: %w", err)
+	}
+	job.update(func(status *RescanJob) { status.TotalEvents = total })
+
+	// the analyzers that learn from the traces replay the history from scratch, without altering the live ones
+	state := &analysisState{
+		learners:         newLearners(nil, r.ta.config.bruteForceThresholds, r.ta.config.enumerationThresholds),
+		endpointFindings: newEndpointFindings(r.ta.accessor),
+		weakBasicAuth:    r.ta.weakBasicAuth.WithoutHistory(),
+	}
+	replacedAPIAnns := map[uint]bool{}
+	// the live endpoint findings of the re-scanned APIs are loaded again from the re-scanned annotations, and the
+	// findings of the APIs changed
+	rescannedAPIs := map[uint]bool{}
+	defer func() {
+		for apiID := range rescannedAPIs {
+			r.ta.endpointFindings.forget(apiID)
+			r.ta.accessor.FindingsChanged(apiID)
+		}
+	}()
+
+	for offset := 0; ; offset += rescanEventsPerQuery {
+		events, err := r.ta.accessor.GetAPIEvents(ctx, database.GetAPIEventsQuery{
+			Offset:           offset,
+			Limit:            rescanEventsPerQuery,
+			Ascending:        true,
+			APIEventsFilters: filters,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to get the events to re-scan: %w", err)
+		}
+
+		for _, event := range events {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			trace, err := r.ta.accessor.GetAPIEventTelemetry(ctx, event.ID)
+			if err != nil {
+				return fmt.Errorf("failed to get the trace of event %d: %w", event.ID, err)
+			}
+			if trace == nil {
+				job.update(func(status *RescanJob) { status.SkippedEvents++ })
+				continue
+			}
+
+			if replaceAPIAnns && !replacedAPIAnns[event.APIInfoID] {
+				if err := r.deleteAPIAnnotations(ctx, event.APIInfoID); err != nil {
+					return err
+				}
+				replacedAPIAnns[event.APIInfoID] = true
+			}
+			// the redacted credentials can't be analyzed again, their findings are kept
+			redacted := hasRedactedCredentials(trace)
+			var eventAnns []core.Annotation
+			if redacted {
+				if eventAnns, err = r.getCredentialsAnnotations(ctx, event.ID); err != nil {
+					return err
+				}
+			}
+			if err := r.ta.accessor.DeleteAPIEventAnnotations(ctx, moduleName, event.ID); err != nil {
+				return fmt.Errorf("failed to delete the annotations of event %d: %w", event.ID, err)
+			}
+			newEventAnns, apiAnns := r.ta.analyze(ctx, state, event, trace, !redacted)
+			r.ta.storeAnnotations(ctx, event, append(eventAnns, newEventAnns...), apiAnns)
+			rescannedAPIs[event.APIInfoID] = true
+			job.update(func(status *RescanJob) { status.ProcessedEvents++ })
+		}
+
+		if len(events) < rescanEventsPerQuery {
+			return nil
+		}
+	}
+}
+
+func (r *rescanner) getCredentialsAnnotations(ctx context.Context, eventID uint) ([]core.Annotation, error) {
+	anns, err := r.ta.accessor.ListAPIEventAnnotations(ctx, moduleName, eventID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the annotations of event %d: %w", eventID, err)
+	}
+	credentialsAnns := []core.Annotation{}
+	for _, ann := range anns {
+		if credentialsAnnotations[ann.Name] {
+			credentialsAnns = append(credentialsAnns, *ann)
+		}
+	}
+	return credentialsAnns, nil
+}
+
+// hasRedactedCredentials returns true if the archive redacted the authorization header of the trace.
+func hasRedactedCredentials(trace *pluginsmodels.Telemetry) bool {
+	if trace.Request == nil || trace.Request.Common == nil {
+		return false
+	}
+	for _, h := range trace.Request.Common.Headers {
+		if h != nil && strings.EqualFold(h.Key, weakbasicauth.AuthorizationHeader) && database.IsRedactedHeaderValue(h.Key, h.Value) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *rescanner) deleteAPIAnnotations(ctx context.Context, apiID uint) error {
+	anns, err := r.ta.accessor.ListAPIInfoAnnotations(ctx, moduleName, apiID)
+	if err != nil {
+		return fmt.Errorf("failed to list the annotations of API %d: %w", apiID, err)
+	}
+	names := make([]string, 0, len(anns))
+	for _, ann := range anns {
+		// the live learnt histories are kept
+		if !isLearningStateAnnotation(ann.Name) {
+			names = append(names, ann.Name)
+		}
+	}
+	if len(names) == 0 {
+		return nil
+	}
+	if err := r.ta.accessor.DeleteAPIInfoAnnotations(ctx, moduleName, apiID, names...); err != nil {
+		return fmt.Errorf("failed to delete the annotations of API %d: %w", apiID, err)
+	}
+	r.ta.apiAnnotationsDeleted(apiID, names...)
+	return nil
+}
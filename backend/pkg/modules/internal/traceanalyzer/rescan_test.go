@@ -0,0 +1,240 @@
+// Copyright © 2022 Cisco Systems, Inc. and its affiliates.
+// All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package traceanalyzer
+
+import (
+	"context"
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+
+	"github.com/openclarity/apiclarity/backend/pkg/database"
+	"github.com/openclarity/apiclarity/backend/pkg/modules/internal/bfla/bfladetector"
+	"github.com/openclarity/apiclarity/backend/pkg/modules/internal/core"
+	"github.com/openclarity/apiclarity/backend/pkg/modules/internal/traceanalyzer/bruteforce"
+	"github.com/openclarity/apiclarity/backend/pkg/modules/internal/traceanalyzer/enumeration"
+	"github.com/openclarity/apiclarity/backend/pkg/modules/internal/traceanalyzer/securityheaders"
+	"github.com/openclarity/apiclarity/backend/pkg/modules/internal/traceanalyzer/sensitive"
+	"github.com/openclarity/apiclarity/backend/pkg/modules/internal/traceanalyzer/unauthenticated"
+	"github.com/openclarity/apiclarity/backend/pkg/modules/internal/traceanalyzer/weakbasicauth"
+	"github.com/openclarity/apiclarity/backend/pkg/modules/internal/traceanalyzer/weakjwt"
+	pluginsmodels "github.com/openclarity/apiclarity/plugins/api/server/models"
+)
+
+func newTestTraceAnalyzer(t *testing.T, accessor core.BackendAccessor) *traceAnalyzer {
+	t.Helper()
+
+	sens, err := sensitive.NewSensitive(nil)
+	if err != nil {
+		t.Fatalf("failed to create sensitive analyzer: %v", err)
+	}
+	ta := &traceAnalyzer{
+		config: traceAnalyzerConfig{
+			bruteForceThresholds:   bruteforce.DefaultThresholds(),
+			enumerationThresholds:  enumeration.DefaultThresholds(),
+			userIdentityExtractors: bfladetector.DefaultUserIdentityExtractors(),
+			traceArchiveEnabled:    true,
+		},
+		ignoreFindings:   map[string]bool{},
+		weakBasicAuth:    weakbasicauth.NewWeakBasicAuth([]string{}),
+		weakJWT:          weakjwt.NewWeakJWT([]string{}, []string{}),
+		sensitive:        sens,
+		securityHeaders:  securityheaders.NewSecurityHeaders(),
+		endpointFindings: newEndpointFindings(accessor),
+		learners:         newLearners(nil, bruteforce.DefaultThresholds(), enumeration.DefaultThresholds()),
+		suppressions:     &suppressions{accessor: accessor, items: map[int64]*Suppression{}, now: time.Now},
+		accessor:         accessor,
+	}
+	ta.rescanner = newRescanner(context.Background(), ta)
+	return ta
+}
+
+func waitRescan(t *testing.T, r *rescanner, jobID int64) RescanJob {
+	t.Helper()
+
+	for i := 0; i < 100; i++ {
+		job, ok := r.Get(jobID)
+		if !ok {
+			t.Fatalf("job %d not found", jobID)
+		}
+		if job.Status != RescanJobStatusRunning {
+			return job
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("job %d did not complete", jobID)
+	return RescanJob{}
+}
+
+func TestRescanner_Start(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	accessor := core.NewMockBackendAccessor(mockCtrl)
+	ta := newTestTraceAnalyzer(t, accessor)
+
+	trace := &pluginsmodels.Telemetry{
+		Request: &pluginsmodels.Request{
+			Method: "GET",
+			Path:   "/users",
+			Host:   "users",
+			Common: &pluginsmodels.Common{
+				Headers: []*pluginsmodels.Header{
+					{Key: "Authorization", Value: "Basic " + base64.StdEncoding.EncodeToString([]byte("alice:pass"))},
+				},
+			},
+		},
+		Response: &pluginsmodels.Response{
+			StatusCode: "401",
+			Common:     &pluginsmodels.Common{},
+		},
+	}
+	// the archive redacted the password of the same user
+	redactedTrace := *trace
+	redactedTrace.Request = &pluginsmodels.Request{
+		Method: "GET",
+		Path:   "/users",
+		Host:   "users",
+		Common: &pluginsmodels.Common{
+			Headers: []*pluginsmodels.Header{
+				{Key: "Authorization", Value: "Basic " + base64.StdEncoding.EncodeToString([]byte("alice:"+database.RedactedValue))},
+			},
+		},
+	}
+	events := []*database.APIEvent{
+		{ID: 1, APIInfoID: 10, Method: "GET", Path: "/users"},
+		{ID: 2, APIInfoID: 10, Method: "GET", Path: "/users"},
+		{ID: 3, APIInfoID: 10, Method: "GET", Path: "/users"},
+	}
+
+	accessor.EXPECT().CountAPIEvents(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, filters *database.APIEventsFilters) (int64, error) {
+			if filters.APIInfoIDIs == nil || *filters.APIInfoIDIs != 10 {
+				t.Errorf("unexpected API filter %v", filters.APIInfoIDIs)
+			}
+			return int64(len(events)), nil
+		})
+	accessor.EXPECT().GetAPIEvents(gomock.Any(), gomock.Any()).Return(events, nil)
+	accessor.EXPECT().GetAPIEventTelemetry(gomock.Any(), uint(1)).Return(trace, nil)
+	// the specs give the endpoint of the failed authentication
+	accessor.EXPECT().GetAPIInfo(gomock.Any(), uint(10)).Return(&database.APIInfo{}, nil).Times(2)
+	accessor.EXPECT().GetAPIEventTelemetry(gomock.Any(), uint(2)).Return(nil, nil)
+	accessor.EXPECT().GetAPIEventTelemetry(gomock.Any(), uint(3)).Return(&redactedTrace, nil)
+	accessor.EXPECT().ListAPIEventAnnotations(gomock.Any(), moduleName, uint(3)).Return(
+		[]*core.Annotation{{Name: weakbasicauth.KindKnownPassword, Annotation: []byte("pass")}, {Name: "STALE"}}, nil)
+	accessor.EXPECT().ListAPIInfoAnnotations(gomock.Any(), moduleName, uint(10)).Return(
+		[]*core.Annotation{{Name: "STALE"}, {Name: unauthenticated.KindUnauthenticatedAccess}}, nil)
+	accessor.EXPECT().DeleteAPIInfoAnnotations(gomock.Any(), moduleName, uint(10), "STALE", unauthenticated.KindUnauthenticatedAccess).Return(nil)
+	// the live learner reported the unauthenticated access of the deleted annotation
+	live := ta.learners.get(10).unauthenticated
+	if err := live.UnmarshalJSON([]byte(`{"GET 1":{"method":"GET","pathID":"1","unauthenticatedAccess":true}}`)); err != nil {
+		t.Fatalf("failed to restore the unauthenticated accesses: %v", err)
+	}
+	created := map[uint][]string{}
+	for _, eventID := range []uint{1, 3} {
+		deleted := accessor.EXPECT().DeleteAPIEventAnnotations(gomock.Any(), moduleName, eventID).Return(nil)
+		accessor.EXPECT().CreateAPIEventAnnotations(gomock.Any(), moduleName, eventID, gomock.Any()).After(deleted).DoAndReturn(
+			func(_ context.Context, _ string, eventID uint, anns ...core.Annotation) error {
+				for _, ann := range anns {
+					created[eventID] = append(created[eventID], ann.Name)
+				}
+				return nil
+			}).AnyTimes()
+	}
+	accessor.EXPECT().StoreAPIInfoAnnotations(gomock.Any(), moduleName, uint(10), gomock.Any()).Return(nil).AnyTimes()
+	accessor.EXPECT().FindingsChanged(uint(10))
+
+	apiID := int64(10)
+	job, err := ta.rescanner.Start(RescanRequest{ApiID: &apiID})
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	job = waitRescan(t, ta.rescanner, job.Id)
+	if job.Status != RescanJobStatusCompleted || job.TotalEvents != 3 || job.ProcessedEvents != 2 || job.SkippedEvents != 1 || job.CompletedAt == nil {
+		t.Errorf("unexpected job status %+v", job)
+	}
+	if len(created[1]) == 0 || created[1][0] != weakbasicauth.KindShortPassword {
+		t.Errorf("unexpected re-scan annotations %v", created[1])
+	}
+	// the redacted password is not analyzed, its findings are kept
+	if len(created[3]) == 0 || created[3][0] != weakbasicauth.KindKnownPassword {
+		t.Errorf("unexpected re-scan annotations of the redacted trace %v", created[3])
+	}
+	for _, name := range created[3] {
+		if name == weakbasicauth.KindShortPassword || name == "STALE" {
+			t.Errorf("unexpected re-scan annotations of the redacted trace %v", created[3])
+		}
+	}
+	// the live analyzer did not learn the re-scanned credentials
+	otherTrace := *trace
+	otherTrace.Request = &pluginsmodels.Request{Method: "GET", Path: "/orders", Host: "orders", Common: trace.Request.Common}
+	if anns, _ := ta.weakBasicAuth.Analyze(&otherTrace); len(anns) != 1 || anns[0].Name != weakbasicauth.KindShortPassword {
+		t.Errorf("unexpected live annotations %v", anns)
+	}
+	// the deleted unauthenticated access is reported again
+	if ops := live.Operations(); len(ops) != 1 || ops[0].UnauthenticatedAccess {
+		t.Errorf("the live unauthenticated accesses were not reset: %+v", ops)
+	}
+	if jobs := ta.rescanner.List(); len(jobs) != 1 || jobs[0].Id != job.Id {
+		t.Errorf("List() = %+v", jobs)
+	}
+}
+
+func TestRescanner_StartErrors(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	accessor := core.NewMockBackendAccessor(mockCtrl)
+	ta := newTestTraceAnalyzer(t, accessor)
+
+	if _, err := ta.rescanner.Start(RescanRequest{}); err != errRescanEmptyScope {
+		t.Errorf("Start() error = %v, want %v", err, errRescanEmptyScope)
+	}
+	apiID := int64(10)
+	ta.config.traceArchiveEnabled = false
+	if _, err := ta.rescanner.Start(RescanRequest{ApiID: &apiID}); err != errRescanNoArchive {
+		t.Errorf("Start() error = %v, want %v", err, errRescanNoArchive)
+	}
+	ta.config.traceArchiveEnabled = true
+
+	// block the first job until the second one is rejected
+	release := make(chan struct{})
+	accessor.EXPECT().CountAPIEvents(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, _ *database.APIEventsFilters) (int64, error) {
+			<-release
+			return 0, ctx.Err()
+		})
+
+	startTime := time.Now().Add(-time.Hour)
+	job, err := ta.rescanner.Start(RescanRequest{StartTime: &startTime})
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	if _, err := ta.rescanner.Start(RescanRequest{StartTime: &startTime}); err != errRescanRunning {
+		t.Errorf("Start() error = %v, want %v", err, errRescanRunning)
+	}
+
+	if !ta.rescanner.Cancel(job.Id) {
+		t.Errorf("Cancel() job %d not found", job.Id)
+	}
+	close(release)
+	if job = waitRescan(t, ta.rescanner, job.Id); job.Status != RescanJobStatusCanceled {
+		t.Errorf("unexpected job status %+v", job)
+	}
+	if ta.rescanner.Cancel(job.Id + 1) {
+		t.Errorf("Cancel() expected unknown job")
+	}
+}
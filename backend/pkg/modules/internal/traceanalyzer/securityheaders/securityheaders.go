@@ -0,0 +1,272 @@
+// Copyright © 2022 Cisco Systems, Inc. and its affiliates.
+// All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package securityheaders
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/openclarity/apiclarity/backend/pkg/modules/internal/core"
+	"github.com/openclarity/apiclarity/backend/pkg/modules/internal/traceanalyzer/utils"
+	_utils "github.com/openclarity/apiclarity/backend/pkg/utils"
+	"github.com/openclarity/apiclarity/plugins/api/server/models"
+)
+
+const (
+	KindHSTSMissing                 = "HSTS_MISSING"
+	KindHSTSWeak                    = "HSTS_WEAK"
+	KindContentTypeOptionsMissing   = "CONTENT_TYPE_OPTIONS_MISSING"
+	KindCacheControlMissing         = "CACHE_CONTROL_MISSING"
+	KindCORSWildcardWithCredentials = "CORS_WILDCARD_WITH_CREDENTIALS"
+	KindCORSReflectedOrigin         = "CORS_REFLECTED_ORIGIN"
+	KindCookieInsecure              = "COOKIE_INSECURE"
+)
+
+const (
+	// 180 days, the minimum max-age recommended for HSTS.
+	MinHSTSMaxAge = 15552000
+	// The endpoints listed by a finding are capped, the first ones are kept.
+	MaxEndpoints = 100
+)
+
+const (
+	hstsHeader                = "strict-transport-security"
+	contentTypeOptionsHeader  = "x-content-type-options"
+	cacheControlHeader        = "cache-control"
+	allowOriginHeader         = "access-control-allow-origin"
+	allowCredentialsHeader    = "access-control-allow-credentials"
+	originHeader              = "origin"
+	setCookieHeader           = "set-cookie"
+	nosniff                   = "nosniff"
+	noStore                   = "no-store"
+	maxAgeDirective           = "max-age"
+	wildcardOrigin            = "*"
+	schemeHTTPS               = "https"
+	allowCredentialsTrueValue = "true"
+)
+
+var kinds = map[string]bool{
+	KindHSTSMissing:                 true,
+	KindHSTSWeak:                    true,
+	KindContentTypeOptionsMissing:   true,
+	KindCacheControlMissing:         true,
+	KindCORSWildcardWithCredentials: true,
+	KindCORSReflectedOrigin:         true,
+	KindCookieInsecure:              true,
+}
+
+// IsKind returns true if the annotation is a finding of the analyzer.
+func IsKind(name string) bool {
+	return kinds[name]
+}
+
+// Endpoint is an operation of the API where a finding was seen, with the details of its first trace.
+type Endpoint struct {
+	Method   string `json:"method"`
+	Location string `json:"location"`
+	Detail   string `json:"detail,omitempty"`
+}
+
+// Finding is the annotation of a kind of findings of an API, it lists the endpoints where it was seen.
+type Finding struct {
+	Endpoints []Endpoint `json:"endpoints"`
+}
+
+// Add adds the endpoint to the finding, it returns false if the endpoint was already listed, or the list is full.
+func (f *Finding) Add(e Endpoint) bool {
+	if len(f.Endpoints) >= MaxEndpoints {
+		return false
+	}
+	for _, known := range f.Endpoints {
+		if known.Method == e.Method && known.Location == e.Location {
+			return false
+		}
+	}
+	f.Endpoints = append(f.Endpoints, e)
+	return true
+}
+
+type SecurityHeaders struct {
+	minHSTSMaxAge int64
+}
+
+func NewSecurityHeaders() *SecurityHeaders {
+	return &SecurityHeaders{
+		minHSTSMaxAge: MinHSTSMaxAge,
+	}
+}
+
+func headerValue(headers []*models.Header, name string) (string, bool) {
+	index, found := utils.FindHeader(headers, name)
+	if !found {
+		return "", false
+	}
+	return strings.TrimSpace(headers[index].Value), true
+}
+
+func hasCredentials(headers []*models.Header) bool {
+	for _, h := range headers {
+		if h != nil && strings.TrimSpace(h.Value) != "" && _utils.IsCredentialsHeader(h.Key) {
+			return true
+		}
+	}
+	return false
+}
+
+// analyzeHSTS checks the HSTS policy over HTTPS, browsers ignore it over plain HTTP. Some gateways don't report
+// the scheme, the policy is not checked then.
+func (s *SecurityHeaders) analyzeHSTS(trace *models.Telemetry, respHeaders []*models.Header) (anns []core.Annotation) {
+	if !strings.EqualFold(trace.Scheme, schemeHTTPS) {
+		return anns
+	}
+
+	hsts, found := headerValue(respHeaders, hstsHeader)
+	if !found {
+		return append(anns, core.Annotation{Name: KindHSTSMissing})
+	}
+
+	maxAge := int64(-1)
+	for _, directive := range strings.Split(hsts, ";") {
+		//nolint:gomnd
+		nameValue := strings.SplitN(strings.TrimSpace(directive), "=", 2)
+		if len(nameValue) == 2 && strings.EqualFold(nameValue[0], maxAgeDirective) {
+			if v, err := strconv.ParseInt(strings.Trim(nameValue[1], `"`), 10, 64); err == nil {
+				maxAge = v
+			}
+		}
+	}
+	if maxAge < s.minHSTSMaxAge {
+		anns = append(anns, core.Annotation{Name: KindHSTSWeak, Annotation: []byte(hsts)})
+	}
+
+	return anns
+}
+
+func analyzeContentTypeOptions(respHeaders []*models.Header) (anns []core.Annotation) {
+	value, _ := headerValue(respHeaders, contentTypeOptionsHeader)
+	if !strings.EqualFold(value, nosniff) {
+		anns = append(anns, core.Annotation{Name: KindContentTypeOptionsMissing, Annotation: []byte(value)})
+	}
+
+	return anns
+}
+
+// analyzeCacheControl checks that the responses to authenticated requests are not stored by the caches.
+func analyzeCacheControl(reqHeaders []*models.Header, respHeaders []*models.Header) (anns []core.Annotation) {
+	if !hasCredentials(reqHeaders) {
+		return anns
+	}
+
+	value, _ := headerValue(respHeaders, cacheControlHeader)
+	for _, directive := range strings.Split(value, ",") {
+		if strings.EqualFold(strings.TrimSpace(directive), noStore) {
+			return anns
+		}
+	}
+
+	return append(anns, core.Annotation{Name: KindCacheControlMissing, Annotation: []byte(value)})
+}
+
+// analyzeCORS checks for the wildcard origin allowed with credentials, and for a cross-origin request origin
+// reflected in the allowed origin.
+func analyzeCORS(trace *models.Telemetry, reqHeaders []*models.Header, respHeaders []*models.Header) (anns []core.Annotation) {
+	allowOrigin, found := headerValue(respHeaders, allowOriginHeader)
+	if !found {
+		return anns
+	}
+	allowCredentials, _ := headerValue(respHeaders, allowCredentialsHeader)
+	withCredentials := strings.EqualFold(allowCredentials, allowCredentialsTrueValue)
+
+	if allowOrigin == wildcardOrigin {
+		if withCredentials {
+			anns = append(anns, core.Annotation{Name: KindCORSWildcardWithCredentials})
+		}
+		return anns
+	}
+
+	origin, _ := headerValue(reqHeaders, originHeader)
+	if origin == "" || origin != allowOrigin || isSameOrigin(origin, trace.Request.Host) {
+		return anns
+	}
+	detail := fmt.Sprintf("origin '%s' allowed", origin)
+	if withCredentials {
+		detail += " with credentials"
+	}
+
+	return append(anns, core.Annotation{Name: KindCORSReflectedOrigin, Annotation: []byte(detail)})
+}
+
+func isSameOrigin(origin string, host string) bool {
+	u, err := url.Parse(origin)
+	if err != nil || host == "" {
+		return false
+	}
+	return strings.EqualFold(u.Host, host) || strings.EqualFold(u.Hostname(), host)
+}
+
+// analyzeCookies checks the attributes of the cookies set by the response.
+func analyzeCookies(respHeaders []*models.Header) (anns []core.Annotation) {
+	header := http.Header{}
+	for _, h := range respHeaders {
+		if h != nil && strings.EqualFold(h.Key, setCookieHeader) {
+			header.Add(setCookieHeader, h.Value)
+		}
+	}
+
+	details := []string{}
+	for _, cookie := range (&http.Response{Header: header}).Cookies() {
+		missing := []string{}
+		if !cookie.Secure {
+			missing = append(missing, "Secure")
+		}
+		if !cookie.HttpOnly {
+			missing = append(missing, "HttpOnly")
+		}
+		if cookie.SameSite == 0 {
+			missing = append(missing, "SameSite")
+		}
+		if len(missing) > 0 {
+			details = append(details, fmt.Sprintf("%s without %s", cookie.Name, strings.Join(missing, ", ")))
+		}
+	}
+	if len(details) > 0 {
+		sort.Strings(details)
+		anns = append(anns, core.Annotation{Name: KindCookieInsecure, Annotation: []byte(strings.Join(details, "; "))})
+	}
+
+	return anns
+}
+
+// Analyze checks the security headers of the response. The annotations are the findings of the trace, the
+// caller aggregates them by endpoint.
+func (s *SecurityHeaders) Analyze(trace *models.Telemetry) (anns []core.Annotation) {
+	if trace.Request == nil || trace.Request.Common == nil || trace.Response == nil || trace.Response.Common == nil {
+		return anns
+	}
+	reqHeaders, respHeaders := trace.Request.Common.Headers, trace.Response.Common.Headers
+
+	anns = append(anns, s.analyzeHSTS(trace, respHeaders)...)
+	anns = append(anns, analyzeContentTypeOptions(respHeaders)...)
+	anns = append(anns, analyzeCacheControl(reqHeaders, respHeaders)...)
+	anns = append(anns, analyzeCORS(trace, reqHeaders, respHeaders)...)
+	anns = append(anns, analyzeCookies(respHeaders)...)
+
+	return anns
+}
@@ -0,0 +1,178 @@
+// Copyright © 2022 Cisco Systems, Inc. and its affiliates.
+// All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package securityheaders
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/openclarity/apiclarity/backend/pkg/modules/internal/core"
+	"github.com/openclarity/apiclarity/plugins/api/server/models"
+)
+
+// secureHeaders are the response headers without any finding.
+func secureHeaders() []*models.Header {
+	return []*models.Header{
+		{Key: "Strict-Transport-Security", Value: "max-age=31536000; includeSubDomains"},
+		{Key: "X-Content-Type-Options", Value: "nosniff"},
+		{Key: "Cache-Control", Value: "private, no-store"},
+	}
+}
+
+func newTrace(reqHeaders []*models.Header, respHeaders []*models.Header) *models.Telemetry {
+	return &models.Telemetry{
+		Scheme: "https",
+		Request: &models.Request{
+			Host:   "api.example.com",
+			Method: "GET",
+			Path:   "/users",
+			Common: &models.Common{Headers: reqHeaders},
+		},
+		Response: &models.Response{
+			StatusCode: "200",
+			Common:     &models.Common{Headers: respHeaders},
+		},
+	}
+}
+
+func annsString(anns []core.Annotation) string {
+	s := ""
+	for _, a := range anns {
+		s += fmt.Sprintf("%s[%s] ", a.Name, a.Annotation)
+	}
+	return s
+}
+
+func TestAnalyze(t *testing.T) {
+	testcases := []struct {
+		name        string
+		scheme      string
+		noScheme    bool
+		reqHeaders  []*models.Header
+		respHeaders []*models.Header
+		wanted      []core.Annotation
+	}{
+		{
+			name:        "secure",
+			reqHeaders:  []*models.Header{{Key: "Authorization", Value: "Bearer token"}},
+			respHeaders: secureHeaders(),
+		},
+		{
+			name:   "missing headers",
+			wanted: []core.Annotation{{Name: KindHSTSMissing}, {Name: KindContentTypeOptionsMissing, Annotation: []byte("")}},
+		},
+		{
+			name:   "HSTS is not checked over HTTP",
+			scheme: "http",
+			respHeaders: []*models.Header{
+				{Key: "X-Content-Type-Options", Value: "nosniff"},
+			},
+		},
+		{
+			name:     "HSTS is not checked without the scheme",
+			noScheme: true,
+			respHeaders: []*models.Header{
+				{Key: "X-Content-Type-Options", Value: "nosniff"},
+			},
+		},
+		{
+			name: "weak HSTS",
+			respHeaders: []*models.Header{
+				{Key: "strict-transport-security", Value: "max-age=3600"},
+				{Key: "X-Content-Type-Options", Value: "NoSniff"},
+			},
+			wanted: []core.Annotation{{Name: KindHSTSWeak, Annotation: []byte("max-age=3600")}},
+		},
+		{
+			name:       "cacheable authenticated response",
+			reqHeaders: []*models.Header{{Key: "Cookie", Value: "session=1234"}},
+			respHeaders: append(secureHeaders()[:2],
+				&models.Header{Key: "Cache-Control", Value: "private, max-age=600"}),
+			wanted: []core.Annotation{{Name: KindCacheControlMissing, Annotation: []byte("private, max-age=600")}},
+		},
+		{
+			name: "wildcard origin with credentials",
+			respHeaders: append(secureHeaders(),
+				&models.Header{Key: "Access-Control-Allow-Origin", Value: "*"},
+				&models.Header{Key: "Access-Control-Allow-Credentials", Value: "true"}),
+			wanted: []core.Annotation{{Name: KindCORSWildcardWithCredentials}},
+		},
+		{
+			name:       "wildcard origin",
+			reqHeaders: []*models.Header{{Key: "Origin", Value: "https://evil.example.org"}},
+			respHeaders: append(secureHeaders(),
+				&models.Header{Key: "Access-Control-Allow-Origin", Value: "*"}),
+		},
+		{
+			name:       "reflected origin",
+			reqHeaders: []*models.Header{{Key: "Origin", Value: "https://evil.example.org"}},
+			respHeaders: append(secureHeaders(),
+				&models.Header{Key: "Access-Control-Allow-Origin", Value: "https://evil.example.org"},
+				&models.Header{Key: "Access-Control-Allow-Credentials", Value: "true"}),
+			wanted: []core.Annotation{{Name: KindCORSReflectedOrigin, Annotation: []byte("origin 'https://evil.example.org' allowed with credentials")}},
+		},
+		{
+			name:       "same origin",
+			reqHeaders: []*models.Header{{Key: "Origin", Value: "https://api.example.com"}},
+			respHeaders: append(secureHeaders(),
+				&models.Header{Key: "Access-Control-Allow-Origin", Value: "https://api.example.com"}),
+		},
+		{
+			name: "insecure cookies",
+			respHeaders: append(secureHeaders(),
+				&models.Header{Key: "Set-Cookie", Value: "session=1234; Secure; HttpOnly; SameSite=Strict"},
+				&models.Header{Key: "Set-Cookie", Value: "theme=dark; Secure"},
+				&models.Header{Key: "set-cookie", Value: "tracking=1; HttpOnly; SameSite=None"}),
+			wanted: []core.Annotation{{Name: KindCookieInsecure, Annotation: []byte("theme without HttpOnly, SameSite; tracking without Secure")}},
+		},
+	}
+
+	s := NewSecurityHeaders()
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			trace := newTrace(tc.reqHeaders, tc.respHeaders)
+			if tc.scheme != "" || tc.noScheme {
+				trace.Scheme = tc.scheme
+			}
+			got := s.Analyze(trace)
+			if annsString(got) != annsString(tc.wanted) {
+				t.Errorf("Analyze() = %s, wanted %s", annsString(got), annsString(tc.wanted))
+			}
+		})
+	}
+}
+
+func TestFinding_Add(t *testing.T) {
+	f := Finding{}
+	if !f.Add(Endpoint{Method: "GET", Location: "/users/{id}", Detail: "max-age=0"}) {
+		t.Errorf("Add() = false for a new endpoint")
+	}
+	if f.Add(Endpoint{Method: "GET", Location: "/users/{id}", Detail: "max-age=10"}) {
+		t.Errorf("Add() = true for a known endpoint")
+	}
+	if !f.Add(Endpoint{Method: "POST", Location: "/users/{id}"}) {
+		t.Errorf("Add() = false for a new method")
+	}
+	for i := len(f.Endpoints); i < MaxEndpoints; i++ {
+		f.Add(Endpoint{Method: "GET", Location: fmt.Sprintf("/path%d", i)})
+	}
+	if f.Add(Endpoint{Method: "GET", Location: "/more"}) || len(f.Endpoints) != MaxEndpoints {
+		t.Errorf("Add() added more than %d endpoints", MaxEndpoints)
+	}
+	if f.Endpoints[0].Detail != "max-age=0" {
+		t.Errorf("the detail of the first trace was replaced: %+v", f.Endpoints[0])
+	}
+}
@@ -0,0 +1,167 @@
+// Copyright © 2022 Cisco Systems, Inc. and its affiliates.
+// All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sensitive
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const jsonPathWildcard = "*"
+
+// jsonPathSegment is a member name, an array index, or the wildcard.
+type jsonPathSegment struct {
+	name  string
+	index int
+	isIdx bool
+}
+
+// jsonPath is a compiled JSON path of the subset: $.member, $.member[0], $.member[*] and $.*.
+// A member segment applied to an array applies to every element of the array.
+type jsonPath []jsonPathSegment
+
+func compileJSONPath(path string) (jsonPath, error) {
+	if !strings.HasPrefix(path, "$") {
+		return nil, fmt.Errorf("the JSON path '%s' must start with '$'", path)
+	}
+
+	compiled := jsonPath{}
+	rest := path[1:]
+	for rest != "" {
+		switch rest[0] {
+		case '.':
+			end := strings.IndexAny(rest[1:], ".[")
+			if end < 0 {
+				end = len(rest) - 1
+			}
+			name := rest[1 : end+1]
+			if name == "" {
+				return nil, fmt.Errorf("the JSON path '%s' has an empty member name", path)
+			}
+			compiled = append(compiled, jsonPathSegment{name: name})
+			rest = rest[end+1:]
+		case '[':
+			end := strings.IndexByte(rest, ']')
+			if end < 0 {
+				return nil, fmt.Errorf("the JSON path '%s' has an unclosed bracket", path)
+			}
+			subscript := rest[1:end]
+			if subscript == jsonPathWildcard {
+				compiled = append(compiled, jsonPathSegment{name: jsonPathWildcard})
+			} else {
+				index, err := strconv.Atoi(subscript)
+				if err != nil || index < 0 {
+					return nil, fmt.Errorf("the JSON path '%s' has an invalid array index '%s'", path, subscript)
+				}
+				compiled = append(compiled, jsonPathSegment{index: index, isIdx: true})
+			}
+			rest = rest[end+1:]
+		default:
+			return nil, fmt.Errorf("the JSON path '%s' is not valid", path)
+		}
+	}
+
+	return compiled, nil
+}
+
+// values returns the values selected by the path in the parsed JSON document.
+func (p jsonPath) values(doc interface{}) []interface{} {
+	current := []interface{}{doc}
+	for _, segment := range p {
+		next := []interface{}{}
+		for _, value := range current {
+			next = append(next, segment.apply(value)...)
+		}
+		current = next
+	}
+
+	return current
+}
+
+func (s jsonPathSegment) apply(value interface{}) []interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if s.isIdx {
+			return nil
+		}
+		if s.name == jsonPathWildcard {
+			values := make([]interface{}, 0, len(v))
+			for _, child := range v {
+				values = append(values, child)
+			}
+			return values
+		}
+		if child, ok := v[s.name]; ok {
+			return []interface{}{child}
+		}
+	case []interface{}:
+		if s.isIdx {
+			if s.index < len(v) {
+				return []interface{}{v[s.index]}
+			}
+			return nil
+		}
+		if s.name == jsonPathWildcard {
+			return v
+		}
+		values := []interface{}{}
+		for _, elem := range v {
+			values = append(values, s.apply(elem)...)
+		}
+		return values
+	}
+
+	return nil
+}
+
+// parseJSONBody parses the body, it returns false if the body is not a JSON document.
+func parseJSONBody(body []byte) (interface{}, bool) {
+	if len(body) == 0 {
+		return nil, false
+	}
+	var doc interface{}
+	d := json.NewDecoder(bytes.NewReader(body))
+	d.UseNumber()
+	if err := d.Decode(&doc); err != nil {
+		return nil, false
+	}
+
+	return doc, true
+}
+
+// jsonValueString returns the text the regex is applied to: the raw strings and numbers, and the JSON of the
+// objects and the arrays.
+func jsonValueString(value interface{}) (string, bool) {
+	switch v := value.(type) {
+	case nil:
+		return "", false
+	case string:
+		return v, true
+	case json.Number:
+		return v.String(), true
+	case bool:
+		return strconv.FormatBool(v), true
+	default:
+		b, err := json.Marshal(v)
+		if err != nil {
+			return "", false
+		}
+		return string(b), true
+	}
+}
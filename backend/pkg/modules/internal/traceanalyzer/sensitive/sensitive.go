@@ -16,9 +16,15 @@
 package sensitive
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"net/url"
 	"os"
 	"regexp"
+	"sort"
+	"strings"
+	"sync/atomic"
 
 	yaml "gopkg.in/yaml.v3"
 
@@ -29,45 +35,150 @@ import (
 const (
 	AuthorizationHeader = "sensitive"
 
-	SearchInRequestHeaders  = "RequestHeaders"
-	SearchInResponseHeaders = "ResponseHeaders"
-	SearchInRequestBody     = "RequestBody"
-	SearchInResponseBody    = "ResponseBody"
+	SearchInRequestHeaders     = "RequestHeaders"
+	SearchInResponseHeaders    = "ResponseHeaders"
+	SearchInRequestBody        = "RequestBody"
+	SearchInResponseBody       = "ResponseBody"
+	SearchInRequestQueryParams = "RequestQueryParams"
+	SearchInRequestPathParams  = "RequestPathParams"
 )
 
 const (
-	RexgexpMatchingRequestBody     = "REGEXP_MATCHING_REQUEST_BODY"
-	RexgexpMatchingResponseBody    = "REGEXP_MATCHING_RESPONSE_BODY"
-	RegexpMatchingRequestHeaders   = "REGEXP_MATCHING_REQUEST_HEADERS"
-	RexgexpMatchingResponseHeaders = "REGEXP_MATCHING_RESPONSE_HEADERS"
+	RexgexpMatchingRequestBody       = "REGEXP_MATCHING_REQUEST_BODY"
+	RexgexpMatchingResponseBody      = "REGEXP_MATCHING_RESPONSE_BODY"
+	RegexpMatchingRequestHeaders     = "REGEXP_MATCHING_REQUEST_HEADERS"
+	RexgexpMatchingResponseHeaders   = "REGEXP_MATCHING_RESPONSE_HEADERS"
+	RegexpMatchingRequestQueryParams = "REGEXP_MATCHING_REQUEST_QUERY_PARAMS"
+	RegexpMatchingRequestPathParams  = "REGEXP_MATCHING_REQUEST_PATH_PARAMS"
 )
 
+// DefaultSeverity is the severity of the rules that don't set one.
+const DefaultSeverity = "MEDIUM"
+
+// The severities of the Trace Analyzer findings.
+var validSeverities = map[string]bool{
+	"INFO":     true,
+	"WARN":     true,
+	"LOW":      true,
+	"MEDIUM":   true,
+	"HIGH":     true,
+	"CRITICAL": true,
+}
+
+// The annotation raised for each searchIn location.
+var searchInAnnotations = map[string]string{
+	SearchInRequestHeaders:     RegexpMatchingRequestHeaders,
+	SearchInResponseHeaders:    RexgexpMatchingResponseHeaders,
+	SearchInRequestBody:        RexgexpMatchingRequestBody,
+	SearchInResponseBody:       RexgexpMatchingResponseBody,
+	SearchInRequestQueryParams: RegexpMatchingRequestQueryParams,
+	SearchInRequestPathParams:  RegexpMatchingRequestPathParams,
+}
+
+// IsMatchAnnotation returns true for the annotations raised by the rules, their value is a Match.
+func IsMatchAnnotation(name string) bool {
+	for _, annName := range searchInAnnotations {
+		if annName == name {
+			return true
+		}
+	}
+	return false
+}
+
 type Rule struct {
-	ID          string   `yaml:"id"`
-	Description string   `yaml:"description"`
-	Regex       string   `yaml:"regex"`
-	SearchIn    []string `yaml:"searchIn"`
+	ID          string   `yaml:"id" json:"id"`
+	Description string   `yaml:"description" json:"description"`
+	Regex       string   `yaml:"regex" json:"regex"`
+	SearchIn    []string `yaml:"searchIn" json:"searchIn"`
+	Severity    string   `yaml:"severity" json:"severity"`
+	// Headers restricts the headers locations to the values of these headers (case insensitive).
+	Headers []string `yaml:"headers" json:"headers,omitempty"`
+	// JSONPaths restricts the body locations to the values selected by these JSON paths (e.g. $.payment.pan).
+	JSONPaths []string `yaml:"jsonPaths" json:"jsonPaths,omitempty"`
+	// Params restricts the query and path parameters locations to the values of these parameters.
+	Params []string `yaml:"params" json:"params,omitempty"`
+
+	CompiledRegex     *regexp.Regexp `yaml:"-" json:"-"`
+	compiledJSONPaths []jsonPath
+}
+
+// Compile validates the rule and compiles its regex and JSON paths. A rule without severity gets the default severity.
+func (r *Rule) Compile() error {
+	if r.ID == "" {
+		return errors.New("the rule id is empty")
+	}
+	if len(r.SearchIn) == 0 || !isValidSearchIn(r.SearchIn) {
+		return fmt.Errorf("the searchIn Location (%v) is not valid", r.SearchIn)
+	}
+	if r.Severity == "" {
+		r.Severity = DefaultSeverity
+	}
+	if !validSeverities[r.Severity] {
+		return fmt.Errorf("the severity (%s) is not valid", r.Severity)
+	}
+	if len(r.Headers) > 0 && !r.searchesIn(SearchInRequestHeaders, SearchInResponseHeaders) {
+		return errors.New("headers are set but the rule doesn't search in the headers")
+	}
+	if len(r.JSONPaths) > 0 && !r.searchesIn(SearchInRequestBody, SearchInResponseBody) {
+		return errors.New("JSON paths are set but the rule doesn't search in the bodies")
+	}
+	if len(r.Params) > 0 && !r.searchesIn(SearchInRequestQueryParams, SearchInRequestPathParams) {
+		return errors.New("params are set but the rule doesn't search in the parameters")
+	}
+
+	compiledRegex, err := regexp.Compile(r.Regex)
+	if err != nil {
+		return fmt.Errorf("unable to compile regexp: %w", err)
+	}
+	r.CompiledRegex = compiledRegex
 
-	CompiledRegex *regexp.Regexp
+	r.compiledJSONPaths = nil
+	for _, path := range r.JSONPaths {
+		compiledPath, err := compileJSONPath(path)
+		if err != nil {
+			return err
+		}
+		r.compiledJSONPaths = append(r.compiledJSONPaths, compiledPath)
+	}
+
+	return nil
+}
+
+func (r *Rule) searchesIn(locations ...string) bool {
+	for _, where := range r.SearchIn {
+		for _, location := range locations {
+			if where == location {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// Match is the content of the annotation raised when a rule matches a location of a trace.
+type Match struct {
+	RuleID      string `json:"ruleID"`
+	Description string `json:"description,omitempty"`
+	Severity    string `json:"severity"`
+	// Location is the header, the parameter or the JSON path that matched, empty for a whole body.
+	Location string `json:"location,omitempty"`
 }
 
 type Sensitive struct {
-	Rules []Rule `yaml:"rules"`
+	// rules holds the []Rule, so that they can be swapped while traces are analyzed.
+	rules atomic.Value
 }
 
 func isValidSearchIn(searchIn []string) bool {
 	for _, s := range searchIn {
-		if s != SearchInRequestHeaders &&
-			s != SearchInResponseHeaders &&
-			s != SearchInRequestBody &&
-			s != SearchInResponseBody {
+		if _, ok := searchInAnnotations[s]; !ok {
 			return false
 		}
 	}
 
 	return true
 }
-
 func loadRules(filename string) (rules []Rule, err error) {
 	f, err := os.Open(filename)
 	if err != nil {
@@ -80,20 +191,17 @@ func loadRules(filename string) (rules []Rule, err error) {
 	}
 
 	// Check validity and compile all regexs
-	for i, r := range rules {
-		if !isValidSearchIn(r.SearchIn) {
-			return nil, fmt.Errorf("in rule file '%s', the searchIn Location (%v) is not valid", filename, r.SearchIn)
-		}
-		rules[i].CompiledRegex, err = regexp.Compile(r.Regex)
-		if err != nil {
-			return nil, fmt.Errorf("in rule file '%s', unable to compile regexp: %w", filename, err)
+	for i := range rules {
+		if err := rules[i].Compile(); err != nil {
+			return nil, fmt.Errorf("in rule file '%s', rule '%s' is not valid: %w", filename, rules[i].ID, err)
 		}
 	}
 
 	return rules, nil
 }
 
-func NewSensitive(rulesFilenames []string) (*Sensitive, error) {
+// loadRulesFiles loads and compiles the rules of the files.
+func loadRulesFiles(rulesFilenames []string) ([]Rule, error) {
 	allRules := []Rule{}
 	for _, filename := range rulesFilenames {
 		rules, err := loadRules(filename)
@@ -103,73 +211,217 @@ func NewSensitive(rulesFilenames []string) (*Sensitive, error) {
 		allRules = append(allRules, rules...)
 	}
 
-	return &Sensitive{
-		Rules: allRules,
-	}, nil
+	return allRules, nil
+}
+
+func NewSensitive(rulesFilenames []string) (*Sensitive, error) {
+	rules, err := loadRulesFiles(rulesFilenames)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Sensitive{}
+	s.SetRules(rules)
+
+	return s, nil
+}
+
+// Rules returns the rules currently applied.
+func (w *Sensitive) Rules() []Rule {
+	rules, _ := w.rules.Load().([]Rule)
+	return rules
+}
+
+// SetRules atomically replaces the compiled rules, the traces being analyzed keep the previous rules.
+func (w *Sensitive) SetRules(rules []Rule) {
+	w.rules.Store(rules)
+}
+
+// SearchesIn returns true if one of the rules searches in the location.
+func (w *Sensitive) SearchesIn(location string) bool {
+	for _, rule := range w.Rules() {
+		if rule.searchesIn(location) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// traceData holds the parsed parts of a trace, shared by the rules.
+type traceData struct {
+	trace       *models.Telemetry
+	pathParams  map[string]string
+	queryParams url.Values
+
+	parsedBodies map[string]interface{}
+	jsonBodies   map[string]bool
+}
+
+func newTraceData(pathParams map[string]string, trace *models.Telemetry) *traceData {
+	d := &traceData{
+		trace:        trace,
+		pathParams:   pathParams,
+		queryParams:  url.Values{},
+		parsedBodies: map[string]interface{}{},
+		jsonBodies:   map[string]bool{},
+	}
+	if u, err := url.Parse(trace.Request.Path); err == nil {
+		d.queryParams = u.Query()
+	}
+
+	return d
+}
+
+func (d *traceData) body(where string) []byte {
+	if where == SearchInRequestBody {
+		return d.trace.Request.Common.Body
+	}
+	return d.trace.Response.Common.Body
+}
+
+func (d *traceData) jsonBody(where string) (interface{}, bool) {
+	if _, parsed := d.jsonBodies[where]; !parsed {
+		d.parsedBodies[where], d.jsonBodies[where] = parseJSONBody(d.body(where))
+	}
+
+	return d.parsedBodies[where], d.jsonBodies[where]
+}
+
+func containsFold(names []string, name string) bool {
+	for _, n := range names {
+		if strings.EqualFold(n, name) {
+			return true
+		}
+	}
+
+	return false
 }
 
-func (w *Sensitive) applyRuleHeaders(headers []*models.Header, rule Rule) bool {
+// matchHeaders returns the name of the first matching header. The header names match as well, unless the rule
+// targets specific headers.
+func matchHeaders(headers []*models.Header, rule Rule) (string, bool) {
 	for _, h := range headers {
+		if len(rule.Headers) > 0 {
+			if containsFold(rule.Headers, h.Key) && rule.CompiledRegex.MatchString(h.Value) {
+				return h.Key, true
+			}
+			continue
+		}
 		for _, value := range []string{h.Key, h.Value} {
 			if rule.CompiledRegex.MatchString(value) {
-				return true
+				return h.Key, true
 			}
 		}
 	}
 
-	return false
+	return "", false
 }
 
-func (w *Sensitive) applyRule(trace *models.Telemetry, rule Rule) []core.Annotation {
+// matchParams returns the name of the first matching parameter, in the order of the names. The parameter names
+// match as well, unless the rule targets specific parameters.
+func matchParams(params map[string][]string, rule Rule) (string, bool) {
+	names := make([]string, 0, len(params))
+	for name := range params {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if len(rule.Params) > 0 && !containsFold(rule.Params, name) {
+			continue
+		}
+		if len(rule.Params) == 0 && rule.CompiledRegex.MatchString(name) {
+			return name, true
+		}
+		for _, value := range params[name] {
+			if rule.CompiledRegex.MatchString(value) {
+				return name, true
+			}
+		}
+	}
+
+	return "", false
+}
+
+// matchBody returns the first matching JSON path, or matches the whole body if the rule doesn't target JSON paths.
+func matchBody(d *traceData, where string, rule Rule) (string, bool) {
+	if len(rule.compiledJSONPaths) == 0 {
+		return "", rule.CompiledRegex.Match(d.body(where))
+	}
+
+	doc, ok := d.jsonBody(where)
+	if !ok {
+		return "", false
+	}
+	for i, path := range rule.compiledJSONPaths {
+		for _, value := range path.values(doc) {
+			if s, ok := jsonValueString(value); ok && rule.CompiledRegex.MatchString(s) {
+				return rule.JSONPaths[i], true
+			}
+		}
+	}
+
+	return "", false
+}
+
+func (w *Sensitive) applyRule(d *traceData, rule Rule) []core.Annotation {
 	anns := []core.Annotation{}
 
 	for _, where := range rule.SearchIn {
+		var location string
+		var matched bool
 		switch where {
-		case SearchInRequestBody:
-			if rule.CompiledRegex.Match(trace.Request.Common.Body) {
-				anns = append(anns, core.Annotation{
-					Name:       RexgexpMatchingRequestBody,
-					Annotation: []byte(rule.ID + ":" + rule.Description),
-				})
-			}
-		case SearchInResponseBody:
-			if rule.CompiledRegex.Match(trace.Response.Common.Body) {
-				anns = append(anns, core.Annotation{
-					Name:       RexgexpMatchingResponseBody,
-					Annotation: []byte(rule.ID + ":" + rule.Description),
-				})
-			}
+		case SearchInRequestBody, SearchInResponseBody:
+			location, matched = matchBody(d, where, rule)
 		case SearchInRequestHeaders:
-			if w.applyRuleHeaders(trace.Request.Common.Headers, rule) {
-				anns = append(anns, core.Annotation{
-					Name:       RegexpMatchingRequestHeaders,
-					Annotation: []byte(rule.ID + ":" + rule.Description),
-				})
-			}
+			location, matched = matchHeaders(d.trace.Request.Common.Headers, rule)
 		case SearchInResponseHeaders:
-			if w.applyRuleHeaders(trace.Response.Common.Headers, rule) {
-				anns = append(anns, core.Annotation{
-					Name:       RexgexpMatchingResponseHeaders,
-					Annotation: []byte(rule.ID + ":" + rule.Description),
-				})
+			location, matched = matchHeaders(d.trace.Response.Common.Headers, rule)
+		case SearchInRequestQueryParams:
+			location, matched = matchParams(d.queryParams, rule)
+		case SearchInRequestPathParams:
+			params := make(map[string][]string, len(d.pathParams))
+			for name, value := range d.pathParams {
+				params[name] = []string{value}
 			}
+			location, matched = matchParams(params, rule)
+		}
+		if !matched {
+			continue
+		}
+
+		match, err := json.Marshal(Match{
+			RuleID:      rule.ID,
+			Description: rule.Description,
+			Severity:    rule.Severity,
+			Location:    location,
+		})
+		if err != nil {
+			continue
 		}
+		anns = append(anns, core.Annotation{
+			Name:       searchInAnnotations[where],
+			Annotation: match,
+		})
 	}
 
 	return anns
 }
 
-func (w *Sensitive) analyzeSensitive(trace *models.Telemetry) (anns []core.Annotation) {
-	for _, rule := range w.Rules {
-		obs := w.applyRule(trace, rule)
+func (w *Sensitive) analyzeSensitive(pathParams map[string]string, trace *models.Telemetry) (anns []core.Annotation) {
+	d := newTraceData(pathParams, trace)
+	for _, rule := range w.Rules() {
+		obs := w.applyRule(d, rule)
 		anns = append(anns, obs...)
 	}
 
 	return anns
 }
 
-func (w *Sensitive) Analyze(trace *models.Telemetry) (eventAnns []core.Annotation, apiAnns []core.Annotation) {
-	eventAnns = append(eventAnns, w.analyzeSensitive(trace)...)
+// Analyze applies the rules to the trace. The path parameters are the ones of the spec path of the event, if known.
+func (w *Sensitive) Analyze(pathParams map[string]string, trace *models.Telemetry) (eventAnns []core.Annotation, apiAnns []core.Annotation) {
+	eventAnns = append(eventAnns, w.analyzeSensitive(pathParams, trace)...)
 
 	return eventAnns, apiAnns
 }
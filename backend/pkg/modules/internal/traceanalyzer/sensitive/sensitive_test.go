@@ -0,0 +1,154 @@
+// Copyright © 2022 Cisco Systems, Inc. and its affiliates.
+// All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sensitive
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/openclarity/apiclarity/plugins/api/server/models"
+)
+
+func TestRule_Compile(t *testing.T) {
+	tests := []struct {
+		name    string
+		rule    Rule
+		wantErr bool
+	}{
+		{name: "valid", rule: Rule{ID: "r", Regex: "a", SearchIn: []string{SearchInRequestBody}}},
+		{name: "json paths", rule: Rule{ID: "r", Regex: "a", SearchIn: []string{SearchInRequestBody}, JSONPaths: []string{"$.a[0].b", "$.c[*]", "$.*"}}},
+		{name: "no id", rule: Rule{Regex: "a", SearchIn: []string{SearchInRequestBody}}, wantErr: true},
+		{name: "bad searchIn", rule: Rule{ID: "r", Regex: "a", SearchIn: []string{"Body"}}, wantErr: true},
+		{name: "bad severity", rule: Rule{ID: "r", Regex: "a", SearchIn: []string{SearchInRequestBody}, Severity: "URGENT"}, wantErr: true},
+		{name: "bad json path", rule: Rule{ID: "r", Regex: "a", SearchIn: []string{SearchInRequestBody}, JSONPaths: []string{"a.b"}}, wantErr: true},
+		{name: "bad json index", rule: Rule{ID: "r", Regex: "a", SearchIn: []string{SearchInRequestBody}, JSONPaths: []string{"$.a[x]"}}, wantErr: true},
+		{name: "json paths without body", rule: Rule{ID: "r", Regex: "a", SearchIn: []string{SearchInRequestHeaders}, JSONPaths: []string{"$.a"}}, wantErr: true},
+		{name: "headers without headers", rule: Rule{ID: "r", Regex: "a", SearchIn: []string{SearchInRequestBody}, Headers: []string{"X-Key"}}, wantErr: true},
+		{name: "params without params", rule: Rule{ID: "r", Regex: "a", SearchIn: []string{SearchInRequestBody}, Params: []string{"id"}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.rule.Compile()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Compile() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && tt.rule.Severity != DefaultSeverity {
+				t.Errorf("Compile() severity = %s, want %s", tt.rule.Severity, DefaultSeverity)
+			}
+		})
+	}
+}
+
+func TestSensitive_Analyze(t *testing.T) {
+	trace := &models.Telemetry{
+		Request: &models.Request{
+			Path: "/users/42/cards?q=free+text&pan=4111111111111111",
+			Common: &models.Common{
+				Headers: []*models.Header{{Key: "X-Api-Key", Value: "secret"}, {Key: "X-Note", Value: "4111111111111111"}},
+				Body:    []byte(`{"payment":{"pan":"4111111111111111"},"comments":[{"text":"my card 4111111111111111"}]}`),
+			},
+		},
+		Response: &models.Response{Common: &models.Common{Body: []byte(`not json 4111111111111111`)}},
+	}
+	pathParams := map[string]string{"userId": "42"}
+	card := `4[0-9]{15}`
+
+	tests := []struct {
+		name         string
+		rule         Rule
+		wantName     string
+		wantLocation string
+	}{
+		{
+			name:         "json path",
+			rule:         Rule{ID: "r", Regex: card, SearchIn: []string{SearchInRequestBody}, JSONPaths: []string{"$.payment.pan"}, Severity: "CRITICAL"},
+			wantName:     RexgexpMatchingRequestBody,
+			wantLocation: "$.payment.pan",
+		},
+		{
+			name:         "json path in arrays",
+			rule:         Rule{ID: "r", Regex: card, SearchIn: []string{SearchInRequestBody}, JSONPaths: []string{"$.comments.text"}, Severity: "INFO"},
+			wantName:     RexgexpMatchingRequestBody,
+			wantLocation: "$.comments.text",
+		},
+		{
+			name: "json path not matching",
+			rule: Rule{ID: "r", Regex: card, SearchIn: []string{SearchInRequestBody}, JSONPaths: []string{"$.payment.cvv", "$.comments[1].text"}},
+		},
+		{
+			name: "json path in a non json body",
+			rule: Rule{ID: "r", Regex: card, SearchIn: []string{SearchInResponseBody}, JSONPaths: []string{"$"}},
+		},
+		{
+			name:     "whole body",
+			rule:     Rule{ID: "r", Regex: card, SearchIn: []string{SearchInResponseBody}},
+			wantName: RexgexpMatchingResponseBody,
+		},
+		{
+			name:         "specific header",
+			rule:         Rule{ID: "r", Regex: card, SearchIn: []string{SearchInRequestHeaders}, Headers: []string{"x-note"}},
+			wantName:     RegexpMatchingRequestHeaders,
+			wantLocation: "X-Note",
+		},
+		{
+			name: "other header",
+			rule: Rule{ID: "r", Regex: card, SearchIn: []string{SearchInRequestHeaders}, Headers: []string{"X-Api-Key"}},
+		},
+		{
+			name:         "query param",
+			rule:         Rule{ID: "r", Regex: card, SearchIn: []string{SearchInRequestQueryParams}},
+			wantName:     RegexpMatchingRequestQueryParams,
+			wantLocation: "pan",
+		},
+		{
+			name: "other query param",
+			rule: Rule{ID: "r", Regex: card, SearchIn: []string{SearchInRequestQueryParams}, Params: []string{"q"}},
+		},
+		{
+			name:         "path param",
+			rule:         Rule{ID: "r", Regex: `^[0-9]+$`, SearchIn: []string{SearchInRequestPathParams}, Params: []string{"userId"}},
+			wantName:     RegexpMatchingRequestPathParams,
+			wantLocation: "userId",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.rule.Compile(); err != nil {
+				t.Fatalf("Compile() error = %v", err)
+			}
+			s := &Sensitive{}
+			s.SetRules([]Rule{tt.rule})
+
+			anns, _ := s.Analyze(pathParams, trace)
+			if tt.wantName == "" {
+				if len(anns) != 0 {
+					t.Errorf("Analyze() = %v, want no annotation", anns)
+				}
+				return
+			}
+			if len(anns) != 1 || anns[0].Name != tt.wantName {
+				t.Fatalf("Analyze() = %v, want %s", anns, tt.wantName)
+			}
+			var match Match
+			if err := json.Unmarshal(anns[0].Annotation, &match); err != nil {
+				t.Fatalf("invalid annotation: %v", err)
+			}
+			if match.RuleID != tt.rule.ID || match.Severity != tt.rule.Severity || match.Location != tt.wantLocation {
+				t.Errorf("Analyze() match = %+v, want location %s", match, tt.wantLocation)
+			}
+		})
+	}
+}
@@ -0,0 +1,286 @@
+// Copyright © 2022 Cisco Systems, Inc. and its affiliates.
+// All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package traceanalyzer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/openclarity/apiclarity/backend/pkg/modules/internal/core"
+	"github.com/openclarity/apiclarity/backend/pkg/modules/internal/traceanalyzer/sensitive"
+)
+
+// The rules created with the API are stored as module annotations named with the prefix and the rule id.
+const sensitiveRuleAnnotationPrefix = "sensitive_rule:"
+
+var (
+	errSensitiveRuleInvalid  = errors.New("invalid sensitive rule")
+	errSensitiveRuleNotFound = errors.New("sensitive rule not found")
+	errSensitiveRuleExists   = errors.New("a sensitive rule with the same id already exists")
+	errSensitiveRuleReadOnly = errors.New("the sensitive rule is defined in the rules files")
+)
+
+// sensitiveRules manages the rules of the sensitive analyzer: the read only rules of the rules files,
+// and the rules created with the API, which are persisted in the database.
+// Every change is compiled and swapped into the analyzer, without a restart.
+type sensitiveRules struct {
+	lock      sync.Mutex
+	accessor  core.BackendAccessor
+	analyzer  *sensitive.Sensitive
+	fileRules []sensitive.Rule
+	apiRules  []sensitive.Rule
+}
+
+// newSensitiveRules adds the persisted API rules to the rules files ones, already loaded by the analyzer.
+func newSensitiveRules(ctx context.Context, accessor core.BackendAccessor, analyzer *sensitive.Sensitive) *sensitiveRules {
+	s := &sensitiveRules{
+		accessor:  accessor,
+		analyzer:  analyzer,
+		fileRules: analyzer.Rules(),
+	}
+
+	anns, err := accessor.ListModuleAnnotations(ctx, moduleName)
+	if err != nil {
+		log.Errorf("[TraceAnalyzer] unable to load the sensitive rules from the database, only the rules files are used: %v", err)
+		return s
+	}
+	for _, ann := range anns {
+		if !strings.HasPrefix(ann.Name, sensitiveRuleAnnotationPrefix) {
+			continue
+		}
+		rule := sensitive.Rule{}
+		if err := json.Unmarshal(ann.Annotation, &rule); err != nil {
+			log.Warnf("[TraceAnalyzer] ignoring the invalid stored sensitive rule %s: %v", ann.Name, err)
+			continue
+		}
+		if err := rule.Compile(); err != nil {
+			log.Warnf("[TraceAnalyzer] ignoring the invalid stored sensitive rule %s: %v", rule.ID, err)
+			continue
+		}
+		if _, _, found := s.find(rule.ID); found {
+			log.Warnf("[TraceAnalyzer] ignoring the stored sensitive rule %s, a rule with the same id is already defined", rule.ID)
+			continue
+		}
+		s.apiRules = append(s.apiRules, rule)
+	}
+	s.apply()
+
+	return s
+}
+
+// find returns the rule with the id, and its source.
+func (s *sensitiveRules) find(id string) (sensitive.Rule, SensitiveRuleSource, bool) {
+	for _, rule := range s.fileRules {
+		if rule.ID == id {
+			return rule, SensitiveRuleSourceFILE, true
+		}
+	}
+	for _, rule := range s.apiRules {
+		if rule.ID == id {
+			return rule, SensitiveRuleSourceAPI, true
+		}
+	}
+	return sensitive.Rule{}, "", false
+}
+
+// apply swaps the rules of the analyzer.
+func (s *sensitiveRules) apply() {
+	rules := make([]sensitive.Rule, 0, len(s.fileRules)+len(s.apiRules))
+	rules = append(rules, s.fileRules...)
+	rules = append(rules, s.apiRules...)
+	s.analyzer.SetRules(rules)
+}
+
+func (s *sensitiveRules) store(ctx context.Context, rule sensitive.Rule) error {
+	ruleBytes, err := json.Marshal(rule)
+	if err != nil {
+		return fmt.Errorf("failed to marshal the sensitive rule: %w", err)
+	}
+	return s.accessor.StoreModuleAnnotations(ctx, moduleName, core.Annotation{
+		Name:       sensitiveRuleAnnotationPrefix + rule.ID,
+		Annotation: ruleBytes,
+	})
+}
+
+func (s *sensitiveRules) List() []SensitiveRule {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	rules := []SensitiveRule{}
+	for _, rule := range s.fileRules {
+		rules = append(rules, toRestSensitiveRule(rule, SensitiveRuleSourceFILE))
+	}
+	for _, rule := range s.apiRules {
+		rules = append(rules, toRestSensitiveRule(rule, SensitiveRuleSourceAPI))
+	}
+	return rules
+}
+
+func (s *sensitiveRules) Get(id string) (SensitiveRule, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	rule, source, found := s.find(id)
+	if !found {
+		return SensitiveRule{}, errSensitiveRuleNotFound
+	}
+	return toRestSensitiveRule(rule, source), nil
+}
+
+func (s *sensitiveRules) Create(ctx context.Context, restRule SensitiveRule) (SensitiveRule, error) {
+	rule := fromRestSensitiveRule(restRule)
+	if err := rule.Compile(); err != nil {
+		return SensitiveRule{}, fmt.Errorf("%w: %v", errSensitiveRuleInvalid, err)
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if _, _, found := s.find(rule.ID); found {
+		return SensitiveRule{}, errSensitiveRuleExists
+	}
+	if err := s.store(ctx, rule); err != nil {
+		return SensitiveRule{}, err
+	}
+	s.apiRules = append(s.apiRules, rule)
+	s.apply()
+
+	return toRestSensitiveRule(rule, SensitiveRuleSourceAPI), nil
+}
+
+func (s *sensitiveRules) Update(ctx context.Context, id string, restRule SensitiveRule) (SensitiveRule, error) {
+	if restRule.Id != "" && restRule.Id != id {
+		return SensitiveRule{}, fmt.Errorf("%w: the rule id can't be changed", errSensitiveRuleInvalid)
+	}
+	restRule.Id = id
+	rule := fromRestSensitiveRule(restRule)
+	if err := rule.Compile(); err != nil {
+		return SensitiveRule{}, fmt.Errorf("%w: %v", errSensitiveRuleInvalid, err)
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	_, source, found := s.find(id)
+	if !found {
+		return SensitiveRule{}, errSensitiveRuleNotFound
+	}
+	if source == SensitiveRuleSourceFILE {
+		return SensitiveRule{}, errSensitiveRuleReadOnly
+	}
+	if err := s.store(ctx, rule); err != nil {
+		return SensitiveRule{}, err
+	}
+	apiRules := make([]sensitive.Rule, 0, len(s.apiRules))
+	for _, r := range s.apiRules {
+		if r.ID == id {
+			r = rule
+		}
+		apiRules = append(apiRules, r)
+	}
+	s.apiRules = apiRules
+	s.apply()
+
+	return toRestSensitiveRule(rule, SensitiveRuleSourceAPI), nil
+}
+
+func (s *sensitiveRules) Delete(ctx context.Context, id string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	_, source, found := s.find(id)
+	if !found {
+		return errSensitiveRuleNotFound
+	}
+	if source == SensitiveRuleSourceFILE {
+		return errSensitiveRuleReadOnly
+	}
+	if err := s.accessor.DeleteModuleAnnotations(ctx, moduleName, sensitiveRuleAnnotationPrefix+id); err != nil {
+		return err
+	}
+	apiRules := make([]sensitive.Rule, 0, len(s.apiRules))
+	for _, r := range s.apiRules {
+		if r.ID != id {
+			apiRules = append(apiRules, r)
+		}
+	}
+	s.apiRules = apiRules
+	s.apply()
+
+	return nil
+}
+
+func toRestSensitiveRule(rule sensitive.Rule, source SensitiveRuleSource) SensitiveRule {
+	searchIn := make([]SensitiveRuleSearchIn, 0, len(rule.SearchIn))
+	for _, s := range rule.SearchIn {
+		searchIn = append(searchIn, SensitiveRuleSearchIn(s))
+	}
+	description := rule.Description
+	severity := SensitiveRuleSeverity(rule.Severity)
+	restRule := SensitiveRule{
+		Id:          rule.ID,
+		Description: &description,
+		Regex:       rule.Regex,
+		SearchIn:    searchIn,
+		Severity:    &severity,
+		Source:      &source,
+	}
+	if len(rule.Headers) > 0 {
+		headers := append([]string{}, rule.Headers...)
+		restRule.Headers = &headers
+	}
+	if len(rule.JSONPaths) > 0 {
+		jsonPaths := append([]string{}, rule.JSONPaths...)
+		restRule.JsonPaths = &jsonPaths
+	}
+	if len(rule.Params) > 0 {
+		params := append([]string{}, rule.Params...)
+		restRule.Params = &params
+	}
+	return restRule
+}
+
+func fromRestSensitiveRule(restRule SensitiveRule) sensitive.Rule {
+	rule := sensitive.Rule{
+		ID:    restRule.Id,
+		Regex: restRule.Regex,
+	}
+	if restRule.Description != nil {
+		rule.Description = *restRule.Description
+	}
+	if restRule.Severity != nil {
+		rule.Severity = string(*restRule.Severity)
+	}
+	if restRule.Headers != nil {
+		rule.Headers = *restRule.Headers
+	}
+	if restRule.JsonPaths != nil {
+		rule.JSONPaths = *restRule.JsonPaths
+	}
+	if restRule.Params != nil {
+		rule.Params = *restRule.Params
+	}
+	for _, s := range restRule.SearchIn {
+		rule.SearchIn = append(rule.SearchIn, string(s))
+	}
+	return rule
+}
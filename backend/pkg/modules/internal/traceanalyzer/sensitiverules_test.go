@@ -0,0 +1,133 @@
+// Copyright © 2022 Cisco Systems, Inc. and its affiliates.
+// All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package traceanalyzer
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+
+	"github.com/openclarity/apiclarity/backend/pkg/modules/internal/core"
+	"github.com/openclarity/apiclarity/backend/pkg/modules/internal/traceanalyzer/sensitive"
+	pluginsmodels "github.com/openclarity/apiclarity/plugins/api/server/models"
+)
+
+func sensitiveRulesIDs(analyzer *sensitive.Sensitive) []string {
+	ids := []string{}
+	for _, rule := range analyzer.Rules() {
+		ids = append(ids, rule.ID)
+	}
+	return ids
+}
+
+func TestSensitiveRules(t *testing.T) {
+	rulesFile := filepath.Join(t.TempDir(), "rules.yaml")
+	if err := os.WriteFile(rulesFile, []byte(`- id: file-001
+  description: password keyword
+  regex: '(?i)password'
+  searchIn:
+    - RequestBody
+`), 0o600); err != nil {
+		t.Fatalf("failed to write rules file: %v", err)
+	}
+	analyzer, err := sensitive.NewSensitive([]string{rulesFile})
+	if err != nil {
+		t.Fatalf("NewSensitive() error = %v", err)
+	}
+
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	accessor := core.NewMockBackendAccessor(mockCtrl)
+	ctx := context.Background()
+
+	accessor.EXPECT().ListModuleAnnotations(gomock.Any(), moduleName).Return([]*core.Annotation{
+		{Name: sensitiveRuleAnnotationPrefix + "api-001", Annotation: []byte(`{"id":"api-001","regex":"(?i)token","searchIn":["RequestHeaders"]}`)},
+		{Name: sensitiveRuleAnnotationPrefix + "api-002", Annotation: []byte(`{"id":"api-002","regex":"(","searchIn":["RequestHeaders"]}`)},
+		{Name: "other", Annotation: []byte(`{}`)},
+	}, nil)
+	rules := newSensitiveRules(ctx, accessor, analyzer)
+	if got := sensitiveRulesIDs(analyzer); len(got) != 2 || got[0] != "file-001" || got[1] != "api-001" {
+		t.Fatalf("loaded rules = %v", got)
+	}
+	if rule, err := rules.Get("api-001"); err != nil || *rule.Source != SensitiveRuleSourceAPI || *rule.Severity != sensitive.DefaultSeverity {
+		t.Errorf("Get() = %+v, %v", rule, err)
+	}
+
+	// create
+	severity := SensitiveRuleSeverityHIGH
+	newRule := SensitiveRule{Id: "api-003", Regex: "(?i)ssn", SearchIn: []SensitiveRuleSearchIn{SensitiveRuleSearchInResponseBody}, Severity: &severity}
+	accessor.EXPECT().StoreModuleAnnotations(gomock.Any(), moduleName, gomock.Any()).Return(nil)
+	if _, err := rules.Create(ctx, newRule); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	trace := &pluginsmodels.Telemetry{
+		Request:  &pluginsmodels.Request{Common: &pluginsmodels.Common{}},
+		Response: &pluginsmodels.Response{Common: &pluginsmodels.Common{Body: []byte(`{"SSN":"123"}`)}},
+	}
+	if anns, _ := analyzer.Analyze(nil, trace); len(anns) != 1 || anns[0].Name != sensitive.RexgexpMatchingResponseBody {
+		t.Errorf("Analyze() after create = %v", anns)
+	}
+	for _, tt := range []struct {
+		rule SensitiveRule
+		want error
+	}{
+		{rule: newRule, want: errSensitiveRuleExists},
+		{rule: SensitiveRule{Id: "file-001", Regex: "a", SearchIn: newRule.SearchIn}, want: errSensitiveRuleExists},
+		{rule: SensitiveRule{Id: "api-004", Regex: "(", SearchIn: newRule.SearchIn}, want: errSensitiveRuleInvalid},
+		{rule: SensitiveRule{Id: "api-004", Regex: "a", SearchIn: []SensitiveRuleSearchIn{"Body"}}, want: errSensitiveRuleInvalid},
+		{rule: SensitiveRule{Regex: "a", SearchIn: newRule.SearchIn}, want: errSensitiveRuleInvalid},
+	} {
+		if _, err := rules.Create(ctx, tt.rule); !errors.Is(err, tt.want) {
+			t.Errorf("Create(%+v) error = %v, want %v", tt.rule, err, tt.want)
+		}
+	}
+
+	// update
+	if _, err := rules.Update(ctx, "file-001", newRule); !errors.Is(err, errSensitiveRuleInvalid) {
+		t.Errorf("Update() with another id error = %v", err)
+	}
+	newRule.Id = ""
+	if _, err := rules.Update(ctx, "file-001", newRule); !errors.Is(err, errSensitiveRuleReadOnly) {
+		t.Errorf("Update() of a file rule error = %v", err)
+	}
+	if _, err := rules.Update(ctx, "unknown", newRule); !errors.Is(err, errSensitiveRuleNotFound) {
+		t.Errorf("Update() of an unknown rule error = %v", err)
+	}
+	newRule.Regex = "(?i)passport"
+	accessor.EXPECT().StoreModuleAnnotations(gomock.Any(), moduleName, gomock.Any()).Return(nil)
+	if updated, err := rules.Update(ctx, "api-003", newRule); err != nil || updated.Id != "api-003" {
+		t.Fatalf("Update() = %+v, %v", updated, err)
+	}
+	if anns, _ := analyzer.Analyze(nil, trace); len(anns) != 0 {
+		t.Errorf("Analyze() after update = %v", anns)
+	}
+
+	// delete
+	if err := rules.Delete(ctx, "file-001"); !errors.Is(err, errSensitiveRuleReadOnly) {
+		t.Errorf("Delete() of a file rule error = %v", err)
+	}
+	accessor.EXPECT().DeleteModuleAnnotations(gomock.Any(), moduleName, sensitiveRuleAnnotationPrefix+"api-003").Return(nil)
+	if err := rules.Delete(ctx, "api-003"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if got := sensitiveRulesIDs(analyzer); len(got) != 2 || len(rules.List()) != 2 {
+		t.Errorf("rules after delete = %v", got)
+	}
+}
@@ -0,0 +1,165 @@
+// Copyright © 2022 Cisco Systems, Inc. and its affiliates.
+// All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package traceanalyzer
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/ghodss/yaml"
+	"github.com/go-openapi/spec"
+
+	"github.com/openclarity/apiclarity/api/server/models"
+	"github.com/openclarity/apiclarity/backend/pkg/modules/internal/traceanalyzer/utils"
+)
+
+// maxSchemaDepth bounds the walk of the body schemas.
+const maxSchemaDepth = 10
+
+// The formats of the string parameters which don't hold identifiers.
+var nonIDFormats = map[string]bool{"binary": true, "byte": true, "date": true, "date-time": true, "password": true}
+
+type specKey struct {
+	apiID         uint
+	reconstructed bool
+}
+
+type parsedSpec struct {
+	hash    [sha256.Size]byte
+	swagger *spec.Swagger
+}
+
+// specs keeps the last parsed spec of each API, so that a spec is parsed again only when it changed.
+type specs struct {
+	lock   sync.Mutex
+	parsed map[specKey]*parsedSpec
+}
+
+func (s *specs) get(key specKey, rawSpec string) (*spec.Swagger, error) {
+	hash := sha256.Sum256([]byte(rawSpec))
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if p, ok := s.parsed[key]; ok && p.hash == hash {
+		return p.swagger, nil
+	}
+
+	jsonSpec, err := yaml.YAMLToJSON([]byte(rawSpec))
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert spec to json: %v", err)
+	}
+	swagger := &spec.Swagger{}
+	if err := json.Unmarshal(jsonSpec, swagger); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal spec: %v", err)
+	}
+	if s.parsed == nil {
+		s.parsed = map[specKey]*parsedSpec{}
+	}
+	s.parsed[key] = &parsedSpec{hash: hash, swagger: swagger}
+
+	return swagger, nil
+}
+
+// getDeclaredParams returns the parameters of the operation of the spec which may hold identifiers: the integer
+// and string parameters, without the enums and the formats of other values (e.g. dates).
+func getDeclaredParams(swagger *spec.Swagger, specPath string, method models.HTTPMethod) *utils.DeclaredParams {
+	if swagger == nil || swagger.Paths == nil {
+		return nil
+	}
+	pathItem, ok := swagger.Paths.Paths[specPath]
+	if !ok {
+		return nil
+	}
+	op := getOperation(&pathItem, method)
+	if op == nil {
+		return nil
+	}
+
+	declared := &utils.DeclaredParams{Query: map[string]bool{}, Header: map[string]bool{}, Body: map[string]bool{}}
+	params := append([]spec.Parameter{}, pathItem.Parameters...)
+	for _, param := range append(params, op.Parameters...) {
+		switch param.In {
+		case utils.ParamInQuery:
+			if isIDType(param.Type, param.Format, param.Enum) {
+				declared.Query[param.Name] = true
+			}
+		case utils.ParamInHeader:
+			if isIDType(param.Type, param.Format, param.Enum) {
+				declared.Header[strings.ToLower(param.Name)] = true
+			}
+		case utils.ParamInBody:
+			addSchemaParams(declared.Body, param.Schema, "", 0)
+		}
+	}
+
+	return declared
+}
+
+func getOperation(pathItem *spec.PathItem, method models.HTTPMethod) *spec.Operation {
+	switch method {
+	case models.HTTPMethodGET:
+		return pathItem.Get
+	case models.HTTPMethodPUT:
+		return pathItem.Put
+	case models.HTTPMethodPOST:
+		return pathItem.Post
+	case models.HTTPMethodDELETE:
+		return pathItem.Delete
+	case models.HTTPMethodOPTIONS:
+		return pathItem.Options
+	case models.HTTPMethodHEAD:
+		return pathItem.Head
+	case models.HTTPMethodPATCH:
+		return pathItem.Patch
+	}
+	return nil
+}
+
+func isIDType(typ string, format string, enum []interface{}) bool {
+	return (typ == "integer" || typ == "string") && !nonIDFormats[format] && len(enum) == 0
+}
+
+// addSchemaParams adds the fields of the schema, named by their path in the body as utils.GetBodyParams does,
+// without the array indexes.
+func addSchemaParams(params map[string]bool, schema *spec.Schema, name string, depth int) {
+	if schema == nil || depth > maxSchemaDepth {
+		return
+	}
+
+	switch {
+	case schema.Type.Contains("object") || len(schema.Properties) > 0:
+		for k := range schema.Properties {
+			property := schema.Properties[k]
+			if name == "" {
+				addSchemaParams(params, &property, k, depth+1)
+			} else {
+				addSchemaParams(params, &property, name+"."+k, depth+1)
+			}
+		}
+	case schema.Type.Contains("array"):
+		if schema.Items != nil {
+			addSchemaParams(params, schema.Items.Schema, name, depth+1)
+		}
+	case len(schema.Type) == 1:
+		if isIDType(schema.Type[0], schema.Format, schema.Enum) {
+			params[name] = true
+		}
+	}
+}
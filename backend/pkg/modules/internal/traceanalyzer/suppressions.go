@@ -0,0 +1,358 @@
+// Copyright © 2022 Cisco Systems, Inc. and its affiliates.
+// All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package traceanalyzer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/openclarity/apiclarity/backend/pkg/modules/internal/core"
+	"github.com/openclarity/apiclarity/backend/pkg/modules/internal/traceanalyzer/sensitive"
+)
+
+// The suppressions are stored as module annotations named with the prefix and the suppression id.
+const suppressionAnnotationPrefix = "suppression:"
+
+var (
+	errSuppressionInvalid  = errors.New("invalid suppression")
+	errSuppressionNotFound = errors.New("suppression not found")
+)
+
+var validSuppressionMethods = map[string]bool{
+	"GET":     true,
+	"HEAD":    true,
+	"POST":    true,
+	"PUT":     true,
+	"DELETE":  true,
+	"CONNECT": true,
+	"OPTIONS": true,
+	"TRACE":   true,
+	"PATCH":   true,
+}
+
+// findingScope is what a finding is about, it is matched against the scope of the suppressions.
+type findingScope struct {
+	apiID  int64
+	kind   string
+	method string
+	// the path of the event and the spec path of the finding, when known
+	paths     []string
+	parameter string
+}
+
+// newFindingScope returns the scope of the annotation of the API, the method and the paths of the event are
+// given for the event annotations.
+func newFindingScope(apiID uint, a core.Annotation, method string, paths ...string) findingScope {
+	scope := findingScope{
+		apiID:  int64(apiID),
+		kind:   a.Name,
+		method: method,
+		paths:  paths,
+	}
+
+	if sensitive.IsMatchAnnotation(a.Name) {
+		var match sensitive.Match
+		if err := json.Unmarshal(a.Annotation, &match); err == nil {
+			scope.parameter = match.Location
+		}
+		return scope
+	}
+
+	var f ParameterFinding
+	if err := json.Unmarshal(a.Annotation, &f); err == nil {
+		if f.Method != "" {
+			scope.method = f.Method
+		}
+		if f.Location != "" {
+			scope.paths = append(scope.paths, f.Location)
+		}
+		scope.parameter = f.Name
+	}
+
+	return scope
+}
+
+// suppressions mutes kinds of findings of an API, optionally only for an endpoint or a parameter. The muted
+// findings are still stored, they are hidden from the annotations endpoints and don't raise alerts.
+// The revoked and the expired suppressions are kept for audit.
+type suppressions struct {
+	lock     sync.RWMutex
+	accessor core.BackendAccessor
+	lastID   int64
+	items    map[int64]*Suppression
+	now      func() time.Time
+}
+
+func newSuppressions(ctx context.Context, accessor core.BackendAccessor) *suppressions {
+	s := &suppressions{
+		accessor: accessor,
+		items:    map[int64]*Suppression{},
+		now:      time.Now,
+	}
+
+	anns, err := accessor.ListModuleAnnotations(ctx, moduleName)
+	if err != nil {
+		log.Errorf("[TraceAnalyzer] unable to load the suppressions from the database, no finding is suppressed: %v", err)
+		return s
+	}
+	for _, ann := range anns {
+		if !strings.HasPrefix(ann.Name, suppressionAnnotationPrefix) {
+			continue
+		}
+		sup := Suppression{}
+		if err := json.Unmarshal(ann.Annotation, &sup); err != nil || sup.Id <= 0 {
+			log.Warnf("[TraceAnalyzer] ignoring the invalid stored suppression %s: %v", ann.Name, err)
+			continue
+		}
+		s.items[sup.Id] = &sup
+		if sup.Id > s.lastID {
+			s.lastID = sup.Id
+		}
+	}
+
+	return s
+}
+
+func (s *suppressions) isActive(sup *Suppression) bool {
+	return sup.RevokedAt == nil && (sup.ExpiresAt == nil || s.now().Before(*sup.ExpiresAt))
+}
+
+// view returns a copy of the suppression, with its current state.
+func (s *suppressions) view(sup *Suppression) Suppression {
+	v := *sup
+	v.Active = s.isActive(sup)
+	return v
+}
+
+func (s *suppressions) store(ctx context.Context, sup *Suppression) error {
+	supBytes, err := json.Marshal(sup)
+	if err != nil {
+		return fmt.Errorf("failed to marshal the suppression: %w", err)
+	}
+	return s.accessor.StoreModuleAnnotations(ctx, moduleName, core.Annotation{
+		Name:       suppressionAnnotationPrefix + strconv.FormatInt(sup.Id, 10),
+		Annotation: supBytes,
+	})
+}
+
+// List returns the suppressions, of an API if set, sorted by id.
+func (s *suppressions) List(apiID *int64, includeInactive bool) []Suppression {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	result := []Suppression{}
+	for _, sup := range s.items {
+		if apiID != nil && sup.ApiID != *apiID {
+			continue
+		}
+		if !includeInactive && !s.isActive(sup) {
+			continue
+		}
+		result = append(result, s.view(sup))
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Id < result[j].Id })
+
+	return result
+}
+
+func (s *suppressions) Get(id int64) (Suppression, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	sup, ok := s.items[id]
+	if !ok {
+		return Suppression{}, errSuppressionNotFound
+	}
+	return s.view(sup), nil
+}
+
+func (s *suppressions) Create(ctx context.Context, req SuppressionRequest) (Suppression, error) {
+	if err := s.validate(&req); err != nil {
+		return Suppression{}, err
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	sup := &Suppression{
+		Id:        s.lastID + 1,
+		ApiID:     req.ApiID,
+		Kind:      req.Kind,
+		Method:    req.Method,
+		Path:      req.Path,
+		Parameter: req.Parameter,
+		Reason:    req.Reason,
+		Author:    req.Author,
+		CreatedAt: s.now().UTC(),
+		ExpiresAt: req.ExpiresAt,
+	}
+	if err := s.store(ctx, sup); err != nil {
+		return Suppression{}, err
+	}
+	s.lastID = sup.Id
+	s.items[sup.Id] = sup
+	s.accessor.FindingsChanged(uint(sup.ApiID))
+	log.Infof("[TraceAnalyzer] %s suppressed the %s findings of API %d (suppression %d): %s", sup.Author, sup.Kind, sup.ApiID, sup.Id, sup.Reason)
+
+	return s.view(sup), nil
+}
+
+func (s *suppressions) validate(req *SuppressionRequest) error {
+	if req.ApiID <= 0 {
+		return fmt.Errorf("%w: the apiID is required", errSuppressionInvalid)
+	}
+	if req.Kind == "" || isLearningStateAnnotation(req.Kind) {
+		return fmt.Errorf("%w: the kind of finding is required", errSuppressionInvalid)
+	}
+	if strings.TrimSpace(req.Reason) == "" {
+		return fmt.Errorf("%w: the reason is required", errSuppressionInvalid)
+	}
+	if strings.TrimSpace(req.Author) == "" {
+		return fmt.Errorf("%w: the author is required", errSuppressionInvalid)
+	}
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(s.now()) {
+		return fmt.Errorf("%w: the expiration date is in the past", errSuppressionInvalid)
+	}
+	// an empty scope field doesn't restrict the suppression
+	for _, field := range []**string{&req.Method, &req.Path, &req.Parameter} {
+		if *field != nil && **field == "" {
+			*field = nil
+		}
+	}
+	if req.Method != nil {
+		method := strings.ToUpper(*req.Method)
+		if !validSuppressionMethods[method] {
+			return fmt.Errorf("%w: the method '%s' is not valid", errSuppressionInvalid, *req.Method)
+		}
+		req.Method = &method
+	}
+
+	return nil
+}
+
+// Revoke ends the suppression, the suppression is kept for audit.
+func (s *suppressions) Revoke(ctx context.Context, id int64, revokedBy string) (Suppression, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	sup, ok := s.items[id]
+	if !ok {
+		return Suppression{}, errSuppressionNotFound
+	}
+	if sup.RevokedAt != nil {
+		return s.view(sup), nil
+	}
+
+	revoked := *sup
+	revokedAt := s.now().UTC()
+	revoked.RevokedAt = &revokedAt
+	if revokedBy != "" {
+		revoked.RevokedBy = &revokedBy
+	}
+	if err := s.store(ctx, &revoked); err != nil {
+		return Suppression{}, err
+	}
+	s.items[id] = &revoked
+	s.accessor.FindingsChanged(uint(sup.ApiID))
+	log.Infof("[TraceAnalyzer] %s revoked the suppression %d of the %s findings of API %d", revokedBy, id, sup.Kind, sup.ApiID)
+
+	return s.view(&revoked), nil
+}
+
+// Find returns the id of an active suppression of the finding.
+func (s *suppressions) Find(scope findingScope) (int64, bool) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	var found int64
+	for id, sup := range s.items {
+		if s.matches(sup, scope) && (found == 0 || id < found) {
+			found = id
+		}
+	}
+
+	return found, found != 0
+}
+
+// Empty returns true if no suppression is active, the findings don't need to be matched.
+func (s *suppressions) Empty() bool {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	for _, sup := range s.items {
+		if s.isActive(sup) {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *suppressions) matches(sup *Suppression, scope findingScope) bool {
+	if !s.isActive(sup) || sup.ApiID != scope.apiID || sup.Kind != scope.kind {
+		return false
+	}
+	if sup.Method != nil && !strings.EqualFold(*sup.Method, scope.method) {
+		return false
+	}
+	if sup.Path != nil && !matchesSuppressionPath(*sup.Path, scope.paths) {
+		return false
+	}
+	if sup.Parameter != nil && *sup.Parameter != scope.parameter {
+		return false
+	}
+	return true
+}
+
+// matchesSuppressionPath returns true if one of the paths is the path of the suppression. The path of the
+// suppression can be a spec path, its parameters match any segment (e.g. /users/{id} matches /users/12).
+func matchesSuppressionPath(path string, paths []string) bool {
+	pathSegs := strings.Split(path, "/")
+	for _, p := range paths {
+		if i := strings.IndexByte(p, '?'); i >= 0 {
+			p = p[:i]
+		}
+		if p == "" {
+			continue
+		}
+		if p == path {
+			return true
+		}
+		segs := strings.Split(p, "/")
+		if len(segs) != len(pathSegs) {
+			continue
+		}
+		matched := true
+		for i, seg := range pathSegs {
+			if seg != segs[i] && !(strings.HasPrefix(seg, "{") && strings.HasSuffix(seg, "}") && segs[i] != "") {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,174 @@
+// Copyright © 2022 Cisco Systems, Inc. and its affiliates.
+// All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package traceanalyzer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+
+	"github.com/openclarity/apiclarity/backend/pkg/database"
+	"github.com/openclarity/apiclarity/backend/pkg/modules/internal/core"
+	"github.com/openclarity/apiclarity/backend/pkg/modules/internal/traceanalyzer/sensitive"
+)
+
+func strPtr(s string) *string {
+	return &s
+}
+
+func TestSuppressions(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	accessor := core.NewMockBackendAccessor(mockCtrl)
+	ctx := context.Background()
+
+	now := time.Date(2022, 6, 1, 12, 0, 0, 0, time.UTC)
+	expired := now.Add(-time.Hour)
+	accessor.EXPECT().ListModuleAnnotations(gomock.Any(), moduleName).Return([]*core.Annotation{
+		{Name: suppressionAnnotationPrefix + "3", Annotation: []byte(`{"id":3,"apiID":1,"kind":"NLID","reason":"r","author":"a","expiresAt":"` + expired.Format(time.RFC3339) + `"}`)},
+		{Name: suppressionAnnotationPrefix + "4", Annotation: []byte(`{`)},
+		{Name: sensitiveRuleAnnotationPrefix + "api-001", Annotation: []byte(`{"id":"api-001"}`)},
+	}, nil)
+	sups := newSuppressions(ctx, accessor)
+	sups.now = func() time.Time { return now }
+	if got := sups.List(nil, true); len(got) != 1 || got[0].Active {
+		t.Fatalf("loaded suppressions = %+v", got)
+	}
+	if !sups.Empty() {
+		t.Errorf("Empty() = false with only an expired suppression")
+	}
+
+	// create
+	for _, req := range []SuppressionRequest{
+		{Kind: "NLID", Reason: "r", Author: "a"},
+		{ApiID: 1, Reason: "r", Author: "a"},
+		{ApiID: 1, Kind: "NLID", Author: "a"},
+		{ApiID: 1, Kind: "NLID", Reason: "r"},
+		{ApiID: 1, Kind: "NLID", Reason: "r", Author: "a", ExpiresAt: &expired},
+		{ApiID: 1, Kind: "NLID", Reason: "r", Author: "a", Method: strPtr("FETCH")},
+	} {
+		if _, err := sups.Create(ctx, req); !errors.Is(err, errSuppressionInvalid) {
+			t.Errorf("Create(%+v) error = %v", req, err)
+		}
+	}
+	accessor.EXPECT().StoreModuleAnnotations(gomock.Any(), moduleName, gomock.Any()).Return(nil).Times(2)
+	// the risk of the API is updated
+	accessor.EXPECT().FindingsChanged(uint(1)).Times(2)
+	guessable, err := sups.Create(ctx, SuppressionRequest{
+		ApiID: 1, Kind: "GUESSABLE_ID", Method: strPtr("get"), Path: strPtr("/users/{id}"), Parameter: strPtr("id"),
+		Reason: "ids are public", Author: "alice",
+	})
+	if err != nil || guessable.Id != 4 || !guessable.Active || *guessable.Method != "GET" {
+		t.Fatalf("Create() = %+v, %v", guessable, err)
+	}
+	sens, err := sups.Create(ctx, SuppressionRequest{ApiID: 1, Kind: sensitive.RegexpMatchingRequestHeaders, Path: strPtr(""), Reason: "test data", Author: "bob"})
+	if err != nil || sens.Path != nil {
+		t.Fatalf("Create() = %+v, %v", sens, err)
+	}
+
+	// match
+	paramFinding := func(location, method, name string) core.Annotation {
+		b, _ := json.Marshal(ParameterFinding{Location: location, Method: method, Name: name})
+		return core.Annotation{Name: "GUESSABLE_ID", Annotation: b}
+	}
+	tests := []struct {
+		name  string
+		scope findingScope
+		want  int64
+	}{
+		{name: "api finding", scope: newFindingScope(1, paramFinding("/users/{id}", "GET", "id"), ""), want: guessable.Id},
+		{name: "other parameter", scope: newFindingScope(1, paramFinding("/users/{id}", "GET", "name"), "")},
+		{name: "other method", scope: newFindingScope(1, paramFinding("/users/{id}", "POST", "id"), "")},
+		{name: "other path", scope: newFindingScope(1, paramFinding("/groups/{id}", "GET", "id"), "")},
+		{name: "other api", scope: newFindingScope(2, paramFinding("/users/{id}", "GET", "id"), "")},
+		{name: "request path", scope: newFindingScope(1, paramFinding("", "GET", "id"), "", "/users/12?id=1"), want: guessable.Id},
+		{name: "any path", scope: newFindingScope(1, core.Annotation{Name: sensitive.RegexpMatchingRequestHeaders, Annotation: []byte(`{"ruleID":"r","location":"X-Token"}`)}, "POST", "/login"), want: sens.Id},
+		{name: "expired", scope: newFindingScope(1, core.Annotation{Name: "NLID", Annotation: []byte(`{}`)}, "GET", "/users/12")},
+	}
+	for _, tt := range tests {
+		if got, _ := sups.Find(tt.scope); got != tt.want {
+			t.Errorf("%s: Find() = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+
+	// revoke
+	if _, err := sups.Revoke(ctx, 42, "carol"); !errors.Is(err, errSuppressionNotFound) {
+		t.Errorf("Revoke() of an unknown suppression error = %v", err)
+	}
+	accessor.EXPECT().StoreModuleAnnotations(gomock.Any(), moduleName, gomock.Any()).Return(nil)
+	accessor.EXPECT().FindingsChanged(uint(1))
+	revoked, err := sups.Revoke(ctx, sens.Id, "carol")
+	if err != nil || revoked.Active || revoked.RevokedAt == nil || *revoked.RevokedBy != "carol" {
+		t.Fatalf("Revoke() = %+v, %v", revoked, err)
+	}
+	if _, found := sups.Find(tests[6].scope); found {
+		t.Errorf("Find() matches a revoked suppression")
+	}
+	apiID := int64(1)
+	if got := sups.List(&apiID, false); len(got) != 1 || got[0].Id != guessable.Id {
+		t.Errorf("List(active) = %+v", got)
+	}
+	if got := sups.List(&apiID, true); len(got) != 3 {
+		t.Errorf("List(all) = %+v", got)
+	}
+}
+
+func TestSuppressedFindings(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	accessor := core.NewMockBackendAccessor(mockCtrl)
+	ctx := context.Background()
+	ta := newTestTraceAnalyzer(t, accessor)
+	h := httpHandler{ta: ta}
+
+	accessor.EXPECT().StoreModuleAnnotations(gomock.Any(), moduleName, gomock.Any()).Return(nil)
+	accessor.EXPECT().FindingsChanged(uint(1))
+	sup, err := ta.suppressions.Create(ctx, SuppressionRequest{ApiID: 1, Kind: "JWT_WEAK_SYMETRIC_SECRET", Path: strPtr("/login"), Reason: "test environment", Author: "alice"})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	// the suppressed findings are stored, but they don't raise the alert
+	event := &database.APIEvent{ID: 7, APIInfoID: 1, Method: "POST", Path: "/login"}
+	eventAnns := []core.Annotation{{Name: "JWT_WEAK_SYMETRIC_SECRET", Annotation: []byte("secret")}, {Name: "JWT_NO_EXPIRE_CLAIM"}}
+	accessor.EXPECT().CreateAPIEventAnnotations(gomock.Any(), moduleName, uint(7), eventAnns[0], eventAnns[1]).Return(nil)
+	ta.storeAnnotations(ctx, event, eventAnns, nil)
+
+	accessor.EXPECT().ListAPIEventAnnotations(gomock.Any(), moduleName, uint(7)).Return([]*core.Annotation{&eventAnns[0], &eventAnns[1]}, nil).Times(2)
+	accessor.EXPECT().GetAPIEvents(gomock.Any(), gomock.Any()).Return([]*database.APIEvent{event}, nil).Times(2)
+
+	for _, includeSuppressed := range []bool{false, true} {
+		w := httptest.NewRecorder()
+		include := includeSuppressed
+		h.GetEventAnnotations(w, httptest.NewRequest(http.MethodGet, "/", nil), 7, GetEventAnnotationsParams{IncludeSuppressed: &include})
+		var anns Annotations
+		if err := json.NewDecoder(w.Body).Decode(&anns); err != nil {
+			t.Fatalf("failed to decode the annotations: %v", err)
+		}
+		switch {
+		case !includeSuppressed && (anns.Total != 1 || (*anns.Items)[0].Kind != "JWT_NO_EXPIRE_CLAIM"):
+			t.Errorf("GetEventAnnotations() = %+v", anns)
+		case includeSuppressed && (anns.Total != 2 || (*anns.Items)[0].SuppressionID == nil || *(*anns.Items)[0].SuppressionID != sup.Id):
+			t.Errorf("GetEventAnnotations(includeSuppressed) = %+v", anns)
+		}
+	}
+}
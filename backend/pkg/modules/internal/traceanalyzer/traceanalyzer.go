@@ -18,10 +18,12 @@ package traceanalyzer
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"path/filepath"
 	"strings"
+	"time"
 
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/viper"
@@ -29,13 +31,19 @@ import (
 	"github.com/openclarity/apiclarity/api/server/models"
 	"github.com/openclarity/apiclarity/backend/pkg/config"
 	"github.com/openclarity/apiclarity/backend/pkg/database"
+	"github.com/openclarity/apiclarity/backend/pkg/modules/internal/bfla/bfladetector"
+	"github.com/openclarity/apiclarity/backend/pkg/modules/internal/bfla/recovery"
 	"github.com/openclarity/apiclarity/backend/pkg/modules/internal/core"
-	"github.com/openclarity/apiclarity/backend/pkg/modules/internal/traceanalyzer/guessableid"
+	"github.com/openclarity/apiclarity/backend/pkg/modules/internal/traceanalyzer/bruteforce"
+	"github.com/openclarity/apiclarity/backend/pkg/modules/internal/traceanalyzer/enumeration"
 	"github.com/openclarity/apiclarity/backend/pkg/modules/internal/traceanalyzer/nlid"
+	"github.com/openclarity/apiclarity/backend/pkg/modules/internal/traceanalyzer/securityheaders"
 	"github.com/openclarity/apiclarity/backend/pkg/modules/internal/traceanalyzer/sensitive"
+	"github.com/openclarity/apiclarity/backend/pkg/modules/internal/traceanalyzer/unauthenticated"
 	"github.com/openclarity/apiclarity/backend/pkg/modules/internal/traceanalyzer/utils"
 	"github.com/openclarity/apiclarity/backend/pkg/modules/internal/traceanalyzer/weakbasicauth"
 	"github.com/openclarity/apiclarity/backend/pkg/modules/internal/traceanalyzer/weakjwt"
+	pluginsmodels "github.com/openclarity/apiclarity/plugins/api/server/models"
 )
 
 const (
@@ -54,6 +62,17 @@ const (
 
 	ignoreFindingsEnvVar  = "TRACE_ANALYZER_IGNORE_FINDINGS"
 	ignoreFindingsDefault = ""
+
+	// bruteForceThresholdsEnvVar overrides the thresholds of the brute force analyzer, see bruteforce.ParseThresholds.
+	bruteForceThresholdsEnvVar  = "TRACE_ANALYZER_BRUTE_FORCE_THRESHOLDS"
+	bruteForceThresholdsDefault = ""
+
+	// enumerationThresholdsEnvVar overrides the thresholds of the enumeration analyzer, see enumeration.ParseThresholds.
+	enumerationThresholdsEnvVar  = "TRACE_ANALYZER_ENUMERATION_THRESHOLDS"
+	enumerationThresholdsDefault = ""
+
+	// userIdentityExtractorsEnvVar is shared with the bfla module, see bfladetector.ParseUserIdentityExtractors.
+	userIdentityExtractorsEnvVar = "BFLA_USER_IDENTITY_EXTRACTORS"
 )
 
 // A finding is an interpreted annotation.
@@ -67,6 +86,7 @@ type Finding struct {
 type ParameterFinding struct {
 	Location string      `json:"location"`
 	Method   string      `json:"method"`
+	In       string      `json:"in,omitempty"` // path, query, header or body
 	Name     string      `json:"name"`
 	Value    string      `json:"value"`
 	Reason   interface{} `json:"reason"`
@@ -77,6 +97,12 @@ type traceAnalyzerConfig struct {
 	rulesFilenames             []string `yaml:"rulesFilenames"`
 	sensitiveKeywordsFilenames []string `yaml:"keywordsFilenames"`
 	ignoreFindings             []string `yaml:"ignoreFindings"`
+	bruteForceThresholds       bruteforce.Thresholds
+	enumerationThresholds      enumeration.Thresholds
+	// the users of the requests are detected as in the BFLA module
+	userIdentityExtractors bfladetector.UserIdentityExtractors
+	// the re-scan replays the archived traces
+	traceArchiveEnabled bool
 }
 
 type traceAnalyzer struct {
@@ -86,11 +112,17 @@ type traceAnalyzer struct {
 
 	ignoreFindings map[string]bool
 
-	guessableID   *guessableid.GuessableAnalyzer
-	nlid          *nlid.NLID
-	weakBasicAuth *weakbasicauth.WeakBasicAuth
-	weakJWT       *weakjwt.WeakJWT
-	sensitive     *sensitive.Sensitive
+	learners         *learners
+	weakBasicAuth    *weakbasicauth.WeakBasicAuth
+	weakJWT          *weakjwt.WeakJWT
+	sensitive        *sensitive.Sensitive
+	securityHeaders  *securityheaders.SecurityHeaders
+	endpointFindings *endpointFindings
+
+	sensitiveRules *sensitiveRules
+	suppressions   *suppressions
+	rescanner      *rescanner
+	specs          specs
 
 	accessor core.BackendAccessor
 }
@@ -103,6 +135,7 @@ func newTraceAnalyzer(ctx context.Context, accessor core.BackendAccessor) (core.
 	p.httpHandler = h
 	p.ignoreFindings = map[string]bool{}
 	p.accessor = accessor
+	p.rescanner = newRescanner(ctx, &p)
 
 	p.config = loadConfig()
 	log.Debugf("TraceAnalyzer Configuration: %+v", p.config)
@@ -124,13 +157,17 @@ func newTraceAnalyzer(ctx context.Context, accessor core.BackendAccessor) (core.
 		return nil, fmt.Errorf("unable to read list of sensitive keywords: %w", err)
 	}
 
-	p.guessableID = guessableid.NewGuessableAnalyzer(guessableid.MaxParamHistory)
-	p.nlid = nlid.NewNLID(nlid.NLIDRingBufferSize)
+	p.learners = newLearners(recovery.NewStatePersister(ctx, accessor, moduleName, learningStatePersistenceInterval),
+		p.config.bruteForceThresholds, p.config.enumerationThresholds)
 	p.weakBasicAuth = weakbasicauth.NewWeakBasicAuth(passwordList)
 	p.weakJWT = weakjwt.NewWeakJWT(weakKeyList, sensitiveKeywords)
+	p.securityHeaders = securityheaders.NewSecurityHeaders()
+	p.endpointFindings = newEndpointFindings(accessor)
 	if p.sensitive, err = sensitive.NewSensitive(p.config.rulesFilenames); err != nil {
 		return nil, fmt.Errorf("unable to initialize Trace Analyzer Regexp Rules: %w", err)
 	}
+	p.sensitiveRules = newSensitiveRules(ctx, accessor, p.sensitive)
+	p.suppressions = newSuppressions(ctx, accessor)
 
 	return &p, nil
 }
@@ -152,14 +189,30 @@ func loadConfig() traceAnalyzerConfig {
 	viper.SetDefault(rulesFilenamesEnvVar, rulesFilenamesDefault)
 	viper.SetDefault(sensitiveKeywordsFilenamesEnvVar, sensitiveKeywordsFilenamesDefault)
 	viper.SetDefault(ignoreFindingsEnvVar, ignoreFindingsDefault)
+	viper.SetDefault(bruteForceThresholdsEnvVar, bruteForceThresholdsDefault)
+	viper.SetDefault(enumerationThresholdsEnvVar, enumerationThresholdsDefault)
 
 	dictFilenames := parseFilenamesFromEnv(viper.GetString(dictFilenamesEnvVar))
 	rulesFilenames := parseFilenamesFromEnv(viper.GetString(rulesFilenamesEnvVar))
 	keywordsFilenames := parseFilenamesFromEnv(viper.GetString(sensitiveKeywordsFilenamesEnvVar))
 	ignoreFindings := viper.GetStringSlice(ignoreFindingsEnvVar)
 	modulesAssets := viper.GetString(config.ModulesAssetsEnvVar)
+	bruteForceThresholds, err := bruteforce.ParseThresholds(viper.GetString(bruteForceThresholdsEnvVar))
+	if err != nil {
+		log.Warnf("Invalid Trace Analyzer brute force thresholds, using the default ones: %s", err)
+		bruteForceThresholds = bruteforce.DefaultThresholds()
+	}
+	enumerationThresholds, err := enumeration.ParseThresholds(viper.GetString(enumerationThresholdsEnvVar))
+	if err != nil {
+		log.Warnf("Invalid Trace Analyzer enumeration thresholds, using the default ones: %s", err)
+		enumerationThresholds = enumeration.DefaultThresholds()
+	}
+	userIdentityExtractors, err := bfladetector.ParseUserIdentityExtractors(viper.GetString(userIdentityExtractorsEnvVar))
+	if err != nil {
+		log.Warnf("Invalid Trace Analyzer user identity extractors, using the default ones: %s", err)
+		userIdentityExtractors = bfladetector.DefaultUserIdentityExtractors()
+	}
 
-	var err error
 	if modulesAssets != "" {
 		if len(dictFilenames) == 0 {
 			dictFilenames, err = utils.WalkFiles(filepath.Join(modulesAssets, moduleName, "dictionaries"))
@@ -186,6 +239,10 @@ func loadConfig() traceAnalyzerConfig {
 		rulesFilenames:             rulesFilenames,
 		sensitiveKeywordsFilenames: keywordsFilenames,
 		ignoreFindings:             ignoreFindings,
+		bruteForceThresholds:       bruteForceThresholds,
+		enumerationThresholds:      enumerationThresholds,
+		userIdentityExtractors:     userIdentityExtractors,
+		traceArchiveEnabled:        viper.GetBool(config.TraceArchiveEnabled),
 	}
 	return c
 }
@@ -201,45 +258,109 @@ func (p *traceAnalyzer) HTTPHandler() http.Handler {
 func (p *traceAnalyzer) EventNotify(ctx context.Context, e *core.Event) {
 	event, trace := e.APIEvent, e.Telemetry
 	log.Debugf("[TraceAnalyzer] received a new trace for API(%v) EventID(%v)", event.APIInfoID, event.ID)
-	eventAnns := []core.Annotation{}
-	apiAnns := []core.Annotation{}
 
-	wbaEventAnns, wbaAPIAnns := p.weakBasicAuth.Analyze(trace)
-	eventAnns = append(eventAnns, wbaEventAnns...)
-	apiAnns = append(apiAnns, wbaAPIAnns...)
+	state := &analysisState{learners: p.learners, endpointFindings: p.endpointFindings, weakBasicAuth: p.weakBasicAuth}
+	eventAnns, apiAnns := p.analyze(ctx, state, event, trace, true)
+	p.storeAnnotations(ctx, event, eventAnns, apiAnns)
+}
 
-	wjtEventAnns, wjtAPIAnns := p.weakJWT.Analyze(trace)
-	eventAnns = append(eventAnns, wjtEventAnns...)
-	apiAnns = append(apiAnns, wjtAPIAnns...)
+// analysisState is the state that the analyzers learn from the traces they analyze. A re-scan replays the history
+// with its own state, without altering the live one.
+type analysisState struct {
+	learners *learners
+	// the security headers, brute force and enumeration findings aggregated by endpoint
+	endpointFindings *endpointFindings
+	// the credentials used by the APIs
+	weakBasicAuth *weakbasicauth.WeakBasicAuth
+}
 
-	sensEventAnns, sensAPIAnns := p.sensitive.Analyze(trace)
-	eventAnns = append(eventAnns, sensEventAnns...)
-	apiAnns = append(apiAnns, sensAPIAnns...)
+// analyze runs the analyzers over the trace of the event, with the given learnt state. The credentials analyzers
+// are skipped when the credentials of the trace can't be analyzed, e.g. once they are redacted by the archive.
+func (p *traceAnalyzer) analyze(ctx context.Context, state *analysisState, event *database.APIEvent,
+	trace *pluginsmodels.Telemetry, analyzeCredentials bool,
+) (eventAnns []core.Annotation, apiAnns []core.Annotation) {
+	if analyzeCredentials {
+		wbaEventAnns, wbaAPIAnns := state.weakBasicAuth.Analyze(trace)
+		eventAnns = append(eventAnns, wbaEventAnns...)
+		apiAnns = append(apiAnns, wbaAPIAnns...)
+
+		wjtEventAnns, wjtAPIAnns := p.weakJWT.Analyze(trace)
+		eventAnns = append(eventAnns, wjtEventAnns...)
+		apiAnns = append(apiAnns, wjtAPIAnns...)
+	}
 
 	// If the status code starts with 2, it means that the request has been
 	// accepted, hence, the parameters were accepted as well. So, we can look at
 	// the parameters to see if they are very similar with the one in previous
 	// accepted queries.
-	if strings.HasPrefix(trace.Response.StatusCode, "2") {
-		// Guessable ID, which is part of the module, not the 3rd party library
-		specPath, pathParams, _, _, _ := p.getParams(ctx, event)
+	accepted := strings.HasPrefix(trace.Response.StatusCode, "2")
+	// The API rejected the credentials of the request
+	failedAuth := bruteforce.IsFailure(trace)
+	var specPath string
+	var params utils.Params
+	if accepted || failedAuth || p.sensitive.SearchesIn(sensitive.SearchInRequestPathParams) {
+		specPath, params = p.getParams(ctx, event, trace)
+	}
+
+	if failedAuth {
+		// Check for the attacks on the authentication, the failures are tracked in sliding windows. Without a
+		// spec, the endpoint is the path of the event, with its identifiers as path parameters.
+		location := specPath
+		if location == "" {
+			location, _ = utils.GuessPathParams(event.Path)
+		}
+		bfEventAnns, bfAPIAnns := state.learners.get(event.APIInfoID).bruteForce.Analyze(getEventTime(event), string(event.Method)+" "+location,
+			p.getDetectedUserID(trace), trace)
+		eventAnns = append(eventAnns, bfEventAnns...)
+		apiAnns = append(apiAnns, state.endpointFindings.add(ctx, event.APIInfoID, string(event.Method), location, bfAPIAnns)...)
+	}
+
+	sensEventAnns, sensAPIAnns := p.sensitive.Analyze(params.Path, trace)
+	eventAnns = append(eventAnns, sensEventAnns...)
+	apiAnns = append(apiAnns, sensAPIAnns...)
+
+	if accepted {
+		// Guessable ID, which is part of the module, not the 3rd party library. Without a spec, the endpoint is the
+		// path of the event, with its numeric and UUID segments as path parameters, which are enumerated.
+		pathParams := params.Path
 		if specPath == "" {
-			specPath = trace.Request.Path
+			specPath, pathParams = utils.GuessPathParams(event.Path)
 		}
 
+		// Check the security headers of the response, the findings are API annotations listing the endpoints
+		headersAnns := p.securityHeaders.Analyze(trace)
+		apiAnns = append(apiAnns, state.endpointFindings.add(ctx, event.APIInfoID, string(event.Method), specPath, headersAnns)...)
+
+		apiLearners := state.learners.get(event.APIInfoID)
+		defer apiLearners.learnt()
+
 		// Check for guessable IDs
-		for pName, pValue := range pathParams {
-			if guessable, reason := p.guessableID.IsGuessableParam("", pName, pValue); guessable {
-				f := ParameterFinding{Location: specPath, Method: string(event.Method), Name: pName, Value: pValue, Reason: reason}
-				bytes, err := json.Marshal(f)
-				if err == nil {
-					apiAnns = append(apiAnns, core.Annotation{Name: "GUESSABLE_ID", Annotation: bytes})
+		for _, located := range params.ByLocation() {
+			for pName, pValue := range located.Params {
+				if guessable, reason := apiLearners.guessableID.IsGuessableParam(located.In, pName, pValue); guessable {
+					f := ParameterFinding{Location: specPath, Method: string(event.Method), In: located.In, Name: pName, Value: pValue, Reason: reason}
+					bytes, err := json.Marshal(f)
+					if err == nil {
+						apiAnns = append(apiAnns, core.Annotation{Name: "GUESSABLE_ID", Annotation: bytes})
+					}
 				}
 			}
 		}
 
+		// Check for the enumeration of the path parameters and the scraping of the API by the client, the detected
+		// user or else the source. The guessable ID analyzer tells whether the enumerated identifiers are guessable.
+		enumEventAnns, enumAPIAnns := apiLearners.enumeration.Analyze(getEventTime(event), string(event.Method), specPath,
+			p.getDetectedUserID(trace), pathParams, trace, func(name string) bool {
+				return apiLearners.guessableID.IsGuessable(utils.ParamInPath, name)
+			})
+		eventAnns = append(eventAnns, enumEventAnns...)
+		apiAnns = append(apiAnns, state.endpointFindings.add(ctx, event.APIInfoID, string(event.Method), specPath, enumAPIAnns)...)
+
+		// Check for the operations succeeding without authentication, although they usually require it
+		apiAnns = append(apiAnns, apiLearners.unauthenticated.Analyze(string(event.Method), getPathID(event), specPath, trace)...)
+
 		// Check for NLIDS
-		eventNLIDAnns, _ := p.nlid.Analyze(pathParams, trace)
+		eventNLIDAnns, _ := apiLearners.nlid.Analyze(params, trace)
 		for _, e := range eventNLIDAnns {
 			f := ParameterFinding{Location: specPath, Method: string(event.Method), Name: "", Value: string(e.Annotation), Reason: nlid.Reason{}}
 			bytes, err := json.Marshal(f)
@@ -249,6 +370,12 @@ func (p *traceAnalyzer) EventNotify(ctx context.Context, e *core.Event) {
 		}
 	}
 
+	return eventAnns, apiAnns
+}
+
+// storeAnnotations stores the annotations that are not ignored, and the alert of the event. The suppressed
+// findings are stored, for audit, but they don't raise the alert.
+func (p *traceAnalyzer) storeAnnotations(ctx context.Context, event *database.APIEvent, eventAnns []core.Annotation, apiAnns []core.Annotation) {
 	filteredEventAnns := []core.Annotation{}
 	for _, a := range eventAnns {
 		if !p.ignoreFindings[a.Name] {
@@ -273,7 +400,16 @@ func (p *traceAnalyzer) EventNotify(ctx context.Context, e *core.Event) {
 		}
 	}
 
-	p.setAlertSeverity(ctx, event.ID, filteredEventAnns)
+	alertAnns := filteredEventAnns
+	if !p.suppressions.Empty() {
+		alertAnns = []core.Annotation{}
+		for _, a := range filteredEventAnns {
+			if _, suppressed := p.suppressions.Find(newFindingScope(event.APIInfoID, a, string(event.Method), event.Path)); !suppressed {
+				alertAnns = append(alertAnns, a)
+			}
+		}
+	}
+	p.setAlertSeverity(ctx, event.ID, alertAnns)
 }
 
 func (p *traceAnalyzer) EventAnnotationNotify(modName string, eventID uint, ann core.Annotation) error {
@@ -284,30 +420,47 @@ func (p *traceAnalyzer) APIAnnotationNotify(modName string, apiID uint, annotati
 	return nil
 }
 
+// setAlertSeverity raises the most severe alert of the findings of the event.
+// apiAnnotationsDeleted resets the live learners whose findings are the deleted API annotations, so that they
+// report them again.
+func (p *traceAnalyzer) apiAnnotationsDeleted(apiID uint, names ...string) {
+	for _, name := range names {
+		if name == unauthenticated.KindUnauthenticatedAccess {
+			// the operations accessed without authentication are reported again
+			apiLearners := p.learners.get(apiID)
+			apiLearners.unauthenticated.ResetFindings()
+			apiLearners.learnt()
+		}
+	}
+}
+
 func (p *traceAnalyzer) setAlertSeverity(ctx context.Context, eventID uint, anns []core.Annotation) {
+	var alert *core.Annotation
 	for _, a := range anns {
 		f := getEventDescription(a)
-		if f.Alert != nil {
-			if err := p.accessor.CreateAPIEventAnnotations(ctx, p.Name(), eventID, *f.Alert); err != nil {
-				log.Error(err)
-			} else {
-				break
-			}
+		if f.Alert != nil && (alert == nil || alertLevel(f.Alert) > alertLevel(alert)) {
+			alert = f.Alert
 		}
 	}
+	if alert == nil {
+		return
+	}
+	if err := p.accessor.CreateAPIEventAnnotations(ctx, p.Name(), eventID, *alert); err != nil {
+		log.Error(err)
+	}
 }
 
-func getAPISpecsInfo(ctx context.Context, accessor core.BackendAccessor, apiID uint) (*models.OpenAPISpecs, error) {
+func getAPISpecsInfo(ctx context.Context, accessor core.BackendAccessor, apiID uint) (*database.APIInfo, *models.OpenAPISpecs, error) {
 	apiInfo, err := accessor.GetAPIInfo(ctx, apiID)
 	if err != nil {
-		return nil, fmt.Errorf("unable to get specification API '%d' for %w", apiID, err)
+		return nil, nil, fmt.Errorf("unable to get specification API '%d' for %w", apiID, err)
 	}
 
 	specsInfo := &models.OpenAPISpecs{}
 	if apiInfo.ProvidedSpecInfo != "" {
 		specInfo := models.SpecInfo{}
 		if err := json.Unmarshal([]byte(apiInfo.ProvidedSpecInfo), &specInfo); err != nil {
-			return nil, fmt.Errorf("failed to unmarshal provided spec info. info=%+v: %v", apiInfo.ProvidedSpecInfo, err)
+			return nil, nil, fmt.Errorf("failed to unmarshal provided spec info. info=%+v: %v", apiInfo.ProvidedSpecInfo, err)
 		}
 		specsInfo.ProvidedSpec = &specInfo
 	}
@@ -315,69 +468,127 @@ func getAPISpecsInfo(ctx context.Context, accessor core.BackendAccessor, apiID u
 	if apiInfo.ReconstructedSpecInfo != "" {
 		specInfo := models.SpecInfo{}
 		if err := json.Unmarshal([]byte(apiInfo.ReconstructedSpecInfo), &specInfo); err != nil {
-			return nil, fmt.Errorf("failed to unmarshal reconstructed spec info. info=%+v: %v", apiInfo.ReconstructedSpecInfo, err)
+			return nil, nil, fmt.Errorf("failed to unmarshal reconstructed spec info. info=%+v: %v", apiInfo.ReconstructedSpecInfo, err)
 		}
 		specsInfo.ReconstructedSpec = &specInfo
 	}
 
-	return specsInfo, nil
+	return apiInfo, specsInfo, nil
 }
 
-// XXX There are too many parameters to this function. It needs refactoring.
-func (p *traceAnalyzer) getParams(ctx context.Context, event *database.APIEvent) (specPath string, pathParams map[string]string, queryParams map[string]string, headerParams map[string]string, bodyParams map[string]string) {
-	specInfo, err := getAPISpecsInfo(ctx, p.accessor, event.APIInfoID)
+// getParams returns the path of the spec operation matching the event, if any, and the parameters of the request.
+// With a spec operation, the parameters are the ones it declares.
+func (p *traceAnalyzer) getParams(ctx context.Context, event *database.APIEvent, trace *pluginsmodels.Telemetry) (specPath string, params utils.Params) {
+	apiInfo, specInfo, err := getAPISpecsInfo(ctx, p.accessor, event.APIInfoID)
 	if err != nil {
-		return "", nil, nil, nil, nil
+		log.Debugf("[TraceAnalyzer] unable to get the specs of API %d: %v", event.APIInfoID, err)
+		return "", utils.GetRequestParams(trace, nil)
 	}
 
-	pathParams = make(map[string]string)
-	queryParams = make(map[string]string)
-	headerParams = make(map[string]string)
-	bodyParams = make(map[string]string)
-
-	var spec *models.SpecInfo
 	// Prefer reconstructed spec
-	if specInfo.ReconstructedSpec != nil {
-		spec = specInfo.ReconstructedSpec
-	} else if specInfo.ProvidedSpec != nil {
-		spec = specInfo.ProvidedSpec
-	}
-
-	if spec != nil {
-		for _, t := range specInfo.ProvidedSpec.Tags {
-			for _, path := range t.MethodAndPathList {
-				if path.PathID.String() == event.ProvidedPathID && path.Method == event.Method {
-					specPath = path.Path
-					pathParams = utils.GetPathParams(path.Path, event.Path)
-					// XXX Need to get other parameters
-					break
-				}
+	rawSpec, reconstructed := apiInfo.ReconstructedSpec, true
+	specPath = findSpecPath(specInfo.ReconstructedSpec, event.ReconstructedPathID, event.Method)
+	if specPath == "" {
+		rawSpec, reconstructed = apiInfo.ProvidedSpec, false
+		specPath = findSpecPath(specInfo.ProvidedSpec, event.ProvidedPathID, event.Method)
+	}
+	if specPath == "" {
+		return "", utils.GetRequestParams(trace, nil)
+	}
+
+	var declared *utils.DeclaredParams
+	if rawSpec != "" {
+		swagger, err := p.specs.get(specKey{apiID: event.APIInfoID, reconstructed: reconstructed}, rawSpec)
+		if err != nil {
+			log.Debugf("[TraceAnalyzer] unable to parse the spec of API %d: %v", event.APIInfoID, err)
+		} else {
+			declared = getDeclaredParams(swagger, specPath, event.Method)
+		}
+	}
+	params = utils.GetRequestParams(trace, declared)
+	params.Path = utils.GetPathParams(specPath, event.Path)
+
+	return specPath, params
+}
+
+// getEventTime returns the time of the event, a re-scan replays the failures at their time.
+func getEventTime(event *database.APIEvent) time.Time {
+	if t := time.Time(event.Time); !t.IsZero() {
+		return t
+	}
+	return time.Now()
+}
+
+// getDetectedUserID returns the ID of the user of the request detected as the BFLA module does, with the same
+// extractors, if any.
+func (p *traceAnalyzer) getDetectedUserID(trace *pluginsmodels.Telemetry) string {
+	headers := http.Header{}
+	for _, h := range trace.Request.Common.Headers {
+		headers.Add(h.Key, h.Value)
+	}
+	user, err := p.config.userIdentityExtractors.GetUserID(headers)
+	if err != nil || user == nil {
+		return ""
+	}
+	return user.ID
+}
+
+// getPathID returns the path ID of the operation of the spec matching the event, preferring the reconstructed spec
+// as getParams does.
+func getPathID(event *database.APIEvent) string {
+	if event.ReconstructedPathID != "" {
+		return event.ReconstructedPathID
+	}
+	return event.ProvidedPathID
+}
+
+// findSpecPath returns the path of the operation of the spec, or an empty string if not found.
+func findSpecPath(spec *models.SpecInfo, pathID string, method models.HTTPMethod) string {
+	if spec == nil || pathID == "" {
+		return ""
+	}
+	for _, t := range spec.Tags {
+		for _, path := range t.MethodAndPathList {
+			if path.PathID.String() == pathID && path.Method == method {
+				return path.Path
 			}
 		}
 	}
 
-	return specPath, pathParams, queryParams, headerParams, bodyParams
+	return ""
 }
 
 type httpHandler struct {
 	ta *traceAnalyzer
 }
 
-func (h httpHandler) GetEventAnnotations(w http.ResponseWriter, r *http.Request, eventID int64) {
+func (h httpHandler) GetEventAnnotations(w http.ResponseWriter, r *http.Request, eventID int64, params GetEventAnnotationsParams) {
 	dbAnns, err := h.ta.accessor.ListAPIEventAnnotations(r.Context(), moduleName, uint(eventID))
 	if err != nil {
 		return
 	}
+	includeSuppressed := params.IncludeSuppressed != nil && *params.IncludeSuppressed
+	event := h.getSuppressibleEvent(r.Context(), eventID)
 	annList := []Annotation{}
 
 	for _, a := range dbAnns {
+		var suppressionID *int64
+		if event != nil {
+			if id, suppressed := h.ta.suppressions.Find(newFindingScope(event.APIInfoID, *a, string(event.Method), event.Path)); suppressed {
+				if !includeSuppressed {
+					continue
+				}
+				suppressionID = &id
+			}
+		}
 		f := getEventDescription(*a)
 
 		annList = append(annList, Annotation{
-			Annotation: f.DetailedDesc,
-			Name:       f.ShortDesc,
-			Severity:   f.Severity,
-			Kind:       a.Name,
+			Annotation:    f.DetailedDesc,
+			Name:          f.ShortDesc,
+			Severity:      f.Severity,
+			Kind:          a.Name,
+			SuppressionID: suppressionID,
 		})
 	}
 	result := Annotations{
@@ -392,20 +603,32 @@ func (h httpHandler) GetEventAnnotations(w http.ResponseWriter, r *http.Request,
 	}
 }
 
-func (h httpHandler) GetAPIAnnotations(w http.ResponseWriter, r *http.Request, apiID int64) {
+func (h httpHandler) GetAPIAnnotations(w http.ResponseWriter, r *http.Request, apiID int64, params GetAPIAnnotationsParams) {
 	dbAnns, err := h.ta.accessor.ListAPIInfoAnnotations(r.Context(), moduleName, uint(apiID))
 	if err != nil {
 		return
 	}
+	includeSuppressed := params.IncludeSuppressed != nil && *params.IncludeSuppressed
 	annList := []Annotation{}
 
 	for _, a := range dbAnns {
+		if isLearningStateAnnotation(a.Name) {
+			continue
+		}
+		var suppressionID *int64
+		if id, suppressed := h.ta.suppressions.Find(newFindingScope(uint(apiID), *a, "")); suppressed {
+			if !includeSuppressed {
+				continue
+			}
+			suppressionID = &id
+		}
 		f := getAPIDescription(*a)
 		annList = append(annList, Annotation{
-			Annotation: f.DetailedDesc,
-			Name:       f.ShortDesc,
-			Severity:   f.Severity,
-			Kind:       a.Name,
+			Annotation:    f.DetailedDesc,
+			Name:          f.ShortDesc,
+			Severity:      f.Severity,
+			Kind:          a.Name,
+			SuppressionID: suppressionID,
 		})
 	}
 	result := Annotations{
@@ -420,16 +643,297 @@ func (h httpHandler) GetAPIAnnotations(w http.ResponseWriter, r *http.Request, a
 	}
 }
 
+// getSuppressibleEvent returns the event, to match its findings against the suppressions, or nil if no
+// suppression is active.
+func (h httpHandler) getSuppressibleEvent(ctx context.Context, eventID int64) *database.APIEvent {
+	if h.ta.suppressions.Empty() {
+		return nil
+	}
+	id := uint32(eventID)
+	events, err := h.ta.accessor.GetAPIEvents(ctx, database.GetAPIEventsQuery{EventID: &id, Limit: 1})
+	if err != nil || len(events) == 0 {
+		log.Warnf("[TraceAnalyzer] unable to get the event %d, its findings are not matched against the suppressions: %v", eventID, err)
+		return nil
+	}
+	return events[0]
+}
+
 func (h httpHandler) DeleteAPIAnnotations(w http.ResponseWriter, r *http.Request, apiID int64, params DeleteAPIAnnotationsParams) {
 	err := h.ta.accessor.DeleteAPIInfoAnnotations(r.Context(), moduleName, uint(apiID), params.Name)
 	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
+	// the endpoints of the deleted endpoint findings are found again
+	h.ta.endpointFindings.forget(uint(apiID))
+	h.ta.apiAnnotationsDeleted(uint(apiID), params.Name)
 
 	w.WriteHeader(http.StatusNoContent)
 }
 
+func (h httpHandler) GetAPIAuthentication(w http.ResponseWriter, r *http.Request, apiID int64) {
+	apiLearners, ok := h.ta.learners.lookup(uint(apiID))
+	if !ok {
+		// the learners are created only for the existing APIs, restoring their learnt history
+		if _, err := h.ta.accessor.GetAPIInfo(r.Context(), uint(apiID)); err != nil {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		apiLearners = h.ta.learners.get(uint(apiID))
+	}
+
+	operations := []OperationAuthentication{}
+	for _, op := range apiLearners.unauthenticated.Operations() {
+		operations = append(operations, OperationAuthentication{
+			Method:                  op.Method,
+			Path:                    op.Location,
+			PathID:                  op.PathID,
+			Schemes:                 OperationAuthentication_Schemes{AdditionalProperties: op.Schemes},
+			AuthenticatedRequests:   op.Authenticated,
+			UnauthenticatedRequests: op.Unauthenticated,
+			UnauthenticatedAccess:   op.UnauthenticatedAccess,
+		})
+	}
+	result := OperationsAuthentication{
+		Items: &operations,
+		Total: len(operations),
+	}
+
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(result); err != nil {
+		log.Error(err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func (h httpHandler) GetRescans(w http.ResponseWriter, r *http.Request) {
+	jobs := h.ta.rescanner.List()
+	result := RescanJobs{
+		Items: &jobs,
+		Total: len(jobs),
+	}
+
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(result); err != nil {
+		log.Error(err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func (h httpHandler) StartRescan(w http.ResponseWriter, r *http.Request) {
+	var scope RescanRequest
+	if err := json.NewDecoder(r.Body).Decode(&scope); err != nil {
+		http.Error(w, fmt.Sprintf("invalid re-scan request: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	job, err := h.ta.rescanner.Start(scope)
+	switch {
+	case errors.Is(err, errRescanEmptyScope), errors.Is(err, errRescanNoArchive):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	case errors.Is(err, errRescanRunning):
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	case err != nil:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+	if err := json.NewEncoder(w).Encode(job); err != nil {
+		log.Error(err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func (h httpHandler) GetRescan(w http.ResponseWriter, r *http.Request, jobID int64) {
+	job, ok := h.ta.rescanner.Get(jobID)
+	if !ok {
+		http.Error(w, fmt.Sprintf("re-scan job %d not found", jobID), http.StatusNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(job); err != nil {
+		log.Error(err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func (h httpHandler) CancelRescan(w http.ResponseWriter, r *http.Request, jobID int64) {
+	if !h.ta.rescanner.Cancel(jobID) {
+		http.Error(w, fmt.Sprintf("re-scan job %d not found", jobID), http.StatusNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h httpHandler) GetSensitiveRules(w http.ResponseWriter, r *http.Request) {
+	rules := h.ta.sensitiveRules.List()
+	result := SensitiveRules{
+		Items: &rules,
+		Total: len(rules),
+	}
+
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(result); err != nil {
+		log.Error(err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func (h httpHandler) CreateSensitiveRule(w http.ResponseWriter, r *http.Request) {
+	var rule SensitiveRule
+	if err := json.NewDecoder(r.Body).Decode(&rule); err != nil {
+		http.Error(w, fmt.Sprintf("%v: %v", errSensitiveRuleInvalid, err), http.StatusBadRequest)
+		return
+	}
+
+	rule, err := h.ta.sensitiveRules.Create(r.Context(), rule)
+	if err != nil {
+		httpSensitiveRuleError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(rule); err != nil {
+		log.Error(err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func (h httpHandler) GetSensitiveRule(w http.ResponseWriter, r *http.Request, ruleID string) {
+	rule, err := h.ta.sensitiveRules.Get(ruleID)
+	if err != nil {
+		httpSensitiveRuleError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(rule); err != nil {
+		log.Error(err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func (h httpHandler) UpdateSensitiveRule(w http.ResponseWriter, r *http.Request, ruleID string) {
+	var rule SensitiveRule
+	if err := json.NewDecoder(r.Body).Decode(&rule); err != nil {
+		http.Error(w, fmt.Sprintf("%v: %v", errSensitiveRuleInvalid, err), http.StatusBadRequest)
+		return
+	}
+
+	rule, err := h.ta.sensitiveRules.Update(r.Context(), ruleID, rule)
+	if err != nil {
+		httpSensitiveRuleError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(rule); err != nil {
+		log.Error(err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func (h httpHandler) DeleteSensitiveRule(w http.ResponseWriter, r *http.Request, ruleID string) {
+	if err := h.ta.sensitiveRules.Delete(r.Context(), ruleID); err != nil {
+		httpSensitiveRuleError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h httpHandler) GetSuppressions(w http.ResponseWriter, r *http.Request, params GetSuppressionsParams) {
+	sups := h.ta.suppressions.List(params.ApiID, params.IncludeInactive != nil && *params.IncludeInactive)
+	result := Suppressions{
+		Items: &sups,
+		Total: len(sups),
+	}
+
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(result); err != nil {
+		log.Error(err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func (h httpHandler) CreateSuppression(w http.ResponseWriter, r *http.Request) {
+	var req SuppressionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, fmt.Sprintf("%v: %v", errSuppressionInvalid, err), http.StatusBadRequest)
+		return
+	}
+
+	sup, err := h.ta.suppressions.Create(r.Context(), req)
+	if err != nil {
+		httpSuppressionError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(sup); err != nil {
+		log.Error(err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func (h httpHandler) GetSuppression(w http.ResponseWriter, r *http.Request, suppressionID int64) {
+	sup, err := h.ta.suppressions.Get(suppressionID)
+	if err != nil {
+		httpSuppressionError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(sup); err != nil {
+		log.Error(err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func (h httpHandler) RevokeSuppression(w http.ResponseWriter, r *http.Request, suppressionID int64, params RevokeSuppressionParams) {
+	revokedBy := ""
+	if params.RevokedBy != nil {
+		revokedBy = *params.RevokedBy
+	}
+	if _, err := h.ta.suppressions.Revoke(r.Context(), suppressionID, revokedBy); err != nil {
+		httpSuppressionError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func httpSuppressionError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, errSuppressionInvalid):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, errSuppressionNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	default:
+		log.Error(err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func httpSensitiveRuleError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, errSensitiveRuleInvalid):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, errSensitiveRuleNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, errSensitiveRuleExists), errors.Is(err, errSensitiveRuleReadOnly):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		log.Error(err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
 //nolint:gochecknoinits
 func init() {
 	core.RegisterModule(newTraceAnalyzer)
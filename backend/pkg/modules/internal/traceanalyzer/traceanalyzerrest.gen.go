@@ -7,23 +7,76 @@ import (
 	"bytes"
 	"compress/gzip"
 	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/url"
 	"path"
 	"strings"
+	"time"
 
 	"github.com/deepmap/oapi-codegen/pkg/runtime"
 	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/go-chi/chi/v5"
 )
 
+// Defines values for RescanJobStatus.
+const (
+	RescanJobStatusCANCELED RescanJobStatus = "CANCELED"
+
+	RescanJobStatusCOMPLETED RescanJobStatus = "COMPLETED"
+
+	RescanJobStatusFAILED RescanJobStatus = "FAILED"
+
+	RescanJobStatusRUNNING RescanJobStatus = "RUNNING"
+)
+
+// Defines values for SensitiveRuleSeverity.
+const (
+	SensitiveRuleSeverityCRITICAL SensitiveRuleSeverity = "CRITICAL"
+
+	SensitiveRuleSeverityHIGH SensitiveRuleSeverity = "HIGH"
+
+	SensitiveRuleSeverityINFO SensitiveRuleSeverity = "INFO"
+
+	SensitiveRuleSeverityLOW SensitiveRuleSeverity = "LOW"
+
+	SensitiveRuleSeverityMEDIUM SensitiveRuleSeverity = "MEDIUM"
+
+	SensitiveRuleSeverityWARN SensitiveRuleSeverity = "WARN"
+)
+
+// Defines values for SensitiveRuleSearchIn.
+const (
+	SensitiveRuleSearchInRequestBody SensitiveRuleSearchIn = "RequestBody"
+
+	SensitiveRuleSearchInRequestHeaders SensitiveRuleSearchIn = "RequestHeaders"
+
+	SensitiveRuleSearchInRequestPathParams SensitiveRuleSearchIn = "RequestPathParams"
+
+	SensitiveRuleSearchInRequestQueryParams SensitiveRuleSearchIn = "RequestQueryParams"
+
+	SensitiveRuleSearchInResponseBody SensitiveRuleSearchIn = "ResponseBody"
+
+	SensitiveRuleSearchInResponseHeaders SensitiveRuleSearchIn = "ResponseHeaders"
+)
+
+// Defines values for SensitiveRuleSource.
+const (
+	SensitiveRuleSourceAPI SensitiveRuleSource = "API"
+
+	SensitiveRuleSourceFILE SensitiveRuleSource = "FILE"
+)
+
 // Annotation defines model for Annotation.
 type Annotation struct {
 	Annotation string `json:"annotation"`
 	Kind       string `json:"kind"`
 	Name       string `json:"name"`
 	Severity   string `json:"severity"`
+
+	// The suppression muting the finding, only set when the suppressed findings are included
+	SuppressionID *int64 `json:"suppressionID,omitempty"`
 }
 
 // Annotations defines model for Annotations.
@@ -34,12 +87,272 @@ type Annotations struct {
 	Total int `json:"total"`
 }
 
+// OperationAuthentication defines model for OperationAuthentication.
+type OperationAuthentication struct {
+	AuthenticatedRequests int64  `json:"authenticatedRequests"`
+	Method                string `json:"method"`
+
+	// The path of the operation in the API specification
+	Path   string `json:"path"`
+	PathID string `json:"pathID"`
+
+	// The number of authenticated requests by scheme (e.g. Bearer, Basic, ApiKey or SessionCookie)
+	Schemes OperationAuthentication_Schemes `json:"schemes"`
+
+	// True if a request without authentication succeeded although the operation usually requires it
+	UnauthenticatedAccess   bool  `json:"unauthenticatedAccess"`
+	UnauthenticatedRequests int64 `json:"unauthenticatedRequests"`
+}
+
+// The number of authenticated requests by scheme (e.g. Bearer, Basic, ApiKey or SessionCookie)
+type OperationAuthentication_Schemes struct {
+	AdditionalProperties map[string]int64 `json:"-"`
+}
+
+// OperationsAuthentication defines model for OperationsAuthentication.
+type OperationsAuthentication struct {
+	Items *[]OperationAuthentication `json:"items,omitempty"`
+
+	// Total operations count
+	Total int `json:"total"`
+}
+
+// RescanJob defines model for RescanJob.
+type RescanJob struct {
+	ApiID       *int64     `json:"apiID,omitempty"`
+	CompletedAt *time.Time `json:"completedAt,omitempty"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	EndTime     *time.Time `json:"endTime,omitempty"`
+	Error       *string    `json:"error,omitempty"`
+	Id          int64      `json:"id"`
+
+	// Number of re-scanned events
+	ProcessedEvents int64 `json:"processedEvents"`
+
+	// Number of events skipped because their trace was not archived
+	SkippedEvents int64           `json:"skippedEvents"`
+	StartTime     *time.Time      `json:"startTime,omitempty"`
+	Status        RescanJobStatus `json:"status"`
+
+	// Number of events in the scope of the job
+	TotalEvents int64 `json:"totalEvents"`
+}
+
+// RescanJobStatus defines model for RescanJobStatus.
+type RescanJobStatus string
+
+// RescanJobs defines model for RescanJobs.
+type RescanJobs struct {
+	Items *[]RescanJob `json:"items,omitempty"`
+
+	// Total re-scan jobs count
+	Total int `json:"total"`
+}
+
+// The scope of a re-scan, at least one of the fields must be set. When no time range is set, the API level annotations of the re-scanned APIs are replaced as well.
+type RescanRequest struct {
+	ApiID     *int64     `json:"apiID,omitempty"`
+	EndTime   *time.Time `json:"endTime,omitempty"`
+	StartTime *time.Time `json:"startTime,omitempty"`
+}
+
+// SensitiveRule defines model for SensitiveRule.
+type SensitiveRule struct {
+	Description *string `json:"description,omitempty"`
+
+	// Restricts the headers locations to the values of these headers
+	Headers *[]string `json:"headers,omitempty"`
+	Id      string    `json:"id"`
+
+	// Restricts the body locations to the values selected by these JSON paths (e.g. $.payment.pan)
+	JsonPaths *[]string `json:"jsonPaths,omitempty"`
+
+	// Restricts the query and path parameters locations to the values of these parameters
+	Params *[]string `json:"params,omitempty"`
+
+	// RE2 regular expression
+	Regex    string                  `json:"regex"`
+	SearchIn []SensitiveRuleSearchIn `json:"searchIn"`
+	Severity *SensitiveRuleSeverity  `json:"severity,omitempty"`
+
+	// FILE for the rules of the rules files, API for the rules created with the API
+	Source *SensitiveRuleSource `json:"source,omitempty"`
+}
+
+// SensitiveRuleSeverity defines model for SensitiveRule.Severity.
+type SensitiveRuleSeverity string
+
+// SensitiveRuleSearchIn defines model for SensitiveRuleSearchIn.
+type SensitiveRuleSearchIn string
+
+// FILE for the rules of the rules files, API for the rules created with the API
+type SensitiveRuleSource string
+
+// SensitiveRules defines model for SensitiveRules.
+type SensitiveRules struct {
+	Items *[]SensitiveRule `json:"items,omitempty"`
+
+	// Total sensitive data rules count
+	Total int `json:"total"`
+}
+
+// Suppression defines model for Suppression.
+type Suppression struct {
+	// False once the suppression expired or was revoked
+	Active    bool       `json:"active"`
+	ApiID     int64      `json:"apiID"`
+	Author    string     `json:"author"`
+	CreatedAt time.Time  `json:"createdAt"`
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+	Id        int64      `json:"id"`
+	Kind      string     `json:"kind"`
+	Method    *string    `json:"method,omitempty"`
+	Parameter *string    `json:"parameter,omitempty"`
+	Path      *string    `json:"path,omitempty"`
+	Reason    string     `json:"reason"`
+	RevokedAt *time.Time `json:"revokedAt,omitempty"`
+	RevokedBy *string    `json:"revokedBy,omitempty"`
+}
+
+// SuppressionRequest defines model for SuppressionRequest.
+type SuppressionRequest struct {
+	ApiID  int64  `json:"apiID"`
+	Author string `json:"author"`
+
+	// The suppression never expires if not set
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+
+	// The kind of the finding (e.g. GUESSABLE_ID)
+	Kind string `json:"kind"`
+
+	// Restricts the suppression to the findings of the endpoints with this method
+	Method *string `json:"method,omitempty"`
+
+	// Restricts the suppression to the findings of this parameter
+	Parameter *string `json:"parameter,omitempty"`
+
+	// Restricts the suppression to the findings of this endpoint path, or spec path
+	Path   *string `json:"path,omitempty"`
+	Reason string  `json:"reason"`
+}
+
+// Suppressions defines model for Suppressions.
+type Suppressions struct {
+	Items *[]Suppression `json:"items,omitempty"`
+
+	// Total suppressions count
+	Total int `json:"total"`
+}
+
 // DeleteAPIAnnotationsParams defines parameters for DeleteAPIAnnotations.
 type DeleteAPIAnnotationsParams struct {
 	// name of the annotation
 	Name string `json:"name"`
 }
 
+// GetAPIAnnotationsParams defines parameters for GetAPIAnnotations.
+type GetAPIAnnotationsParams struct {
+	// include the findings muted by a suppression
+	IncludeSuppressed *bool `json:"includeSuppressed,omitempty"`
+}
+
+// GetEventAnnotationsParams defines parameters for GetEventAnnotations.
+type GetEventAnnotationsParams struct {
+	// include the findings muted by a suppression
+	IncludeSuppressed *bool `json:"includeSuppressed,omitempty"`
+}
+
+// StartRescanJSONBody defines parameters for StartRescan.
+type StartRescanJSONBody RescanRequest
+
+// CreateSensitiveRuleJSONBody defines parameters for CreateSensitiveRule.
+type CreateSensitiveRuleJSONBody SensitiveRule
+
+// UpdateSensitiveRuleJSONBody defines parameters for UpdateSensitiveRule.
+type UpdateSensitiveRuleJSONBody SensitiveRule
+
+// GetSuppressionsParams defines parameters for GetSuppressions.
+type GetSuppressionsParams struct {
+	ApiID *int64 `json:"apiID,omitempty"`
+
+	// include the expired and revoked suppressions
+	IncludeInactive *bool `json:"includeInactive,omitempty"`
+}
+
+// CreateSuppressionJSONBody defines parameters for CreateSuppression.
+type CreateSuppressionJSONBody SuppressionRequest
+
+// RevokeSuppressionParams defines parameters for RevokeSuppression.
+type RevokeSuppressionParams struct {
+	// who revokes the suppression
+	RevokedBy *string `json:"revokedBy,omitempty"`
+}
+
+// StartRescanJSONRequestBody defines body for StartRescan for application/json ContentType.
+type StartRescanJSONRequestBody StartRescanJSONBody
+
+// CreateSensitiveRuleJSONRequestBody defines body for CreateSensitiveRule for application/json ContentType.
+type CreateSensitiveRuleJSONRequestBody CreateSensitiveRuleJSONBody
+
+// UpdateSensitiveRuleJSONRequestBody defines body for UpdateSensitiveRule for application/json ContentType.
+type UpdateSensitiveRuleJSONRequestBody UpdateSensitiveRuleJSONBody
+
+// CreateSuppressionJSONRequestBody defines body for CreateSuppression for application/json ContentType.
+type CreateSuppressionJSONRequestBody CreateSuppressionJSONBody
+
+// Getter for additional properties for OperationAuthentication_Schemes. Returns the specified
+// element and whether it was found
+func (a OperationAuthentication_Schemes) Get(fieldName string) (value int64, found bool) {
+	if a.AdditionalProperties != nil {
+		value, found = a.AdditionalProperties[fieldName]
+	}
+	return
+}
+
+// Setter for additional properties for OperationAuthentication_Schemes
+func (a *OperationAuthentication_Schemes) Set(fieldName string, value int64) {
+	if a.AdditionalProperties == nil {
+		a.AdditionalProperties = make(map[string]int64)
+	}
+	a.AdditionalProperties[fieldName] = value
+}
+
+// Override default JSON handling for OperationAuthentication_Schemes to handle AdditionalProperties
+func (a *OperationAuthentication_Schemes) UnmarshalJSON(b []byte) error {
+	object := make(map[string]json.RawMessage)
+	err := json.Unmarshal(b, &object)
+	if err != nil {
+		return err
+	}
+
+	if len(object) != 0 {
+		a.AdditionalProperties = make(map[string]int64)
+		for fieldName, fieldBuf := range object {
+			var fieldVal int64
+			err := json.Unmarshal(fieldBuf, &fieldVal)
+			if err != nil {
+				return fmt.Errorf("error unmarshaling field %s: %w", fieldName, err)
+			}
+			a.AdditionalProperties[fieldName] = fieldVal
+		}
+	}
+	return nil
+}
+
+// Override default JSON handling for OperationAuthentication_Schemes to handle AdditionalProperties
+func (a OperationAuthentication_Schemes) MarshalJSON() ([]byte, error) {
+	var err error
+	object := make(map[string]json.RawMessage)
+
+	for fieldName, field := range a.AdditionalProperties {
+		object[fieldName], err = json.Marshal(field)
+		if err != nil {
+			return nil, fmt.Errorf("error marshaling '%s': %w", fieldName, err)
+		}
+	}
+	return json.Marshal(object)
+}
+
 // ServerInterface represents all server handlers.
 type ServerInterface interface {
 	// Delete Annotations for an API
@@ -47,10 +360,52 @@ type ServerInterface interface {
 	DeleteAPIAnnotations(w http.ResponseWriter, r *http.Request, apiID int64, params DeleteAPIAnnotationsParams)
 	// Get Annotations for an API
 	// (GET /apiAnnotations/{apiID})
-	GetAPIAnnotations(w http.ResponseWriter, r *http.Request, apiID int64)
+	GetAPIAnnotations(w http.ResponseWriter, r *http.Request, apiID int64, params GetAPIAnnotationsParams)
+	// Get the authentication of the operations of an API
+	// (GET /apiAuthentication/{apiID})
+	GetAPIAuthentication(w http.ResponseWriter, r *http.Request, apiID int64)
 	// Get Annotations for an event
 	// (GET /eventAnnotations/{eventID})
-	GetEventAnnotations(w http.ResponseWriter, r *http.Request, eventID int64)
+	GetEventAnnotations(w http.ResponseWriter, r *http.Request, eventID int64, params GetEventAnnotationsParams)
+	// Get the re-scan jobs
+	// (GET /rescans)
+	GetRescans(w http.ResponseWriter, r *http.Request)
+	// Start a re-scan job
+	// (POST /rescans)
+	StartRescan(w http.ResponseWriter, r *http.Request)
+	// Cancel a re-scan job
+	// (DELETE /rescans/{jobID})
+	CancelRescan(w http.ResponseWriter, r *http.Request, jobID int64)
+	// Get a re-scan job
+	// (GET /rescans/{jobID})
+	GetRescan(w http.ResponseWriter, r *http.Request, jobID int64)
+	// Get the sensitive data rules
+	// (GET /sensitiveRules)
+	GetSensitiveRules(w http.ResponseWriter, r *http.Request)
+	// Create a sensitive data rule
+	// (POST /sensitiveRules)
+	CreateSensitiveRule(w http.ResponseWriter, r *http.Request)
+	// Delete a sensitive data rule
+	// (DELETE /sensitiveRules/{ruleID})
+	DeleteSensitiveRule(w http.ResponseWriter, r *http.Request, ruleID string)
+	// Get a sensitive data rule
+	// (GET /sensitiveRules/{ruleID})
+	GetSensitiveRule(w http.ResponseWriter, r *http.Request, ruleID string)
+	// Update a sensitive data rule
+	// (PUT /sensitiveRules/{ruleID})
+	UpdateSensitiveRule(w http.ResponseWriter, r *http.Request, ruleID string)
+	// Get the finding suppressions
+	// (GET /suppressions)
+	GetSuppressions(w http.ResponseWriter, r *http.Request, params GetSuppressionsParams)
+	// Mute a finding
+	// (POST /suppressions)
+	CreateSuppression(w http.ResponseWriter, r *http.Request)
+	// Revoke a finding suppression
+	// (DELETE /suppressions/{suppressionID})
+	RevokeSuppression(w http.ResponseWriter, r *http.Request, suppressionID int64, params RevokeSuppressionParams)
+	// Get a finding suppression
+	// (GET /suppressions/{suppressionID})
+	GetSuppression(w http.ResponseWriter, r *http.Request, suppressionID int64)
 }
 
 // ServerInterfaceWrapper converts contexts to parameters.
@@ -120,8 +475,48 @@ func (siw *ServerInterfaceWrapper) GetAPIAnnotations(w http.ResponseWriter, r *h
 		return
 	}
 
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetAPIAnnotationsParams
+
+	// ------------- Optional query parameter "includeSuppressed" -------------
+	if paramValue := r.URL.Query().Get("includeSuppressed"); paramValue != "" {
+
+	}
+
+	err = runtime.BindQueryParameter("form", true, false, "includeSuppressed", r.URL.Query(), &params.IncludeSuppressed)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "includeSuppressed", Err: err})
+		return
+	}
+
 	var handler = func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.GetAPIAnnotations(w, r, apiID)
+		siw.Handler.GetAPIAnnotations(w, r, apiID, params)
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler(w, r.WithContext(ctx))
+}
+
+// GetAPIAuthentication operation middleware
+func (siw *ServerInterfaceWrapper) GetAPIAuthentication(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "apiID" -------------
+	var apiID int64
+
+	err = runtime.BindStyledParameter("simple", false, "apiID", chi.URLParam(r, "apiID"), &apiID)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "apiID", Err: err})
+		return
+	}
+
+	var handler = func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetAPIAuthentication(w, r, apiID)
 	}
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -146,8 +541,335 @@ func (siw *ServerInterfaceWrapper) GetEventAnnotations(w http.ResponseWriter, r
 		return
 	}
 
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetEventAnnotationsParams
+
+	// ------------- Optional query parameter "includeSuppressed" -------------
+	if paramValue := r.URL.Query().Get("includeSuppressed"); paramValue != "" {
+
+	}
+
+	err = runtime.BindQueryParameter("form", true, false, "includeSuppressed", r.URL.Query(), &params.IncludeSuppressed)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "includeSuppressed", Err: err})
+		return
+	}
+
+	var handler = func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetEventAnnotations(w, r, eventID, params)
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler(w, r.WithContext(ctx))
+}
+
+// GetRescans operation middleware
+func (siw *ServerInterfaceWrapper) GetRescans(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var handler = func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetRescans(w, r)
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler(w, r.WithContext(ctx))
+}
+
+// StartRescan operation middleware
+func (siw *ServerInterfaceWrapper) StartRescan(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var handler = func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.StartRescan(w, r)
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler(w, r.WithContext(ctx))
+}
+
+// CancelRescan operation middleware
+func (siw *ServerInterfaceWrapper) CancelRescan(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "jobID" -------------
+	var jobID int64
+
+	err = runtime.BindStyledParameter("simple", false, "jobID", chi.URLParam(r, "jobID"), &jobID)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "jobID", Err: err})
+		return
+	}
+
 	var handler = func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.GetEventAnnotations(w, r, eventID)
+		siw.Handler.CancelRescan(w, r, jobID)
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler(w, r.WithContext(ctx))
+}
+
+// GetRescan operation middleware
+func (siw *ServerInterfaceWrapper) GetRescan(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "jobID" -------------
+	var jobID int64
+
+	err = runtime.BindStyledParameter("simple", false, "jobID", chi.URLParam(r, "jobID"), &jobID)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "jobID", Err: err})
+		return
+	}
+
+	var handler = func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetRescan(w, r, jobID)
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler(w, r.WithContext(ctx))
+}
+
+// GetSensitiveRules operation middleware
+func (siw *ServerInterfaceWrapper) GetSensitiveRules(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var handler = func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetSensitiveRules(w, r)
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler(w, r.WithContext(ctx))
+}
+
+// CreateSensitiveRule operation middleware
+func (siw *ServerInterfaceWrapper) CreateSensitiveRule(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var handler = func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.CreateSensitiveRule(w, r)
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler(w, r.WithContext(ctx))
+}
+
+// DeleteSensitiveRule operation middleware
+func (siw *ServerInterfaceWrapper) DeleteSensitiveRule(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "ruleID" -------------
+	var ruleID string
+
+	err = runtime.BindStyledParameter("simple", false, "ruleID", chi.URLParam(r, "ruleID"), &ruleID)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "ruleID", Err: err})
+		return
+	}
+
+	var handler = func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.DeleteSensitiveRule(w, r, ruleID)
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler(w, r.WithContext(ctx))
+}
+
+// GetSensitiveRule operation middleware
+func (siw *ServerInterfaceWrapper) GetSensitiveRule(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "ruleID" -------------
+	var ruleID string
+
+	err = runtime.BindStyledParameter("simple", false, "ruleID", chi.URLParam(r, "ruleID"), &ruleID)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "ruleID", Err: err})
+		return
+	}
+
+	var handler = func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetSensitiveRule(w, r, ruleID)
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler(w, r.WithContext(ctx))
+}
+
+// UpdateSensitiveRule operation middleware
+func (siw *ServerInterfaceWrapper) UpdateSensitiveRule(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "ruleID" -------------
+	var ruleID string
+
+	err = runtime.BindStyledParameter("simple", false, "ruleID", chi.URLParam(r, "ruleID"), &ruleID)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "ruleID", Err: err})
+		return
+	}
+
+	var handler = func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.UpdateSensitiveRule(w, r, ruleID)
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler(w, r.WithContext(ctx))
+}
+
+// GetSuppressions operation middleware
+func (siw *ServerInterfaceWrapper) GetSuppressions(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetSuppressionsParams
+
+	// ------------- Optional query parameter "apiID" -------------
+	if paramValue := r.URL.Query().Get("apiID"); paramValue != "" {
+
+	}
+
+	err = runtime.BindQueryParameter("form", true, false, "apiID", r.URL.Query(), &params.ApiID)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "apiID", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "includeInactive" -------------
+	if paramValue := r.URL.Query().Get("includeInactive"); paramValue != "" {
+
+	}
+
+	err = runtime.BindQueryParameter("form", true, false, "includeInactive", r.URL.Query(), &params.IncludeInactive)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "includeInactive", Err: err})
+		return
+	}
+
+	var handler = func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetSuppressions(w, r, params)
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler(w, r.WithContext(ctx))
+}
+
+// CreateSuppression operation middleware
+func (siw *ServerInterfaceWrapper) CreateSuppression(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var handler = func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.CreateSuppression(w, r)
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler(w, r.WithContext(ctx))
+}
+
+// RevokeSuppression operation middleware
+func (siw *ServerInterfaceWrapper) RevokeSuppression(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "suppressionID" -------------
+	var suppressionID int64
+
+	err = runtime.BindStyledParameter("simple", false, "suppressionID", chi.URLParam(r, "suppressionID"), &suppressionID)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "suppressionID", Err: err})
+		return
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params RevokeSuppressionParams
+
+	// ------------- Optional query parameter "revokedBy" -------------
+	if paramValue := r.URL.Query().Get("revokedBy"); paramValue != "" {
+
+	}
+
+	err = runtime.BindQueryParameter("form", true, false, "revokedBy", r.URL.Query(), &params.RevokedBy)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "revokedBy", Err: err})
+		return
+	}
+
+	var handler = func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.RevokeSuppression(w, r, suppressionID, params)
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler(w, r.WithContext(ctx))
+}
+
+// GetSuppression operation middleware
+func (siw *ServerInterfaceWrapper) GetSuppression(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "suppressionID" -------------
+	var suppressionID int64
+
+	err = runtime.BindStyledParameter("simple", false, "suppressionID", chi.URLParam(r, "suppressionID"), &suppressionID)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "suppressionID", Err: err})
+		return
+	}
+
+	var handler = func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetSuppression(w, r, suppressionID)
 	}
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -276,9 +998,51 @@ func HandlerWithOptions(si ServerInterface, options ChiServerOptions) http.Handl
 	r.Group(func(r chi.Router) {
 		r.Get(options.BaseURL+"/apiAnnotations/{apiID}", wrapper.GetAPIAnnotations)
 	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/apiAuthentication/{apiID}", wrapper.GetAPIAuthentication)
+	})
 	r.Group(func(r chi.Router) {
 		r.Get(options.BaseURL+"/eventAnnotations/{eventID}", wrapper.GetEventAnnotations)
 	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/rescans", wrapper.GetRescans)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/rescans", wrapper.StartRescan)
+	})
+	r.Group(func(r chi.Router) {
+		r.Delete(options.BaseURL+"/rescans/{jobID}", wrapper.CancelRescan)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/rescans/{jobID}", wrapper.GetRescan)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/sensitiveRules", wrapper.GetSensitiveRules)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/sensitiveRules", wrapper.CreateSensitiveRule)
+	})
+	r.Group(func(r chi.Router) {
+		r.Delete(options.BaseURL+"/sensitiveRules/{ruleID}", wrapper.DeleteSensitiveRule)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/sensitiveRules/{ruleID}", wrapper.GetSensitiveRule)
+	})
+	r.Group(func(r chi.Router) {
+		r.Put(options.BaseURL+"/sensitiveRules/{ruleID}", wrapper.UpdateSensitiveRule)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/suppressions", wrapper.GetSuppressions)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/suppressions", wrapper.CreateSuppression)
+	})
+	r.Group(func(r chi.Router) {
+		r.Delete(options.BaseURL+"/suppressions/{suppressionID}", wrapper.RevokeSuppression)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/suppressions/{suppressionID}", wrapper.GetSuppression)
+	})
 
 	return r
 }
@@ -286,16 +1050,52 @@ func HandlerWithOptions(si ServerInterface, options ChiServerOptions) http.Handl
 // Base64 encoded, gzipped, json marshaled Swagger object
 var swaggerSpec = []string{
 
-	"H4sIAAAAAAAC/+RUMW/bPBD9K8R93yhYbhN00KY2ReDNQLMFGVj6ZDOVSOZ4MqAa/O8FKcWWLAXI0KJD",
-	"J0s6+r13793xBMo2zho07KE4gVcHbGR6LI2xLFlbE98cWYfEGlNNTmrcOYQCPJM2ewgZ/NBmt1gwssHF",
-	"gscjkuZuoRgyIHxpNeEOikfQOxhwsrGMEcTA/5S9Itnvz6g40lx68vOmNGMzffifsIIC/ssvJuWDQ/nI",
-	"nnBmkkSyS++WZR0hdugVaddbBQ/xs8AjGhYX8V4o2xqGM4w2jHukWe896ryxeE6bys4Jy+3mSy2jLWJb",
-	"t3ttSlFuN5FJc41LBz5DBkck3/9/vVqvPsSGrEMjnYYCblbr1Q1k4CQfkku5dHpkbH6STm/uQi+mRsa5",
-	"rHtk8UBSoSiNrLufSGKEICpLQgrvUOlKq0FxjCrVNzso4C4hl9vNONIoimSDjOSheDyBjmRR6OvMFJDE",
-	"wdhXphazYfKj1MpSI7mP4dPtYirX/URsYSvBBxSToUwCXlqk7qJgGN63BVwvwFM87J01vh/Uj+vbuaXf",
-	"WqXQ+6qtRXI9DWbcrLZpJHVny+ZGm2RwyGCP/Ceiukf+SznNjVvHH2UNo0m9SudqrZKs/Nn319mF4X37",
-	"7/sFvFq80fUwSSH6+VYEIYM8XQ6TdUpfhoX6DREluKWQvl4xvyumQdy/E1RvXwgh/AoAAP//NX1lKzwH",
-	"AAA=",
+	"H4sIAAAAAAAC/+xbW2/bOhL+KwT3PGwB1cnpKRbYvDlp2vpsmmTtFH04KBa0NLbZyKRKUk69gf/7ghdJ",
+	"lERJdnPrwz7FsXgZznxz4TfyPY75OuMMmJL45B7LeAVrYj6OGeOKKMqZ/i8TPAOhKJhnpPZMbTPAJ1gq",
+	"QdkS7yJ8S1kSfMDIGoIPJGxAULUNP8yzTICUlLPJOz0iARkLmtn98c0KkDcErXNF2RKpFaAFZQllywhx",
+	"lm6RBIXuVsCQ8mZAUoySiAhAlMVpnkCCI7zgYk0UPsGUqX+8xVEhGWUKliDwbhdhAd9zKiDBJ39hmmB3",
+	"xMjXkHc6p5qv5VJ8/g1ipQ9ZqVu29U0VrOsffhOwwCf4b0eV/Y6c8Y6qpfCu3IkIQbbmf65IGtCi/hrB",
+	"BphClfASxTxnavjsdtXQwa4yEGatca5WwBSNu0BVPYdkCt9zkBaUg3aI8BrUiocxlxG1CoNGP0F8YeDA",
+	"CykRtfgYX0+QzCCmi0LgKLy2hWTrkTGGO1iSUL0CSa9rB97jXG2hWb6eg9Bi1/SFhFMYmm+R3Rv9HUbL",
+	"EToFIkBE6JRIGkdonNF/wRZxgWbWX844v6XwCgdMl7PaHuM4BikDuhQ5ILpApBAC3VG14rnyRdSqlXkc",
+	"AySQIJLqActVQ/e5zEmabpFDlkTUg96c8xQICwh2EFgawHXIcTgpTVoZMOoAZrcUXYrr9Q455B6HxYAu",
+	"rzs0IJS2eYRIMAUZE/Ynnwd8P6OTd3uZLzIZKwWtVlWbkRAFrxVdQzWrcsdYADlwCrDkhq7hgAlCcBEM",
+	"BjTZ82yZ4LFJSuebIiXXrXJZur+A11qdDBIbteU+GSvC8pZm2T7r20WRG4/mEJNcgnZYKpASJAZ0RyRi",
+	"XCEi4hXd7JczIywVEeowzUpFVD6I+RJfMzu8wPbeZ3WhX8Y8gyIxfOPzny0FnNR1Kdo2btrEB2uvG81K",
+	"rQDL13rb6efLy8nlBxzhs6tP1xfnN+c6kr0fTy7Mh7Px5dm5/vg1oONy2QfXH+VKB0cbh2mt9EeLNy4s",
+	"d9SNhalJsXeEiEIpEKkQZyUKFhTSRKJ1LhWaA5KgRuiLLiUZRxq0SBC2BESlfhSVBUQKG0hr5ZRbz3Pe",
+	"8fXEVp4CspTEOjlKdAdpOsJRwwyHRMmDo9fBbrkLqHwGTFJFNzDNU2jjqGaAQJxcAUlAtIdqUypBYyWN",
+	"9twwlPLYqVVx82BD0hwKJctyII4q4Lb2bAKUhsvIb5Kza6JWg8LNebLtlExCCrEu1+ZbJ+Kfs6tLU4lK",
+	"V6/9NsrIdg1MjTLCXh0kekYEWQ8K+D0HsUWEJWZbZCaB2kuf1diD5BKwhB8Bsc7fIAHLPCUCwY/i/hbE",
+	"JugUM2F7h6AaDmfF7IBo/sWziKKTy/dXOMJfxtNLHOGLqy84wp/O300+f8IR/jj58FGH0unkZnI2vgiG",
+	"UslzEcNhMtopwTxi1edp4euQ5808fZW5wcbBU57oe+gUZMaZhPJf8/Bj6S/Fc/8bM+TfGj3XFmjll9ox",
+	"3HchfYRO2kLD+8nFOVpwYeNjnkIVLM0/C5qCjExYrY9y2dJcOorIi6Py3HpdHGH95aBsD059tdUOTn+y",
+	"mI0SokhxvIemwVnFjbSPR2K9X8AaJJWAOIuhxpboSxr8yPSm+gqpC0ABG35rar/2Pe2QhKVvTB3l888U",
+	"70ZIeciUvYv0Tnqrl4VwkbOXo2g9EEBkR650ej/khG7KaYhmCwUeaz534FKY0lS+YaICSQMI9Kqxn69t",
+	"eqBSs3s/T8h06Hdolpq/0FcZCQpHe6qzwEF7G/2kKh0Nu+iy+4fP57PZ+PTi/D+Td69w1Iehvhzun0Nx",
+	"f58yagJLMk6ZkkVgpBKVZEc/Ph+0M5VVmdDFmT3GJsX5TBUT6WgkM4iRo3H296QG8AcwPwDvhyeQaq3D",
+	"04cnx0PThh5H2YK39xpfT85SomsmdJ3mS8rGLuEqqlIIDTjFEd6AsAkIH4+OR7/rs/AMGMkoPsF/jI5H",
+	"fzjuzWjoiGTUI8SP7o1VdlaYFFQgYX0AhW4MKzFmJN3+FwTyVjAlAykJXSdxSW5NEnyC35mVx9cTbx72",
+	"PEPik7/uMdWbOZDZdkYJmUqvSuTgGESyJynZPI9eu3DlWjPBCGBq+EoC86dPgCbmv+rBtsIzCn9z/Lat",
+	"0llueMtFniKjdYNJ049Zr4nYliprK5oZBe8ivAT1FKb6AOpXsZPrF9Vj1Dp3dzziO2WH8dwKs7IhhX2R",
+	"EliQPFX4ZKErsnaRFTDlsf4Tc6aAKZtas9Txv0ffXBCsNtivkyRtSGiEAlY1mmq40BbuAsUusu5d46V9",
+	"Dw9iRmfVZjfBEvSIzyWIjS5Ii85eCduyKaI5PhKvvFaDc61WmycyN+O7FagViEZ7oupfdDQ4yrYGVR29",
+	"jCCOa4s8G5SfEjidbY0AiqqxDXXqAPI2FJi00XSxtuA5SwLQU22wNNt8BhI+JA0FXMs55ps+TB4Wx8xy",
+	"IQScN3beCwBOuP9Hs5eLZtagBjzCMM2yFymW9o2BqTrN7ZBZB5KlkNdcqmLOggoZhM/U7f2EivNaAwG9",
+	"Tb3TdDijqA2JcMZDhPwUXoucBbSBiPsgEd+4qCwVF2Xrq3JmHb2PjONVtHzkqPXirZBMwIbyXLZ3afH0",
+	"dvkRutLvj1Rf2CsVYWXnyzXE9BnnPrvvZnJmGknmuT4iUU7AUcuiM0WEs6lz7oK0e1xrFhfx3W7XjCG7",
+	"FpTePD6UBpCETDsCEpsDjttgmbANSWlSQksU59Hj/xnIGT4IEZWIpAJIstX2YMVFsEKuMQMi/qSaqx/d",
+	"f+PzgSvJGWGxbv8UW/iLWQcvZWj2ctEtQOZ6rQzufGi2EGO3KSEznDuM5I9ePLwNOnSp8dhIWRh0YHBX",
+	"cq806psl6g+6meBLAVLW2nyur9sRTV9QjcfP7moPsohWcsBLZItV78+LPt1fzkWUWQ2a0rqWGbv6A91p",
+	"ssHzP6HeGzsFlD8L8PwdmVMGh3Zl0DPDxyI7LtK3EB3o9EkgKYg0k6lkoc8ELQRfI8bvEGctvdn1agd6",
+	"oqRU32OvpPT7U24+aLCi7TSYoAIW7EtSeumykyU1CUSTMk/ADypVEyql0YM7tb3x6F4/Gchdjtmpn7XW",
+	"YhtwRPtGA0nMq7cdXFsTWMMx10r+BFRXwMBWNd0JKzTHC5MdJr5xetKemcCC6pzv3jvy1Bfm2UgXmFxw",
+	"7Q96z6fg4xf1zJ81WCCvdag7ywOx93OWkCf0GLv88xn0lwjxLwsklBudPyDEv2zUKBHZnRganaveIq1o",
+	"ZPqT3DXGtH4RZxrY+r6bsxSkLH5LMWFugH09Llid+YKEYd3gmQoG9NHoruK1Bk3/ukZ57aj9fFdxxl+H",
+	"7aqpNAT4+vNQ8RmyeHfx+Sk3YCt638XkivuPEM/s7yAMLW4bvrYwJazq55YMjt9GDtalNTLySUJW+8WF",
+	"5y5N/X5wrxH3L0hrS/p2dxZ0lmuHiKP72k+xegvIqXGhZjff8ZqGUK797Equ+B1DZEkoG6HmmxpU8yGZ",
+	"slDKE6ratJndro6I4eRYO85jc+l3K+4CSeulho5YUr2f8wi920p9btnulOSN7aqHnDlJKCb0VqAvaJFn",
+	"iq4DfvkgtdsyNKjz3W73vwEASb1Rb745AAA=",
 }
 
 // GetSwagger returns the content of the embedded swagger specification file
@@ -0,0 +1,270 @@
+// Copyright © 2022 Cisco Systems, Inc. and its affiliates.
+// All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package unauthenticated
+
+import (
+	"encoding/json"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/openclarity/apiclarity/backend/pkg/modules/internal/core"
+	_utils "github.com/openclarity/apiclarity/backend/pkg/utils"
+	"github.com/openclarity/apiclarity/plugins/api/server/models"
+)
+
+const (
+	KindUnauthenticatedAccess = "UNAUTHENTICATED_ACCESS"
+)
+
+const (
+	// An operation usually requires authentication once this number of successful requests were authenticated...
+	MinAuthenticatedRequests = 20
+	// ... and at least this ratio of its successful requests.
+	AuthenticatedRatio = 0.95
+	// The operations learnt for each API are capped.
+	MaxOperations = 1000
+)
+
+const (
+	SchemeAPIKey        = "ApiKey"
+	SchemeSessionCookie = "SessionCookie"
+
+	authorizationHeader = "authorization"
+	cookieHeader        = "cookie"
+	maxSchemeLen        = 32
+)
+
+// sessionCookieRegexp matches the names of the usual session cookies.
+var sessionCookieRegexp = regexp.MustCompile(`(?i)(sess|sid|token|auth|jwt)`)
+
+// AuthSchemes returns the authentication schemes of the request: the scheme of the Authorization header
+// (e.g. Bearer or Basic), ApiKey for an API key header and SessionCookie for a session cookie.
+func AuthSchemes(headers []*models.Header) []string {
+	schemes := map[string]bool{}
+	for _, h := range headers {
+		if h == nil || strings.TrimSpace(h.Value) == "" {
+			continue
+		}
+		key := strings.ToLower(h.Key)
+		switch {
+		case key == authorizationHeader:
+			scheme := strings.Fields(h.Value)[0]
+			if len(scheme) > maxSchemeLen {
+				scheme = scheme[:maxSchemeLen]
+			}
+			// e.g. Bearer for "bearer" or "BEARER"
+			schemes[strings.ToUpper(scheme[:1])+strings.ToLower(scheme[1:])] = true
+		case _utils.IsAPIKeyHeader(key):
+			schemes[SchemeAPIKey] = true
+		case key == cookieHeader && hasSessionCookie(h.Value):
+			schemes[SchemeSessionCookie] = true
+		}
+	}
+
+	result := make([]string, 0, len(schemes))
+	for scheme := range schemes {
+		result = append(result, scheme)
+	}
+	sort.Strings(result)
+	return result
+}
+
+func hasSessionCookie(cookies string) bool {
+	for _, cookie := range (&http.Request{Header: http.Header{"Cookie": {cookies}}}).Cookies() {
+		if cookie.Value != "" && sessionCookieRegexp.MatchString(cookie.Name) {
+			return true
+		}
+	}
+	return false
+}
+
+// Operation is what was learnt of the successful requests of an operation of the API specification.
+type Operation struct {
+	Method   string `json:"method"`
+	PathID   string `json:"pathID"`
+	Location string `json:"location"`
+	// the authenticated requests by scheme, a request may use several schemes
+	Schemes         map[string]int64 `json:"schemes"`
+	Authenticated   int64            `json:"authenticated"`
+	Unauthenticated int64            `json:"unauthenticated"`
+	// true once an unauthenticated request succeeded although the operation usually requires authentication
+	UnauthenticatedAccess bool `json:"unauthenticatedAccess"`
+}
+
+// requiresAuthentication returns true if the operation usually requires authentication.
+func (o *Operation) requiresAuthentication(minAuthenticated int64, ratio float64) bool {
+	return o.Authenticated >= minAuthenticated &&
+		float64(o.Authenticated) >= ratio*float64(o.Authenticated+o.Unauthenticated)
+}
+
+// Finding is the annotation of the API, it lists its operations that succeeded without authentication.
+type Finding struct {
+	Operations []Operation `json:"operations"`
+}
+
+type UnauthenticatedAccess struct {
+	lock             sync.Mutex
+	minAuthenticated int64
+	ratio            float64
+	operations       map[string]*Operation
+}
+
+func NewUnauthenticatedAccess() *UnauthenticatedAccess {
+	return &UnauthenticatedAccess{
+		minAuthenticated: MinAuthenticatedRequests,
+		ratio:            AuthenticatedRatio,
+		operations:       map[string]*Operation{},
+	}
+}
+
+func operationKey(method string, pathID string) string {
+	return method + " " + pathID
+}
+
+func (u *UnauthenticatedAccess) MarshalJSON() ([]byte, error) {
+	u.lock.Lock()
+	defer u.lock.Unlock()
+
+	return json.Marshal(u.operations)
+}
+
+func (u *UnauthenticatedAccess) UnmarshalJSON(data []byte) error {
+	operations := map[string]*Operation{}
+	if err := json.Unmarshal(data, &operations); err != nil {
+		return err
+	}
+	for key, op := range operations {
+		if op == nil {
+			delete(operations, key)
+		} else if op.Schemes == nil {
+			op.Schemes = map[string]int64{}
+		}
+	}
+
+	u.lock.Lock()
+	defer u.lock.Unlock()
+	if u.minAuthenticated == 0 {
+		u.minAuthenticated, u.ratio = MinAuthenticatedRequests, AuthenticatedRatio
+	}
+	u.operations = operations
+
+	return nil
+}
+
+// Analyze learns the authentication of a successful request to the operation, identified by the path ID of the
+// API specification. It returns the API annotation when the request is the first one without authentication
+// to succeed on an operation which usually requires authentication.
+func (u *UnauthenticatedAccess) Analyze(method string, pathID string, location string, trace *models.Telemetry) (apiAnns []core.Annotation) {
+	if pathID == "" || trace.Request == nil || trace.Request.Common == nil {
+		return apiAnns
+	}
+	schemes := AuthSchemes(trace.Request.Common.Headers)
+
+	u.lock.Lock()
+	defer u.lock.Unlock()
+
+	key := operationKey(method, pathID)
+	op, ok := u.operations[key]
+	if !ok {
+		if len(u.operations) >= MaxOperations {
+			return apiAnns
+		}
+		op = &Operation{Method: method, PathID: pathID, Schemes: map[string]int64{}}
+		u.operations[key] = op
+	}
+	// the spec path of the path ID may be known only later
+	if location != "" {
+		op.Location = location
+	}
+
+	if len(schemes) > 0 {
+		op.Authenticated++
+		for _, scheme := range schemes {
+			op.Schemes[scheme]++
+		}
+		return apiAnns
+	}
+
+	// the operation is checked before its unauthenticated requests count the request
+	report := !op.UnauthenticatedAccess && op.requiresAuthentication(u.minAuthenticated, u.ratio)
+	op.Unauthenticated++
+	if !report {
+		return apiAnns
+	}
+	op.UnauthenticatedAccess = true
+
+	bytes, err := json.Marshal(u.finding())
+	if err == nil {
+		apiAnns = append(apiAnns, core.Annotation{Name: KindUnauthenticatedAccess, Annotation: bytes})
+	}
+	return apiAnns
+}
+
+// finding lists the operations accessed without authentication, the lock must be held.
+func (u *UnauthenticatedAccess) finding() Finding {
+	f := Finding{Operations: []Operation{}}
+	for _, op := range u.operations {
+		if op.UnauthenticatedAccess {
+			f.Operations = append(f.Operations, *op)
+		}
+	}
+	sortOperations(f.Operations)
+	return f
+}
+
+// Operations returns what was learnt of the operations.
+func (u *UnauthenticatedAccess) Operations() []Operation {
+	u.lock.Lock()
+	defer u.lock.Unlock()
+
+	ops := make([]Operation, 0, len(u.operations))
+	for _, op := range u.operations {
+		o := *op
+		o.Schemes = make(map[string]int64, len(op.Schemes))
+		for scheme, count := range op.Schemes {
+			o.Schemes[scheme] = count
+		}
+		ops = append(ops, o)
+	}
+	sortOperations(ops)
+	return ops
+}
+
+// ResetFindings forgets the unauthenticated accesses, e.g. once their annotation is deleted, they are reported
+// again on the next unauthenticated request.
+func (u *UnauthenticatedAccess) ResetFindings() {
+	u.lock.Lock()
+	defer u.lock.Unlock()
+
+	for _, op := range u.operations {
+		op.UnauthenticatedAccess = false
+	}
+}
+
+func sortOperations(ops []Operation) {
+	sort.Slice(ops, func(i, j int) bool {
+		if ops[i].Location != ops[j].Location {
+			return ops[i].Location < ops[j].Location
+		}
+		if ops[i].Method != ops[j].Method {
+			return ops[i].Method < ops[j].Method
+		}
+		return ops[i].PathID < ops[j].PathID
+	})
+}
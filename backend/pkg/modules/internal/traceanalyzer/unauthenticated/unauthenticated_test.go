@@ -0,0 +1,130 @@
+// Copyright © 2022 Cisco Systems, Inc. and its affiliates.
+// All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package unauthenticated
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/openclarity/apiclarity/plugins/api/server/models"
+)
+
+func newTrace(headers ...*models.Header) *models.Telemetry {
+	return &models.Telemetry{
+		Request: &models.Request{
+			Method: "GET",
+			Path:   "/users/1",
+			Common: &models.Common{Headers: headers},
+		},
+		Response: &models.Response{StatusCode: "200", Common: &models.Common{}},
+	}
+}
+
+func TestAuthSchemes(t *testing.T) {
+	testcases := []struct {
+		headers []*models.Header
+		wanted  []string
+	}{
+		{headers: nil, wanted: []string{}},
+		{headers: []*models.Header{{Key: "Accept", Value: "*/*"}, {Key: "Authorization", Value: " "}}, wanted: []string{}},
+		{headers: []*models.Header{{Key: "authorization", Value: "BEARER eyJhbGciOiJIUzI1NiJ9"}}, wanted: []string{"Bearer"}},
+		{headers: []*models.Header{{Key: "Authorization", Value: "Basic dXNlcjpwYXNz"}, {Key: "X-API-Key", Value: "secret"}}, wanted: []string{"ApiKey", "Basic"}},
+		{headers: []*models.Header{{Key: "Cookie", Value: "theme=dark; JSESSIONID=1234"}}, wanted: []string{"SessionCookie"}},
+		{headers: []*models.Header{{Key: "Cookie", Value: "theme=dark; _ga=GA1.2"}}, wanted: []string{}},
+	}
+
+	for _, tc := range testcases {
+		if got := AuthSchemes(tc.headers); !reflect.DeepEqual(got, tc.wanted) {
+			t.Errorf("AuthSchemes(%v) = %v, wanted %v", tc.headers, got, tc.wanted)
+		}
+	}
+}
+
+func TestUnauthenticatedAccess_Analyze(t *testing.T) {
+	u := NewUnauthenticatedAccess()
+	authenticated := newTrace(&models.Header{Key: "Authorization", Value: "Bearer token"})
+	unauthenticated := newTrace()
+
+	// an operation without authentication is never reported
+	for i := 0; i < 2*MinAuthenticatedRequests; i++ {
+		if anns := u.Analyze("GET", "public", "/public", unauthenticated); len(anns) != 0 {
+			t.Fatalf("Analyze() = %v for a public operation", anns)
+		}
+	}
+
+	// not enough authenticated requests yet
+	for i := 0; i < MinAuthenticatedRequests-1; i++ {
+		u.Analyze("GET", "user", "/users/{id}", authenticated)
+	}
+	if anns := u.Analyze("GET", "user", "/users/{id}", unauthenticated); len(anns) != 0 {
+		t.Fatalf("Analyze() = %v before the operation was learnt", anns)
+	}
+	for i := 0; i < MinAuthenticatedRequests; i++ {
+		u.Analyze("GET", "user", "/users/{id}", authenticated)
+	}
+
+	anns := u.Analyze("GET", "user", "/users/{id}", unauthenticated)
+	if len(anns) != 1 || anns[0].Name != KindUnauthenticatedAccess {
+		t.Fatalf("Analyze() = %v for an unauthenticated access", anns)
+	}
+	var f Finding
+	if err := json.Unmarshal(anns[0].Annotation, &f); err != nil {
+		t.Fatalf("invalid finding %s: %v", anns[0].Annotation, err)
+	}
+	wanted := Operation{
+		Method: "GET", PathID: "user", Location: "/users/{id}", Schemes: map[string]int64{"Bearer": 2*MinAuthenticatedRequests - 1},
+		Authenticated: 2*MinAuthenticatedRequests - 1, Unauthenticated: 2, UnauthenticatedAccess: true,
+	}
+	if len(f.Operations) != 1 || !reflect.DeepEqual(f.Operations[0], wanted) {
+		t.Errorf("finding = %+v, wanted %+v", f.Operations, wanted)
+	}
+
+	// reported once
+	if anns := u.Analyze("GET", "user", "/users/{id}", unauthenticated); len(anns) != 0 {
+		t.Errorf("Analyze() = %v for a reported operation", anns)
+	}
+	// the events without a path ID are not learnt
+	if anns := u.Analyze("GET", "", "/users/1", authenticated); len(anns) != 0 || len(u.Operations()) != 2 {
+		t.Errorf("Analyze() learnt an event without a path ID")
+	}
+}
+
+func TestUnauthenticatedAccess_RestoreAndReset(t *testing.T) {
+	u := NewUnauthenticatedAccess()
+	authenticated := newTrace(&models.Header{Key: "X-Api-Key", Value: "secret"})
+	for i := 0; i < MinAuthenticatedRequests; i++ {
+		u.Analyze("DELETE", "user", "/users/{id}", authenticated)
+	}
+	u.Analyze("DELETE", "user", "/users/{id}", newTrace())
+
+	state, err := json.Marshal(u)
+	if err != nil {
+		t.Fatalf("failed to marshal the state: %v", err)
+	}
+	restored := &UnauthenticatedAccess{}
+	if err := json.Unmarshal(state, restored); err != nil {
+		t.Fatalf("failed to unmarshal the state: %v", err)
+	}
+	if !reflect.DeepEqual(restored.Operations(), u.Operations()) {
+		t.Errorf("restored operations = %+v, wanted %+v", restored.Operations(), u.Operations())
+	}
+
+	restored.ResetFindings()
+	if anns := restored.Analyze("DELETE", "user", "/users/{id}", newTrace()); len(anns) != 1 {
+		t.Errorf("Analyze() = %v after the findings were reset", anns)
+	}
+}
@@ -0,0 +1,305 @@
+// Copyright © 2022 Cisco Systems, Inc. and its affiliates.
+// All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	_models "github.com/openclarity/apiclarity/plugins/api/server/models"
+)
+
+const (
+	ParamInPath   = "path"
+	ParamInQuery  = "query"
+	ParamInHeader = "header"
+	ParamInBody   = "body"
+)
+
+// MaxBodyParams bounds the number of fields taken from a body, so that a large body can't blow up the histories.
+const MaxBodyParams = 100
+
+// The request headers which are not parameters of the API: content negotiation, credentials, transport and
+// tracing headers.
+var ignoredHeaders = map[string]bool{
+	"accept":                    true,
+	"accept-encoding":           true,
+	"accept-language":           true,
+	"authorization":             true,
+	"cache-control":             true,
+	"connection":                true,
+	"content-length":            true,
+	"content-type":              true,
+	"cookie":                    true,
+	"date":                      true,
+	"host":                      true,
+	"if-modified-since":         true,
+	"if-none-match":             true,
+	"origin":                    true,
+	"pragma":                    true,
+	"referer":                   true,
+	"te":                        true,
+	"traceparent":               true,
+	"tracestate":                true,
+	"upgrade-insecure-requests": true,
+	"user-agent":                true,
+	"x-forwarded-for":           true,
+	"x-forwarded-host":          true,
+	"x-forwarded-port":          true,
+	"x-forwarded-proto":         true,
+	"x-real-ip":                 true,
+	"x-request-id":              true,
+}
+
+var ignoredHeaderPrefixes = []string{":", "x-b3-", "x-envoy-", "x-ot-span-context"}
+
+// The names of the parameters holding identifiers, alone or as a suffix (e.g. userId, user_id or X-Tenant-Id).
+var idNames = []string{"id", "ids", "uuid", "guid", "identifier", "identifiers"}
+
+// The array indexes of the body parameters names, e.g. [1] of items[1].skuId.
+var arrayIndexRegex = regexp.MustCompile(`\[\d+\]`)
+
+// The parts of the names of the parameters holding credentials, their values are not analyzed.
+var credentialsWords = []string{"auth", "cookie", "credential", "key", "passw", "secret", "session", "token"}
+
+// Params are the parameters of a request, by location.
+type Params struct {
+	Path   map[string]string
+	Query  map[string]string
+	Header map[string]string
+	Body   map[string]string
+}
+
+// ByLocation returns the parameters of each location, in a stable order.
+func (p Params) ByLocation() []LocatedParams {
+	return []LocatedParams{
+		{In: ParamInPath, Params: p.Path},
+		{In: ParamInQuery, Params: p.Query},
+		{In: ParamInHeader, Params: p.Header},
+		{In: ParamInBody, Params: p.Body},
+	}
+}
+
+type LocatedParams struct {
+	In     string
+	Params map[string]string
+}
+
+func isIgnoredHeader(name string) bool {
+	name = strings.ToLower(name)
+	if ignoredHeaders[name] {
+		return true
+	}
+	for _, prefix := range ignoredHeaderPrefixes {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsIDParam returns true if the parameter is named as an identifier, e.g. id, userId, user_id or items[0].skuIds.
+func IsIDParam(name string) bool {
+	// the last field of a body parameter
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		name = name[i+1:]
+	}
+	if i := strings.Index(name, "["); i >= 0 {
+		name = name[:i]
+	}
+
+	lowerName := strings.ToLower(name)
+	for _, idName := range idNames {
+		if !strings.HasSuffix(lowerName, idName) {
+			continue
+		}
+		prefix, suffix := name[:len(name)-len(idName)], name[len(name)-len(idName):]
+		// the whole name, a separated word or a camel case word, but not e.g. paid
+		if prefix == "" || strings.HasSuffix(prefix, "_") || strings.HasSuffix(prefix, "-") ||
+			unicode.IsUpper(rune(suffix[0])) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsCredentialsParam returns true if the name of the parameter suggests that it holds credentials.
+func IsCredentialsParam(name string) bool {
+	name = strings.ToLower(name)
+	for _, word := range credentialsWords {
+		if strings.Contains(name, word) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// DeclaredParams are the names of the parameters declared by a spec operation, by location. The header names are
+// lower case, and the body parameters are the paths of the fields of the body schema, without array indexes
+// (e.g. items.skuId).
+type DeclaredParams struct {
+	Query  map[string]bool
+	Header map[string]bool
+	Body   map[string]bool
+}
+
+// isAnalyzedParam returns true if the parameter holds an identifier, and not credentials. The identifiers are the
+// declared parameters when there is a spec operation, else the parameters named as identifiers.
+func isAnalyzedParam(in string, name string, declared *DeclaredParams) bool {
+	if IsCredentialsParam(name) {
+		return false
+	}
+	if declared == nil {
+		return IsIDParam(name)
+	}
+
+	switch in {
+	case ParamInQuery:
+		return declared.Query[name]
+	case ParamInHeader:
+		return declared.Header[strings.ToLower(name)]
+	case ParamInBody:
+		return declared.Body[arrayIndexRegex.ReplaceAllString(name, "")]
+	}
+
+	return false
+}
+
+func filterParams(in string, params map[string]string, declared *DeclaredParams) map[string]string {
+	filtered := map[string]string{}
+	for name, value := range params {
+		if isAnalyzedParam(in, name, declared) {
+			filtered[name] = value
+		}
+	}
+
+	return filtered
+}
+
+// GetRequestParams returns the query, header and JSON body parameters of the request of the trace that hold
+// identifiers: the parameters declared by the spec operation of the request, or without one, the parameters named
+// as identifiers. The parameters holding credentials are left out, so that their values are never stored. The path
+// parameters depend on the spec path of the operation, see GetPathParams.
+func GetRequestParams(trace *_models.Telemetry, declared *DeclaredParams) Params {
+	params := Params{
+		Path:   map[string]string{},
+		Query:  filterParams(ParamInQuery, GetQueryParams(trace.Request.Path), declared),
+		Header: map[string]string{},
+		Body:   map[string]string{},
+	}
+
+	for _, h := range trace.Request.Common.Headers {
+		if !isIgnoredHeader(h.Key) && isAnalyzedParam(ParamInHeader, h.Key, declared) {
+			params.Header[h.Key] = h.Value
+		}
+	}
+
+	if !trace.Request.Common.TruncatedBody && len(trace.Request.Common.Body) > 0 {
+		// the fields are filtered before they are counted, so that the identifiers are not left out of a large body
+		if body, err := getBodyParams(trace.Request.Common.Body, func(name string) bool {
+			return isAnalyzedParam(ParamInBody, name, declared)
+		}); err == nil {
+			params.Body = body
+		}
+	}
+
+	return params
+}
+
+// GetQueryParams returns the first value of each query parameter of the path.
+func GetQueryParams(path string) map[string]string {
+	result := make(map[string]string)
+
+	u, err := url.Parse(path)
+	if err != nil {
+		return result
+	}
+	for k, v := range u.Query() {
+		if len(v) > 0 {
+			result[k] = v[0]
+		}
+	}
+
+	return result
+}
+
+// GetBodyParams flattens a JSON body into its string and integer fields, named by their path in the document
+// (e.g. user.ids[1]). Booleans and floats are unlikely identifiers, they are skipped.
+func GetBodyParams(body []byte) (map[string]string, error) {
+	return getBodyParams(body, nil)
+}
+
+// getBodyParams returns the fields of the body kept by the keep function, if any, up to MaxBodyParams of them.
+func getBodyParams(body []byte, keep func(name string) bool) (map[string]string, error) {
+	var parsed interface{}
+
+	// We deserialize the JSON object this way because json.Unmarshal doesn't
+	// distinguish between int and floats. Here, thanks to d.UseNumber(), we
+	// can switch on json.Number and then try to cast to Int64.
+	d := json.NewDecoder(bytes.NewReader(body))
+	d.UseNumber()
+	if err := d.Decode(&parsed); err != nil {
+		return nil, fmt.Errorf("unable to decode body: %w", err)
+	}
+
+	result := make(map[string]string)
+	flattenBodyParams(result, parsed, "", keep)
+
+	return result, nil
+}
+
+func flattenBodyParams(result map[string]string, val interface{}, name string, keep func(name string) bool) {
+	if len(result) >= MaxBodyParams {
+		return
+	}
+
+	switch val := val.(type) {
+	case json.Number:
+		if n, err := val.Int64(); err == nil && (keep == nil || keep(name)) {
+			result[name] = fmt.Sprintf("%d", n)
+		}
+	case string:
+		if keep == nil || keep(name) {
+			result[name] = val
+		}
+	case []interface{}:
+		for i, v := range val {
+			flattenBodyParams(result, v, fmt.Sprintf("%s[%d]", name, i), keep)
+		}
+	case map[string]interface{}:
+		keys := make([]string, 0, len(val))
+		for k := range val {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			if name == "" {
+				flattenBodyParams(result, val[k], k, keep)
+			} else {
+				flattenBodyParams(result, val[k], name+"."+k, keep)
+			}
+		}
+	}
+}
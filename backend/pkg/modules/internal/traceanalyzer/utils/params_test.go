@@ -0,0 +1,125 @@
+// Copyright © 2022 Cisco Systems, Inc. and its affiliates.
+// All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	_models "github.com/openclarity/apiclarity/plugins/api/server/models"
+)
+
+func TestGetRequestParams(t *testing.T) {
+	trace := &_models.Telemetry{
+		Request: &_models.Request{
+			Path: "/users/42/orders?userId=1234&page=2&page=3",
+			Common: &_models.Common{
+				Headers: []*_models.Header{
+					{Key: "Content-Type", Value: "application/json"},
+					{Key: "X-B3-TraceId", Value: "463ac35c9f6413ad"},
+					{Key: "X-Tenant-Id", Value: "tenant-0001"},
+					{Key: "X-API-Key", Value: "secret"},
+					{Key: "X-Auth-Token", Value: "secret"},
+					{Key: "X-Session-Id", Value: "secret"},
+				},
+				Body: []byte(`{"order": {"petId": 12, "price": 1.5, "paid": "yes", "note": null}, "items": [{"skuId": "A1"}, {"skuId": "A2"}], ` +
+					`"user": {"name": "alice", "password": "secret", "token": "secret", "session_id": "secret"}}`),
+			},
+		},
+	}
+
+	got := GetRequestParams(trace, nil)
+	want := Params{
+		Path:   map[string]string{},
+		Query:  map[string]string{"userId": "1234"},
+		Header: map[string]string{"X-Tenant-Id": "tenant-0001"},
+		Body:   map[string]string{"order.petId": "12", "items[0].skuId": "A1", "items[1].skuId": "A2"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GetRequestParams() = %+v, want %+v", got, want)
+	}
+
+	// with a spec operation, its declared parameters are analyzed whatever their name, but not the credentials
+	declared := &DeclaredParams{
+		Query:  map[string]bool{"page": true},
+		Header: map[string]bool{"x-api-key": true},
+		Body:   map[string]bool{"user.name": true, "items.skuId": true},
+	}
+	want = Params{
+		Path:   map[string]string{},
+		Query:  map[string]string{"page": "2"},
+		Header: map[string]string{},
+		Body:   map[string]string{"user.name": "alice", "items[0].skuId": "A1", "items[1].skuId": "A2"},
+	}
+	if got := GetRequestParams(trace, declared); !reflect.DeepEqual(got, want) {
+		t.Errorf("GetRequestParams() = %+v, want %+v", got, want)
+	}
+
+	trace.Request.Common.TruncatedBody = true
+	if got := GetRequestParams(trace, nil); len(got.Body) != 0 {
+		t.Errorf("GetRequestParams() of a truncated body = %+v", got.Body)
+	}
+}
+
+func TestGetRequestParams_LargeBody(t *testing.T) {
+	// the identifier comes after more than MaxBodyParams other fields
+	var body strings.Builder
+	body.WriteString(`{`)
+	for i := 0; i < 2*MaxBodyParams; i++ {
+		fmt.Fprintf(&body, `"field%03d": "value", `, i)
+	}
+	body.WriteString(`"userId": "42"}`)
+	trace := &_models.Telemetry{Request: &_models.Request{Path: "/users", Common: &_models.Common{Body: []byte(body.String())}}}
+
+	if got := GetRequestParams(trace, nil); !reflect.DeepEqual(got.Body, map[string]string{"userId": "42"}) {
+		t.Errorf("GetRequestParams() = %+v", got.Body)
+	}
+}
+
+func TestIsIDParam(t *testing.T) {
+	for name, want := range map[string]bool{
+		"id": true, "ID": true, "userId": true, "userID": true, "user_id": true, "X-Tenant-Id": true, "orderIds": true,
+		"items[0].uuid": true, "order.petId": true, "ids[1]": true,
+		"paid": false, "android": false, "page": false, "name": false, "order.valid": false,
+	} {
+		if got := IsIDParam(name); got != want {
+			t.Errorf("IsIDParam(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
+
+func TestGetPathParams(t *testing.T) {
+	if got := GetPathParams("/users/{userId}/orders/{orderId}", "/users/42/orders/7"); !reflect.DeepEqual(got, map[string]string{"userId": "42", "orderId": "7"}) {
+		t.Errorf("GetPathParams() = %v", got)
+	}
+	if got := GetPathParams("/users/{userId}/orders/{orderId}", "/users/42"); len(got) != 0 {
+		t.Errorf("GetPathParams() of another path = %v", got)
+	}
+}
+
+func TestGuessPathParams(t *testing.T) {
+	specPath, params := GuessPathParams("/users/42/orders/3f2504e0-4f89-11d3-9a0c-0305e82c3301/items?limit=10")
+	if specPath != "/users/{param1}/orders/{param2}/items" ||
+		!reflect.DeepEqual(params, map[string]string{"param1": "42", "param2": "3f2504e0-4f89-11d3-9a0c-0305e82c3301"}) {
+		t.Errorf("GuessPathParams() = %s, %v", specPath, params)
+	}
+	// the other segments are not identifiers, e.g. a version
+	if specPath, params := GuessPathParams("/v2/users/alice"); specPath != "/v2/users/alice" || len(params) != 0 {
+		t.Errorf("GuessPathParams() = %s, %v without identifiers", specPath, params)
+	}
+}
@@ -18,9 +18,13 @@ package utils
 import (
 	"fmt"
 	"io/fs"
+	"net"
 	"os"
 	"path/filepath"
+	"regexp"
+	"strconv"
 	"strings"
+	"time"
 
 	_models "github.com/openclarity/apiclarity/plugins/api/server/models"
 )
@@ -29,8 +33,12 @@ const (
 	pathSep     = "/"
 	paramPrefix = "{"
 	paramSuffix = "}"
+	// guessedParamPrefix names the guessed path parameters param1, param2... as in the reconstructed specs
+	guessedParamPrefix = "param"
 )
 
+var uuidRegexp = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
 func isPathParam(segment string) bool {
 	return strings.HasPrefix(segment, paramPrefix) && strings.HasSuffix(segment, paramSuffix)
 }
@@ -40,6 +48,10 @@ func GetPathParams(specPath string, opPath string) map[string]string {
 
 	specSegs := strings.Split(specPath, pathSep)
 	opSegs := strings.Split(opPath, pathSep)
+	if len(specSegs) != len(opSegs) {
+		// the path doesn't match the spec path
+		return result
+	}
 
 	for i, specSeg := range specSegs {
 		if specSeg == opSegs[i] {
@@ -52,6 +64,39 @@ func GetPathParams(specPath string, opPath string) map[string]string {
 	return result
 }
 
+// GuessPathParams returns the path of a request without a spec, with its numeric and UUID segments replaced by
+// path parameters, and the values of these parameters. E.g. /users/42/orders is /users/{param1}/orders.
+func GuessPathParams(path string) (specPath string, params map[string]string) {
+	params = make(map[string]string)
+	if i := strings.IndexAny(path, "?#"); i >= 0 {
+		path = path[:i]
+	}
+
+	segs := strings.Split(path, pathSep)
+	for i, seg := range segs {
+		if !isNumeric(seg) && !uuidRegexp.MatchString(seg) {
+			continue
+		}
+		name := guessedParamPrefix + strconv.Itoa(len(params)+1)
+		params[name] = seg
+		segs[i] = paramPrefix + name + paramSuffix
+	}
+
+	return strings.Join(segs, pathSep), params
+}
+
+func isNumeric(segment string) bool {
+	if segment == "" {
+		return false
+	}
+	for _, c := range segment {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
 func WalkFiles(root string) ([]string, error) {
 	files := []string{}
 
@@ -91,4 +136,54 @@ func FindHeader(headers []*_models.Header, headerName string) (index int, found
 	return -1, false
 }
 
+// GetSourceAddress returns the client address of the request, the first one of X-Forwarded-For behind a proxy.
+func GetSourceAddress(trace *_models.Telemetry) string {
+	if trace.Request != nil && trace.Request.Common != nil {
+		if i, found := FindHeader(trace.Request.Common.Headers, "X-Forwarded-For"); found {
+			if client := strings.TrimSpace(strings.Split(trace.Request.Common.Headers[i].Value, ",")[0]); client != "" {
+				return client
+			}
+		}
+	}
+	if host, _, err := net.SplitHostPort(trace.SourceAddress); err == nil {
+		return host
+	}
+	return trace.SourceAddress
+}
+
+// ParseThresholds overrides the thresholds of an analyzer with a comma separated list of "<name>=<value>", e.g.
+// "window=10m,userFailures=5". The window is a duration, the counts are positive integers.
+func ParseThresholds(config string, window *time.Duration, counts map[string]*int) error {
+	for _, item := range strings.Split(config, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		kv := strings.SplitN(item, "=", 2) //nolint:gomnd
+		if len(kv) != 2 {                  //nolint:gomnd
+			return fmt.Errorf("invalid threshold %q, expecting <name>=<value>", item)
+		}
+		name, value := strings.TrimSpace(kv[0]), strings.TrimSpace(kv[1])
+		if name == "window" {
+			d, err := time.ParseDuration(value)
+			if err != nil || d <= 0 {
+				return fmt.Errorf("invalid window %q", value)
+			}
+			*window = d
+			continue
+		}
+		count, ok := counts[name]
+		if !ok {
+			return fmt.Errorf("unknown threshold %q", name)
+		}
+		n, err := strconv.Atoi(value)
+		if err != nil || n <= 0 {
+			return fmt.Errorf("invalid %s threshold %q", name, value)
+		}
+		*count = n
+	}
+
+	return nil
+}
+
 type API = string
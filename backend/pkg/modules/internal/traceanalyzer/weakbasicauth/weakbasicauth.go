@@ -97,6 +97,15 @@ func NewWeakBasicAuth(knownPasswords []string) *WeakBasicAuth {
 	}
 }
 
+// WithoutHistory returns an analyzer of the same known passwords, that has not seen any credentials yet.
+func (w *WeakBasicAuth) WithoutHistory() *WeakBasicAuth {
+	return &WeakBasicAuth{
+		shortPasswordLen: w.shortPasswordLen,
+		knownPasswordsAC: w.knownPasswordsAC,
+		usedCredentials:  make(map[userPassword]map[utils.API]bool),
+	}
+}
+
 func (w *WeakBasicAuth) analyzeShortPassword(password string) (anns []core.Annotation) {
 	if len(password) <= w.shortPasswordLen {
 		anns = append(anns, core.Annotation{
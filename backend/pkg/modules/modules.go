@@ -24,6 +24,8 @@ import (
 
 	// Enables the bfla module.
 	_ "github.com/openclarity/apiclarity/backend/pkg/modules/internal/bfla"
+	// Enables the bola module.
+	_ "github.com/openclarity/apiclarity/backend/pkg/modules/internal/bola"
 	"github.com/openclarity/apiclarity/backend/pkg/modules/internal/core"
 
 	// Enables the demo module.
@@ -38,11 +40,21 @@ type (
 	BackendAccessor     = core.BackendAccessor
 	MockBackendAccessor = core.MockBackendAccessor
 	Event               = core.Event
+	Finding             = core.Finding
+	FindingsDescriber   = core.FindingsDescriber
+	FindingsDescribers  = core.FindingsDescribers
+	FindingsListener    = core.FindingsListener
+
+	DefaultFindingsDescriber = core.DefaultFindingsDescriber
 )
 
 var (
 	NewMockModule          = core.NewMockModule
 	NewMockBackendAccessor = core.NewMockBackendAccessor
+	FindingSeverityLevel   = core.FindingSeverityLevel
+	FindingSeverity        = core.FindingSeverity
+	GetFindingsDescribers  = core.GetFindingsDescribers
+	AddFindingsListener    = core.AddFindingsListener
 )
 
 func New(ctx context.Context, dbHandler *database.Handler, clientset kubernetes.Interface) Module {
@@ -0,0 +1,238 @@
+// Copyright © 2022 Cisco Systems, Inc. and its affiliates.
+// All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package replay
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	uuid "github.com/satori/go.uuid"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/openclarity/apiclarity/plugins/api/server/models"
+)
+
+const (
+	requestIDHeader = "x-request-id"
+	// HAR files don't record the client address.
+	unknownSourceAddress = "0.0.0.0:0"
+)
+
+// HAR is the subset of the HTTP Archive 1.2 format needed to build telemetries.
+type HAR struct {
+	Log HARLog `json:"log"`
+}
+
+type HARLog struct {
+	Entries []*HAREntry `json:"entries"`
+}
+
+type HAREntry struct {
+	StartedDateTime time.Time   `json:"startedDateTime"`
+	Time            float64     `json:"time"` // total elapsed time of the request in milliseconds
+	Request         HARRequest  `json:"request"`
+	Response        HARResponse `json:"response"`
+	ServerIPAddress string      `json:"serverIPAddress,omitempty"`
+}
+
+type HARRequest struct {
+	Method      string       `json:"method"`
+	URL         string       `json:"url"`
+	HTTPVersion string       `json:"httpVersion"`
+	Headers     []*HARHeader `json:"headers"`
+	PostData    *HARPostData `json:"postData,omitempty"`
+}
+
+type HARResponse struct {
+	Status      int          `json:"status"`
+	HTTPVersion string       `json:"httpVersion"`
+	Headers     []*HARHeader `json:"headers"`
+	Content     HARContent   `json:"content"`
+}
+
+type HARHeader struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type HARPostData struct {
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+}
+
+type HARContent struct {
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+	Encoding string `json:"encoding,omitempty"`
+}
+
+// LoadHARFile reads a HAR file and converts its entries into telemetries.
+// Entries that can't be converted are skipped.
+func LoadHARFile(fileName string) ([]*models.Telemetry, error) {
+	data, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file %v: %v", fileName, err)
+	}
+
+	var har HAR
+	if err := json.Unmarshal(data, &har); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal HAR file %v: %v", fileName, err)
+	}
+
+	telemetries := make([]*models.Telemetry, 0, len(har.Log.Entries))
+	for i, entry := range har.Log.Entries {
+		telemetry, err := ConvertHAREntry(entry)
+		if err != nil {
+			log.Warnf("Skipping entry %v of HAR file %v: %v", i, fileName, err)
+			continue
+		}
+		telemetries = append(telemetries, telemetry)
+	}
+
+	return telemetries, nil
+}
+
+// ConvertHAREntry converts a HAR request/response pair into a telemetry.
+func ConvertHAREntry(entry *HAREntry) (*models.Telemetry, error) {
+	if entry == nil {
+		return nil, fmt.Errorf("empty entry")
+	}
+	if entry.Response.Status <= 0 {
+		// aborted or blocked request, no response was received
+		return nil, fmt.Errorf("missing response status")
+	}
+
+	reqURL, err := url.Parse(entry.Request.URL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse url %q: %v", entry.Request.URL, err)
+	}
+	if reqURL.Host == "" {
+		return nil, fmt.Errorf("missing host in url %q", entry.Request.URL)
+	}
+
+	reqBody := []byte(entry.Request.PostData.getText())
+	resBody, err := entry.Response.Content.getBody()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get response body: %v", err)
+	}
+
+	reqHeaders := convertHARHeaders(entry.Request.Headers)
+	reqTime := entry.StartedDateTime.UnixMilli()
+
+	return &models.Telemetry{
+		DestinationAddress: getDestinationAddress(reqURL, entry.ServerIPAddress),
+		Request: &models.Request{
+			Common: &models.Common{
+				Body:    reqBody,
+				Headers: reqHeaders,
+				Time:    reqTime,
+				Version: getVersion(entry.Request.HTTPVersion),
+			},
+			Host:   reqURL.Host,
+			Method: entry.Request.Method,
+			Path:   reqURL.RequestURI(),
+		},
+		RequestID: getRequestID(reqHeaders),
+		Response: &models.Response{
+			Common: &models.Common{
+				Body:    resBody,
+				Headers: convertHARHeaders(entry.Response.Headers),
+				Time:    reqTime + int64(entry.Time),
+				Version: getVersion(entry.Response.HTTPVersion),
+			},
+			StatusCode: strconv.Itoa(entry.Response.Status),
+		},
+		Scheme:        reqURL.Scheme,
+		SourceAddress: unknownSourceAddress,
+	}, nil
+}
+
+func (p *HARPostData) getText() string {
+	if p == nil {
+		return ""
+	}
+	return p.Text
+}
+
+func (c HARContent) getBody() ([]byte, error) {
+	if c.Encoding == "base64" {
+		body, err := base64.StdEncoding.DecodeString(c.Text)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode base64 content: %v", err)
+		}
+		return body, nil
+	}
+	return []byte(c.Text), nil
+}
+
+func convertHARHeaders(harHeaders []*HARHeader) []*models.Header {
+	headers := make([]*models.Header, 0, len(harHeaders))
+	for _, header := range harHeaders {
+		// HTTP/2 pseudo headers (:authority, :path, ...) are not real headers
+		if strings.HasPrefix(header.Name, ":") {
+			continue
+		}
+		headers = append(headers, &models.Header{
+			Key:   strings.ToLower(header.Name),
+			Value: header.Value,
+		})
+	}
+	return headers
+}
+
+func getDestinationAddress(reqURL *url.URL, serverIPAddress string) string {
+	port := reqURL.Port()
+	if port == "" {
+		if reqURL.Scheme == "https" {
+			port = "443"
+		} else {
+			port = "80"
+		}
+	}
+
+	host := reqURL.Hostname()
+	// prefer the recorded server IP, ipv6 addresses are not supported as destination addresses
+	if ip := net.ParseIP(strings.Trim(serverIPAddress, "[]")); ip != nil && ip.To4() != nil {
+		host = ip.String()
+	}
+
+	return host + ":" + port
+}
+
+// getVersion converts an HTTP version (e.g. HTTP/1.1) into the telemetry version format (e.g. 1.1).
+func getVersion(httpVersion string) string {
+	version := strings.TrimPrefix(strings.ToUpper(httpVersion), "HTTP/")
+	if version == "H2" {
+		return "2.0"
+	}
+	return version
+}
+
+func getRequestID(headers []*models.Header) string {
+	for _, header := range headers {
+		if header.Key == requestIDHeader && header.Value != "" {
+			return header.Value
+		}
+	}
+	return uuid.NewV4().String()
+}
@@ -0,0 +1,138 @@
+// Copyright © 2022 Cisco Systems, Inc. and its affiliates.
+// All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package replay
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gotest.tools/assert"
+
+	"github.com/openclarity/apiclarity/plugins/api/server/models"
+)
+
+const harFile = `{
+  "log": {
+    "version": "1.2",
+    "entries": [
+      {
+        "startedDateTime": "2022-05-01T10:00:00.000Z",
+        "time": 25.4,
+        "serverIPAddress": "10.0.0.5",
+        "request": {
+          "method": "POST",
+          "url": "https://users.example.com/api/users?verbose=true",
+          "httpVersion": "HTTP/1.1",
+          "headers": [
+            {"name": "Content-Type", "value": "application/json"},
+            {"name": "X-Request-Id", "value": "req-1"}
+          ],
+          "postData": {"mimeType": "application/json", "text": "{\"name\":\"bob\"}"}
+        },
+        "response": {
+          "status": 201,
+          "httpVersion": "HTTP/1.1",
+          "headers": [{"name": "Content-Type", "value": "application/json"}],
+          "content": {"mimeType": "application/json", "text": "eyJpZCI6MX0=", "encoding": "base64"}
+        }
+      },
+      {
+        "startedDateTime": "2022-05-01T10:00:01.000Z",
+        "time": 0,
+        "request": {"method": "GET", "url": "http://users.example.com/api/users", "httpVersion": "HTTP/1.1", "headers": []},
+        "response": {"status": 0, "httpVersion": "", "headers": [], "content": {}}
+      }
+    ]
+  }
+}`
+
+func writeHARFile(t *testing.T) string {
+	t.Helper()
+	fileName := filepath.Join(t.TempDir(), "test.har")
+	if err := os.WriteFile(fileName, []byte(harFile), 0o600); err != nil {
+		t.Fatalf("failed to write HAR file: %v", err)
+	}
+	return fileName
+}
+
+func TestLoadHARFile(t *testing.T) {
+	telemetries, err := LoadHARFile(writeHARFile(t))
+	assert.NilError(t, err)
+	// the aborted request has no response and is skipped
+	assert.Equal(t, len(telemetries), 1)
+	assert.DeepEqual(t, telemetries[0], &models.Telemetry{
+		DestinationAddress: "10.0.0.5:443",
+		Request: &models.Request{
+			Common: &models.Common{
+				Body: []byte(`{"name":"bob"}`),
+				Headers: []*models.Header{
+					{Key: "content-type", Value: "application/json"},
+					{Key: "x-request-id", Value: "req-1"},
+				},
+				Time:    1651399200000,
+				Version: "1.1",
+			},
+			Host:   "users.example.com",
+			Method: "POST",
+			Path:   "/api/users?verbose=true",
+		},
+		RequestID: "req-1",
+		Response: &models.Response{
+			Common: &models.Common{
+				Body:    []byte(`{"id":1}`),
+				Headers: []*models.Header{{Key: "content-type", Value: "application/json"}},
+				Time:    1651399200025,
+				Version: "1.1",
+			},
+			StatusCode: "201",
+		},
+		Scheme:        "https",
+		SourceAddress: unknownSourceAddress,
+	})
+}
+
+func TestReplayToUpstream(t *testing.T) {
+	var received []*models.Telemetry
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, r.URL.Path, telemetriesPath)
+		var batch []*models.Telemetry
+		assert.NilError(t, json.NewDecoder(r.Body).Decode(&batch))
+		received = append(received, batch...)
+
+		result := &models.TelemetriesResult{}
+		for _, telemetry := range batch {
+			statusCode := int64(http.StatusOK)
+			if telemetry.RequestID == "bad" {
+				statusCode = http.StatusInternalServerError
+			}
+			result.Results = append(result.Results, &models.TelemetryResult{RequestID: telemetry.RequestID, StatusCode: statusCode})
+		}
+		_ = json.NewEncoder(w).Encode(result)
+	}))
+	defer server.Close()
+
+	telemetries := []*models.Telemetry{{RequestID: "1"}, {RequestID: "bad"}, {RequestID: "3"}}
+	result, err := ReplayToUpstream(context.Background(), strings.TrimPrefix(server.URL, "http://"), telemetries, 2)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, result, &Result{Total: 3, Succeeded: 2, Failed: 1})
+	assert.Equal(t, len(received), 3)
+}
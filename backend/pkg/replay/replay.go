@@ -0,0 +1,135 @@
+// Copyright © 2022 Cisco Systems, Inc. and its affiliates.
+// All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package replay
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/openclarity/apiclarity/backend/pkg/traces"
+	"github.com/openclarity/apiclarity/plugins/api/server/models"
+)
+
+const (
+	DefaultBatchSize = 100
+
+	telemetriesPath = "/api/telemetries"
+	upstreamTimeout = 30 * time.Second
+)
+
+type Result struct {
+	Total     int
+	Succeeded int
+	Failed    int
+}
+
+func (r *Result) String() string {
+	return fmt.Sprintf("total=%v, succeeded=%v, failed=%v", r.Total, r.Succeeded, r.Failed)
+}
+
+// Replay sends the telemetries one by one, in order, to the trace handling func.
+func Replay(ctx context.Context, telemetries []*models.Telemetry, handleFunc traces.HandleTraceFunc) *Result {
+	result := &Result{Total: len(telemetries)}
+
+	for _, telemetry := range telemetries {
+		if ctx.Err() != nil {
+			result.Failed += result.Total - result.Succeeded - result.Failed
+			break
+		}
+		if err := handleFunc(ctx, telemetry); err != nil {
+			log.Errorf("Failed to replay telemetry %v: %v", telemetry.RequestID, err)
+			result.Failed++
+			continue
+		}
+		result.Succeeded++
+	}
+
+	return result
+}
+
+// ReplayToUpstream posts the telemetries in batches to the telemetries endpoint of a running backend (host:port).
+func ReplayToUpstream(ctx context.Context, address string, telemetries []*models.Telemetry, batchSize int) (*Result, error) {
+	if batchSize <= 0 {
+		batchSize = DefaultBatchSize
+	}
+
+	client := &http.Client{Timeout: upstreamTimeout}
+	url := "http://" + address + telemetriesPath
+	result := &Result{Total: len(telemetries)}
+
+	for len(telemetries) > 0 {
+		size := batchSize
+		if size > len(telemetries) {
+			size = len(telemetries)
+		}
+
+		batchResult, err := postTelemetries(ctx, client, url, telemetries[:size])
+		if err != nil {
+			return result, fmt.Errorf("failed to post telemetries: %v", err)
+		}
+		for _, telemetryResult := range batchResult.Results {
+			if telemetryResult.StatusCode == http.StatusOK {
+				result.Succeeded++
+				continue
+			}
+			log.Errorf("Failed to replay telemetry %v: status=%v, message=%v",
+				telemetryResult.RequestID, telemetryResult.StatusCode, telemetryResult.Message)
+			result.Failed++
+		}
+
+		telemetries = telemetries[size:]
+	}
+
+	return result, nil
+}
+
+func postTelemetries(ctx context.Context, client *http.Client, url string, telemetries []*models.Telemetry) (*models.TelemetriesResult, error) {
+	body, err := json.Marshal(telemetries)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal telemetries: %v", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %v", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code: %v", resp.StatusCode)
+	}
+
+	var result models.TelemetriesResult
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %v", err)
+	}
+
+	return &result, nil
+}
@@ -0,0 +1,47 @@
+// Copyright © 2022 Cisco Systems, Inc. and its affiliates.
+// All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rest
+
+import (
+	"github.com/go-openapi/runtime/middleware"
+	"github.com/go-openapi/strfmt"
+
+	"github.com/openclarity/apiclarity/api/server/models"
+	"github.com/openclarity/apiclarity/api/server/restapi/operations"
+)
+
+func (s *Server) GetDataRetentionStatus(_ operations.GetDataRetentionStatusParams) middleware.Responder {
+	status := &models.DataRetentionStatus{}
+	if s.retention == nil {
+		return operations.NewGetDataRetentionStatusOK().WithPayload(status)
+	}
+
+	status.Enabled = s.retention.Enabled()
+	if lastRun := s.retention.LastRun(); lastRun != nil {
+		status.LastRun = &models.DataRetentionRun{
+			StartTime:               strfmt.DateTime(lastRun.StartTime),
+			EndTime:                 strfmt.DateTime(lastRun.EndTime),
+			DeletedEvents:           lastRun.DeletedEvents,
+			DeletedEventAnnotations: lastRun.DeletedEventAnnotations,
+			DeletedAPIAnnotations:   lastRun.DeletedAPIAnnotations,
+		}
+		if lastRun.Err != nil {
+			status.LastRun.Error = lastRun.Err.Error()
+		}
+	}
+
+	return operations.NewGetDataRetentionStatusOK().WithPayload(status)
+}
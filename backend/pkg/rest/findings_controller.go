@@ -0,0 +1,310 @@
+// Copyright © 2022 Cisco Systems, Inc. and its affiliates.
+// All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rest
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-openapi/runtime/middleware"
+	"github.com/go-openapi/strfmt"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/openclarity/apiclarity/api/server/models"
+	"github.com/openclarity/apiclarity/api/server/restapi/operations"
+	"github.com/openclarity/apiclarity/backend/pkg/database"
+	"github.com/openclarity/apiclarity/backend/pkg/modules"
+)
+
+func (s *Server) GetFindings(params operations.GetFindingsParams) middleware.Responder {
+	filters := createFindingsFilters(params.ModuleIs, params.KindIs, params.APIInfoIDIs, params.NamespaceIs, params.StartTime, params.EndTime)
+	sortKey := models.FindingSortKey(*params.SortKey)
+	desc := strings.EqualFold(*params.SortDir, "DESC")
+	start := int((params.Page - 1) * params.PageSize)
+	if start < 0 {
+		start = 0
+	}
+	end := start + int(params.PageSize)
+
+	var total int64
+	items := []*models.Finding{}
+	err := s.walkFindings(params.HTTPRequest.Context(), filters, params.SeverityIs, database.FindingsPage{SortKey: sortKey, Desc: desc}, func(f *models.Finding) {
+		if total >= int64(start) && total < int64(end) {
+			items = append(items, f)
+		}
+		total++
+	})
+	if err != nil {
+		log.Error(err)
+		return operations.NewGetFindingsDefault(http.StatusInternalServerError).WithPayload(&models.APIResponse{
+			Message: "Oops",
+		})
+	}
+
+	return operations.NewGetFindingsOK().WithPayload(
+		&operations.GetFindingsOKBody{
+			Items: items,
+			Total: &total,
+		})
+}
+
+func createFindingsFilters(moduleIs, kindIs []string, apiInfoIDIs []uint32, namespaceIs []string, startTime, endTime *strfmt.DateTime) database.FindingsFilters {
+	filters := database.FindingsFilters{
+		ModuleNameIs: moduleIs,
+		NameIs:       kindIs,
+		APIInfoIDIs:  apiInfoIDIs,
+		NamespaceIs:  namespaceIs,
+	}
+	if startTime != nil {
+		t := time.Time(*startTime).UTC()
+		filters.StartTime = &t
+	}
+	if endTime != nil {
+		t := time.Time(*endTime).UTC()
+		filters.EndTime = &t
+	}
+	return filters
+}
+
+// findingsBatchSize is the number of findings read at once from the database.
+const findingsBatchSize = 500
+
+// walkFindings calls fn with each finding of the events and the APIs with the given severities, in the order of
+// the page. The findings are read from the database by batches, the modules describe them as they are read.
+func (s *Server) walkFindings(ctx context.Context, filters database.FindingsFilters, severities []string, page database.FindingsPage,
+	fn func(f *models.Finding),
+) error {
+	eventFindings := &findingsReader{read: func(offset int) ([]*models.Finding, bool, error) {
+		groups, err := s.dbHandler.APIEventsAnnotationsTable().ListFindingsGroups(ctx, filters,
+			database.FindingsPage{SortKey: page.SortKey, Desc: page.Desc, Offset: offset, Limit: findingsBatchSize})
+		if err != nil {
+			return nil, false, err
+		}
+		findings := describeFindings(s.findingsDescribers, groups, nil)
+		return filterFindingsSeverity(findings, severities), len(groups) == findingsBatchSize, nil
+	}}
+	apiFindings := &findingsReader{read: func(offset int) ([]*models.Finding, bool, error) {
+		annotations, err := s.dbHandler.APIInfoAnnotationsTable().ListFindings(ctx, filters,
+			database.FindingsPage{SortKey: page.SortKey, Desc: page.Desc, Offset: offset, Limit: findingsBatchSize})
+		if err != nil {
+			return nil, false, err
+		}
+		findings := describeFindings(s.findingsDescribers, nil, annotations)
+		return filterFindingsSeverity(findings, severities), len(annotations) == findingsBatchSize, nil
+	}}
+
+	for {
+		eventFinding, err := eventFindings.peek()
+		if err != nil {
+			return err
+		}
+		apiFinding, err := apiFindings.peek()
+		if err != nil {
+			return err
+		}
+		switch {
+		case eventFinding == nil && apiFinding == nil:
+			return nil
+		case apiFinding == nil || (eventFinding != nil && !isFindingBefore(apiFinding, eventFinding, page)):
+			fn(eventFindings.next())
+		default:
+			fn(apiFindings.next())
+		}
+	}
+}
+
+// isFindingBefore returns true if the finding a comes before b in the order of the page of the database.
+func isFindingBefore(a, b *models.Finding, page database.FindingsPage) bool {
+	if page.SortKey == models.FindingSortKeySeverity || page.SortKey == models.FindingSortKeyCount || page.SortKey == models.FindingSortKeyFirstSeen {
+		if c := compareFindings(a, b, page.SortKey); c != 0 {
+			return (c < 0) != page.Desc
+		}
+	} else if page.SortKey == models.FindingSortKeyLastSeen && !page.Desc {
+		return compareFindings(a, b, models.FindingSortKeyLastSeen) < 0
+	}
+	return compareFindings(a, b, models.FindingSortKeyLastSeen) > 0
+}
+
+// findingsReader reads the findings of the database by batches.
+type findingsReader struct {
+	// read returns the findings of the batch at the offset, and false if it is the last batch
+	read     func(offset int) ([]*models.Finding, bool, error)
+	offset   int
+	findings []*models.Finding
+	more     bool
+	started  bool
+}
+
+// peek returns the next finding, nil if there are no more findings.
+func (r *findingsReader) peek() (*models.Finding, error) {
+	// the batches may be empty once the annotations which are not findings are left out
+	for len(r.findings) == 0 && (r.more || !r.started) {
+		findings, more, err := r.read(r.offset)
+		if err != nil {
+			return nil, err
+		}
+		r.offset += findingsBatchSize
+		r.findings, r.more, r.started = findings, more, true
+	}
+	if len(r.findings) == 0 {
+		return nil, nil
+	}
+	return r.findings[0], nil
+}
+
+// next returns the finding returned by peek, and moves to the following one.
+func (r *findingsReader) next() *models.Finding {
+	f := r.findings[0]
+	r.findings = r.findings[1:]
+	return f
+}
+
+// describeFindings keeps the annotations that the modules describe as findings.
+func describeFindings(describers modules.FindingsDescribers, eventGroups []*database.EventFindingsGroup, apiFindings []*database.APIFinding) []*models.Finding {
+	findings := []*models.Finding{}
+
+	for _, g := range eventGroups {
+		lastEvent := &database.APIEvent{ID: g.LastEventID, APIInfoID: g.APIInfoID, Method: g.Method, Path: g.Path}
+		f, ok := describers.FindingsDescriber(g.ModuleName).DescribeEventFinding(lastEvent, modules.Annotation{Name: g.Name, Annotation: g.Annotation})
+		if !ok {
+			continue
+		}
+		// the severity of the group is the highest of its annotations, as it is sorted by the database
+		if g.Severity > modules.FindingSeverityLevel(f.Severity) {
+			f.Severity = modules.FindingSeverity(g.Severity)
+		}
+		findings = append(findings, &models.Finding{
+			Module:      g.ModuleName,
+			Kind:        g.Name,
+			Name:        f.Name,
+			Description: f.Description,
+			Severity:    models.FindingSeverity(f.Severity),
+			APIInfoID:   uint32(g.APIInfoID),
+			APIName:     g.APIName,
+			APIPort:     g.APIPort,
+			Namespace:   g.Namespace,
+			LastEventID: uint32(g.LastEventID),
+			Method:      g.Method,
+			Path:        g.Path,
+			SpecPath:    g.SpecPath,
+			Count:       g.Count,
+			FirstSeen:   g.FirstSeen,
+			LastSeen:    g.LastSeen,
+		})
+	}
+
+	for _, a := range apiFindings {
+		f, ok := describers.FindingsDescriber(a.ModuleName).DescribeAPIFinding(a.APIID, modules.Annotation{Name: a.Name, Annotation: a.Annotation})
+		if !ok {
+			continue
+		}
+		findings = append(findings, &models.Finding{
+			Module:      a.ModuleName,
+			Kind:        a.Name,
+			Name:        f.Name,
+			Description: f.Description,
+			Severity:    models.FindingSeverity(f.Severity),
+			APIInfoID:   uint32(a.APIID),
+			APIName:     a.APIName,
+			APIPort:     a.APIPort,
+			Namespace:   a.Namespace,
+			Count:       1,
+			FirstSeen:   strfmt.DateTime(a.CreatedAt),
+			LastSeen:    strfmt.DateTime(a.UpdatedAt),
+		})
+	}
+
+	return findings
+}
+
+func filterFindingsSeverity(findings []*models.Finding, severities []string) []*models.Finding {
+	if len(severities) == 0 {
+		return findings
+	}
+
+	filtered := []*models.Finding{}
+	for _, f := range findings {
+		for _, severity := range severities {
+			if string(f.Severity) == severity {
+				filtered = append(filtered, f)
+				break
+			}
+		}
+	}
+	return filtered
+}
+
+// sortFindings sorts the findings by the sort key, the ties are sorted by severity and then by last seen time,
+// the most recent and severe first.
+func sortFindings(findings []*models.Finding, sortKey models.FindingSortKey, sortDir string) {
+	desc := strings.EqualFold(sortDir, "DESC")
+	sort.SliceStable(findings, func(i, j int) bool {
+		a, b := findings[i], findings[j]
+		if c := compareFindings(a, b, sortKey); c != 0 {
+			if desc {
+				return c > 0
+			}
+			return c < 0
+		}
+		if c := compareFindings(a, b, models.FindingSortKeySeverity); c != 0 {
+			return c > 0
+		}
+		if c := compareFindings(a, b, models.FindingSortKeyLastSeen); c != 0 {
+			return c > 0
+		}
+		if a.Module != b.Module {
+			return a.Module < b.Module
+		}
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		return a.APIInfoID < b.APIInfoID
+	})
+}
+
+func compareFindings(a, b *models.Finding, sortKey models.FindingSortKey) int {
+	switch sortKey {
+	case models.FindingSortKeySeverity:
+		return modules.FindingSeverityLevel(string(a.Severity)) - modules.FindingSeverityLevel(string(b.Severity))
+	case models.FindingSortKeyCount:
+		switch {
+		case a.Count < b.Count:
+			return -1
+		case a.Count > b.Count:
+			return 1
+		}
+		return 0
+	case models.FindingSortKeyFirstSeen:
+		return compareTime(time.Time(a.FirstSeen), time.Time(b.FirstSeen))
+	case models.FindingSortKeyLastSeen:
+		return compareTime(time.Time(a.LastSeen), time.Time(b.LastSeen))
+	}
+	log.Warnf("Unknown findings sort key: %s", sortKey)
+	return 0
+}
+
+func compareTime(a, b time.Time) int {
+	switch {
+	case a.Before(b):
+		return -1
+	case a.After(b):
+		return 1
+	}
+	return 0
+}
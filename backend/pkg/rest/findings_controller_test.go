@@ -0,0 +1,149 @@
+// Copyright © 2022 Cisco Systems, Inc. and its affiliates.
+// All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rest
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/go-openapi/strfmt"
+
+	"github.com/openclarity/apiclarity/api/server/models"
+	"github.com/openclarity/apiclarity/backend/pkg/database"
+	"github.com/openclarity/apiclarity/backend/pkg/modules"
+)
+
+func Test_describeFindings(t *testing.T) {
+	now := time.Now().UTC()
+	eventGroups := []*database.EventFindingsGroup{
+		{
+			ModuleName: "mod", Name: "WEAK_JWT", Annotation: []byte("weak"), APIInfoID: 1, Count: 3,
+			FirstSeen: strfmt.DateTime(now.Add(-time.Hour)), LastSeen: strfmt.DateTime(now), LastEventID: 7,
+			Method: models.HTTPMethodGET, Path: "/users/1", APIName: "users", Namespace: "prod",
+			// an annotation of the group is more severe than the last one
+			Severity: 2,
+		},
+		{ModuleName: "mod", Name: "ALERT", APIInfoID: 1, Count: 3},
+	}
+	apiFindings := []*database.APIFinding{
+		{ModuleName: "mod", Name: "GUESSABLE_ID", APIID: 2, CreatedAt: now.Add(-time.Hour), UpdatedAt: now, APIName: "orders"},
+	}
+
+	got := describeFindings(modules.GetFindingsDescribers(nil), eventGroups, apiFindings)
+	want := []*models.Finding{
+		{
+			Module: "mod", Kind: "WEAK_JWT", Name: "WEAK_JWT", Description: "weak", Severity: models.FindingSeverityMEDIUM,
+			APIInfoID: 1, APIName: "users", Namespace: "prod", LastEventID: 7, Method: models.HTTPMethodGET, Path: "/users/1",
+			Count: 3, FirstSeen: strfmt.DateTime(now.Add(-time.Hour)), LastSeen: strfmt.DateTime(now),
+		},
+		{
+			Module: "mod", Kind: "GUESSABLE_ID", Name: "GUESSABLE_ID", Severity: models.FindingSeverityINFO,
+			APIInfoID: 2, APIName: "orders", Count: 1, FirstSeen: strfmt.DateTime(now.Add(-time.Hour)), LastSeen: strfmt.DateTime(now),
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("describeFindings() = %v, want %v", marshal(got), marshal(want))
+	}
+}
+
+func Test_sortFindings(t *testing.T) {
+	now := time.Now().UTC()
+	findings := []*models.Finding{
+		{Kind: "a", Severity: models.FindingSeverityLOW, Count: 5, LastSeen: strfmt.DateTime(now)},
+		{Kind: "b", Severity: models.FindingSeverityHIGH, Count: 1, LastSeen: strfmt.DateTime(now.Add(-time.Hour))},
+		{Kind: "c", Severity: models.FindingSeverityHIGH, Count: 5, LastSeen: strfmt.DateTime(now.Add(-time.Minute))},
+	}
+	kinds := func() []string {
+		var kinds []string
+		for _, f := range findings {
+			kinds = append(kinds, f.Kind)
+		}
+		return kinds
+	}
+
+	tests := []struct {
+		sortKey models.FindingSortKey
+		sortDir string
+		want    []string
+	}{
+		{sortKey: models.FindingSortKeyLastSeen, sortDir: "DESC", want: []string{"a", "c", "b"}},
+		{sortKey: models.FindingSortKeySeverity, sortDir: "DESC", want: []string{"c", "b", "a"}},
+		{sortKey: models.FindingSortKeySeverity, sortDir: "ASC", want: []string{"a", "c", "b"}},
+		// the ties are sorted by severity
+		{sortKey: models.FindingSortKeyCount, sortDir: "DESC", want: []string{"c", "a", "b"}},
+	}
+	for _, tt := range tests {
+		sortFindings(findings, tt.sortKey, tt.sortDir)
+		if got := kinds(); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("sortFindings(%v, %v) = %v, want %v", tt.sortKey, tt.sortDir, got, tt.want)
+		}
+	}
+
+	if got := filterFindingsSeverity(findings, []string{"LOW"}); len(got) != 1 || got[0].Kind != "a" {
+		t.Errorf("filterFindingsSeverity() = %v", marshal(got))
+	}
+}
+
+func Test_findingsReader(t *testing.T) {
+	// the second batch has no findings, the annotations were not findings
+	batches := [][]*models.Finding{make([]*models.Finding, findingsBatchSize), {}, {{Kind: "last"}}}
+	for i := range batches[0] {
+		batches[0][i] = &models.Finding{Kind: "first"}
+	}
+	r := &findingsReader{read: func(offset int) ([]*models.Finding, bool, error) {
+		i := offset / findingsBatchSize
+		return batches[i], i < len(batches)-1, nil
+	}}
+
+	var kinds []string
+	for {
+		f, err := r.peek()
+		if err != nil {
+			t.Fatalf("peek() error = %v", err)
+		}
+		if f == nil {
+			break
+		}
+		kinds = append(kinds, r.next().Kind)
+	}
+	if len(kinds) != findingsBatchSize+1 || kinds[len(kinds)-1] != "last" {
+		t.Errorf("read %d findings, the last one %v", len(kinds), kinds[len(kinds)-1])
+	}
+}
+
+func Test_isFindingBefore(t *testing.T) {
+	now := time.Now().UTC()
+	recent := &models.Finding{Severity: models.FindingSeverityLOW, Count: 1, FirstSeen: strfmt.DateTime(now.Add(-time.Hour)), LastSeen: strfmt.DateTime(now)}
+	old := &models.Finding{Severity: models.FindingSeverityHIGH, Count: 5, FirstSeen: strfmt.DateTime(now.Add(-2 * time.Hour)), LastSeen: strfmt.DateTime(now.Add(-time.Hour))}
+
+	tests := []struct {
+		page database.FindingsPage
+		want bool
+	}{
+		{page: database.FindingsPage{}, want: true},
+		{page: database.FindingsPage{SortKey: models.FindingSortKeyLastSeen}, want: false},
+		{page: database.FindingsPage{SortKey: models.FindingSortKeyCount, Desc: true}, want: false},
+		{page: database.FindingsPage{SortKey: models.FindingSortKeyFirstSeen, Desc: true}, want: true},
+		{page: database.FindingsPage{SortKey: models.FindingSortKeySeverity, Desc: true}, want: false},
+		{page: database.FindingsPage{SortKey: models.FindingSortKeySeverity}, want: true},
+	}
+	for _, tt := range tests {
+		if got := isFindingBefore(recent, old, tt.page); got != tt.want {
+			t.Errorf("isFindingBefore(%+v) = %v, want %v", tt.page, got, tt.want)
+		}
+	}
+}
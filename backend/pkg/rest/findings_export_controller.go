@@ -0,0 +1,389 @@
+// Copyright © 2022 Cisco Systems, Inc. and its affiliates.
+// All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rest
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-openapi/runtime"
+	"github.com/go-openapi/runtime/middleware"
+	"github.com/go-openapi/strfmt"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/openclarity/apiclarity/api/server/models"
+	"github.com/openclarity/apiclarity/api/server/restapi/operations"
+	"github.com/openclarity/apiclarity/backend/pkg/database"
+	"github.com/openclarity/apiclarity/backend/pkg/modules"
+)
+
+const (
+	sarifVersion    = "2.1.0"
+	sarifSchema     = "https://json.schemastore.org/sarif-2.1.0.json"
+	sarifToolName   = "APIClarity"
+	sarifToolURI    = "https://github.com/openclarity/apiclarity"
+	findingsCSVName = "apiclarity-findings.csv"
+
+	// maxExportedFindings caps the findings of an export, the most severe are exported.
+	maxExportedFindings = 10000
+	// the headers of the exports with the number of the findings, more than exported if the export is truncated
+	findingsTotalHeader     = "X-Total-Count"
+	findingsTruncatedHeader = "X-Truncated"
+)
+
+// The SARIF 2.1.0 objects of the export, see https://docs.oasis-open.org/sarif/sarif/v2.1.0/sarif-v2.1.0.html
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool       sarifTool              `json:"tool"`
+	Results    []sarifResult          `json:"results"`
+	Properties map[string]interface{} `json:"properties,omitempty"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string                 `json:"id"`
+	Name             string                 `json:"name,omitempty"`
+	ShortDescription sarifMessage           `json:"shortDescription"`
+	Properties       map[string]interface{} `json:"properties,omitempty"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID              string                 `json:"ruleId"`
+	RuleIndex           int                    `json:"ruleIndex"`
+	Level               string                 `json:"level"`
+	Message             sarifMessage           `json:"message"`
+	Locations           []sarifLocation        `json:"locations"`
+	OccurrenceCount     int64                  `json:"occurrenceCount,omitempty"`
+	PartialFingerprints map[string]string      `json:"partialFingerprints,omitempty"`
+	Properties          map[string]interface{} `json:"properties,omitempty"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation  `json:"physicalLocation"`
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations,omitempty"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifLogicalLocation struct {
+	Name               string `json:"name"`
+	FullyQualifiedName string `json:"fullyQualifiedName,omitempty"`
+	Kind               string `json:"kind"`
+}
+
+// sarifLevels maps the severities of the findings to the SARIF levels.
+var sarifLevels = map[models.FindingSeverity]string{
+	models.FindingSeverityINFO:     "note",
+	models.FindingSeverityLOW:      "warning",
+	models.FindingSeverityMEDIUM:   "warning",
+	models.FindingSeverityHIGH:     "error",
+	models.FindingSeverityCRITICAL: "error",
+}
+
+// sarifSecuritySeverities are the scores of the severities, as used by the code scanning tools to rank the rules.
+var sarifSecuritySeverities = map[models.FindingSeverity]string{
+	models.FindingSeverityINFO:     "0.0",
+	models.FindingSeverityLOW:      "3.0",
+	models.FindingSeverityMEDIUM:   "5.5",
+	models.FindingSeverityHIGH:     "8.0",
+	models.FindingSeverityCRITICAL: "9.5",
+}
+
+var findingsCSVHeader = []string{
+	"module", "kind", "name", "severity", "description", "apiInfoId", "apiName", "apiPort", "namespace",
+	"method", "path", "specPath", "lastEventId", "count", "firstSeen", "lastSeen",
+}
+
+func (s *Server) GetFindingsExportSarif(params operations.GetFindingsExportSarifParams) middleware.Responder {
+	filters := createFindingsFilters(params.ModuleIs, params.KindIs, params.APIInfoIDIs, params.NamespaceIs, params.StartTime, params.EndTime)
+	findings, total, err := s.listExportedFindings(params.HTTPRequest.Context(), filters, params.SeverityIs)
+	if err != nil {
+		log.Error(err)
+		return operations.NewGetFindingsExportSarifDefault(http.StatusInternalServerError).WithPayload(&models.APIResponse{
+			Message: "Oops",
+		})
+	}
+
+	ok := operations.NewGetFindingsExportSarifOK().WithPayload(createSarifLog(findings, total))
+	return middleware.ResponderFunc(func(rw http.ResponseWriter, producer runtime.Producer) {
+		setExportHeaders(rw, len(findings), total)
+		ok.WriteResponse(rw, producer)
+	})
+}
+
+func (s *Server) GetFindingsExportCsv(params operations.GetFindingsExportCsvParams) middleware.Responder {
+	filters := createFindingsFilters(params.ModuleIs, params.KindIs, params.APIInfoIDIs, params.NamespaceIs, params.StartTime, params.EndTime)
+	findings, total, err := s.listExportedFindings(params.HTTPRequest.Context(), filters, params.SeverityIs)
+	if err != nil {
+		log.Error(err)
+		return operations.NewGetFindingsExportCsvDefault(http.StatusInternalServerError).WithPayload(&models.APIResponse{
+			Message: "Oops",
+		})
+	}
+
+	payload, err := createFindingsCSV(findings)
+	if err != nil {
+		log.Error(err)
+		return operations.NewGetFindingsExportCsvDefault(http.StatusInternalServerError).WithPayload(&models.APIResponse{
+			Message: "Oops",
+		})
+	}
+
+	ok := operations.NewGetFindingsExportCsvOK().WithPayload(payload)
+	return middleware.ResponderFunc(func(rw http.ResponseWriter, producer runtime.Producer) {
+		rw.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", findingsCSVName))
+		setExportHeaders(rw, len(findings), total)
+		ok.WriteResponse(rw, producer)
+	})
+}
+
+// listExportedFindings returns the most severe findings with the given severities up to maxExportedFindings, and
+// the number of all the findings. The findings are read by pages sorted by the database, the others are counted only.
+func (s *Server) listExportedFindings(ctx context.Context, filters database.FindingsFilters, severities []string) ([]*models.Finding, int64, error) {
+	findings := []*models.Finding{}
+	var total int64
+	page := database.FindingsPage{SortKey: models.FindingSortKeySeverity, Desc: true}
+	if err := s.walkFindings(ctx, filters, severities, page, func(f *models.Finding) {
+		if len(findings) < maxExportedFindings {
+			findings = append(findings, f)
+		}
+		total++
+	}); err != nil {
+		return nil, 0, err
+	}
+	if total > int64(len(findings)) {
+		log.Warnf("The export of the findings is truncated, %d findings of %d are exported", len(findings), total)
+	}
+	sortFindings(findings, models.FindingSortKeySeverity, "DESC")
+
+	return findings, total, nil
+}
+
+func setExportHeaders(rw http.ResponseWriter, exported int, total int64) {
+	rw.Header().Set(findingsTotalHeader, strconv.FormatInt(total, 10))
+	rw.Header().Set(findingsTruncatedHeader, strconv.FormatBool(total > int64(exported)))
+}
+
+// createSarifLog creates a SARIF log with a rule for each kind of finding of each module. The run tells if the
+// findings are truncated, out of the total number of findings.
+func createSarifLog(findings []*models.Finding, total int64) *sarifLog {
+	run := sarifRun{
+		Tool: sarifTool{
+			Driver: sarifDriver{
+				Name:           sarifToolName,
+				InformationURI: sarifToolURI,
+				Rules:          []sarifRule{},
+			},
+		},
+		Results: []sarifResult{},
+	}
+
+	ruleIndexes := map[string]int{}
+	for _, f := range findings {
+		ruleID := f.Module + "/" + f.Kind
+		ruleIndex, ok := ruleIndexes[ruleID]
+		if !ok {
+			ruleIndex = len(run.Tool.Driver.Rules)
+			ruleIndexes[ruleID] = ruleIndex
+			run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, sarifRule{
+				ID:               ruleID,
+				Name:             f.Kind,
+				ShortDescription: sarifMessage{Text: f.Name},
+				Properties: map[string]interface{}{
+					"module": f.Module,
+					"tags":   []string{"security"},
+				},
+			})
+		}
+		// the rule is as severe as its most severe finding
+		rule := &run.Tool.Driver.Rules[ruleIndex]
+		if current, ok := rule.Properties["severity"].(models.FindingSeverity); !ok ||
+			modules.FindingSeverityLevel(string(f.Severity)) > modules.FindingSeverityLevel(string(current)) {
+			rule.Properties["severity"] = f.Severity
+			rule.Properties["security-severity"] = sarifSecuritySeverities[f.Severity]
+		}
+
+		run.Results = append(run.Results, createSarifResult(f, ruleID, ruleIndex))
+	}
+
+	if total > int64(len(findings)) {
+		run.Properties = map[string]interface{}{
+			"truncated":     true,
+			"totalFindings": total,
+		}
+	}
+
+	return &sarifLog{
+		Schema:  sarifSchema,
+		Version: sarifVersion,
+		Runs:    []sarifRun{run},
+	}
+}
+
+func createSarifResult(f *models.Finding, ruleID string, ruleIndex int) sarifResult {
+	level, ok := sarifLevels[f.Severity]
+	if !ok {
+		level = "none"
+	}
+	message := f.Description
+	if message == "" {
+		message = f.Name
+	}
+	path := f.SpecPath
+	if path == "" {
+		path = f.Path
+	}
+	host := getFindingHost(f)
+	location := sarifLocation{
+		PhysicalLocation: sarifPhysicalLocation{
+			ArtifactLocation: sarifArtifactLocation{
+				URI: (&url.URL{Scheme: "http", Host: host, Path: path}).String(),
+			},
+		},
+	}
+	if path != "" {
+		operation := path
+		if f.Method != "" {
+			operation = string(f.Method) + " " + path
+		}
+		location.LogicalLocations = []sarifLogicalLocation{
+			{Name: operation, FullyQualifiedName: host + " " + operation, Kind: "resource"},
+		}
+	}
+
+	return sarifResult{
+		RuleID:          ruleID,
+		RuleIndex:       ruleIndex,
+		Level:           level,
+		Message:         sarifMessage{Text: message},
+		Locations:       []sarifLocation{location},
+		OccurrenceCount: f.Count,
+		// the grouped findings are identified by the module, the kind, the API and the spec path
+		PartialFingerprints: map[string]string{
+			"apiclarityFinding/v1": fmt.Sprintf("%s/%d/%s %s", ruleID, f.APIInfoID, f.Method, path),
+		},
+		Properties: map[string]interface{}{
+			"severity":    f.Severity,
+			"apiInfoId":   f.APIInfoID,
+			"apiName":     f.APIName,
+			"namespace":   f.Namespace,
+			"lastEventId": f.LastEventID,
+			"firstSeen":   f.FirstSeen,
+			"lastSeen":    f.LastSeen,
+		},
+	}
+}
+
+func getFindingHost(f *models.Finding) string {
+	if f.APIPort == 0 {
+		return f.APIName
+	}
+	return f.APIName + ":" + strconv.FormatInt(f.APIPort, 10)
+}
+
+func createFindingsCSV(findings []*models.Finding) (string, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.Write(findingsCSVHeader); err != nil {
+		return "", fmt.Errorf("failed to write the CSV header: %v", err)
+	}
+	for _, f := range findings {
+		record := []string{
+			f.Module,
+			f.Kind,
+			f.Name,
+			string(f.Severity),
+			f.Description,
+			strconv.FormatUint(uint64(f.APIInfoID), 10),
+			f.APIName,
+			strconv.FormatInt(f.APIPort, 10),
+			f.Namespace,
+			string(f.Method),
+			f.Path,
+			f.SpecPath,
+			formatOptionalID(f.LastEventID),
+			strconv.FormatInt(f.Count, 10),
+			formatCSVTime(f.FirstSeen),
+			formatCSVTime(f.LastSeen),
+		}
+		for i := range record {
+			record[i] = escapeCSVFormula(record[i])
+		}
+		if err := w.Write(record); err != nil {
+			return "", fmt.Errorf("failed to write the CSV record: %v", err)
+		}
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return "", fmt.Errorf("failed to write the CSV: %v", err)
+	}
+
+	return buf.String(), nil
+}
+
+// escapeCSVFormula prefixes the values that a spreadsheet would take for a formula with a quote. The values come
+// from the traffic, e.g. the paths, a crafted request must not run a formula when the export is opened.
+func escapeCSVFormula(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+func formatOptionalID(id uint32) string {
+	if id == 0 {
+		return ""
+	}
+	return strconv.FormatUint(uint64(id), 10)
+}
+
+func formatCSVTime(t strfmt.DateTime) string {
+	if time.Time(t).IsZero() {
+		return ""
+	}
+	return time.Time(t).UTC().Format(time.RFC3339)
+}
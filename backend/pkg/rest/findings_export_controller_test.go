@@ -0,0 +1,141 @@
+// Copyright © 2022 Cisco Systems, Inc. and its affiliates.
+// All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rest
+
+import (
+	"encoding/csv"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-openapi/strfmt"
+
+	"github.com/openclarity/apiclarity/api/server/models"
+)
+
+func testExportedFindings(now time.Time) []*models.Finding {
+	return []*models.Finding{
+		{
+			Module: "traceanalyzer", Kind: "JWT_WEAK_SYMETRIC_SECRET", Name: "Weak JWT secret", Description: "The secret is weak",
+			Severity: models.FindingSeverityHIGH, APIInfoID: 1, APIName: "users.prod", APIPort: 8080, Namespace: "prod",
+			LastEventID: 7, Method: models.HTTPMethodGET, Path: "/users/1", SpecPath: "/users/{id}", Count: 3,
+			FirstSeen: strfmt.DateTime(now.Add(-time.Hour)), LastSeen: strfmt.DateTime(now),
+		},
+		{
+			Module: "traceanalyzer", Kind: "JWT_WEAK_SYMETRIC_SECRET", Name: "Weak JWT secret", Description: "The secret is weak",
+			Severity: models.FindingSeverityLOW, APIInfoID: 2, APIName: "orders", APIPort: 80,
+			LastEventID: 9, Method: models.HTTPMethodPOST, Path: "/orders", Count: 1,
+			FirstSeen: strfmt.DateTime(now), LastSeen: strfmt.DateTime(now),
+		},
+		{
+			Module: "traceanalyzer", Kind: "GUESSABLE_ID", Name: "Guessable ID", Severity: models.FindingSeverityINFO,
+			APIInfoID: 2, APIName: "orders", APIPort: 80, Count: 1,
+			FirstSeen: strfmt.DateTime(now), LastSeen: strfmt.DateTime(now),
+		},
+	}
+}
+
+func Test_createSarifLog(t *testing.T) {
+	findings := testExportedFindings(time.Now().UTC())
+	log := createSarifLog(findings, int64(len(findings)))
+
+	if log.Version != sarifVersion || len(log.Runs) != 1 {
+		t.Fatalf("createSarifLog() = %v", marshal(log))
+	}
+	run := log.Runs[0]
+	if len(run.Tool.Driver.Rules) != 2 || len(run.Results) != 3 || run.Properties != nil {
+		t.Fatalf("createSarifLog() = %v", marshal(log))
+	}
+	rule := run.Tool.Driver.Rules[0]
+	if rule.ID != "traceanalyzer/JWT_WEAK_SYMETRIC_SECRET" || rule.Properties["security-severity"] != "8.0" {
+		t.Errorf("rule = %v", marshal(rule))
+	}
+
+	var levels, uris []string
+	for _, r := range run.Results {
+		levels = append(levels, r.Level)
+		uris = append(uris, r.Locations[0].PhysicalLocation.ArtifactLocation.URI)
+	}
+	if want := []string{"error", "warning", "note"}; !reflect.DeepEqual(levels, want) {
+		t.Errorf("levels = %v, want %v", levels, want)
+	}
+	if want := []string{"http://users.prod:8080/users/%7Bid%7D", "http://orders:80/orders", "http://orders:80"}; !reflect.DeepEqual(uris, want) {
+		t.Errorf("uris = %v, want %v", uris, want)
+	}
+	if r := run.Results[0]; r.RuleIndex != 0 || r.OccurrenceCount != 3 || r.Locations[0].LogicalLocations[0].Name != "GET /users/{id}" {
+		t.Errorf("result = %v", marshal(r))
+	}
+	if r := run.Results[2]; r.RuleIndex != 1 || r.Message.Text != "Guessable ID" || len(r.Locations[0].LogicalLocations) != 0 {
+		t.Errorf("result = %v", marshal(r))
+	}
+}
+
+func Test_createSarifLog_Truncated(t *testing.T) {
+	findings := testExportedFindings(time.Now().UTC())
+	log := createSarifLog(findings, 10)
+
+	if props := log.Runs[0].Properties; props["truncated"] != true || props["totalFindings"] != int64(10) {
+		t.Errorf("properties = %v", props)
+	}
+}
+
+func Test_createFindingsCSV(t *testing.T) {
+	now := time.Date(2022, 5, 1, 10, 0, 0, 0, time.UTC)
+	payload, err := createFindingsCSV(testExportedFindings(now))
+	if err != nil {
+		t.Fatalf("createFindingsCSV() error = %v", err)
+	}
+
+	records, err := csv.NewReader(strings.NewReader(payload)).ReadAll()
+	if err != nil {
+		t.Fatalf("invalid CSV: %v", err)
+	}
+	if len(records) != 4 || !reflect.DeepEqual(records[0], findingsCSVHeader) {
+		t.Fatalf("createFindingsCSV() = %v", records)
+	}
+	want := []string{
+		"traceanalyzer", "JWT_WEAK_SYMETRIC_SECRET", "Weak JWT secret", "HIGH", "The secret is weak", "1", "users.prod", "8080", "prod",
+		"GET", "/users/1", "/users/{id}", "7", "3", "2022-05-01T09:00:00Z", "2022-05-01T10:00:00Z",
+	}
+	if !reflect.DeepEqual(records[1], want) {
+		t.Errorf("createFindingsCSV() record = %v, want %v", records[1], want)
+	}
+	if lastEventID := records[3][12]; lastEventID != "" {
+		t.Errorf("createFindingsCSV() lastEventId of an API finding = %q", lastEventID)
+	}
+
+	// the values taken for formulas are escaped
+	payload, err = createFindingsCSV([]*models.Finding{{Module: "mod", Kind: "NLID", Path: "=HYPERLINK(\"http://example.com\")", Description: "@SUM(1)"}})
+	if err != nil {
+		t.Fatalf("createFindingsCSV() error = %v", err)
+	}
+	if records, err = csv.NewReader(strings.NewReader(payload)).ReadAll(); err != nil || len(records) != 2 ||
+		records[1][10] != "'=HYPERLINK(\"http://example.com\")" || records[1][4] != "'@SUM(1)" || records[1][0] != "mod" {
+		t.Errorf("createFindingsCSV() = %v, %v", records, err)
+	}
+}
+
+func Test_escapeCSVFormula(t *testing.T) {
+	for value, want := range map[string]string{
+		"=1+2": "'=1+2", "+1": "'+1", "-1": "'-1", "@A1": "'@A1", "\tx": "'\tx", "\rx": "'\rx",
+		"": "", "/users/1": "/users/1", "a=b": "a=b",
+	} {
+		if got := escapeCSVFormula(value); got != want {
+			t.Errorf("escapeCSVFormula(%q) = %q, want %q", value, got, want)
+		}
+	}
+}
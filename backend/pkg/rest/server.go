@@ -20,6 +20,7 @@ import (
 	"net/http"
 
 	"github.com/go-openapi/loads"
+	"github.com/go-openapi/runtime"
 	"github.com/go-openapi/runtime/middleware"
 	log "github.com/sirupsen/logrus"
 
@@ -35,12 +36,18 @@ type Server struct {
 	server     *restapi.Server
 	dbHandler  database.Database
 	speculator *_speculator.Speculator
+	retention  *database.Retention
+
+	findingsDescribers modules.FindingsDescribers
 }
 
-func CreateRESTServer(port int, speculator *_speculator.Speculator, dbHandler *database.Handler, modules modules.Module) (*Server, error) {
+func CreateRESTServer(port int, speculator *_speculator.Speculator, dbHandler *database.Handler, module modules.Module, retention *database.Retention) (*Server, error) {
 	s := &Server{
 		speculator: speculator,
 		dbHandler:  dbHandler,
+		retention:  retention,
+
+		findingsDescribers: modules.GetFindingsDescribers(module),
 	}
 
 	swaggerSpec, err := loads.Embedded(restapi.SwaggerJSON, restapi.FlatSwaggerJSON)
@@ -126,6 +133,25 @@ func CreateRESTServer(port int, speculator *_speculator.Speculator, dbHandler *d
 		return s.DeleteAPIInventoryAPIIDSpecsReconstructedSpec(params)
 	})
 
+	api.GetDataRetentionStatusHandler = operations.GetDataRetentionStatusHandlerFunc(func(params operations.GetDataRetentionStatusParams) middleware.Responder {
+		return s.GetDataRetentionStatus(params)
+	})
+
+	api.GetFindingsHandler = operations.GetFindingsHandlerFunc(func(params operations.GetFindingsParams) middleware.Responder {
+		return s.GetFindings(params)
+	})
+
+	api.GetFindingsExportSarifHandler = operations.GetFindingsExportSarifHandlerFunc(func(params operations.GetFindingsExportSarifParams) middleware.Responder {
+		return s.GetFindingsExportSarif(params)
+	})
+
+	api.GetFindingsExportCsvHandler = operations.GetFindingsExportCsvHandlerFunc(func(params operations.GetFindingsExportCsvParams) middleware.Responder {
+		return s.GetFindingsExportCsv(params)
+	})
+
+	// the CSV export is already formatted
+	api.CsvProducer = runtime.TextProducer()
+
 	server := restapi.NewServer(api)
 
 	server.ConfigureFlags()
@@ -136,7 +162,7 @@ func CreateRESTServer(port int, speculator *_speculator.Speculator, dbHandler *d
 	newHandler := http.NewServeMux()
 
 	// Enhance the default handler with modules apis handlers
-	newHandler.Handle("/api/modules/", modules.HTTPHandler())
+	newHandler.Handle("/api/modules/", module.HTTPHandler())
 	newHandler.Handle("/", origHandler)
 	server.SetHandler(newHandler)
 	s.server = server
@@ -0,0 +1,302 @@
+// Copyright © 2022 Cisco Systems, Inc. and its affiliates.
+// All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package risk
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/openclarity/apiclarity/api/server/models"
+	"github.com/openclarity/apiclarity/backend/pkg/database"
+	"github.com/openclarity/apiclarity/backend/pkg/modules"
+	"github.com/openclarity/apiclarity/backend/pkg/utils"
+	pluginsmodels "github.com/openclarity/apiclarity/plugins/api/server/models"
+)
+
+const (
+	MaxScore = 100
+
+	bflaModuleName = "bfla"
+
+	// the points of each open finding, by severity
+	criticalFindingPoints = 25
+	highFindingPoints     = 10
+	mediumFindingPoints   = 4
+	lowFindingPoints      = 1
+	// the points of each BFLA violation, which are not counted by severity
+	bflaViolationPoints = 15
+	// the points of each shadow or zombie diff event, up to the max
+	specDiffEventPoints    = 3
+	maxSpecDiffEventPoints = 15
+	// the points when all the successful requests are unauthenticated
+	unauthenticatedPoints = 20
+	// the score of the external APIs is increased
+	externalExposureFactor = 1.5
+	// the occurrences of a finding add up to this times its points, by half of the log10 of the occurrences: a
+	// finding which occurs 10 times counts 1.5 times, 100 times or more twice
+	maxOccurrencesBonus = 1
+)
+
+var findingPoints = map[models.FindingSeverity]float64{
+	models.FindingSeverityCRITICAL: criticalFindingPoints,
+	models.FindingSeverityHIGH:     highFindingPoints,
+	models.FindingSeverityMEDIUM:   mediumFindingPoints,
+	models.FindingSeverityLOW:      lowFindingPoints,
+}
+
+// Factors are the inputs of the risk score of an API.
+type Factors struct {
+	// the open findings by severity, the BFLA violations excluded, weighted by OccurrencesWeight
+	Findings       map[models.FindingSeverity]float64
+	BFLAViolations float64
+	database.APIRiskFactors
+	External bool
+}
+
+// OccurrencesWeight is the weight of a finding which occurs the given number of times: each distinct finding counts
+// once, with a bonus for its occurrences, so that a finding found again and again doesn't max the score.
+func OccurrencesWeight(occurrences int64) float64 {
+	if occurrences <= 1 {
+		return 1
+	}
+	return 1 + math.Min(math.Log10(float64(occurrences))/2, maxOccurrencesBonus)
+}
+
+// Score computes the risk score of the API, from 0 to MaxScore.
+func Score(f Factors) int64 {
+	var score float64
+	for severity, weight := range f.Findings {
+		score += findingPoints[severity] * weight
+	}
+	score += bflaViolationPoints * f.BFLAViolations
+	// the counters may be negative for a while, when the retention deletes events not added yet
+	score += math.Min(specDiffEventPoints*math.Max(float64(f.ShadowDiffEvents), 0), maxSpecDiffEventPoints)
+	score += math.Min(specDiffEventPoints*math.Max(float64(f.ZombieDiffEvents), 0), maxSpecDiffEventPoints)
+	if f.SuccessfulEvents > 0 && f.UnauthenticatedEvents > 0 {
+		score += unauthenticatedPoints * math.Min(float64(f.UnauthenticatedEvents)/float64(f.SuccessfulEvents), 1)
+	}
+	if f.External {
+		score *= externalExposureFactor
+	}
+
+	return int64(math.Round(math.Min(score, MaxScore)))
+}
+
+// HasCredentials returns true if the request headers hold credentials.
+func HasCredentials(headers []*pluginsmodels.Header) bool {
+	for _, h := range headers {
+		if h != nil && h.Value != "" && utils.IsCredentialsHeader(h.Key) {
+			return true
+		}
+	}
+	return false
+}
+
+// Scorer accumulates the risk factors of the events, and periodically updates the risk scores of the APIs of the
+// new events, and of the APIs whose findings or events changed. The findings of the modules are counted when the
+// score is updated.
+type Scorer struct {
+	dbHandler  database.Database
+	describers modules.FindingsDescribers
+	interval   time.Duration
+
+	lock sync.Mutex
+	// the risk factors of the new events, by API
+	pending map[uint]*database.APIRiskFactors
+}
+
+func NewScorer(dbHandler database.Database, describers modules.FindingsDescribers, interval time.Duration) *Scorer {
+	return &Scorer{
+		dbHandler:  dbHandler,
+		describers: describers,
+		interval:   interval,
+		pending:    map[uint]*database.APIRiskFactors{},
+	}
+}
+
+// EventNotify adds the risk factors of the event to its API, the score is updated on the next run.
+func (s *Scorer) EventNotify(event *database.APIEvent) {
+	if s == nil || event.APIInfoID == 0 {
+		return
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.addPending(database.NewAPIRiskFactors(event))
+}
+
+// FindingsChanged updates the score of the API on the next run, once its findings changed other than by a new
+// event, e.g. they were suppressed, approved or deleted, or its events were pruned.
+func (s *Scorer) FindingsChanged(apiID uint) {
+	if s == nil || apiID == 0 {
+		return
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.addPending(database.APIRiskFactors{APIID: apiID})
+}
+
+// addPending adds the risk factors to the pending ones of the API, the lock must be held.
+func (s *Scorer) addPending(factors database.APIRiskFactors) {
+	pending, ok := s.pending[factors.APIID]
+	if !ok {
+		pending = &database.APIRiskFactors{APIID: factors.APIID}
+		s.pending[factors.APIID] = pending
+	}
+	pending.SuccessfulEvents += factors.SuccessfulEvents
+	pending.UnauthenticatedEvents += factors.UnauthenticatedEvents
+	pending.ShadowDiffEvents += factors.ShadowDiffEvents
+	pending.ZombieDiffEvents += factors.ZombieDiffEvents
+}
+
+// Start updates the scores of all the APIs, then periodically the scores of the APIs of the new events.
+func (s *Scorer) Start(ctx context.Context) {
+	go func() {
+		if err := s.UpdateAll(ctx); err != nil {
+			log.Errorf("Failed to update the risk scores: %v", err)
+		}
+		for {
+			select {
+			case <-ctx.Done():
+				log.Debugf("Stopping risk scores updates")
+				return
+			case <-time.After(s.interval):
+				if err := s.Update(ctx); err != nil {
+					log.Errorf("Failed to update the risk scores: %v", err)
+				}
+			}
+		}
+	}()
+}
+
+// Update stores the risk factors of the new events and updates the scores of their APIs.
+func (s *Scorer) Update(ctx context.Context) error {
+	s.lock.Lock()
+	pending := s.pending
+	s.pending = map[uint]*database.APIRiskFactors{}
+	s.lock.Unlock()
+
+	if len(pending) == 0 {
+		return nil
+	}
+
+	factors := make([]database.APIRiskFactors, 0, len(pending))
+	for _, f := range pending {
+		factors = append(factors, *f)
+	}
+	if err := s.dbHandler.APIRiskFactorsTable().Add(ctx, factors...); err != nil {
+		// the factors are added on the next run
+		s.lock.Lock()
+		for _, f := range pending {
+			s.addPending(*f)
+		}
+		s.lock.Unlock()
+		return err
+	}
+
+	for apiID := range pending {
+		if err := s.UpdateAPI(ctx, apiID); err != nil {
+			// the scores of the other APIs are still updated
+			log.Errorf("Failed to update the risk score of API %d: %v", apiID, err)
+		}
+	}
+
+	return nil
+}
+
+// UpdateAll updates the scores of all the APIs.
+func (s *Scorer) UpdateAll(ctx context.Context) error {
+	apiIDs, err := s.dbHandler.APIInventoryTable().GetAPIIDs(ctx)
+	if err != nil {
+		return err
+	}
+	for _, apiID := range apiIDs {
+		if err := s.UpdateAPI(ctx, apiID); err != nil {
+			log.Errorf("Failed to update the risk score of API %d: %v", apiID, err)
+		}
+	}
+
+	return nil
+}
+
+// UpdateAPI computes and stores the risk score of the API.
+func (s *Scorer) UpdateAPI(ctx context.Context, apiID uint) error {
+	apiInfo := &database.APIInfo{}
+	if err := s.dbHandler.APIInventoryTable().First(apiInfo, apiID); err != nil {
+		return fmt.Errorf("failed to get API %d: %v", apiID, err)
+	}
+	riskFactors, err := s.dbHandler.APIRiskFactorsTable().Get(ctx, apiID)
+	if err != nil {
+		return err
+	}
+	factors := Factors{
+		APIRiskFactors: *riskFactors,
+		External:       apiInfo.Type == models.APITypeEXTERNAL,
+	}
+	if err := s.countFindings(ctx, apiID, &factors); err != nil {
+		return err
+	}
+
+	score := Score(factors)
+	if score == apiInfo.RiskScore {
+		return nil
+	}
+	log.Debugf("Risk score of API %d: %d, factors: %+v", apiID, score, factors)
+
+	return s.dbHandler.APIInventoryTable().SetRiskScore(ctx, apiID, score)
+}
+
+// countFindings weights the open findings of the API by severity, as described by their modules. The findings of the
+// events are weighted by their occurrences, those of the API occur once.
+func (s *Scorer) countFindings(ctx context.Context, apiID uint, factors *Factors) error {
+	filters := database.FindingsFilters{APIInfoIDIs: []uint32{uint32(apiID)}}
+	eventGroups, err := s.dbHandler.APIEventsAnnotationsTable().ListFindingsGroups(ctx, filters, database.FindingsPage{})
+	if err != nil {
+		return err
+	}
+	apiFindings, err := s.dbHandler.APIInfoAnnotationsTable().ListFindings(ctx, filters, database.FindingsPage{})
+	if err != nil {
+		return err
+	}
+
+	factors.Findings = map[models.FindingSeverity]float64{}
+	count := func(moduleName string, f modules.Finding, ok bool, occurrences int64) {
+		switch {
+		case !ok:
+		case moduleName == bflaModuleName:
+			factors.BFLAViolations += OccurrencesWeight(occurrences)
+		default:
+			factors.Findings[models.FindingSeverity(f.Severity)] += OccurrencesWeight(occurrences)
+		}
+	}
+	for _, g := range eventGroups {
+		lastEvent := &database.APIEvent{ID: g.LastEventID, APIInfoID: g.APIInfoID, Method: g.Method, Path: g.Path}
+		f, ok := s.describers.FindingsDescriber(g.ModuleName).DescribeEventFinding(lastEvent, modules.Annotation{Name: g.Name, Annotation: g.Annotation})
+		count(g.ModuleName, f, ok, g.Count)
+	}
+	for _, a := range apiFindings {
+		f, ok := s.describers.FindingsDescriber(a.ModuleName).DescribeAPIFinding(a.APIID, modules.Annotation{Name: a.Name, Annotation: a.Annotation})
+		count(a.ModuleName, f, ok, 1)
+	}
+
+	return nil
+}
@@ -0,0 +1,205 @@
+// Copyright © 2022 Cisco Systems, Inc. and its affiliates.
+// All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package risk
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-openapi/strfmt"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"github.com/openclarity/apiclarity/api/server/models"
+	"github.com/openclarity/apiclarity/backend/pkg/database"
+	"github.com/openclarity/apiclarity/backend/pkg/modules"
+	pluginsmodels "github.com/openclarity/apiclarity/plugins/api/server/models"
+)
+
+func TestScore(t *testing.T) {
+	tests := []struct {
+		name    string
+		factors Factors
+		want    int64
+	}{
+		{
+			name: "no risk",
+			want: 0,
+		},
+		{
+			name: "findings by severity",
+			factors: Factors{
+				Findings: map[models.FindingSeverity]float64{models.FindingSeverityHIGH: 2, models.FindingSeverityLOW: 3, models.FindingSeverityINFO: 10},
+			},
+			want: 23,
+		},
+		{
+			name: "a recurring finding counts twice at most",
+			factors: Factors{
+				Findings: map[models.FindingSeverity]float64{models.FindingSeverityMEDIUM: OccurrencesWeight(100000)},
+			},
+			want: 8,
+		},
+		{
+			name: "negative counters are ignored",
+			factors: Factors{
+				APIRiskFactors: database.APIRiskFactors{SuccessfulEvents: -1, UnauthenticatedEvents: 1, ShadowDiffEvents: -2},
+			},
+			want: 0,
+		},
+		{
+			name: "spec drift is capped",
+			factors: Factors{
+				APIRiskFactors: database.APIRiskFactors{ShadowDiffEvents: 100, ZombieDiffEvents: 1},
+			},
+			want: 18,
+		},
+		{
+			name: "unauthenticated requests of an external API",
+			factors: Factors{
+				BFLAViolations: 1,
+				APIRiskFactors: database.APIRiskFactors{SuccessfulEvents: 4, UnauthenticatedEvents: 1},
+				External:       true,
+			},
+			want: 30,
+		},
+		{
+			name: "score is capped",
+			factors: Factors{
+				Findings: map[models.FindingSeverity]float64{models.FindingSeverityCRITICAL: 5},
+			},
+			want: MaxScore,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Score(tt.factors); got != tt.want {
+				t.Errorf("Score() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOccurrencesWeight(t *testing.T) {
+	for occurrences, want := range map[int64]float64{0: 1, 1: 1, 10: 1.5, 100: 2, 1000: 2} {
+		if got := OccurrencesWeight(occurrences); got != want {
+			t.Errorf("OccurrencesWeight(%v) = %v, want %v", occurrences, got, want)
+		}
+	}
+}
+
+func TestHasCredentials(t *testing.T) {
+	if HasCredentials([]*pluginsmodels.Header{{Key: "Accept", Value: "*/*"}, {Key: "Authorization", Value: ""}}) {
+		t.Errorf("HasCredentials() = true without credentials")
+	}
+	if !HasCredentials([]*pluginsmodels.Header{{Key: "X-Api-Key", Value: "secret"}}) {
+		t.Errorf("HasCredentials() = false with an API key")
+	}
+}
+
+// severityDescriber describes the findings with the severity in their annotation.
+type severityDescriber struct{}
+
+func (severityDescriber) FindingsDescriber(string) modules.FindingsDescriber {
+	return severityDescriber{}
+}
+
+func (severityDescriber) DescribeEventFinding(_ *database.APIEvent, ann modules.Annotation) (modules.Finding, bool) {
+	return modules.Finding{Name: ann.Name, Severity: string(ann.Annotation)}, true
+}
+
+func (severityDescriber) DescribeAPIFinding(_ uint, ann modules.Annotation) (modules.Finding, bool) {
+	return modules.Finding{Name: ann.Name, Severity: string(ann.Annotation)}, true
+}
+
+func TestScorer(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "db.db")), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+	if err := db.AutoMigrate(&database.APIEvent{}, &database.APIInfo{}, &database.APIEventAnnotation{}, &database.APIInfoAnnotation{},
+		&database.APIRiskFactors{}); err != nil {
+		t.Fatalf("failed to migrate db: %v", err)
+	}
+	handler := &database.Handler{DB: db}
+	ctx := context.Background()
+
+	api := &database.APIInfo{Type: models.APITypeEXTERNAL, Name: "users", Port: 80}
+	if err := handler.APIInventoryTable().FirstOrCreate(api); err != nil {
+		t.Fatalf("failed to create API: %v", err)
+	}
+	unauthenticated, authenticated := true, false
+	event := &database.APIEvent{APIInfoID: api.ID, Time: strfmt.DateTime(time.Now().UTC()), Method: models.HTTPMethodGET, Path: "/users", StatusCode: 200,
+		Unauthenticated: &unauthenticated}
+	if err := db.Create(event).Error; err != nil {
+		t.Fatalf("failed to create event: %v", err)
+	}
+	if err := handler.APIEventsAnnotationsTable().Create(ctx, database.APIEventAnnotation{ModuleName: "mod", EventID: event.ID, Name: "WEAK", Annotation: []byte("HIGH")}); err != nil {
+		t.Fatalf("failed to create event annotation: %v", err)
+	}
+	if err := handler.APIInfoAnnotationsTable().UpdateOrCreate(ctx, database.APIInfoAnnotation{ModuleName: bflaModuleName, APIID: api.ID, Name: "VIOLATION"}); err != nil {
+		t.Fatalf("failed to create API annotation: %v", err)
+	}
+
+	scorer := NewScorer(handler, severityDescriber{}, time.Minute)
+	scorer.EventNotify(event)
+	scorer.EventNotify(&database.APIEvent{APIInfoID: api.ID, StatusCode: 200, SpecDiffType: models.DiffTypeSHADOWDIFF, Unauthenticated: &authenticated})
+	// the events stored before their authentication was recorded are not counted
+	scorer.EventNotify(&database.APIEvent{APIInfoID: api.ID, StatusCode: 200})
+	if err := scorer.Update(ctx); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+
+	factors, err := handler.APIRiskFactorsTable().Get(ctx, api.ID)
+	if err != nil || factors.SuccessfulEvents != 2 || factors.UnauthenticatedEvents != 1 || factors.ShadowDiffEvents != 1 {
+		t.Fatalf("risk factors = %+v, %v", factors, err)
+	}
+	if err := handler.APIInventoryTable().First(api, api.ID); err != nil {
+		t.Fatalf("failed to get API: %v", err)
+	}
+	// (10 for the HIGH finding + 15 for the BFLA violation + 3 for the shadow diff + 10 for half unauthenticated) * 1.5
+	if api.RiskScore != 57 {
+		t.Errorf("risk score = %v, want 57", api.RiskScore)
+	}
+
+	// the finding occurs in another event, e.g. found by a re-scan: the occurrence adds a bonus, not another finding
+	other := &database.APIEvent{APIInfoID: api.ID, Time: strfmt.DateTime(time.Now().UTC()), Method: models.HTTPMethodGET, Path: "/users", StatusCode: 200}
+	if err := db.Create(other).Error; err != nil {
+		t.Fatalf("failed to create event: %v", err)
+	}
+	if err := handler.APIEventsAnnotationsTable().Create(ctx, database.APIEventAnnotation{ModuleName: "mod", EventID: other.ID, Name: "WEAK", Annotation: []byte("HIGH")}); err != nil {
+		t.Fatalf("failed to create event annotation: %v", err)
+	}
+	scorer.FindingsChanged(api.ID)
+	if err := scorer.Update(ctx); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if factors, err := handler.APIRiskFactorsTable().Get(ctx, api.ID); err != nil || factors.SuccessfulEvents != 2 {
+		t.Fatalf("risk factors = %+v, %v after the findings changed", factors, err)
+	}
+	if err := handler.APIInventoryTable().First(api, api.ID); err != nil {
+		t.Fatalf("failed to get API: %v", err)
+	}
+	// (11.5 for the HIGH finding which occurred twice + 15 + 3 + 10) * 1.5
+	if api.RiskScore != 59 {
+		t.Errorf("risk score = %v, want 59 after the findings changed", api.RiskScore)
+	}
+}
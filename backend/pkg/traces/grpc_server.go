@@ -0,0 +1,181 @@
+// Copyright © 2022 Cisco Systems, Inc. and its affiliates.
+// All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package traces
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/status"
+
+	"github.com/openclarity/apiclarity/backend/pkg/common"
+	api "github.com/openclarity/apiclarity/plugins/api/grpc_gen/telemetries-service"
+	"github.com/openclarity/apiclarity/plugins/api/server/models"
+)
+
+type GRPCTracesServer struct {
+	api.UnimplementedTelemetriesServer
+
+	traceHandleFunc HandleTraceFunc
+	listener        net.Listener
+	server          *grpc.Server
+	port            int
+}
+
+func CreateGRPCTracesServer(port int, traceHandleFunc HandleTraceFunc, tlsOptions *TLSOptions) (*GRPCTracesServer, error) {
+	var opts []grpc.ServerOption
+	if tlsOptions != nil {
+		creds, err := credentials.NewServerTLSFromFile(tlsOptions.CertFile, tlsOptions.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load server certificate: %v", err)
+		}
+		opts = append(opts, grpc.Creds(creds))
+	}
+
+	s := &GRPCTracesServer{
+		traceHandleFunc: traceHandleFunc,
+		server:          grpc.NewServer(opts...),
+		port:            port,
+	}
+
+	api.RegisterTelemetriesServer(s.server, s)
+
+	return s, nil
+}
+
+func (s *GRPCTracesServer) Start(errChan chan struct{}) error {
+	log.Infof("Starting GRPC traces server")
+
+	listener, err := net.Listen("tcp", ":"+strconv.Itoa(s.port))
+	if err != nil {
+		return fmt.Errorf("GRPC traces server failed to listen: %v", err)
+	}
+	s.listener = listener
+
+	go func() {
+		if err := s.server.Serve(s.listener); err != nil && !errors.Is(err, grpc.ErrServerStopped) {
+			log.Errorf("Failed to serve GRPC traces server: %v", err)
+			errChan <- common.Empty
+		}
+	}()
+
+	return nil
+}
+
+func (s *GRPCTracesServer) Stop() {
+	log.Infof("Stopping GRPC traces server")
+	if s.server != nil {
+		s.server.GracefulStop()
+	}
+	if s.listener != nil {
+		_ = s.listener.Close()
+	}
+}
+
+func (s *GRPCTracesServer) PostTelemetry(ctx context.Context, telemetry *api.Telemetry) (*api.TelemetryResult, error) {
+	return s.handleTelemetry(ctx, telemetry), nil
+}
+
+func (s *GRPCTracesServer) StreamTelemetries(stream api.Telemetries_StreamTelemetriesServer) error {
+	ret := &api.StreamTelemetriesResult{}
+
+	for {
+		telemetry, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return stream.SendAndClose(ret)
+		}
+		if err != nil {
+			return status.Errorf(codes.Unknown, "failed to receive telemetry: %v", err)
+		}
+
+		ret.Received++
+		switch s.handleTelemetry(stream.Context(), telemetry).StatusCode {
+		case http.StatusOK:
+			ret.Accepted++
+		case http.StatusTooManyRequests:
+			ret.Rejected++
+		default:
+			ret.Failed++
+		}
+	}
+}
+
+func (s *GRPCTracesServer) handleTelemetry(ctx context.Context, telemetry *api.Telemetry) *api.TelemetryResult {
+	ret := &api.TelemetryResult{
+		RequestID:  telemetry.GetRequestID(),
+		StatusCode: http.StatusOK,
+	}
+
+	if err := s.traceHandleFunc(ctx, ConvertGRPCTelemetry(telemetry)); err != nil {
+		if errors.Is(err, ErrQueueFull) {
+			ret.StatusCode = http.StatusTooManyRequests
+		} else {
+			log.Errorf("Error from trace handling func: %v", err)
+			ret.StatusCode = http.StatusInternalServerError
+		}
+		ret.Message = err.Error()
+	}
+
+	return ret
+}
+
+// ConvertGRPCTelemetry converts a GRPC telemetry into the telemetries API model.
+func ConvertGRPCTelemetry(telemetry *api.Telemetry) *models.Telemetry {
+	return &models.Telemetry{
+		DestinationAddress:   telemetry.GetDestinationAddress(),
+		DestinationNamespace: telemetry.GetDestinationNamespace(),
+		Request: &models.Request{
+			Common: convertGRPCCommon(telemetry.GetRequest().GetCommon()),
+			Host:   telemetry.GetRequest().GetHost(),
+			Method: telemetry.GetRequest().GetMethod(),
+			Path:   telemetry.GetRequest().GetPath(),
+		},
+		RequestID: telemetry.GetRequestID(),
+		Response: &models.Response{
+			Common:     convertGRPCCommon(telemetry.GetResponse().GetCommon()),
+			StatusCode: telemetry.GetResponse().GetStatusCode(),
+		},
+		Scheme:        telemetry.GetScheme(),
+		SourceAddress: telemetry.GetSourceAddress(),
+	}
+}
+
+func convertGRPCCommon(grpcCommon *api.Common) *models.Common {
+	headers := make([]*models.Header, 0, len(grpcCommon.GetHeaders()))
+	for _, header := range grpcCommon.GetHeaders() {
+		headers = append(headers, &models.Header{
+			Key:   header.GetKey(),
+			Value: header.GetValue(),
+		})
+	}
+
+	return &models.Common{
+		TruncatedBody: grpcCommon.GetTruncatedBody(),
+		Body:          grpcCommon.GetBody(),
+		Headers:       headers,
+		Time:          grpcCommon.GetTime(),
+		Version:       grpcCommon.GetVersion(),
+	}
+}
@@ -0,0 +1,192 @@
+// Copyright © 2022 Cisco Systems, Inc. and its affiliates.
+// All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package traces
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+
+	api "github.com/openclarity/apiclarity/plugins/api/grpc_gen/telemetries-service"
+	"github.com/openclarity/apiclarity/plugins/api/server/models"
+)
+
+func TestConvertGRPCTelemetry(t *testing.T) {
+	telemetry := &api.Telemetry{
+		RequestID:          "req-id",
+		Scheme:             "http",
+		DestinationAddress: "1.1.1.1:80",
+		SourceAddress:      "2.2.2.2:1234",
+		Request: &api.Request{
+			Method: "GET",
+			Path:   "/foo?bar=1",
+			Host:   "foo.com",
+			Common: &api.Common{
+				Version: "1.1",
+				Headers: []*api.Header{{Key: "accept", Value: "application/json"}},
+				Time:    1000,
+			},
+		},
+		Response: &api.Response{
+			StatusCode: "200",
+			Common: &api.Common{
+				Body:          []byte("{}"),
+				TruncatedBody: true,
+			},
+		},
+	}
+	want := &models.Telemetry{
+		RequestID:          "req-id",
+		Scheme:             "http",
+		DestinationAddress: "1.1.1.1:80",
+		SourceAddress:      "2.2.2.2:1234",
+		Request: &models.Request{
+			Method: "GET",
+			Path:   "/foo?bar=1",
+			Host:   "foo.com",
+			Common: &models.Common{
+				Version: "1.1",
+				Headers: []*models.Header{{Key: "accept", Value: "application/json"}},
+				Time:    1000,
+			},
+		},
+		Response: &models.Response{
+			StatusCode: "200",
+			Common: &models.Common{
+				Headers:       []*models.Header{},
+				Body:          []byte("{}"),
+				TruncatedBody: true,
+			},
+		},
+	}
+	if got := ConvertGRPCTelemetry(telemetry); !reflect.DeepEqual(got, want) {
+		t.Errorf("ConvertGRPCTelemetry() = %+v, want %+v", got, want)
+	}
+}
+
+func TestGRPCTracesServer_PostTelemetry(t *testing.T) {
+	s, err := CreateGRPCTracesServer(0, func(ctx context.Context, trace *models.Telemetry) error {
+		return ErrQueueFull
+	}, nil)
+	if err != nil {
+		t.Fatalf("CreateGRPCTracesServer() error = %v", err)
+	}
+	result, err := s.PostTelemetry(context.Background(), &api.Telemetry{RequestID: "req-id"})
+	if err != nil {
+		t.Fatalf("PostTelemetry() error = %v", err)
+	}
+	if result.StatusCode != http.StatusTooManyRequests || result.RequestID != "req-id" {
+		t.Errorf("PostTelemetry() = %+v", result)
+	}
+}
+
+func writeServerCertificate(t *testing.T) (*TLSOptions, *x509.CertPool) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "apiclarity"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("failed to create certificate: %v", err)
+	}
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("failed to marshal key: %v", err)
+	}
+
+	dir := t.TempDir()
+	options := &TLSOptions{
+		CertFile: filepath.Join(dir, "server.crt"),
+		KeyFile:  filepath.Join(dir, "server.key"),
+	}
+	if err := os.WriteFile(options.CertFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600); err != nil {
+		t.Fatalf("failed to write certificate: %v", err)
+	}
+	if err := os.WriteFile(options.KeyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}), 0o600); err != nil {
+		t.Fatalf("failed to write key: %v", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("failed to parse certificate: %v", err)
+	}
+	pool := x509.NewCertPool()
+	pool.AddCert(cert)
+
+	return options, pool
+}
+
+func TestGRPCTracesServer_TLS(t *testing.T) {
+	tlsOptions, pool := writeServerCertificate(t)
+	s, err := CreateGRPCTracesServer(0, func(ctx context.Context, trace *models.Telemetry) error {
+		return nil
+	}, tlsOptions)
+	if err != nil {
+		t.Fatalf("CreateGRPCTracesServer() error = %v", err)
+	}
+	if err := s.Start(make(chan struct{}, 1)); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	defer s.Stop()
+
+	addr := net.JoinHostPort("127.0.0.1", strconv.Itoa(s.listener.Addr().(*net.TCPAddr).Port))
+	conn, err := grpc.Dial(addr, grpc.WithTransportCredentials(credentials.NewTLS(&tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12})))
+	if err != nil {
+		t.Fatalf("failed to dial: %v", err)
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	result, err := api.NewTelemetriesClient(conn).PostTelemetry(ctx, &api.Telemetry{RequestID: "req-id"})
+	if err != nil {
+		t.Fatalf("PostTelemetry() error = %v", err)
+	}
+	if result.StatusCode != http.StatusOK {
+		t.Errorf("PostTelemetry() = %+v", result)
+	}
+}
+
+func TestCreateGRPCTracesServer_MissingCertificate(t *testing.T) {
+	if _, err := CreateGRPCTracesServer(0, nil, &TLSOptions{CertFile: "missing.crt", KeyFile: "missing.key"}); err == nil {
+		t.Errorf("CreateGRPCTracesServer() expected error for a missing certificate")
+	}
+}
@@ -0,0 +1,143 @@
+// Copyright © 2022 Cisco Systems, Inc. and its affiliates.
+// All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package traces
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Trace handling stages for which latency is measured.
+const (
+	StageQueueWait  = "queue_wait"
+	StageHandle     = "handle"
+	StageSpeculator = "speculator"
+	StageDatabase   = "database"
+	StageModules    = "modules"
+)
+
+type LatencyStats struct {
+	Count int64         `json:"count"`
+	Total time.Duration `json:"totalNs"`
+	Max   time.Duration `json:"maxNs"`
+}
+
+func (l LatencyStats) Average() time.Duration {
+	if l.Count == 0 {
+		return 0
+	}
+	return l.Total / time.Duration(l.Count)
+}
+
+type MetricsSnapshot struct {
+	Accepted   int64                   `json:"accepted"`
+	Dropped    int64                   `json:"dropped"`
+	Rejected   int64                   `json:"rejected"`
+	Failed     int64                   `json:"failed"`
+	QueueDepth int                     `json:"queueDepth"`
+	Stages     map[string]LatencyStats `json:"stages"`
+}
+
+// Metrics collects counters and per-stage latencies of the trace ingestion pipeline.
+// A nil *Metrics is valid and ignores all observations.
+type Metrics struct {
+	lock     sync.Mutex
+	accepted int64
+	dropped  int64
+	rejected int64
+	failed   int64
+	stages   map[string]*LatencyStats
+
+	queueDepth func() int
+}
+
+func NewMetrics() *Metrics {
+	return &Metrics{
+		stages: make(map[string]*LatencyStats),
+	}
+}
+
+func (m *Metrics) ObserveLatency(stage string, d time.Duration) {
+	if m == nil {
+		return
+	}
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	stats, ok := m.stages[stage]
+	if !ok {
+		stats = &LatencyStats{}
+		m.stages[stage] = stats
+	}
+	stats.Count++
+	stats.Total += d
+	if d > stats.Max {
+		stats.Max = d
+	}
+}
+
+// ObserveSince records the time elapsed since start for the given stage.
+func (m *Metrics) ObserveSince(stage string, start time.Time) {
+	m.ObserveLatency(stage, time.Since(start))
+}
+
+func (m *Metrics) incAccepted() { m.inc(&m.accepted) }
+func (m *Metrics) incDropped()  { m.inc(&m.dropped) }
+func (m *Metrics) incRejected() { m.inc(&m.rejected) }
+func (m *Metrics) incFailed()   { m.inc(&m.failed) }
+
+func (m *Metrics) inc(counter *int64) {
+	if m == nil {
+		return
+	}
+	m.lock.Lock()
+	*counter++
+	m.lock.Unlock()
+}
+
+func (m *Metrics) Snapshot() MetricsSnapshot {
+	if m == nil {
+		return MetricsSnapshot{}
+	}
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	snapshot := MetricsSnapshot{
+		Accepted: m.accepted,
+		Dropped:  m.dropped,
+		Rejected: m.rejected,
+		Failed:   m.failed,
+		Stages:   make(map[string]LatencyStats, len(m.stages)),
+	}
+	if m.queueDepth != nil {
+		snapshot.QueueDepth = m.queueDepth()
+	}
+	for stage, stats := range m.stages {
+		snapshot.Stages[stage] = *stats
+	}
+
+	return snapshot
+}
+
+// ServeHTTP writes the current metrics snapshot as JSON.
+func (m *Metrics) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(m.Snapshot()); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+}
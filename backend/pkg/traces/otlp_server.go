@@ -0,0 +1,402 @@
+// Copyright © 2022 Cisco Systems, Inc. and its affiliates.
+// All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package traces
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	collectortrace "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	commonv1 "go.opentelemetry.io/proto/otlp/common/v1"
+	tracev1 "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/openclarity/apiclarity/backend/pkg/common"
+	"github.com/openclarity/apiclarity/plugins/api/server/models"
+)
+
+const (
+	otlpTracesPath = "/v1/traces"
+
+	contentTypeProtobuf = "application/x-protobuf"
+	contentTypeJSON     = "application/json"
+
+	// maxOTLPRequestSize limits the size of a single (uncompressed) export request.
+	maxOTLPRequestSize = 32 * 1000 * 1000
+)
+
+// HTTP semantic convention attributes used to build a telemetry from a server span.
+const (
+	attrHTTPMethod         = "http.method"
+	attrHTTPTarget         = "http.target"
+	attrHTTPURL            = "http.url"
+	attrHTTPHost           = "http.host"
+	attrHTTPScheme         = "http.scheme"
+	attrHTTPFlavor         = "http.flavor"
+	attrHTTPStatusCode     = "http.status_code"
+	attrHTTPRequestHeader  = "http.request.header."
+	attrHTTPResponseHeader = "http.response.header."
+	attrNetHostName        = "net.host.name"
+	attrNetHostIP          = "net.host.ip"
+	attrNetHostPort        = "net.host.port"
+	attrNetPeerIP          = "net.peer.ip"
+	attrNetPeerPort        = "net.peer.port"
+	attrK8sNamespace       = "k8s.namespace.name"
+)
+
+var errNotHTTPServerSpan = errors.New("not an HTTP server span")
+
+// OTLPTracesServer receives OTLP/HTTP trace exports and converts HTTP server spans into telemetries.
+type OTLPTracesServer struct {
+	traceHandleFunc HandleTraceFunc
+	server          *http.Server
+}
+
+func CreateOTLPTracesServer(port int, traceHandleFunc HandleTraceFunc) *OTLPTracesServer {
+	s := &OTLPTracesServer{
+		traceHandleFunc: traceHandleFunc,
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(otlpTracesPath, s.handleExport)
+	s.server = &http.Server{
+		Addr:    ":" + strconv.Itoa(port),
+		Handler: mux,
+	}
+
+	return s
+}
+
+func (s *OTLPTracesServer) Start(errChan chan struct{}) {
+	log.Infof("Starting OTLP traces server")
+
+	go func() {
+		if err := s.server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Errorf("Failed to serve OTLP traces server: %v", err)
+			errChan <- common.Empty
+		}
+	}()
+}
+
+func (s *OTLPTracesServer) Stop() {
+	log.Infof("Stopping OTLP traces server")
+	if err := s.server.Shutdown(context.Background()); err != nil {
+		log.Errorf("Failed to shutdown OTLP traces server: %v", err)
+	}
+}
+
+func (s *OTLPTracesServer) handleExport(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	contentType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil || (contentType != contentTypeProtobuf && contentType != contentTypeJSON) {
+		w.WriteHeader(http.StatusUnsupportedMediaType)
+		return
+	}
+
+	body, err := readOTLPBody(r)
+	if err != nil {
+		log.Errorf("Failed to read OTLP request: %v", err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	request := &collectortrace.ExportTraceServiceRequest{}
+	if contentType == contentTypeJSON {
+		err = protojson.Unmarshal(body, request)
+	} else {
+		err = proto.Unmarshal(body, request)
+	}
+	if err != nil {
+		log.Errorf("Failed to decode OTLP request: %v", err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	telemetries := ConvertOTLPRequest(request, contentType == contentTypeJSON)
+
+	var rejected int
+	for _, telemetry := range telemetries {
+		if err := s.traceHandleFunc(r.Context(), telemetry); err != nil {
+			if errors.Is(err, ErrQueueFull) {
+				rejected++
+				continue
+			}
+			log.Errorf("Error from trace handling func: %v", err)
+		}
+	}
+	// the exporter retries the whole request, so ask for a retry only when nothing was accepted.
+	if rejected > 0 && rejected == len(telemetries) {
+		w.WriteHeader(http.StatusTooManyRequests)
+		return
+	}
+	if rejected > 0 {
+		log.Warnf("Dropped %v out of %v OTLP spans: %v", rejected, len(telemetries), ErrQueueFull)
+	}
+
+	writeOTLPResponse(w, contentType)
+}
+
+func readOTLPBody(r *http.Request) ([]byte, error) {
+	reader := io.Reader(r.Body)
+	if r.Header.Get("Content-Encoding") == "gzip" {
+		gzipReader, err := gzip.NewReader(r.Body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create gzip reader: %v", err)
+		}
+		defer gzipReader.Close()
+		reader = gzipReader
+	}
+
+	body, err := ioutil.ReadAll(io.LimitReader(reader, maxOTLPRequestSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read body: %v", err)
+	}
+	if len(body) > maxOTLPRequestSize {
+		return nil, fmt.Errorf("request is too large")
+	}
+
+	return body, nil
+}
+
+func writeOTLPResponse(w http.ResponseWriter, contentType string) {
+	var body []byte
+	var err error
+
+	response := &collectortrace.ExportTraceServiceResponse{}
+	if contentType == contentTypeJSON {
+		body, err = protojson.Marshal(response)
+	} else {
+		body, err = proto.Marshal(response)
+	}
+	if err != nil {
+		log.Errorf("Failed to encode OTLP response: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(body); err != nil {
+		log.Errorf("Failed to write OTLP response: %v", err)
+	}
+}
+
+// ConvertOTLPRequest converts all HTTP server spans of the export request into telemetries.
+// isJSON is needed since OTLP/JSON encodes span IDs as hex instead of base64.
+func ConvertOTLPRequest(request *collectortrace.ExportTraceServiceRequest, isJSON bool) []*models.Telemetry {
+	var ret []*models.Telemetry
+
+	for _, resourceSpans := range request.GetResourceSpans() {
+		resourceAttrs := attributesToMap(resourceSpans.GetResource().GetAttributes())
+		for _, librarySpans := range resourceSpans.GetInstrumentationLibrarySpans() {
+			for _, span := range librarySpans.GetSpans() {
+				telemetry, err := ConvertOTLPSpan(span, resourceAttrs, isJSON)
+				if err != nil {
+					log.Debugf("Skipping span %q: %v", span.GetName(), err)
+					continue
+				}
+				ret = append(ret, telemetry)
+			}
+		}
+	}
+
+	return ret
+}
+
+// ConvertOTLPSpan converts an HTTP server span into a telemetry.
+func ConvertOTLPSpan(span *tracev1.Span, resourceAttrs map[string]*commonv1.AnyValue, isJSON bool) (*models.Telemetry, error) {
+	if span.GetKind() != tracev1.Span_SPAN_KIND_SERVER {
+		return nil, errNotHTTPServerSpan
+	}
+
+	attrs := attributesToMap(span.GetAttributes())
+	method := getStringAttribute(attrs, attrHTTPMethod)
+	if method == "" {
+		return nil, errNotHTTPServerSpan
+	}
+
+	statusCode := getIntAttribute(attrs, attrHTTPStatusCode)
+	if statusCode == 0 {
+		return nil, fmt.Errorf("missing %v attribute", attrHTTPStatusCode)
+	}
+
+	scheme := getStringAttribute(attrs, attrHTTPScheme)
+	host := getStringAttribute(attrs, attrHTTPHost)
+	target := getStringAttribute(attrs, attrHTTPTarget)
+	if rawURL := getStringAttribute(attrs, attrHTTPURL); rawURL != "" {
+		if parsedURL, err := url.Parse(rawURL); err == nil {
+			if scheme == "" {
+				scheme = parsedURL.Scheme
+			}
+			if host == "" {
+				host = parsedURL.Host
+			}
+			if target == "" {
+				target = parsedURL.RequestURI()
+			}
+		}
+	}
+	if host == "" {
+		host = getStringAttribute(attrs, attrNetHostName)
+	}
+	if target == "" {
+		return nil, fmt.Errorf("missing %v attribute", attrHTTPTarget)
+	}
+	if scheme == "" {
+		scheme = "http"
+	}
+
+	version := getStringAttribute(attrs, attrHTTPFlavor)
+
+	return &models.Telemetry{
+		DestinationAddress:   getDestinationAddress(attrs, host, scheme),
+		DestinationNamespace: getStringAttribute(resourceAttrs, attrK8sNamespace),
+		Request: &models.Request{
+			Common: &models.Common{
+				Headers: getHeaders(attrs, attrHTTPRequestHeader),
+				Time:    nanosToMillis(span.GetStartTimeUnixNano()),
+				Version: version,
+			},
+			Host:   host,
+			Method: method,
+			Path:   target,
+		},
+		RequestID: spanIDToString(span.GetSpanId(), isJSON),
+		Response: &models.Response{
+			Common: &models.Common{
+				Headers: getHeaders(attrs, attrHTTPResponseHeader),
+				Time:    nanosToMillis(span.GetEndTimeUnixNano()),
+				Version: version,
+			},
+			StatusCode: strconv.FormatInt(statusCode, 10),
+		},
+		Scheme:        scheme,
+		SourceAddress: getSourceAddress(attrs),
+	}, nil
+}
+
+func getDestinationAddress(attrs map[string]*commonv1.AnyValue, host, scheme string) string {
+	port := getIntAttribute(attrs, attrNetHostPort)
+	hostname := host
+	if hostAndPort := strings.SplitN(host, ":", 2); len(hostAndPort) == 2 {
+		hostname = hostAndPort[0]
+		if port == 0 {
+			port, _ = strconv.ParseInt(hostAndPort[1], 10, 64)
+		}
+	}
+	if port == 0 {
+		port = 80
+		if scheme == "https" {
+			port = 443
+		}
+	}
+
+	if ip := getStringAttribute(attrs, attrNetHostIP); ip != "" {
+		hostname = ip
+	}
+
+	return hostname + ":" + strconv.FormatInt(port, 10)
+}
+
+func getSourceAddress(attrs map[string]*commonv1.AnyValue) string {
+	ip := getStringAttribute(attrs, attrNetPeerIP)
+	if ip == "" {
+		ip = "0.0.0.0"
+	}
+
+	return ip + ":" + strconv.FormatInt(getIntAttribute(attrs, attrNetPeerPort), 10)
+}
+
+// getHeaders collects captured headers, e.g. http.request.header.content_type=["application/json"].
+func getHeaders(attrs map[string]*commonv1.AnyValue, prefix string) []*models.Header {
+	headers := []*models.Header{}
+
+	for key, value := range attrs {
+		if !strings.HasPrefix(key, prefix) {
+			continue
+		}
+		name := strings.ReplaceAll(strings.TrimPrefix(key, prefix), "_", "-")
+		var headerValue string
+		if values := value.GetArrayValue().GetValues(); len(values) > 0 {
+			headerValue = values[0].GetStringValue()
+		} else {
+			headerValue = value.GetStringValue()
+		}
+		headers = append(headers, &models.Header{
+			Key:   name,
+			Value: headerValue,
+		})
+	}
+
+	return headers
+}
+
+func attributesToMap(attrs []*commonv1.KeyValue) map[string]*commonv1.AnyValue {
+	ret := make(map[string]*commonv1.AnyValue, len(attrs))
+	for _, attr := range attrs {
+		ret[attr.GetKey()] = attr.GetValue()
+	}
+
+	return ret
+}
+
+func getStringAttribute(attrs map[string]*commonv1.AnyValue, key string) string {
+	return attrs[key].GetStringValue()
+}
+
+func getIntAttribute(attrs map[string]*commonv1.AnyValue, key string) int64 {
+	value, ok := attrs[key]
+	if !ok {
+		return 0
+	}
+	if stringValue := value.GetStringValue(); stringValue != "" {
+		ret, _ := strconv.ParseInt(stringValue, 10, 64)
+		return ret
+	}
+
+	return value.GetIntValue()
+}
+
+func nanosToMillis(nanos uint64) int64 {
+	return time.Unix(0, int64(nanos)).UnixMilli()
+}
+
+func spanIDToString(spanID []byte, isJSON bool) string {
+	if isJSON {
+		// protojson decoded the hex string as base64, encoding it back gives the original hex
+		return base64.StdEncoding.EncodeToString(spanID)
+	}
+
+	return hex.EncodeToString(spanID)
+}
@@ -0,0 +1,137 @@
+// Copyright © 2022 Cisco Systems, Inc. and its affiliates.
+// All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package traces
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	collectortrace "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	commonv1 "go.opentelemetry.io/proto/otlp/common/v1"
+	resourcev1 "go.opentelemetry.io/proto/otlp/resource/v1"
+	tracev1 "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/protobuf/proto"
+	"gotest.tools/assert"
+
+	"github.com/openclarity/apiclarity/plugins/api/server/models"
+)
+
+func stringAttr(key, value string) *commonv1.KeyValue {
+	return &commonv1.KeyValue{Key: key, Value: &commonv1.AnyValue{Value: &commonv1.AnyValue_StringValue{StringValue: value}}}
+}
+
+func intAttr(key string, value int64) *commonv1.KeyValue {
+	return &commonv1.KeyValue{Key: key, Value: &commonv1.AnyValue{Value: &commonv1.AnyValue_IntValue{IntValue: value}}}
+}
+
+func headerAttr(key, value string) *commonv1.KeyValue {
+	return &commonv1.KeyValue{Key: key, Value: &commonv1.AnyValue{Value: &commonv1.AnyValue_ArrayValue{ArrayValue: &commonv1.ArrayValue{
+		Values: []*commonv1.AnyValue{{Value: &commonv1.AnyValue_StringValue{StringValue: value}}},
+	}}}}
+}
+
+func createOTLPRequest(spans ...*tracev1.Span) *collectortrace.ExportTraceServiceRequest {
+	return &collectortrace.ExportTraceServiceRequest{
+		ResourceSpans: []*tracev1.ResourceSpans{{
+			Resource: &resourcev1.Resource{
+				Attributes: []*commonv1.KeyValue{stringAttr(attrK8sNamespace, "default")},
+			},
+			InstrumentationLibrarySpans: []*tracev1.InstrumentationLibrarySpans{{
+				Spans: spans,
+			}},
+		}},
+	}
+}
+
+var serverSpan = &tracev1.Span{
+	SpanId:            []byte{0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08},
+	Kind:              tracev1.Span_SPAN_KIND_SERVER,
+	StartTimeUnixNano: 2000000000,
+	EndTimeUnixNano:   3000000000,
+	Attributes: []*commonv1.KeyValue{
+		stringAttr(attrHTTPMethod, "GET"),
+		stringAttr(attrHTTPTarget, "/users/1?verbose=true"),
+		stringAttr(attrHTTPHost, "users.default:8080"),
+		stringAttr(attrHTTPFlavor, "1.1"),
+		intAttr(attrHTTPStatusCode, 200),
+		stringAttr(attrNetPeerIP, "10.0.0.2"),
+		intAttr(attrNetPeerPort, 51000),
+		headerAttr(attrHTTPResponseHeader+"content_type", "application/json"),
+	},
+}
+
+func TestConvertOTLPSpan(t *testing.T) {
+	got, err := ConvertOTLPSpan(serverSpan, map[string]*commonv1.AnyValue{}, false)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, got, &models.Telemetry{
+		DestinationAddress: "users.default:8080",
+		Request: &models.Request{
+			Common: &models.Common{
+				Headers: []*models.Header{},
+				Time:    2000,
+				Version: "1.1",
+			},
+			Host:   "users.default:8080",
+			Method: "GET",
+			Path:   "/users/1?verbose=true",
+		},
+		RequestID: "0102030405060708",
+		Response: &models.Response{
+			Common: &models.Common{
+				Headers: []*models.Header{{Key: "content-type", Value: "application/json"}},
+				Time:    3000,
+				Version: "1.1",
+			},
+			StatusCode: "200",
+		},
+		Scheme:        "http",
+		SourceAddress: "10.0.0.2:51000",
+	})
+
+	clientSpan := proto.Clone(serverSpan).(*tracev1.Span)
+	clientSpan.Kind = tracev1.Span_SPAN_KIND_CLIENT
+	_, err = ConvertOTLPSpan(clientSpan, nil, false)
+	assert.Equal(t, err, errNotHTTPServerSpan)
+}
+
+func TestOTLPTracesServer_handleExport(t *testing.T) {
+	var received []*models.Telemetry
+	s := CreateOTLPTracesServer(0, func(ctx context.Context, trace *models.Telemetry) error {
+		received = append(received, trace)
+		return nil
+	})
+
+	body, err := proto.Marshal(createOTLPRequest(serverSpan))
+	assert.NilError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, otlpTracesPath, bytes.NewReader(body))
+	req.Header.Set("Content-Type", contentTypeProtobuf)
+	w := httptest.NewRecorder()
+	s.handleExport(w, req)
+
+	assert.Equal(t, w.Code, http.StatusOK)
+	assert.Equal(t, len(received), 1)
+	assert.Equal(t, received[0].DestinationNamespace, "default")
+
+	req = httptest.NewRequest(http.MethodPost, otlpTracesPath, bytes.NewReader(body))
+	req.Header.Set("Content-Type", "text/plain")
+	w = httptest.NewRecorder()
+	s.handleExport(w, req)
+	assert.Equal(t, w.Code, http.StatusUnsupportedMediaType)
+}
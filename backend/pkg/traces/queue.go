@@ -0,0 +1,230 @@
+// Copyright © 2022 Cisco Systems, Inc. and its affiliates.
+// All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package traces
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/openclarity/apiclarity/plugins/api/server/models"
+)
+
+// QueueFullPolicy defines what happens to a trace that arrives when the queue is full.
+type QueueFullPolicy string
+
+const (
+	// QueueFullPolicyReject rejects the trace immediately, the sender receives 429.
+	QueueFullPolicyReject QueueFullPolicy = "reject"
+	// QueueFullPolicyDrop accepts the trace but silently drops it.
+	QueueFullPolicyDrop QueueFullPolicy = "drop"
+	// QueueFullPolicyBlock waits up to BlockTimeout for room in the queue, then rejects.
+	QueueFullPolicyBlock QueueFullPolicy = "block"
+)
+
+const (
+	defaultQueueSize          = 1000
+	defaultQueueWorkers       = 4
+	defaultQueueBlockTimeout  = time.Second
+	defaultMetricsLogInterval = time.Minute
+	defaultQueueDrainTimeout  = 10 * time.Second
+)
+
+// ErrQueueFull is returned by Enqueue when the trace was rejected due to a full queue.
+var ErrQueueFull = errors.New("traces queue is full")
+
+type QueueConfig struct {
+	Size               int
+	Workers            int
+	FullPolicy         QueueFullPolicy
+	BlockTimeout       time.Duration
+	MetricsLogInterval time.Duration
+	// DrainTimeout bounds the handling of the traces still queued at shutdown.
+	DrainTimeout time.Duration
+}
+
+type queuedTrace struct {
+	trace      *models.Telemetry
+	enqueuedAt time.Time
+}
+
+// TraceQueue is a bounded worker pool that decouples trace reception from trace handling.
+type TraceQueue struct {
+	config          QueueConfig
+	traceHandleFunc HandleTraceFunc
+	metrics         *Metrics
+	traces          chan queuedTrace
+	wg              sync.WaitGroup
+}
+
+func NewTraceQueue(config QueueConfig, traceHandleFunc HandleTraceFunc, metrics *Metrics) (*TraceQueue, error) {
+	if config.Size <= 0 {
+		config.Size = defaultQueueSize
+	}
+	if config.Workers <= 0 {
+		config.Workers = defaultQueueWorkers
+	}
+	if config.BlockTimeout <= 0 {
+		config.BlockTimeout = defaultQueueBlockTimeout
+	}
+	if config.MetricsLogInterval <= 0 {
+		config.MetricsLogInterval = defaultMetricsLogInterval
+	}
+	if config.DrainTimeout <= 0 {
+		config.DrainTimeout = defaultQueueDrainTimeout
+	}
+	switch config.FullPolicy {
+	case "":
+		config.FullPolicy = QueueFullPolicyReject
+	case QueueFullPolicyReject, QueueFullPolicyDrop, QueueFullPolicyBlock:
+	default:
+		return nil, fmt.Errorf("unknown queue full policy: %v", config.FullPolicy)
+	}
+
+	q := &TraceQueue{
+		config:          config,
+		traceHandleFunc: traceHandleFunc,
+		metrics:         metrics,
+		traces:          make(chan queuedTrace, config.Size),
+	}
+	if metrics != nil {
+		metrics.queueDepth = q.Len
+	}
+
+	return q, nil
+}
+
+// Start starts the queue workers. Once ctx is done, workers drain the queued traces and exit.
+func (q *TraceQueue) Start(ctx context.Context) {
+	log.Infof("Starting traces queue. size=%v, workers=%v, policy=%v", q.config.Size, q.config.Workers, q.config.FullPolicy)
+
+	for i := 0; i < q.config.Workers; i++ {
+		q.wg.Add(1)
+		go q.worker(ctx)
+	}
+
+	go q.logMetrics(ctx)
+}
+
+// Wait blocks until all workers have drained the queue and exited.
+func (q *TraceQueue) Wait() {
+	q.wg.Wait()
+}
+
+func (q *TraceQueue) Len() int {
+	return len(q.traces)
+}
+
+// Enqueue queues the trace for asynchronous handling according to the configured full policy.
+// ErrQueueFull is returned when the trace was rejected.
+func (q *TraceQueue) Enqueue(ctx context.Context, trace *models.Telemetry) error {
+	item := queuedTrace{trace: trace, enqueuedAt: time.Now()}
+
+	select {
+	case q.traces <- item:
+		q.metrics.incAccepted()
+		return nil
+	default:
+	}
+
+	switch q.config.FullPolicy {
+	case QueueFullPolicyDrop:
+		q.metrics.incDropped()
+		log.Debugf("Traces queue is full, dropping trace")
+		return nil
+	case QueueFullPolicyBlock:
+		timer := time.NewTimer(q.config.BlockTimeout)
+		defer timer.Stop()
+		select {
+		case q.traces <- item:
+			q.metrics.incAccepted()
+			return nil
+		case <-timer.C:
+		case <-ctx.Done():
+		}
+	case QueueFullPolicyReject:
+	}
+
+	q.metrics.incRejected()
+	return ErrQueueFull
+}
+
+func (q *TraceQueue) worker(ctx context.Context) {
+	defer q.wg.Done()
+
+	for {
+		select {
+		case <-ctx.Done():
+			q.drain()
+			return
+		case item := <-q.traces:
+			q.handle(ctx, item)
+		}
+	}
+}
+
+// drain handles the traces left in the queue, until it is empty or DrainTimeout passed.
+func (q *TraceQueue) drain() {
+	ctx, cancel := context.WithTimeout(context.Background(), q.config.DrainTimeout)
+	defer cancel()
+
+	for {
+		select {
+		case <-ctx.Done():
+			if remaining := q.Len(); remaining > 0 {
+				log.Warnf("Traces queue drain timed out, %v traces were not handled", remaining)
+			}
+			return
+		case item := <-q.traces:
+			q.handle(ctx, item)
+		default:
+			return
+		}
+	}
+}
+
+func (q *TraceQueue) handle(ctx context.Context, item queuedTrace) {
+	q.metrics.ObserveSince(StageQueueWait, item.enqueuedAt)
+	start := time.Now()
+	if err := q.traceHandleFunc(ctx, item.trace); err != nil {
+		q.metrics.incFailed()
+		log.Errorf("Error from trace handling func: %v", err)
+	}
+	q.metrics.ObserveSince(StageHandle, start)
+}
+
+func (q *TraceQueue) logMetrics(ctx context.Context) {
+	if q.metrics == nil {
+		return
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(q.config.MetricsLogInterval):
+			snapshot := q.metrics.Snapshot()
+			log.Infof("Traces queue metrics: accepted=%v, dropped=%v, rejected=%v, failed=%v, depth=%v",
+				snapshot.Accepted, snapshot.Dropped, snapshot.Rejected, snapshot.Failed, snapshot.QueueDepth)
+			for stage, stats := range snapshot.Stages {
+				log.Debugf("Traces stage latency: stage=%v, count=%v, avg=%v, max=%v", stage, stats.Count, stats.Average(), stats.Max)
+			}
+		}
+	}
+}
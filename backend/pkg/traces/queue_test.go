@@ -0,0 +1,153 @@
+// Copyright © 2022 Cisco Systems, Inc. and its affiliates.
+// All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package traces
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/openclarity/apiclarity/plugins/api/server/models"
+)
+
+func TestTraceQueue_Enqueue(t *testing.T) {
+	tests := []struct {
+		name         string
+		policy       QueueFullPolicy
+		wantErr      error
+		wantAccepted int64
+		wantDropped  int64
+		wantRejected int64
+	}{
+		{
+			name:         "reject policy",
+			policy:       QueueFullPolicyReject,
+			wantErr:      ErrQueueFull,
+			wantAccepted: 1,
+			wantRejected: 1,
+		},
+		{
+			name:         "drop policy",
+			policy:       QueueFullPolicyDrop,
+			wantErr:      nil,
+			wantAccepted: 1,
+			wantDropped:  1,
+		},
+		{
+			name:         "block policy times out",
+			policy:       QueueFullPolicyBlock,
+			wantErr:      ErrQueueFull,
+			wantAccepted: 1,
+			wantRejected: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			metrics := NewMetrics()
+			q, err := NewTraceQueue(QueueConfig{
+				Size:         1,
+				Workers:      1,
+				FullPolicy:   tt.policy,
+				BlockTimeout: 10 * time.Millisecond,
+			}, func(ctx context.Context, trace *models.Telemetry) error { return nil }, metrics)
+			if err != nil {
+				t.Fatalf("NewTraceQueue() error = %v", err)
+			}
+			// workers are not started, so the second trace finds the queue full
+			if err := q.Enqueue(context.Background(), &models.Telemetry{}); err != nil {
+				t.Fatalf("Enqueue() error = %v", err)
+			}
+			if err := q.Enqueue(context.Background(), &models.Telemetry{}); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Enqueue() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			snapshot := metrics.Snapshot()
+			if snapshot.Accepted != tt.wantAccepted || snapshot.Dropped != tt.wantDropped || snapshot.Rejected != tt.wantRejected {
+				t.Errorf("Snapshot() = %+v", snapshot)
+			}
+			if snapshot.QueueDepth != 1 {
+				t.Errorf("QueueDepth = %v, want 1", snapshot.QueueDepth)
+			}
+		})
+	}
+}
+
+func TestTraceQueue_Workers(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	handled := make(chan *models.Telemetry, 2)
+	metrics := NewMetrics()
+	q, err := NewTraceQueue(QueueConfig{Size: 2, Workers: 2}, func(ctx context.Context, trace *models.Telemetry) error {
+		handled <- trace
+		return nil
+	}, metrics)
+	if err != nil {
+		t.Fatalf("NewTraceQueue() error = %v", err)
+	}
+	q.Start(ctx)
+
+	for i := 0; i < 2; i++ {
+		if err := q.Enqueue(context.Background(), &models.Telemetry{}); err != nil {
+			t.Fatalf("Enqueue() error = %v", err)
+		}
+	}
+	for i := 0; i < 2; i++ {
+		select {
+		case <-handled:
+		case <-time.After(time.Second):
+			t.Fatalf("trace was not handled")
+		}
+	}
+	cancel()
+	q.Wait()
+
+	if stats := metrics.Snapshot().Stages[StageQueueWait]; stats.Count != 2 {
+		t.Errorf("queue wait count = %v, want 2", stats.Count)
+	}
+}
+
+func TestTraceQueue_DrainOnShutdown(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	var handled int
+	q, err := NewTraceQueue(QueueConfig{Size: 5, Workers: 1}, func(ctx context.Context, trace *models.Telemetry) error {
+		handled++
+		return nil
+	}, NewMetrics())
+	if err != nil {
+		t.Fatalf("NewTraceQueue() error = %v", err)
+	}
+	for i := 0; i < 5; i++ {
+		if err := q.Enqueue(context.Background(), &models.Telemetry{}); err != nil {
+			t.Fatalf("Enqueue() error = %v", err)
+		}
+	}
+	// cancel before starting, the pending traces must still be handled
+	cancel()
+	q.Start(ctx)
+	q.Wait()
+
+	if handled != 5 {
+		t.Errorf("handled = %v, want 5", handled)
+	}
+	if q.Len() != 0 {
+		t.Errorf("Len() = %v, want 0", q.Len())
+	}
+}
+
+func TestNewTraceQueue_UnknownPolicy(t *testing.T) {
+	if _, err := NewTraceQueue(QueueConfig{FullPolicy: "unknown"}, nil, nil); err == nil {
+		t.Errorf("NewTraceQueue() expected error for unknown policy")
+	}
+}
@@ -17,11 +17,13 @@ package traces
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 
 	"github.com/go-openapi/loads"
 	"github.com/go-openapi/runtime/middleware"
+	"github.com/jessevdk/go-flags"
 	log "github.com/sirupsen/logrus"
 
 	"github.com/openclarity/apiclarity/backend/pkg/common"
@@ -30,14 +32,23 @@ import (
 	"github.com/openclarity/apiclarity/plugins/api/server/restapi/operations"
 )
 
+// MaxTelemetriesBatchSize is the maximum number of telemetries accepted in a single batch.
+const MaxTelemetriesBatchSize = 1000
+
 type HandleTraceFunc func(ctx context.Context, trace *models.Telemetry) error
 
+// TLSOptions is the server certificate of the traces servers, used by tapers that enable TLS.
+type TLSOptions struct {
+	CertFile string
+	KeyFile  string
+}
+
 type HTTPTracesServer struct {
 	traceHandleFunc HandleTraceFunc
 	server          *restapi.Server
 }
 
-func CreateHTTPTracesServer(port int, traceHandleFunc HandleTraceFunc) (*HTTPTracesServer, error) {
+func CreateHTTPTracesServer(port int, traceHandleFunc HandleTraceFunc, tlsOptions *TLSOptions) (*HTTPTracesServer, error) {
 	s := &HTTPTracesServer{}
 
 	swaggerSpec, err := loads.Embedded(restapi.SwaggerJSON, restapi.FlatSwaggerJSON)
@@ -51,12 +62,23 @@ func CreateHTTPTracesServer(port int, traceHandleFunc HandleTraceFunc) (*HTTPTra
 		return s.PostTelemetry(params)
 	})
 
+	api.PostTelemetriesHandler = operations.PostTelemetriesHandlerFunc(func(params operations.PostTelemetriesParams) middleware.Responder {
+		return s.PostTelemetries(params)
+	})
+
 	server := restapi.NewServer(api)
 
 	server.ConfigureFlags()
 	server.ConfigureAPI()
 
-	server.Port = port
+	if tlsOptions != nil {
+		server.EnabledListeners = []string{"https"}
+		server.TLSPort = port
+		server.TLSCertificate = flags.Filename(tlsOptions.CertFile)
+		server.TLSCertificateKey = flags.Filename(tlsOptions.KeyFile)
+	} else {
+		server.Port = port
+	}
 	s.server = server
 	s.traceHandleFunc = traceHandleFunc
 
@@ -85,9 +107,50 @@ func (s *HTTPTracesServer) Stop() {
 
 func (s *HTTPTracesServer) PostTelemetry(params operations.PostTelemetryParams) middleware.Responder {
 	if err := s.traceHandleFunc(params.HTTPRequest.Context(), params.Body); err != nil {
+		if errors.Is(err, ErrQueueFull) {
+			log.Debugf("Rejecting trace: %v", err)
+			return operations.NewPostTelemetryDefault(http.StatusTooManyRequests)
+		}
 		log.Errorf("Error from trace handling func: %v", err)
 		return operations.NewPostTelemetryDefault(http.StatusInternalServerError)
 	}
 
 	return operations.NewPostTelemetryOK()
 }
+
+func (s *HTTPTracesServer) PostTelemetries(params operations.PostTelemetriesParams) middleware.Responder {
+	if len(params.Body) > MaxTelemetriesBatchSize {
+		log.Debugf("Rejecting telemetries batch of %v items", len(params.Body))
+		return operations.NewPostTelemetriesDefault(http.StatusRequestEntityTooLarge).WithPayload(&models.APIResponse{
+			Message: fmt.Sprintf("batch size %v is above the maximum of %v", len(params.Body), MaxTelemetriesBatchSize),
+		})
+	}
+
+	results := make([]*models.TelemetryResult, 0, len(params.Body))
+
+	for _, telemetry := range params.Body {
+		if telemetry == nil {
+			results = append(results, &models.TelemetryResult{
+				StatusCode: http.StatusBadRequest,
+				Message:    "empty telemetry",
+			})
+			continue
+		}
+		result := &models.TelemetryResult{
+			RequestID:  telemetry.RequestID,
+			StatusCode: http.StatusOK,
+		}
+		if err := s.traceHandleFunc(params.HTTPRequest.Context(), telemetry); err != nil {
+			if errors.Is(err, ErrQueueFull) {
+				result.StatusCode = http.StatusTooManyRequests
+			} else {
+				log.Errorf("Error from trace handling func: %v", err)
+				result.StatusCode = http.StatusInternalServerError
+			}
+			result.Message = err.Error()
+		}
+		results = append(results, result)
+	}
+
+	return operations.NewPostTelemetriesOK().WithPayload(&models.TelemetriesResult{Results: results})
+}
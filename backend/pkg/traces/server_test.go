@@ -0,0 +1,55 @@
+// Copyright © 2022 Cisco Systems, Inc. and its affiliates.
+// All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package traces
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-openapi/runtime"
+	"gotest.tools/assert"
+
+	"github.com/openclarity/apiclarity/plugins/api/server/models"
+	"github.com/openclarity/apiclarity/plugins/api/server/restapi/operations"
+)
+
+func TestHTTPTracesServer_PostTelemetries(t *testing.T) {
+	var handled int
+	s := &HTTPTracesServer{traceHandleFunc: func(ctx context.Context, trace *models.Telemetry) error {
+		handled++
+		return nil
+	}}
+	post := func(size int) int {
+		params := operations.PostTelemetriesParams{
+			HTTPRequest: httptest.NewRequest(http.MethodPost, "/telemetries", nil),
+			Body:        make([]*models.Telemetry, size),
+		}
+		for i := range params.Body {
+			params.Body[i] = &models.Telemetry{}
+		}
+		rec := httptest.NewRecorder()
+		s.PostTelemetries(params).WriteResponse(rec, runtime.JSONProducer())
+		return rec.Code
+	}
+
+	assert.Equal(t, post(MaxTelemetriesBatchSize), http.StatusOK)
+	assert.Equal(t, handled, MaxTelemetriesBatchSize)
+
+	assert.Equal(t, post(MaxTelemetriesBatchSize+1), http.StatusRequestEntityTooLarge)
+	assert.Equal(t, handled, MaxTelemetriesBatchSize)
+}
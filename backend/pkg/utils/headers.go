@@ -0,0 +1,54 @@
+// Copyright © 2021 Cisco Systems, Inc. and its affiliates.
+// All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import "strings"
+
+// CredentialsHeaders are the request headers that carry credentials: the authorization headers, the cookies and
+// the API keys.
+var CredentialsHeaders = []string{
+	"authorization",
+	"proxy-authorization",
+	"cookie",
+	"x-api-key",
+	"api-key",
+	"apikey",
+	"x-auth-token",
+}
+
+// apiKeyHeaders are the credentials headers holding an API key.
+var apiKeyHeaders = map[string]bool{
+	"x-api-key":    true,
+	"api-key":      true,
+	"apikey":       true,
+	"x-auth-token": true,
+}
+
+// IsCredentialsHeader returns true if the request header carries credentials (case insensitive).
+func IsCredentialsHeader(name string) bool {
+	name = strings.ToLower(name)
+	for _, header := range CredentialsHeaders {
+		if header == name {
+			return true
+		}
+	}
+	return false
+}
+
+// IsAPIKeyHeader returns true if the request header holds an API key (case insensitive).
+func IsAPIKeyHeader(name string) bool {
+	return apiKeyHeaders[strings.ToLower(name)]
+}
@@ -0,0 +1,32 @@
+// Copyright © 2021 Cisco Systems, Inc. and its affiliates.
+// All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import "testing"
+
+func TestIsCredentialsHeader(t *testing.T) {
+	for name, want := range map[string]bool{
+		"Authorization": true, "cookie": true, "X-API-Key": true, "ApiKey": true,
+		"Content-Type": false, "Set-Cookie": false, "X-Api-Key-Id": false,
+	} {
+		if got := IsCredentialsHeader(name); got != want {
+			t.Errorf("IsCredentialsHeader(%q) = %v, want %v", name, got, want)
+		}
+	}
+	if !IsAPIKeyHeader("X-Auth-Token") || IsAPIKeyHeader("Authorization") {
+		t.Errorf("IsAPIKeyHeader() is wrong")
+	}
+}
@@ -28,11 +28,50 @@ type ClientOption func(*runtime.ClientOperation)
 
 // ClientService is the interface for Client methods
 type ClientService interface {
+	PostTelemetries(params *PostTelemetriesParams, opts ...ClientOption) (*PostTelemetriesOK, error)
+
 	PostTelemetry(params *PostTelemetryParams, opts ...ClientOption) (*PostTelemetryOK, error)
 
 	SetTransport(transport runtime.ClientTransport)
 }
 
+/*
+  PostTelemetries posts a batch of http telemetries
+*/
+func (a *Client) PostTelemetries(params *PostTelemetriesParams, opts ...ClientOption) (*PostTelemetriesOK, error) {
+	// TODO: Validate the params before sending
+	if params == nil {
+		params = NewPostTelemetriesParams()
+	}
+	op := &runtime.ClientOperation{
+		ID:                 "PostTelemetries",
+		Method:             "POST",
+		PathPattern:        "/telemetries",
+		ProducesMediaTypes: []string{"application/json"},
+		ConsumesMediaTypes: []string{"application/json"},
+		Schemes:            []string{"http"},
+		Params:             params,
+		Reader:             &PostTelemetriesReader{formats: a.formats},
+		Context:            params.Context,
+		Client:             params.HTTPClient,
+	}
+	for _, opt := range opts {
+		opt(op)
+	}
+
+	result, err := a.transport.Submit(op)
+	if err != nil {
+		return nil, err
+	}
+	success, ok := result.(*PostTelemetriesOK)
+	if ok {
+		return success, nil
+	}
+	// unexpected success response
+	unexpectedSuccess := result.(*PostTelemetriesDefault)
+	return nil, runtime.NewAPIError("unexpected success response: content available as default response in error", unexpectedSuccess, unexpectedSuccess.Code())
+}
+
 /*
   PostTelemetry posts an http telemetry
 */
@@ -0,0 +1,148 @@
+// Code generated by go-swagger; DO NOT EDIT.
+
+package operations
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the swagger generate command
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/go-openapi/errors"
+	"github.com/go-openapi/runtime"
+	cr "github.com/go-openapi/runtime/client"
+	"github.com/go-openapi/strfmt"
+
+	"github.com/openclarity/apiclarity/plugins/api/client/models"
+)
+
+// NewPostTelemetriesParams creates a new PostTelemetriesParams object,
+// with the default timeout for this client.
+//
+// Default values are not hydrated, since defaults are normally applied by the API server side.
+//
+// To enforce default values in parameter, use SetDefaults or WithDefaults.
+func NewPostTelemetriesParams() *PostTelemetriesParams {
+	return &PostTelemetriesParams{
+		timeout: cr.DefaultTimeout,
+	}
+}
+
+// NewPostTelemetriesParamsWithTimeout creates a new PostTelemetriesParams object
+// with the ability to set a timeout on a request.
+func NewPostTelemetriesParamsWithTimeout(timeout time.Duration) *PostTelemetriesParams {
+	return &PostTelemetriesParams{
+		timeout: timeout,
+	}
+}
+
+// NewPostTelemetriesParamsWithContext creates a new PostTelemetriesParams object
+// with the ability to set a context for a request.
+func NewPostTelemetriesParamsWithContext(ctx context.Context) *PostTelemetriesParams {
+	return &PostTelemetriesParams{
+		Context: ctx,
+	}
+}
+
+// NewPostTelemetriesParamsWithHTTPClient creates a new PostTelemetriesParams object
+// with the ability to set a custom HTTPClient for a request.
+func NewPostTelemetriesParamsWithHTTPClient(client *http.Client) *PostTelemetriesParams {
+	return &PostTelemetriesParams{
+		HTTPClient: client,
+	}
+}
+
+/* PostTelemetriesParams contains all the parameters to send to the API endpoint
+   for the post telemetries operation.
+
+   Typically these are written to a http.Request.
+*/
+type PostTelemetriesParams struct {
+
+	// Body.
+	Body []*models.Telemetry
+
+	timeout    time.Duration
+	Context    context.Context
+	HTTPClient *http.Client
+}
+
+// WithDefaults hydrates default values in the post telemetries params (not the query body).
+//
+// All values with no default are reset to their zero value.
+func (o *PostTelemetriesParams) WithDefaults() *PostTelemetriesParams {
+	o.SetDefaults()
+	return o
+}
+
+// SetDefaults hydrates default values in the post telemetries params (not the query body).
+//
+// All values with no default are reset to their zero value.
+func (o *PostTelemetriesParams) SetDefaults() {
+	// no default values defined for this parameter
+}
+
+// WithTimeout adds the timeout to the post telemetries params
+func (o *PostTelemetriesParams) WithTimeout(timeout time.Duration) *PostTelemetriesParams {
+	o.SetTimeout(timeout)
+	return o
+}
+
+// SetTimeout adds the timeout to the post telemetries params
+func (o *PostTelemetriesParams) SetTimeout(timeout time.Duration) {
+	o.timeout = timeout
+}
+
+// WithContext adds the context to the post telemetries params
+func (o *PostTelemetriesParams) WithContext(ctx context.Context) *PostTelemetriesParams {
+	o.SetContext(ctx)
+	return o
+}
+
+// SetContext adds the context to the post telemetries params
+func (o *PostTelemetriesParams) SetContext(ctx context.Context) {
+	o.Context = ctx
+}
+
+// WithHTTPClient adds the HTTPClient to the post telemetries params
+func (o *PostTelemetriesParams) WithHTTPClient(client *http.Client) *PostTelemetriesParams {
+	o.SetHTTPClient(client)
+	return o
+}
+
+// SetHTTPClient adds the HTTPClient to the post telemetries params
+func (o *PostTelemetriesParams) SetHTTPClient(client *http.Client) {
+	o.HTTPClient = client
+}
+
+// WithBody adds the body to the post telemetries params
+func (o *PostTelemetriesParams) WithBody(body []*models.Telemetry) *PostTelemetriesParams {
+	o.SetBody(body)
+	return o
+}
+
+// SetBody adds the body to the post telemetries params
+func (o *PostTelemetriesParams) SetBody(body []*models.Telemetry) {
+	o.Body = body
+}
+
+// WriteToRequest writes these params to a swagger request
+func (o *PostTelemetriesParams) WriteToRequest(r runtime.ClientRequest, reg strfmt.Registry) error {
+
+	if err := r.SetTimeout(o.timeout); err != nil {
+		return err
+	}
+	var res []error
+	if o.Body != nil {
+		if err := r.SetBodyParam(o.Body); err != nil {
+			return err
+		}
+	}
+
+	if len(res) > 0 {
+		return errors.CompositeValidationError(res...)
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+// Code generated by go-swagger; DO NOT EDIT.
+
+package operations
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the swagger generate command
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/go-openapi/runtime"
+	"github.com/go-openapi/strfmt"
+
+	"github.com/openclarity/apiclarity/plugins/api/client/models"
+)
+
+// PostTelemetriesReader is a Reader for the PostTelemetries structure.
+type PostTelemetriesReader struct {
+	formats strfmt.Registry
+}
+
+// ReadResponse reads a server response into the received o.
+func (o *PostTelemetriesReader) ReadResponse(response runtime.ClientResponse, consumer runtime.Consumer) (interface{}, error) {
+	switch response.Code() {
+	case 200:
+		result := NewPostTelemetriesOK()
+		if err := result.readResponse(response, consumer, o.formats); err != nil {
+			return nil, err
+		}
+		return result, nil
+	default:
+		result := NewPostTelemetriesDefault(response.Code())
+		if err := result.readResponse(response, consumer, o.formats); err != nil {
+			return nil, err
+		}
+		if response.Code()/100 == 2 {
+			return result, nil
+		}
+		return nil, result
+	}
+}
+
+// NewPostTelemetriesOK creates a PostTelemetriesOK with default headers values
+func NewPostTelemetriesOK() *PostTelemetriesOK {
+	return &PostTelemetriesOK{}
+}
+
+/* PostTelemetriesOK describes a response with status code 200, with default header values.
+
+Result per telemetry, in the order of the request
+*/
+type PostTelemetriesOK struct {
+	Payload *models.TelemetriesResult
+}
+
+func (o *PostTelemetriesOK) Error() string {
+	return fmt.Sprintf("[POST /telemetries][%d] postTelemetriesOK  %+v", 200, o.Payload)
+}
+func (o *PostTelemetriesOK) GetPayload() *models.TelemetriesResult {
+	return o.Payload
+}
+
+func (o *PostTelemetriesOK) readResponse(response runtime.ClientResponse, consumer runtime.Consumer, formats strfmt.Registry) error {
+
+	o.Payload = new(models.TelemetriesResult)
+
+	// response payload
+	if err := consumer.Consume(response.Body(), o.Payload); err != nil && err != io.EOF {
+		return err
+	}
+
+	return nil
+}
+
+// NewPostTelemetriesDefault creates a PostTelemetriesDefault with default headers values
+func NewPostTelemetriesDefault(code int) *PostTelemetriesDefault {
+	return &PostTelemetriesDefault{
+		_statusCode: code,
+	}
+}
+
+/* PostTelemetriesDefault describes a response with status code -1, with default header values.
+
+unknown error
+*/
+type PostTelemetriesDefault struct {
+	_statusCode int
+
+	Payload *models.APIResponse
+}
+
+// Code gets the status code for the post telemetries default response
+func (o *PostTelemetriesDefault) Code() int {
+	return o._statusCode
+}
+
+func (o *PostTelemetriesDefault) Error() string {
+	return fmt.Sprintf("[POST /telemetries][%d] PostTelemetries default  %+v", o._statusCode, o.Payload)
+}
+func (o *PostTelemetriesDefault) GetPayload() *models.APIResponse {
+	return o.Payload
+}
+
+func (o *PostTelemetriesDefault) readResponse(response runtime.ClientResponse, consumer runtime.Consumer, formats strfmt.Registry) error {
+
+	o.Payload = new(models.APIResponse)
+
+	// response payload
+	if err := consumer.Consume(response.Body(), o.Payload); err != nil && err != io.EOF {
+		return err
+	}
+
+	return nil
+}
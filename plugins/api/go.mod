@@ -12,4 +12,6 @@ require (
 	github.com/go-openapi/validate v0.20.3
 	github.com/jessevdk/go-flags v1.5.0
 	golang.org/x/net v0.0.0-20211101193420-4a448f8816b3
+	google.golang.org/grpc v1.42.0
+	google.golang.org/protobuf v1.27.1
 )
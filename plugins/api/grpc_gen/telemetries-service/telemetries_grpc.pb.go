@@ -0,0 +1,176 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+
+package telemetries
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// TelemetriesClient is the client API for Telemetries service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type TelemetriesClient interface {
+	// PostTelemetry handles a single telemetry.
+	PostTelemetry(ctx context.Context, in *Telemetry, opts ...grpc.CallOption) (*TelemetryResult, error)
+	// StreamTelemetries handles telemetries sent over one long-lived stream.
+	StreamTelemetries(ctx context.Context, opts ...grpc.CallOption) (Telemetries_StreamTelemetriesClient, error)
+}
+
+type telemetriesClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewTelemetriesClient(cc grpc.ClientConnInterface) TelemetriesClient {
+	return &telemetriesClient{cc}
+}
+
+func (c *telemetriesClient) PostTelemetry(ctx context.Context, in *Telemetry, opts ...grpc.CallOption) (*TelemetryResult, error) {
+	out := new(TelemetryResult)
+	err := c.cc.Invoke(ctx, "/telemetries.Telemetries/PostTelemetry", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *telemetriesClient) StreamTelemetries(ctx context.Context, opts ...grpc.CallOption) (Telemetries_StreamTelemetriesClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Telemetries_ServiceDesc.Streams[0], "/telemetries.Telemetries/StreamTelemetries", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &telemetriesStreamTelemetriesClient{stream}
+	return x, nil
+}
+
+type Telemetries_StreamTelemetriesClient interface {
+	Send(*Telemetry) error
+	CloseAndRecv() (*StreamTelemetriesResult, error)
+	grpc.ClientStream
+}
+
+type telemetriesStreamTelemetriesClient struct {
+	grpc.ClientStream
+}
+
+func (x *telemetriesStreamTelemetriesClient) Send(m *Telemetry) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *telemetriesStreamTelemetriesClient) CloseAndRecv() (*StreamTelemetriesResult, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(StreamTelemetriesResult)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// TelemetriesServer is the server API for Telemetries service.
+// All implementations must embed UnimplementedTelemetriesServer
+// for forward compatibility
+type TelemetriesServer interface {
+	// PostTelemetry handles a single telemetry.
+	PostTelemetry(context.Context, *Telemetry) (*TelemetryResult, error)
+	// StreamTelemetries handles telemetries sent over one long-lived stream.
+	StreamTelemetries(Telemetries_StreamTelemetriesServer) error
+	mustEmbedUnimplementedTelemetriesServer()
+}
+
+// UnimplementedTelemetriesServer must be embedded to have forward compatible implementations.
+type UnimplementedTelemetriesServer struct {
+}
+
+func (UnimplementedTelemetriesServer) PostTelemetry(context.Context, *Telemetry) (*TelemetryResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PostTelemetry not implemented")
+}
+func (UnimplementedTelemetriesServer) StreamTelemetries(Telemetries_StreamTelemetriesServer) error {
+	return status.Errorf(codes.Unimplemented, "method StreamTelemetries not implemented")
+}
+func (UnimplementedTelemetriesServer) mustEmbedUnimplementedTelemetriesServer() {}
+
+// UnsafeTelemetriesServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to TelemetriesServer will
+// result in compilation errors.
+type UnsafeTelemetriesServer interface {
+	mustEmbedUnimplementedTelemetriesServer()
+}
+
+func RegisterTelemetriesServer(s grpc.ServiceRegistrar, srv TelemetriesServer) {
+	s.RegisterService(&Telemetries_ServiceDesc, srv)
+}
+
+func _Telemetries_PostTelemetry_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Telemetry)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TelemetriesServer).PostTelemetry(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/telemetries.Telemetries/PostTelemetry",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TelemetriesServer).PostTelemetry(ctx, req.(*Telemetry))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Telemetries_StreamTelemetries_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(TelemetriesServer).StreamTelemetries(&telemetriesStreamTelemetriesServer{stream})
+}
+
+type Telemetries_StreamTelemetriesServer interface {
+	SendAndClose(*StreamTelemetriesResult) error
+	Recv() (*Telemetry, error)
+	grpc.ServerStream
+}
+
+type telemetriesStreamTelemetriesServer struct {
+	grpc.ServerStream
+}
+
+func (x *telemetriesStreamTelemetriesServer) SendAndClose(m *StreamTelemetriesResult) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *telemetriesStreamTelemetriesServer) Recv() (*Telemetry, error) {
+	m := new(Telemetry)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// Telemetries_ServiceDesc is the grpc.ServiceDesc for Telemetries service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Telemetries_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "telemetries.Telemetries",
+	HandlerType: (*TelemetriesServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "PostTelemetry",
+			Handler:    _Telemetries_PostTelemetry_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamTelemetries",
+			Handler:       _Telemetries_StreamTelemetries_Handler,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "telemetries-service/telemetries.proto",
+}
@@ -0,0 +1,3 @@
+package grpc_proto
+
+//go:generate protoc --go_out=../grpc_gen --go_opt=paths=source_relative --go-grpc_out=../grpc_gen --go-grpc_opt=paths=source_relative telemetries-service/telemetries.proto
@@ -0,0 +1,56 @@
+// Code generated by go-swagger; DO NOT EDIT.
+
+package models
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the swagger generate command
+
+import (
+	"context"
+
+	"github.com/go-openapi/strfmt"
+	"github.com/go-openapi/swag"
+)
+
+// TelemetryResult telemetry result
+//
+// swagger:model TelemetryResult
+type TelemetryResult struct {
+
+	// message
+	Message string `json:"message,omitempty"`
+
+	// request ID
+	RequestID string `json:"requestID,omitempty"`
+
+	// The HTTP status code the telemetry would have got if posted by itself
+	StatusCode int64 `json:"statusCode,omitempty"`
+}
+
+// Validate validates this telemetry result
+func (m *TelemetryResult) Validate(formats strfmt.Registry) error {
+	return nil
+}
+
+// ContextValidate validates this telemetry result based on context it is used
+func (m *TelemetryResult) ContextValidate(ctx context.Context, formats strfmt.Registry) error {
+	return nil
+}
+
+// MarshalBinary interface implementation
+func (m *TelemetryResult) MarshalBinary() ([]byte, error) {
+	if m == nil {
+		return nil, nil
+	}
+	return swag.WriteJSON(m)
+}
+
+// UnmarshalBinary interface implementation
+func (m *TelemetryResult) UnmarshalBinary(b []byte) error {
+	var res TelemetryResult
+	if err := swag.ReadJSON(b, &res); err != nil {
+		return err
+	}
+	*m = res
+	return nil
+}
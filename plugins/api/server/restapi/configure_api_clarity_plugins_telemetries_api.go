@@ -37,6 +37,11 @@ func configureAPI(api *operations.APIClarityPluginsTelemetriesAPIAPI) http.Handl
 
 	api.JSONProducer = runtime.JSONProducer()
 
+	if api.PostTelemetriesHandler == nil {
+		api.PostTelemetriesHandler = operations.PostTelemetriesHandlerFunc(func(params operations.PostTelemetriesParams) middleware.Responder {
+			return middleware.NotImplemented("operation operations.PostTelemetries has not yet been implemented")
+		})
+	}
 	if api.PostTelemetryHandler == nil {
 		api.PostTelemetryHandler = operations.PostTelemetryHandlerFunc(func(params operations.PostTelemetryParams) middleware.Responder {
 			return middleware.NotImplemented("operation operations.PostTelemetry has not yet been implemented")
@@ -34,6 +34,36 @@ func init() {
   },
   "basePath": "/api",
   "paths": {
+    "/telemetries": {
+      "post": {
+        "summary": "Post a batch of http telemetries",
+        "parameters": [
+          {
+            "name": "body",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "description": "Telemetries",
+              "type": "array",
+              "items": {
+                "$ref": "#/definitions/Telemetry"
+              }
+            }
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "Result per telemetry, in the order of the request",
+            "schema": {
+              "$ref": "#/definitions/TelemetriesResult"
+            }
+          },
+          "default": {
+            "$ref": "#/responses/UnknownError"
+          }
+        }
+      }
+    },
     "/telemetry": {
       "post": {
         "summary": "Post an http telemetry",
@@ -145,6 +175,17 @@ func init() {
         }
       }
     },
+    "TelemetriesResult": {
+      "type": "object",
+      "properties": {
+        "results": {
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/TelemetryResult"
+          }
+        }
+      }
+    },
     "Telemetry": {
       "type": "object",
       "properties": {
@@ -170,6 +211,21 @@ func init() {
           "type": "string"
         }
       }
+    },
+    "TelemetryResult": {
+      "type": "object",
+      "properties": {
+        "message": {
+          "type": "string"
+        },
+        "requestID": {
+          "type": "string"
+        },
+        "statusCode": {
+          "description": "The HTTP status code the telemetry would have got if posted by itself",
+          "type": "integer"
+        }
+      }
     }
   },
   "responses": {
@@ -204,6 +260,39 @@ func init() {
   },
   "basePath": "/api",
   "paths": {
+    "/telemetries": {
+      "post": {
+        "summary": "Post a batch of http telemetries",
+        "parameters": [
+          {
+            "name": "body",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "description": "Telemetries",
+              "type": "array",
+              "items": {
+                "$ref": "#/definitions/Telemetry"
+              }
+            }
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "Result per telemetry, in the order of the request",
+            "schema": {
+              "$ref": "#/definitions/TelemetriesResult"
+            }
+          },
+          "default": {
+            "description": "unknown error",
+            "schema": {
+              "$ref": "#/definitions/ApiResponse"
+            }
+          }
+        }
+      }
+    },
     "/telemetry": {
       "post": {
         "summary": "Post an http telemetry",
@@ -321,6 +410,17 @@ func init() {
         }
       }
     },
+    "TelemetriesResult": {
+      "type": "object",
+      "properties": {
+        "results": {
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/TelemetryResult"
+          }
+        }
+      }
+    },
     "Telemetry": {
       "type": "object",
       "properties": {
@@ -346,6 +446,21 @@ func init() {
           "type": "string"
         }
       }
+    },
+    "TelemetryResult": {
+      "type": "object",
+      "properties": {
+        "message": {
+          "type": "string"
+        },
+        "requestID": {
+          "type": "string"
+        },
+        "statusCode": {
+          "description": "The HTTP status code the telemetry would have got if posted by itself",
+          "type": "integer"
+        }
+      }
     }
   },
   "responses": {
@@ -42,6 +42,9 @@ func NewAPIClarityPluginsTelemetriesAPIAPI(spec *loads.Document) *APIClarityPlug
 
 		JSONProducer: runtime.JSONProducer(),
 
+		PostTelemetriesHandler: PostTelemetriesHandlerFunc(func(params PostTelemetriesParams) middleware.Responder {
+			return middleware.NotImplemented("operation PostTelemetries has not yet been implemented")
+		}),
 		PostTelemetryHandler: PostTelemetryHandlerFunc(func(params PostTelemetryParams) middleware.Responder {
 			return middleware.NotImplemented("operation PostTelemetry has not yet been implemented")
 		}),
@@ -81,6 +84,8 @@ type APIClarityPluginsTelemetriesAPIAPI struct {
 	//   - application/json
 	JSONProducer runtime.Producer
 
+	// PostTelemetriesHandler sets the operation handler for the post telemetries operation
+	PostTelemetriesHandler PostTelemetriesHandler
 	// PostTelemetryHandler sets the operation handler for the post telemetry operation
 	PostTelemetryHandler PostTelemetryHandler
 
@@ -160,6 +165,9 @@ func (o *APIClarityPluginsTelemetriesAPIAPI) Validate() error {
 		unregistered = append(unregistered, "JSONProducer")
 	}
 
+	if o.PostTelemetriesHandler == nil {
+		unregistered = append(unregistered, "PostTelemetriesHandler")
+	}
 	if o.PostTelemetryHandler == nil {
 		unregistered = append(unregistered, "PostTelemetryHandler")
 	}
@@ -251,6 +259,10 @@ func (o *APIClarityPluginsTelemetriesAPIAPI) initHandlerCache() {
 		o.handlers = make(map[string]map[string]http.Handler)
 	}
 
+	if o.handlers["POST"] == nil {
+		o.handlers["POST"] = make(map[string]http.Handler)
+	}
+	o.handlers["POST"]["/telemetries"] = NewPostTelemetries(o.context, o.PostTelemetriesHandler)
 	if o.handlers["POST"] == nil {
 		o.handlers["POST"] = make(map[string]http.Handler)
 	}
@@ -0,0 +1,55 @@
+// Code generated by go-swagger; DO NOT EDIT.
+
+package operations
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the generate command
+
+import (
+	"net/http"
+
+	"github.com/go-openapi/runtime/middleware"
+)
+
+// PostTelemetriesHandlerFunc turns a function with the right signature into a post telemetries handler
+type PostTelemetriesHandlerFunc func(PostTelemetriesParams) middleware.Responder
+
+// Handle executing the request and returning a response
+func (fn PostTelemetriesHandlerFunc) Handle(params PostTelemetriesParams) middleware.Responder {
+	return fn(params)
+}
+
+// PostTelemetriesHandler interface for that can handle valid post telemetries params
+type PostTelemetriesHandler interface {
+	Handle(PostTelemetriesParams) middleware.Responder
+}
+
+// NewPostTelemetries creates a new http.Handler for the post telemetries operation
+func NewPostTelemetries(ctx *middleware.Context, handler PostTelemetriesHandler) *PostTelemetries {
+	return &PostTelemetries{Context: ctx, Handler: handler}
+}
+
+/* PostTelemetries swagger:route POST /telemetries postTelemetries
+
+Post a batch of http telemetries
+*/
+type PostTelemetries struct {
+	Context *middleware.Context
+	Handler PostTelemetriesHandler
+}
+
+func (o *PostTelemetries) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	route, rCtx, _ := o.Context.RouteInfo(r)
+	if rCtx != nil {
+		*r = *rCtx
+	}
+	var Params = NewPostTelemetriesParams()
+	if err := o.Context.BindValidRequest(r, route, &Params); err != nil { // bind params
+		o.Context.Respond(rw, r, route.Produces, route, err)
+		return
+	}
+
+	res := o.Handler.Handle(Params) // actually handle the request
+	o.Context.Respond(rw, r, route.Produces, route, res)
+
+}
@@ -0,0 +1,116 @@
+// Code generated by go-swagger; DO NOT EDIT.
+
+package operations
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the swagger generate command
+
+import (
+	"net/http"
+
+	"github.com/go-openapi/runtime"
+
+	"github.com/openclarity/apiclarity/plugins/api/server/models"
+)
+
+// PostTelemetriesOKCode is the HTTP code returned for type PostTelemetriesOK
+const PostTelemetriesOKCode int = 200
+
+/*PostTelemetriesOK Result per telemetry, in the order of the request
+
+swagger:response postTelemetriesOK
+*/
+type PostTelemetriesOK struct {
+
+	/*
+	  In: Body
+	*/
+	Payload *models.TelemetriesResult `json:"body,omitempty"`
+}
+
+// NewPostTelemetriesOK creates PostTelemetriesOK with default headers values
+func NewPostTelemetriesOK() *PostTelemetriesOK {
+
+	return &PostTelemetriesOK{}
+}
+
+// WithPayload adds the payload to the post telemetries o k response
+func (o *PostTelemetriesOK) WithPayload(payload *models.TelemetriesResult) *PostTelemetriesOK {
+	o.Payload = payload
+	return o
+}
+
+// SetPayload sets the payload to the post telemetries o k response
+func (o *PostTelemetriesOK) SetPayload(payload *models.TelemetriesResult) {
+	o.Payload = payload
+}
+
+// WriteResponse to the client
+func (o *PostTelemetriesOK) WriteResponse(rw http.ResponseWriter, producer runtime.Producer) {
+
+	rw.WriteHeader(200)
+	if o.Payload != nil {
+		payload := o.Payload
+		if err := producer.Produce(rw, payload); err != nil {
+			panic(err) // let the recovery middleware deal with this
+		}
+	}
+}
+
+/*PostTelemetriesDefault unknown error
+
+swagger:response postTelemetriesDefault
+*/
+type PostTelemetriesDefault struct {
+	_statusCode int
+
+	/*
+	  In: Body
+	*/
+	Payload *models.APIResponse `json:"body,omitempty"`
+}
+
+// NewPostTelemetriesDefault creates PostTelemetriesDefault with default headers values
+func NewPostTelemetriesDefault(code int) *PostTelemetriesDefault {
+	if code <= 0 {
+		code = 500
+	}
+
+	return &PostTelemetriesDefault{
+		_statusCode: code,
+	}
+}
+
+// WithStatusCode adds the status to the post telemetries default response
+func (o *PostTelemetriesDefault) WithStatusCode(code int) *PostTelemetriesDefault {
+	o._statusCode = code
+	return o
+}
+
+// SetStatusCode sets the status to the post telemetries default response
+func (o *PostTelemetriesDefault) SetStatusCode(code int) {
+	o._statusCode = code
+}
+
+// WithPayload adds the payload to the post telemetries default response
+func (o *PostTelemetriesDefault) WithPayload(payload *models.APIResponse) *PostTelemetriesDefault {
+	o.Payload = payload
+	return o
+}
+
+// SetPayload sets the payload to the post telemetries default response
+func (o *PostTelemetriesDefault) SetPayload(payload *models.APIResponse) {
+	o.Payload = payload
+}
+
+// WriteResponse to the client
+func (o *PostTelemetriesDefault) WriteResponse(rw http.ResponseWriter, producer runtime.Producer) {
+
+	rw.WriteHeader(o._statusCode)
+	if o.Payload != nil {
+		payload := o.Payload
+		if err := producer.Produce(rw, payload); err != nil {
+			panic(err) // let the recovery middleware deal with this
+		}
+	}
+}
@@ -0,0 +1,201 @@
+// Copyright © 2022 Cisco Systems, Inc. and its affiliates.
+// All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package telemetries_grpc_client
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"github.com/openclarity/apiclarity/plugins/api/client/models"
+	api "github.com/openclarity/apiclarity/plugins/api/grpc_gen/telemetries-service"
+	"github.com/openclarity/apiclarity/plugins/common"
+)
+
+// StreamMaxTelemetries is the number of telemetries sent over a stream before it is closed,
+// so the server summary of rejected and failed telemetries is reported periodically.
+const StreamMaxTelemetries = 1000
+
+// Client sends telemetries to the backend GRPC traces server, either one by one
+// or over a single long-lived client stream.
+type Client struct {
+	conn   *grpc.ClientConn
+	client api.TelemetriesClient
+
+	lock       sync.Mutex
+	stream     api.Telemetries_StreamTelemetriesClient
+	streamSent int
+}
+
+func Create(host string, tlsOptions *common.ClientTLSOptions) (*Client, error) {
+	transportCredentials := insecure.NewCredentials()
+	if tlsOptions != nil {
+		tlsConfig, err := common.NewClientTLSConfig(tlsOptions)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create tls config: %v", err)
+		}
+		transportCredentials = credentials.NewTLS(tlsConfig)
+	}
+
+	conn, err := grpc.Dial(host, grpc.WithTransportCredentials(transportCredentials))
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial %v: %v", host, err)
+	}
+
+	return &Client{
+		conn:   conn,
+		client: api.NewTelemetriesClient(conn),
+	}, nil
+}
+
+// PostTelemetry sends a single telemetry with a unary call.
+func (c *Client) PostTelemetry(ctx context.Context, telemetry *models.Telemetry) (*api.TelemetryResult, error) {
+	result, err := c.client.PostTelemetry(ctx, ConvertTelemetry(telemetry))
+	if err != nil {
+		return nil, fmt.Errorf("failed to post telemetry: %v", err)
+	}
+
+	return result, nil
+}
+
+// Send sends the telemetry over the client stream, opening the stream if needed.
+// On failure the stream is dropped and a new one is opened by the next call.
+// Every StreamMaxTelemetries telemetries the stream is closed and its summary is reported.
+func (c *Client) Send(telemetry *models.Telemetry) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.stream == nil {
+		stream, err := c.client.StreamTelemetries(context.Background())
+		if err != nil {
+			return fmt.Errorf("failed to open telemetries stream: %v", err)
+		}
+		c.stream = stream
+		c.streamSent = 0
+	}
+
+	if err := c.stream.Send(ConvertTelemetry(telemetry)); err != nil {
+		// the real error is returned from CloseAndRecv
+		_, recvErr := c.stream.CloseAndRecv()
+		c.stream = nil
+		if recvErr != nil {
+			err = recvErr
+		}
+		return fmt.Errorf("failed to send telemetry: %v", err)
+	}
+
+	c.streamSent++
+	if c.streamSent >= StreamMaxTelemetries {
+		if _, err := c.closeStream(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// CloseStream closes the current client stream and returns the server summary of it.
+func (c *Client) CloseStream() (*api.StreamTelemetriesResult, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.closeStream()
+}
+
+func (c *Client) closeStream() (*api.StreamTelemetriesResult, error) {
+	if c.stream == nil {
+		return &api.StreamTelemetriesResult{}, nil
+	}
+
+	result, err := c.stream.CloseAndRecv()
+	c.stream = nil
+	if err != nil {
+		return nil, fmt.Errorf("failed to close telemetries stream: %v", err)
+	}
+	if result.GetRejected() > 0 || result.GetFailed() > 0 {
+		log.Warnf("Telemetries were not accepted by the server: received=%v, rejected=%v, failed=%v",
+			result.GetReceived(), result.GetRejected(), result.GetFailed())
+	} else {
+		log.Debugf("Telemetries stream closed: %+v", result)
+	}
+
+	return result, nil
+}
+
+func (c *Client) Close() error {
+	if _, err := c.CloseStream(); err != nil {
+		log.Warnf("Failed to close telemetries stream: %v", err)
+	}
+
+	return c.conn.Close()
+}
+
+// ConvertTelemetry converts a telemetries API model into a GRPC telemetry.
+func ConvertTelemetry(telemetry *models.Telemetry) *api.Telemetry {
+	ret := &api.Telemetry{
+		DestinationAddress:   telemetry.DestinationAddress,
+		DestinationNamespace: telemetry.DestinationNamespace,
+		RequestID:            telemetry.RequestID,
+		Scheme:               telemetry.Scheme,
+		SourceAddress:        telemetry.SourceAddress,
+	}
+	if telemetry.Request != nil {
+		ret.Request = &api.Request{
+			Common: convertCommon(telemetry.Request.Common),
+			Host:   telemetry.Request.Host,
+			Method: telemetry.Request.Method,
+			Path:   telemetry.Request.Path,
+		}
+	}
+	if telemetry.Response != nil {
+		ret.Response = &api.Response{
+			Common:     convertCommon(telemetry.Response.Common),
+			StatusCode: telemetry.Response.StatusCode,
+		}
+	}
+
+	return ret
+}
+
+func convertCommon(modelsCommon *models.Common) *api.Common {
+	if modelsCommon == nil {
+		return nil
+	}
+
+	headers := make([]*api.Header, 0, len(modelsCommon.Headers))
+	for _, header := range modelsCommon.Headers {
+		if header == nil {
+			continue
+		}
+		headers = append(headers, &api.Header{
+			Key:   header.Key,
+			Value: header.Value,
+		})
+	}
+
+	return &api.Common{
+		Body:          modelsCommon.Body,
+		Headers:       headers,
+		Time:          modelsCommon.Time,
+		TruncatedBody: modelsCommon.TruncatedBody,
+		Version:       modelsCommon.Version,
+	}
+}
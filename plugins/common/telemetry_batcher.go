@@ -0,0 +1,186 @@
+// Copyright © 2022 Cisco Systems, Inc. and its affiliates.
+// All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/openclarity/apiclarity/plugins/api/client/client/operations"
+	"github.com/openclarity/apiclarity/plugins/api/client/models"
+)
+
+const (
+	DefaultTelemetryBatchSize     = 100
+	DefaultTelemetryFlushInterval = time.Second
+	// MaxTelemetryBatchSize is the largest batch accepted by the backend.
+	MaxTelemetryBatchSize = 1000
+)
+
+// TelemetriesPoster is implemented by the telemetries API client operations.
+type TelemetriesPoster interface {
+	PostTelemetries(params *operations.PostTelemetriesParams, opts ...operations.ClientOption) (*operations.PostTelemetriesOK, error)
+}
+
+type TelemetryBatcherConfig struct {
+	// MaxBatchSize is the number of telemetries that triggers a flush, at most MaxTelemetryBatchSize.
+	MaxBatchSize int
+	// FlushInterval is the maximum time a telemetry waits before being sent.
+	FlushInterval time.Duration
+	// MaxPending is the number of telemetries kept while a flush is in progress,
+	// telemetries above it are dropped. Defaults to 10 times MaxBatchSize.
+	MaxPending int
+}
+
+// TelemetryBatcher accumulates telemetries and posts them to the backend in batches.
+type TelemetryBatcher struct {
+	poster TelemetriesPoster
+	config TelemetryBatcherConfig
+
+	lock    sync.Mutex
+	pending []*models.Telemetry
+
+	flushCh chan struct{}
+	stopCh  chan struct{}
+	doneCh  chan struct{}
+}
+
+func NewTelemetryBatcher(poster TelemetriesPoster, config TelemetryBatcherConfig) *TelemetryBatcher {
+	if config.MaxBatchSize <= 0 {
+		config.MaxBatchSize = DefaultTelemetryBatchSize
+	}
+	if config.MaxBatchSize > MaxTelemetryBatchSize {
+		config.MaxBatchSize = MaxTelemetryBatchSize
+	}
+	if config.FlushInterval <= 0 {
+		config.FlushInterval = DefaultTelemetryFlushInterval
+	}
+	if config.MaxPending < config.MaxBatchSize {
+		config.MaxPending = 10 * config.MaxBatchSize // nolint:gomnd
+	}
+
+	return &TelemetryBatcher{
+		poster:  poster,
+		config:  config,
+		flushCh: make(chan struct{}, 1),
+		stopCh:  make(chan struct{}),
+		doneCh:  make(chan struct{}),
+	}
+}
+
+func (b *TelemetryBatcher) Start() {
+	go func() {
+		defer close(b.doneCh)
+		ticker := time.NewTicker(b.config.FlushInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-b.stopCh:
+				b.flush()
+				return
+			case <-ticker.C:
+				b.flush()
+			case <-b.flushCh:
+				b.flush()
+			}
+		}
+	}()
+}
+
+// Stop flushes the pending telemetries and stops the batcher.
+func (b *TelemetryBatcher) Stop() {
+	close(b.stopCh)
+	<-b.doneCh
+}
+
+// Send adds the telemetry to the next batch. It returns false if the telemetry was dropped.
+func (b *TelemetryBatcher) Send(telemetry *models.Telemetry) bool {
+	b.lock.Lock()
+	if len(b.pending) >= b.config.MaxPending {
+		b.lock.Unlock()
+		log.Warnf("Too many pending telemetries, dropping telemetry")
+		return false
+	}
+	b.pending = append(b.pending, telemetry)
+	shouldFlush := len(b.pending) >= b.config.MaxBatchSize
+	b.lock.Unlock()
+
+	if shouldFlush {
+		select {
+		case b.flushCh <- struct{}{}:
+		default:
+			// flush already requested
+		}
+	}
+
+	return true
+}
+
+func (b *TelemetryBatcher) takePending() []*models.Telemetry {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	pending := b.pending
+	b.pending = nil
+	return pending
+}
+
+func (b *TelemetryBatcher) flush() {
+	pending := b.takePending()
+
+	for len(pending) > 0 {
+		size := b.config.MaxBatchSize
+		if size > len(pending) {
+			size = len(pending)
+		}
+		b.post(pending[:size])
+		pending = pending[size:]
+	}
+}
+
+func (b *TelemetryBatcher) post(batch []*models.Telemetry) {
+	params := operations.NewPostTelemetriesParams().WithBody(batch)
+
+	response, err := b.poster.PostTelemetries(params)
+	if err != nil {
+		log.Errorf("Failed to post %v telemetries: %v", len(batch), err)
+		return
+	}
+	if response.Payload == nil {
+		log.Debugf("Telemetries batch has been sent: %v", len(batch))
+		return
+	}
+
+	var rejected, failed int
+	for _, result := range response.Payload.Results {
+		switch result.StatusCode {
+		case http.StatusOK:
+		case http.StatusTooManyRequests:
+			rejected++
+		default:
+			failed++
+		}
+	}
+	if rejected > 0 || failed > 0 {
+		log.Warnf("Telemetries batch was partially handled: sent=%v, rejected=%v, failed=%v", len(batch), rejected, failed)
+		return
+	}
+	log.Debugf("Telemetries batch has been sent: %v", len(batch))
+}
@@ -0,0 +1,96 @@
+// Copyright © 2022 Cisco Systems, Inc. and its affiliates.
+// All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/openclarity/apiclarity/plugins/api/client/client/operations"
+	"github.com/openclarity/apiclarity/plugins/api/client/models"
+)
+
+type fakePoster struct {
+	lock    sync.Mutex
+	batches [][]*models.Telemetry
+}
+
+func (f *fakePoster) PostTelemetries(params *operations.PostTelemetriesParams, _ ...operations.ClientOption) (*operations.PostTelemetriesOK, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.batches = append(f.batches, params.Body)
+	return &operations.PostTelemetriesOK{Payload: &models.TelemetriesResult{}}, nil
+}
+
+func (f *fakePoster) batchSizes() []int {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	var sizes []int
+	for _, batch := range f.batches {
+		sizes = append(sizes, len(batch))
+	}
+	return sizes
+}
+
+func TestTelemetryBatcher_FlushOnSize(t *testing.T) {
+	poster := &fakePoster{}
+	batcher := NewTelemetryBatcher(poster, TelemetryBatcherConfig{MaxBatchSize: 2, FlushInterval: time.Hour})
+	batcher.Start()
+
+	batcher.Send(&models.Telemetry{})
+	batcher.Send(&models.Telemetry{})
+
+	deadline := time.Now().Add(time.Second)
+	for len(poster.batchSizes()) == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	batcher.Send(&models.Telemetry{})
+	batcher.Stop()
+
+	sizes := poster.batchSizes()
+	if len(sizes) != 2 || sizes[0] != 2 || sizes[1] != 1 {
+		t.Errorf("batch sizes = %v, want [2 1]", sizes)
+	}
+}
+
+func TestTelemetryBatcher_FlushOnInterval(t *testing.T) {
+	poster := &fakePoster{}
+	batcher := NewTelemetryBatcher(poster, TelemetryBatcherConfig{MaxBatchSize: 100, FlushInterval: 10 * time.Millisecond})
+	batcher.Start()
+	defer batcher.Stop()
+
+	batcher.Send(&models.Telemetry{})
+
+	deadline := time.Now().Add(time.Second)
+	for len(poster.batchSizes()) == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if sizes := poster.batchSizes(); len(sizes) != 1 || sizes[0] != 1 {
+		t.Errorf("batch sizes = %v, want [1]", sizes)
+	}
+}
+
+func TestTelemetryBatcher_DropWhenFull(t *testing.T) {
+	batcher := NewTelemetryBatcher(&fakePoster{}, TelemetryBatcherConfig{MaxBatchSize: 1, MaxPending: 1})
+	// not started, so nothing is flushed
+	if !batcher.Send(&models.Telemetry{}) {
+		t.Errorf("Send() = false, want true")
+	}
+	if batcher.Send(&models.Telemetry{}) {
+		t.Errorf("Send() = true, want false")
+	}
+}
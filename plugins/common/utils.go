@@ -128,7 +128,8 @@ func NewTraceSamplingAPIClient(host string, tlsOptions *ClientTLSOptions) (*trac
 	return apiClient, nil
 }
 
-func createClientTransportTLS(host string, tlsOptions *ClientTLSOptions) (runtime.ClientTransport, error) {
+// NewClientTLSConfig returns a TLS config that trusts the system cert pool augmented with the root CA from tlsOptions.
+func NewClientTLSConfig(tlsOptions *ClientTLSOptions) (*tls.Config, error) {
 	// Get the SystemCertPool
 	rootCAs, _ := x509.SystemCertPool()
 	if rootCAs == nil {
@@ -147,8 +148,15 @@ func createClientTransportTLS(host string, tlsOptions *ClientTLSOptions) (runtim
 	}
 
 	//Trust the augmented cert pool in our client
-	tlsConfig := &tls.Config{
+	return &tls.Config{
 		RootCAs: rootCAs,
+	}, nil
+}
+
+func createClientTransportTLS(host string, tlsOptions *ClientTLSOptions) (runtime.ClientTransport, error) {
+	tlsConfig, err := NewClientTLSConfig(tlsOptions)
+	if err != nil {
+		return nil, err
 	}
 	customTransport := http.DefaultTransport.(*http.Transport).Clone()
 	customTransport.TLSClientConfig = tlsConfig
@@ -7,10 +7,12 @@ KongGatewayIngressNamespace="${KONG_GATEWAY_INGRESS_NAMESPACE:-default}"
 UpstreamTelemetryHostName="${UPSTREAM_TELEMETRY_HOST_NAME:-apiclarity-apiclarity.apiclarity:9000}"
 TraceSamplingHostName="${TRACE_SAMPLING_HOST_NAME:-apiclarity-apiclarity.apiclarity:9990}"
 TraceSamplingEnabled="${TRACE_SAMPLING_ENABLED:-false}"
+TelemetryBatchSize="${TELEMETRY_BATCH_SIZE:-0}"
+TelemetryFlushIntervalMsec="${TELEMETRY_FLUSH_INTERVAL_MSEC:-1000}"
 
 DIR="$( cd "$( dirname "${BASH_SOURCE[0]}" )" >/dev/null 2>&1 && pwd )"
 
-cat "${DIR}/kongPlugin.yaml" | sed "s/{{UPSTREAM_TELEMETRY_HOST_NAME}}/$UpstreamTelemetryHostName/g" | sed "s/{{TRACE_SAMPLING_HOST_NAME}}/$TraceSamplingHostName/g" | sed "s/{{TRACE_SAMPLING_ENABLED}}/$TraceSamplingEnabled/g" | kubectl -n ${KongGatewayIngressNamespace} apply -f -
+cat "${DIR}/kongPlugin.yaml" | sed "s/{{UPSTREAM_TELEMETRY_HOST_NAME}}/$UpstreamTelemetryHostName/g" | sed "s/{{TRACE_SAMPLING_HOST_NAME}}/$TraceSamplingHostName/g" | sed "s/{{TRACE_SAMPLING_ENABLED}}/$TraceSamplingEnabled/g" | sed "s/{{TELEMETRY_BATCH_SIZE}}/$TelemetryBatchSize/g" | sed "s/{{TELEMETRY_FLUSH_INTERVAL_MSEC}}/$TelemetryFlushIntervalMsec/g" | kubectl -n ${KongGatewayIngressNamespace} apply -f -
 
 deploymentPatch=`cat "${DIR}/patch-deployment.yaml" | sed "s/{{KONG_PROXY_CONTAINER_NAME}}/$KongProxyContainerName/g"`
 
@@ -19,6 +19,7 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/Kong/go-pdk"
@@ -35,6 +36,8 @@ import (
 var (
 	traceSamplingClient *trace_sampling_client.Client
 	telemetriesAPI      *client.APIClarityPluginsTelemetriesAPI
+	telemetryBatcher    *common.TelemetryBatcher
+	telemetriesInitOnce sync.Once
 )
 
 type Config struct {
@@ -42,6 +45,9 @@ type Config struct {
 	Host                 string `json:"host"`
 	TraceSamplingHost    string `json:"trace_sampling_host"`
 	TraceSamplingEnabled bool   `json:"trace_sampling_enabled"`
+	// TelemetryBatchSize above 1 enables sending telemetries in batches
+	TelemetryBatchSize         int `json:"telemetry_batch_size"`
+	TelemetryFlushIntervalMsec int `json:"telemetry_flush_interval_msec"`
 }
 
 func New() interface{} {
@@ -78,19 +84,13 @@ func (conf Config) Response(kong *pdk.PDK) {
 			return
 		}
 	}
+	// Kong may handle responses concurrently, the clients are created once
+	telemetriesInitOnce.Do(func() {
+		initTelemetriesAPI(kong, conf)
+	})
 	if telemetriesAPI == nil {
-		var tlsOptions *common.ClientTLSOptions
-		if conf.EnableTLS {
-			tlsOptions = &common.ClientTLSOptions{
-				RootCAFileName: common.CACertFile,
-			}
-		}
-		apiClient, err := common.NewTelemetryAPIClient(conf.Host, tlsOptions)
-		if err != nil {
-			_ = kong.Log.Err(fmt.Sprintf("Failed to create new api client: %v", err))
-			return
-		}
-		telemetriesAPI = apiClient
+		_ = kong.Log.Err("Telemetries api client is not available")
+		return
 	}
 	telemetry, err := createTelemetry(kong)
 	if err != nil {
@@ -98,6 +98,13 @@ func (conf Config) Response(kong *pdk.PDK) {
 		return
 	}
 
+	if telemetryBatcher != nil {
+		if telemetryBatcher.Send(telemetry) {
+			_ = kong.Log.Debug("Telemetry has been queued")
+		}
+		return
+	}
+
 	params := operations.NewPostTelemetryParams().WithBody(telemetry)
 
 	_, err = telemetriesAPI.Operations.PostTelemetry(params)
@@ -105,7 +112,30 @@ func (conf Config) Response(kong *pdk.PDK) {
 		_ = kong.Log.Err(fmt.Sprintf("Failed to post telemetry: %v", err))
 		return
 	}
-	_ = kong.Log.Info(fmt.Sprintf("Telemetry has been sent: %v", telemetry))
+	_ = kong.Log.Debug("Telemetry has been sent")
+}
+
+func initTelemetriesAPI(kong *pdk.PDK, conf Config) {
+	var tlsOptions *common.ClientTLSOptions
+	if conf.EnableTLS {
+		tlsOptions = &common.ClientTLSOptions{
+			RootCAFileName: common.CACertFile,
+		}
+	}
+	apiClient, err := common.NewTelemetryAPIClient(conf.Host, tlsOptions)
+	if err != nil {
+		_ = kong.Log.Err(fmt.Sprintf("Failed to create new api client: %v", err))
+		return
+	}
+	if conf.TelemetryBatchSize > 1 {
+		// the plugin server runs as long as Kong, the batcher is never stopped
+		telemetryBatcher = common.NewTelemetryBatcher(apiClient.Operations, common.TelemetryBatcherConfig{
+			MaxBatchSize:  conf.TelemetryBatchSize,
+			FlushInterval: time.Duration(conf.TelemetryFlushIntervalMsec) * time.Millisecond,
+		})
+		telemetryBatcher.Start()
+	}
+	telemetriesAPI = apiClient
 }
 
 func shouldTrace(kong *pdk.PDK) (bool, error) {
@@ -54,6 +54,7 @@ var (
 	traceSamplingHost    string
 	traceSamplingEnabled bool
 	TraceSamplingClient  *trace_sampling_client.Client
+	telemetryBatcher     *common.TelemetryBatcher
 )
 
 //nolint:gochecknoinits
@@ -74,6 +75,30 @@ func init() {
 			TraceSamplingClient.Start()
 		}
 	}
+	// TELEMETRY_BATCH_SIZE above 1 enables sending telemetries in batches
+	if batchSize, _ := strconv.Atoi(os.Getenv("TELEMETRY_BATCH_SIZE")); batchSize > 1 {
+		apiClient, err := common.NewTelemetryAPIClient(telemetryHost, getTLSOptions())
+		if err != nil {
+			logger.Errorf("Failed to create new api client: %v", err)
+			return
+		}
+		flushIntervalMsec, _ := strconv.Atoi(os.Getenv("TELEMETRY_FLUSH_INTERVAL_MSEC"))
+		// the plugin is loaded as long as Tyk runs, the batcher is never stopped
+		telemetryBatcher = common.NewTelemetryBatcher(apiClient.Operations, common.TelemetryBatcherConfig{
+			MaxBatchSize:  batchSize,
+			FlushInterval: time.Duration(flushIntervalMsec) * time.Millisecond,
+		})
+		telemetryBatcher.Start()
+	}
+}
+
+func getTLSOptions() *common.ClientTLSOptions {
+	if !enableTLS {
+		return nil
+	}
+	return &common.ClientTLSOptions{
+		RootCAFileName: common.CACertFile,
+	}
 }
 
 // Called during post phase.
@@ -122,13 +147,14 @@ func ResponseSendTelemetry(_ http.ResponseWriter, res *http.Response, req *http.
 		return
 	}
 
-	var tlsOptions *common.ClientTLSOptions
-	if enableTLS {
-		tlsOptions = &common.ClientTLSOptions{
-			RootCAFileName: common.CACertFile,
+	if telemetryBatcher != nil {
+		if telemetryBatcher.Send(telemetry) {
+			logger.Infof("Telemetry has been queued")
 		}
+		return
 	}
-	apiClient, err := common.NewTelemetryAPIClient(telemetryHost, tlsOptions)
+
+	apiClient, err := common.NewTelemetryAPIClient(telemetryHost, getTLSOptions())
 	if err != nil {
 		logger.Errorf("Failed to create new api client: %v", err)
 		return
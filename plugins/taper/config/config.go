@@ -22,28 +22,39 @@ import (
 
 const (
 	UpstreamAddressEnv          = "UPSTREAM_TELEMETRY_HOST_NAME"
+	UpstreamGRPCAddressEnv      = "UPSTREAM_TELEMETRY_GRPC_HOST_NAME"
 	NamespacesToTapEnv          = "NAMESPACES_TO_TAP"
 	TapLogLevelEnv              = "TAP_LOG_LEVEL"
 	EnableTLSEnv                = "ENABLE_TLS"
 	TraceSamplingManagerAddress = "TRACE_SAMPLING_HOST_NAME"
 	TraceSamplingEnabled        = "TRACE_SAMPLING_ENABLED"
+	TelemetryBatchSize          = "TELEMETRY_BATCH_SIZE"
+	TelemetryFlushIntervalMsec  = "TELEMETRY_FLUSH_INTERVAL_MSEC"
 )
 
 type Config struct {
-	NamespaceToTap              []string
-	UpstreamAddress             string
+	NamespaceToTap  []string
+	UpstreamAddress string
+	// UpstreamGRPCAddress, when set, makes the taper stream telemetries over GRPC
+	UpstreamGRPCAddress         string
 	MizuLogLevel                logging.Level
 	EnableTLS                   bool
 	TraceSamplingManagerAddress string
 	TraceSamplingEnabled        bool
+	// TelemetryBatchSize above 1 enables sending telemetries in batches
+	TelemetryBatchSize         int
+	TelemetryFlushIntervalMsec int
 }
 
 func LoadConfig() *Config {
 	return &Config{
 		NamespaceToTap:              viper.GetStringSlice(NamespacesToTapEnv),
 		UpstreamAddress:             viper.GetString(UpstreamAddressEnv),
+		UpstreamGRPCAddress:         viper.GetString(UpstreamGRPCAddressEnv),
 		EnableTLS:                   viper.GetBool(EnableTLSEnv),
 		TraceSamplingManagerAddress: viper.GetString(TraceSamplingManagerAddress),
 		TraceSamplingEnabled:        viper.GetBool(TraceSamplingEnabled),
+		TelemetryBatchSize:          viper.GetInt(TelemetryBatchSize),
+		TelemetryFlushIntervalMsec:  viper.GetInt(TelemetryFlushIntervalMsec),
 	}
 }
@@ -58,6 +58,10 @@ func main() {
 		cli.BoolFlag{
 			Name: "nodefrag",
 		},
+		cli.StringFlag{
+			Name:  pcapFileFlag,
+			Usage: "pcap file to replay instead of tapping an interface, pod filtering is disabled in this mode",
+		},
 		cli.StringFlag{
 			Name:  logutils.LogLevelFlag,
 			Value: logutils.LogLevelDefaultValue,
@@ -17,6 +17,7 @@ package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
 	"io/ioutil"
 	"net/http"
@@ -27,6 +28,7 @@ import (
 	"sort"
 	"strconv"
 	"syscall"
+	"time"
 
 	logutils "github.com/Portshift/go-utils/log"
 	log "github.com/sirupsen/logrus"
@@ -39,14 +41,20 @@ import (
 	"github.com/openclarity/apiclarity/plugins/api/client/client/operations"
 	"github.com/openclarity/apiclarity/plugins/api/client/models"
 	"github.com/openclarity/apiclarity/plugins/common"
+	"github.com/openclarity/apiclarity/plugins/common/telemetries_grpc_client"
 	"github.com/openclarity/apiclarity/plugins/common/trace_sampling_client"
 	"github.com/openclarity/apiclarity/plugins/taper/config"
 	"github.com/openclarity/apiclarity/plugins/taper/monitor"
 )
 
+// pcapFileFlag is also the name of the mizu tapper global flag for reading packets from a file.
+const pcapFileFlag = "r"
+
 type Agent struct {
 	podMonitor          *monitor.PodMonitor
 	apiClient           *client.APIClarityPluginsTelemetriesAPI
+	telemetryBatcher    *common.TelemetryBatcher
+	grpcClient          *telemetries_grpc_client.Client
 	traceSamplingClient *trace_sampling_client.Client
 }
 
@@ -81,6 +89,27 @@ func run(c *cli.Context) {
 		apiClient: apiClient,
 	}
 
+	if runConfig.UpstreamGRPCAddress != "" {
+		grpcClient, err := telemetries_grpc_client.Create(runConfig.UpstreamGRPCAddress, tlsOptions)
+		if err != nil {
+			log.Errorf("Failed to create new grpc client: %v", err)
+			return
+		}
+		agent.grpcClient = grpcClient
+		defer func() {
+			if err := grpcClient.Close(); err != nil {
+				log.Errorf("Failed to close grpc client: %v", err)
+			}
+		}()
+	} else if runConfig.TelemetryBatchSize > 1 {
+		agent.telemetryBatcher = common.NewTelemetryBatcher(apiClient.Operations, common.TelemetryBatcherConfig{
+			MaxBatchSize:  runConfig.TelemetryBatchSize,
+			FlushInterval: time.Duration(runConfig.TelemetryFlushIntervalMsec) * time.Millisecond,
+		})
+		agent.telemetryBatcher.Start()
+		defer agent.telemetryBatcher.Stop()
+	}
+
 	if runConfig.TraceSamplingEnabled {
 		TSM, err := trace_sampling_client.Create(false, runConfig.TraceSamplingManagerAddress, common.SamplingInterval)
 		if err != nil {
@@ -93,14 +122,25 @@ func run(c *cli.Context) {
 	// set mizu logger
 	logger.InitLoggerStderrOnly(runConfig.MizuLogLevel)
 
-	podMonitor, err := monitor.NewPodMonitor(runConfig.NamespaceToTap)
-	if err != nil {
-		log.Errorf("Failed to create pod monitor: %v", err)
-		return
-	}
 	ctx, cancel := context.WithCancel(context.Background())
-	agent.podMonitor = podMonitor
-	go podMonitor.Start(ctx)
+	if pcapFile := c.String(pcapFileFlag); pcapFile != "" {
+		// offline replay, there is no cluster to resolve the pods from
+		log.Infof("Replaying pcap file: %v", pcapFile)
+		if err := flag.Set(pcapFileFlag, pcapFile); err != nil {
+			log.Errorf("Failed to set pcap file: %v", err)
+			cancel()
+			return
+		}
+	} else {
+		podMonitor, err := monitor.NewPodMonitor(runConfig.NamespaceToTap)
+		if err != nil {
+			log.Errorf("Failed to create pod monitor: %v", err)
+			cancel()
+			return
+		}
+		agent.podMonitor = podMonitor
+		go podMonitor.Start(ctx)
+	}
 
 	outputItems := make(chan *api.OutputChannelItem)
 	options := &api.TrafficFilteringOptions{}
@@ -140,19 +180,34 @@ func (a *Agent) startReadOutputItems(ctx context.Context, outputItems chan *api.
 					return
 				}
 
-				params := operations.NewPostTelemetryParams().WithBody(telemetry)
-
-				_, err = a.apiClient.Operations.PostTelemetry(params)
-				if err != nil {
-					log.Errorf("Failed to post telemetry: %v", err)
-					return
-				}
-				log.Info("Telemetry has been sent")
+				a.sendTelemetry(telemetry)
 			}()
 		}
 	}
 }
 
+func (a *Agent) sendTelemetry(telemetry *models.Telemetry) {
+	if a.grpcClient != nil {
+		if err := a.grpcClient.Send(telemetry); err != nil {
+			log.Errorf("Failed to stream telemetry: %v", err)
+		}
+		return
+	}
+	if a.telemetryBatcher != nil {
+		a.telemetryBatcher.Send(telemetry)
+		return
+	}
+
+	params := operations.NewPostTelemetryParams().WithBody(telemetry)
+
+	_, err := a.apiClient.Operations.PostTelemetry(params)
+	if err != nil {
+		log.Errorf("Failed to post telemetry: %v", err)
+		return
+	}
+	log.Info("Telemetry has been sent")
+}
+
 func (a *Agent) shouldTrace(host string) bool {
 	if a.traceSamplingClient == nil {
 		return true
@@ -168,6 +223,9 @@ func (a *Agent) shouldTrace(host string) bool {
 // the filtering logic is that we only send to API Clarity telemetries where the client is in a monitored namespace
 // and the destination is not a pod IP (service IP or external IP). Same behaviour as the wasm filter.
 func (a *Agent) shouldIgnoreTelemetry(item *api.OutputChannelItem) bool {
+	if a.podMonitor == nil {
+		return false
+	}
 	clientNamespace := a.podMonitor.GetPodNamespaceByIP(item.ConnectionInfo.ClientIP)
 	if !a.podMonitor.IsMonitoredNamespace(clientNamespace) {
 		// client pod is not on monitored namespace, ignore